@@ -0,0 +1,190 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnsafePath   = errors.New("archive: unsafe path")
+	ErrTooManyFiles = errors.New("archive: too many entries")
+	ErrTooLarge     = errors.New("archive: size limit exceeded")
+	ErrUnsupported  = errors.New("archive: unsupported entry type")
+)
+
+// Progress 进度信息
+type Progress struct {
+	// Entry 当前条目名
+	Entry string
+	// Files 已处理条目数
+	Files int
+	// Bytes 已处理的未压缩字节数
+	Bytes int64
+}
+
+type options struct {
+	maxFiles    int
+	maxSize     int64
+	maxFileSize int64
+	progress    func(Progress)
+	gzip        bool
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithMaxFiles 限制解压的条目数量，默认 10000；创建归档时不生效
+func WithMaxFiles(n int) Option {
+	return func(o *options) {
+		o.maxFiles = n
+	}
+}
+
+// WithMaxSize 限制解压后总大小，默认 1GB；按实际写入字节计算，不信任头部声明的大小。创建归档时不生效
+func WithMaxSize(n int64) Option {
+	return func(o *options) {
+		o.maxSize = n
+	}
+}
+
+// WithMaxFileSize 限制单个文件解压后大小，默认不单独限制；创建归档时不生效
+func WithMaxFileSize(n int64) Option {
+	return func(o *options) {
+		o.maxFileSize = n
+	}
+}
+
+// WithProgress 设置进度回调，每处理完一个条目调用一次
+func WithProgress(fn func(Progress)) Option {
+	return func(o *options) {
+		o.progress = fn
+	}
+}
+
+// WithGzip 创建 tar 时使用 gzip 压缩
+func WithGzip() Option {
+	return func(o *options) {
+		o.gzip = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		maxFiles: 10000,
+		maxSize:  1 << 30,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// createTracker 创建归档用的 tracker，只统计进度不检查限制：打包的是本地可信目录，
+// 限制用于防御不可信的上传归档
+func createTracker(opts []Option) *tracker {
+	o := newOptions(opts)
+	o.maxFiles, o.maxSize, o.maxFileSize = 0, 0, 0
+	return &tracker{opts: o}
+}
+
+// tracker 统计条目数与字节数并检查限制
+type tracker struct {
+	opts  *options
+	files int
+	bytes int64
+}
+
+func (t *tracker) next() error {
+	t.files++
+	if t.opts.maxFiles > 0 && t.files > t.opts.maxFiles {
+		return fmt.Errorf("%w: more than %d", ErrTooManyFiles, t.opts.maxFiles)
+	}
+	return nil
+}
+
+func (t *tracker) done(name string) {
+	if t.opts.progress != nil {
+		t.opts.progress(Progress{Entry: name, Files: t.files, Bytes: t.bytes})
+	}
+}
+
+// copy 复制单个文件内容，超过单文件或总大小限制时返回 ErrTooLarge
+func (t *tracker) copy(dst io.Writer, src io.Reader, name string) error {
+	limit := int64(-1)
+	if t.opts.maxSize > 0 {
+		limit = t.opts.maxSize - t.bytes
+	}
+	if t.opts.maxFileSize > 0 && (limit < 0 || t.opts.maxFileSize < limit) {
+		limit = t.opts.maxFileSize
+	}
+
+	if limit < 0 {
+		n, err := io.Copy(dst, src)
+		t.bytes += n
+		return err
+	}
+
+	// 多读一个字节用于判断是否超限
+	n, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	t.bytes += n
+	if err != nil {
+		return err
+	}
+	if n > limit {
+		return fmt.Errorf("%w: %s", ErrTooLarge, name)
+	}
+	return nil
+}
+
+// safeJoin 将条目名拼接到目标目录下，拒绝绝对路径与 .. 逃逸
+func safeJoin(dst, name string) (string, error) {
+	name = strings.ReplaceAll(name, `\`, "/")
+	if name == "" || strings.HasPrefix(name, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+
+	target := filepath.Join(dst, filepath.FromSlash(name))
+	rel, err := filepath.Rel(dst, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+	return target, nil
+}
+
+// writeFile 创建文件（含父目录）并写入内容
+func (t *tracker) writeFile(target string, r io.Reader, mode os.FileMode, name string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	// 只保留权限位，去掉 setuid 等特殊位
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm()|0o600)
+	if err != nil {
+		return err
+	}
+	if err := t.copy(f, r, name); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// walk 遍历源目录，回调相对路径（以 / 分隔）；符号链接被跳过
+func walk(src string, fn func(path, rel string, info os.FileInfo) error) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." || info.Mode()&os.ModeSymlink != 0 {
+			return nil
+		}
+		return fn(path, filepath.ToSlash(rel), info)
+	})
+}
@@ -0,0 +1,117 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T) string {
+	t.Helper()
+	src := t.TempDir()
+	files := map[string]string{
+		"a.txt":       "hello",
+		"dir/b.txt":   "world",
+		"dir/c/d.txt": strings.Repeat("x", 100),
+	}
+	for name, content := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return src
+}
+
+func checkTree(t *testing.T, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dst, "dir", "b.txt"))
+	if err != nil || string(data) != "world" {
+		t.Fatalf("unexpected extracted content %q: %v", data, err)
+	}
+}
+
+func TestZipRoundTrip(t *testing.T) {
+	src := writeTree(t)
+	var buf bytes.Buffer
+	// 解压限制不作用于创建
+	if err := CreateZip(&buf, src, WithMaxFiles(1), WithMaxSize(1)); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var last Progress
+	dst := t.TempDir()
+	err := ExtractZipReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()), dst,
+		WithProgress(func(p Progress) { last = p }))
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	checkTree(t, dst)
+	if last.Bytes != 110 {
+		t.Fatalf("unexpected progress %+v", last)
+	}
+
+	err = ExtractZipReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()), t.TempDir(), WithMaxSize(50))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	err = ExtractZipReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()), t.TempDir(), WithMaxFiles(2))
+	if !errors.Is(err, ErrTooManyFiles) {
+		t.Fatalf("expected ErrTooManyFiles, got %v", err)
+	}
+}
+
+func TestTarGzRoundTrip(t *testing.T) {
+	src := writeTree(t)
+	var buf bytes.Buffer
+	if err := CreateTar(&buf, src, WithGzip(), WithMaxFiles(1), WithMaxFileSize(1)); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	dst := t.TempDir()
+	if err := ExtractTar(bytes.NewReader(buf.Bytes()), dst); err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	checkTree(t, dst)
+
+	err := ExtractTar(bytes.NewReader(buf.Bytes()), t.TempDir(), WithMaxFileSize(10))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestRejectTraversal(t *testing.T) {
+	var zbuf bytes.Buffer
+	zw := zip.NewWriter(&zbuf)
+	w, _ := zw.Create("../evil.txt")
+	_, _ = w.Write([]byte("x"))
+	_ = zw.Close()
+
+	err := ExtractZipReader(bytes.NewReader(zbuf.Bytes()), int64(zbuf.Len()), t.TempDir())
+	if !errors.Is(err, ErrUnsafePath) {
+		t.Fatalf("expected ErrUnsafePath, got %v", err)
+	}
+
+	var tbuf bytes.Buffer
+	tw := tar.NewWriter(&tbuf)
+	_ = tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"})
+	_ = tw.Close()
+
+	if err := ExtractTar(&tbuf, t.TempDir()); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported, got %v", err)
+	}
+
+	for _, name := range []string{"/abs", "a/../../b", `..\win`} {
+		if _, err := safeJoin("/tmp/dst", name); !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("%q: expected ErrUnsafePath, got %v", name, err)
+		}
+	}
+}
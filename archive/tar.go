@@ -0,0 +1,119 @@
+package archive
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// CreateTar 将目录 src 打包为 tar 写入 w，配合 WithGzip 生成 tar.gz
+func CreateTar(w io.Writer, src string, opts ...Option) (err error) {
+	t := createTracker(opts)
+
+	if t.opts.gzip {
+		gw := gzip.NewWriter(w)
+		defer func() {
+			if cerr := gw.Close(); err == nil && cerr != nil {
+				err = fmt.Errorf("archive: create tar: %w", cerr)
+			}
+		}()
+		w = gw
+	}
+	tw := tar.NewWriter(w)
+
+	err = walk(src, func(path, rel string, info os.FileInfo) error {
+		if err := t.next(); err != nil {
+			return err
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := t.copy(tw, f, rel); err != nil {
+				return err
+			}
+		}
+		t.done(rel)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("archive: create tar: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("archive: create tar: %w", err)
+	}
+	return nil
+}
+
+// ExtractTar 解压 tar 或 tar.gz（自动识别 gzip）到 dst，
+// 拒绝路径穿越、符号链接、硬链接和设备文件条目
+func ExtractTar(r io.Reader, dst string, opts ...Option) error {
+	t := &tracker{opts: newOptions(opts)}
+
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return fmt.Errorf("archive: open gzip: %w", err)
+		}
+		defer gr.Close()
+		r = gr
+	} else {
+		r = br
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("archive: read tar: %w", err)
+		}
+
+		// pax 全局头不对应实际文件
+		if header.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		if err := t.next(); err != nil {
+			return err
+		}
+		target, err := safeJoin(dst, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("archive: mkdir %s: %w", header.Name, err)
+			}
+		case tar.TypeReg:
+			if err := t.writeFile(target, tr, header.FileInfo().Mode(), header.Name); err != nil {
+				return fmt.Errorf("archive: extract %s: %w", header.Name, err)
+			}
+		default:
+			return fmt.Errorf("%w: %s (type %q)", ErrUnsupported, header.Name, header.Typeflag)
+		}
+		t.done(header.Name)
+	}
+}
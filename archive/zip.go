@@ -0,0 +1,125 @@
+package archive
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// CreateZip 将目录 src 打包为 zip 写入 w
+func CreateZip(w io.Writer, src string, opts ...Option) error {
+	t := createTracker(opts)
+	zw := zip.NewWriter(w)
+
+	err := walk(src, func(path, rel string, info os.FileInfo) error {
+		if err := t.next(); err != nil {
+			return err
+		}
+
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if info.IsDir() {
+			header.Name += "/"
+			if _, err := zw.CreateHeader(header); err != nil {
+				return err
+			}
+			t.done(rel)
+			return nil
+		}
+		header.Method = zip.Deflate
+
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := t.copy(fw, f, rel); err != nil {
+			return err
+		}
+		t.done(rel)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("archive: create zip: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("archive: create zip: %w", err)
+	}
+	return nil
+}
+
+// ExtractZip 解压 zip 文件到 dst，拒绝路径穿越与符号链接条目
+func ExtractZip(src, dst string, opts ...Option) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("archive: open zip %s: %w", src, err)
+	}
+	defer zr.Close()
+	return extractZip(&zr.Reader, dst, opts...)
+}
+
+// ExtractZipReader 从 io.ReaderAt 解压 zip，适用于上传内容已在内存或临时文件中的场景
+func ExtractZipReader(r io.ReaderAt, size int64, dst string, opts ...Option) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return fmt.Errorf("archive: open zip: %w", err)
+	}
+	return extractZip(zr, dst, opts...)
+}
+
+func extractZip(zr *zip.Reader, dst string, opts ...Option) error {
+	t := &tracker{opts: newOptions(opts)}
+
+	// 先检查条目数量，避免对超限的压缩包做无用功
+	if t.opts.maxFiles > 0 && len(zr.File) > t.opts.maxFiles {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyFiles, len(zr.File), t.opts.maxFiles)
+	}
+
+	for _, f := range zr.File {
+		if err := t.next(); err != nil {
+			return err
+		}
+		target, err := safeJoin(dst, f.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := f.Mode()
+		switch {
+		case mode.IsDir() || strings.HasSuffix(f.Name, "/"):
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return fmt.Errorf("archive: mkdir %s: %w", f.Name, err)
+			}
+		case mode.IsRegular():
+			if err := extractZipFile(t, f, target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %s (%s)", ErrUnsupported, f.Name, mode.Type())
+		}
+		t.done(f.Name)
+	}
+	return nil
+}
+
+func extractZipFile(t *tracker, f *zip.File, target string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("archive: open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := t.writeFile(target, rc, f.Mode(), f.Name); err != nil {
+		return fmt.Errorf("archive: extract %s: %w", f.Name, err)
+	}
+	return nil
+}
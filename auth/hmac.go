@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 签名相关请求头
+const (
+	HeaderKeyID     = "X-Auth-Key"
+	HeaderTimestamp = "X-Auth-Timestamp"
+	HeaderNonce     = "X-Auth-Nonce"
+	HeaderSignature = "X-Auth-Signature"
+)
+
+var (
+	ErrMissingSignature = errors.New("auth: missing signature headers")
+	ErrUnknownKey       = errors.New("auth: unknown key id")
+	ErrExpired          = errors.New("auth: timestamp outside allowed window")
+	ErrReplayed         = errors.New("auth: nonce already used")
+	ErrBadSignature     = errors.New("auth: signature mismatch")
+	ErrBodyTooLarge     = errors.New("auth: request body too large")
+)
+
+// DefaultMaxBodySize 未设置 Verifier.MaxBodySize 时允许的请求体大小
+const DefaultMaxBodySize = 1 << 20
+
+// Signer 对出站请求签名
+type Signer struct {
+	KeyID  string
+	Secret []byte
+	// Now 可替换的时间来源，便于测试
+	Now func() time.Time
+}
+
+// NewSigner 创建签名器
+func NewSigner(keyID string, secret []byte) *Signer {
+	return &Signer{KeyID: keyID, Secret: secret, Now: time.Now}
+}
+
+// Sign 为请求添加时间戳、随机数和签名头，请求体会被读取后重新设置
+func (s *Signer) Sign(req *http.Request) error {
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("auth: generate nonce: %w", err)
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+
+	ts := strconv.FormatInt(now().Unix(), 10)
+	n := hex.EncodeToString(nonce)
+
+	req.Header.Set(HeaderKeyID, s.KeyID)
+	req.Header.Set(HeaderTimestamp, ts)
+	req.Header.Set(HeaderNonce, n)
+	req.Header.Set(HeaderSignature, computeSignature(s.Secret, req, ts, n, body))
+	return nil
+}
+
+// Transport 返回自动签名的 http.RoundTripper，base 为 nil 时使用 http.DefaultTransport
+func (s *Signer) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// RoundTripper 不应修改原始请求
+		req = req.Clone(req.Context())
+		if err := s.Sign(req); err != nil {
+			return nil, err
+		}
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NonceStore 记录已使用的随机数，用于防重放
+type NonceStore interface {
+	// Use 标记 nonce 已使用，若 ttl 内已存在则返回 false
+	Use(nonce string, ttl time.Duration) bool
+}
+
+// Verifier 校验入站请求签名
+type Verifier struct {
+	// Secrets 根据 key id 查找密钥
+	Secrets func(keyID string) ([]byte, bool)
+	// MaxSkew 允许的时间偏差，默认 5 分钟
+	MaxSkew time.Duration
+	// MaxBodySize 校验签名前读取的请求体上限，默认 DefaultMaxBodySize，超出时返回 ErrBodyTooLarge
+	MaxBodySize int64
+	Nonces      NonceStore
+	Now         func() time.Time
+}
+
+// NewVerifier 使用静态密钥表创建校验器，内存 nonce 存储与校验器使用同一时钟（Now）
+func NewVerifier(secrets map[string][]byte) *Verifier {
+	v := &Verifier{
+		Secrets: func(keyID string) ([]byte, bool) {
+			s, ok := secrets[keyID]
+			return s, ok
+		},
+		MaxSkew:     5 * time.Minute,
+		MaxBodySize: DefaultMaxBodySize,
+		Now:         time.Now,
+	}
+	nonces := NewMemoryNonceStore()
+	nonces.Now = v.now
+	v.Nonces = nonces
+	return v
+}
+
+func (v *Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+// Verify 校验请求签名、时间窗口与随机数
+func (v *Verifier) Verify(req *http.Request) error {
+	keyID := req.Header.Get(HeaderKeyID)
+	ts := req.Header.Get(HeaderTimestamp)
+	nonce := req.Header.Get(HeaderNonce)
+	sig := req.Header.Get(HeaderSignature)
+	if keyID == "" || ts == "" || nonce == "" || sig == "" {
+		return ErrMissingSignature
+	}
+
+	secret, ok := v.Secrets(keyID)
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrExpired
+	}
+
+	skew := v.MaxSkew
+	if skew <= 0 {
+		skew = 5 * time.Minute
+	}
+	if d := v.now().Sub(time.Unix(unix, 0)); d > skew || d < -skew {
+		return ErrExpired
+	}
+
+	limit := v.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = http.MaxBytesReader(nil, req.Body, limit)
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+
+	expected := computeSignature(secret, req, ts, nonce, body)
+	if !hmac.Equal([]byte(expected), []byte(sig)) {
+		return ErrBadSignature
+	}
+
+	// 签名通过后再记录 nonce，避免伪造请求占用 nonce
+	if v.Nonces != nil && !v.Nonces.Use(keyID+":"+nonce, 2*skew) {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// Middleware 校验失败时返回 401，请求体超过 MaxBodySize 时返回 413
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := v.Verify(r); err != nil {
+			status := http.StatusUnauthorized
+			if errors.Is(err, ErrBodyTooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// computeSignature 签名串：METHOD\nPATH\nQUERY\nTIMESTAMP\nNONCE\nSHA256(BODY)
+func computeSignature(secret []byte, req *http.Request, ts, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		ts,
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(canonical))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, fmt.Errorf("%w: limit %d bytes", ErrBodyTooLarge, tooLarge.Limit)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auth: read body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// MemoryNonceStore 基于内存的 NonceStore，适用于单实例部署
+type MemoryNonceStore struct {
+	// Now 可替换的时间来源，应与 Verifier 一致，NewVerifier 会自动设置
+	Now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]time.Time
+	lastGC  time.Time
+}
+
+// NewMemoryNonceStore 创建内存 nonce 存储
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{Now: time.Now, entries: make(map[string]time.Time)}
+}
+
+func (m *MemoryNonceStore) Use(nonce string, ttl time.Duration) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if m.Now != nil {
+		now = m.Now()
+	}
+	if now.Sub(m.lastGC) > ttl {
+		for k, exp := range m.entries {
+			if now.After(exp) {
+				delete(m.entries, k)
+			}
+		}
+		m.lastGC = now
+	}
+
+	if exp, ok := m.entries[nonce]; ok && now.Before(exp) {
+		return false
+	}
+	m.entries[nonce] = now.Add(ttl)
+	return true
+}
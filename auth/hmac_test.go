@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	secret := []byte("s3cr3t")
+	signer := NewSigner("svc-a", secret)
+	verifier := NewVerifier(map[string][]byte{"svc-a": secret})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?id=1", strings.NewReader(`{"a":1}`))
+	if err := signer.Sign(req); err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	if err := verifier.Verify(req); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+
+	// 同一请求再次提交视为重放
+	if err := verifier.Verify(req); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected ErrReplayed, got %v", err)
+	}
+}
+
+func TestVerifyRejectsTamperedBodyAndStaleTimestamp(t *testing.T) {
+	secret := []byte("s3cr3t")
+	verifier := NewVerifier(map[string][]byte{"svc-a": secret})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("original"))
+	_ = NewSigner("svc-a", secret).Sign(req)
+	tampered := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader("changed"))
+	tampered.Header = req.Header.Clone()
+	if err := verifier.Verify(tampered); !errors.Is(err, ErrBadSignature) {
+		t.Fatalf("expected ErrBadSignature, got %v", err)
+	}
+
+	stale := NewSigner("svc-a", secret)
+	stale.Now = func() time.Time { return time.Now().Add(-time.Hour) }
+	req = httptest.NewRequest(http.MethodGet, "/orders", nil)
+	_ = stale.Sign(req)
+	if err := verifier.Verify(req); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	verifier := NewVerifier(map[string][]byte{"svc-a": []byte("k")})
+	h := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestVerifyBodyLimitAndClock(t *testing.T) {
+	secret := []byte("s3cr3t")
+	verifier := NewVerifier(map[string][]byte{"svc-a": secret})
+	verifier.MaxBodySize = 16
+	h := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(strings.Repeat("x", 17)))
+	_ = NewSigner("svc-a", secret).Sign(req)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", rec.Code)
+	}
+
+	// nonce 过期按校验器的时钟计算
+	now := time.Now()
+	verifier.Now = func() time.Time { return now }
+	signer := NewSigner("svc-a", secret)
+	signer.Now = verifier.Now
+	req = httptest.NewRequest(http.MethodGet, "/orders", nil)
+	_ = signer.Sign(req)
+	if err := verifier.Verify(req); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	now = now.Add(4 * time.Minute)
+	if err := verifier.Verify(req); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("expected ErrReplayed within nonce ttl, got %v", err)
+	}
+	now = now.Add(7 * time.Minute)
+	if err := verifier.Verify(req); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected ErrExpired, got %v", err)
+	}
+	store := verifier.Nonces.(*MemoryNonceStore)
+	if !store.Use("svc-a:other", time.Minute) || store.Use("svc-a:other", time.Minute) {
+		t.Fatal("unexpected nonce reuse result")
+	}
+	now = now.Add(2 * time.Minute)
+	if !store.Use("svc-a:other", time.Minute) {
+		t.Fatal("expected nonce to expire on the verifier clock")
+	}
+}
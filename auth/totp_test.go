@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 附录 B 的 SHA1 测试向量
+func TestTOTPRFCVectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	otp := &TOTP{Secret: secret, Digits: 8}
+
+	cases := map[int64]string{
+		59:         "94287082",
+		1111111109: "07081804",
+		2000000000: "69279037",
+	}
+	for unix, want := range cases {
+		got, err := otp.Code(time.Unix(unix, 0))
+		if err != nil || got != want {
+			t.Fatalf("at %d: got %s (%v), want %s", unix, got, err, want)
+		}
+	}
+}
+
+func TestTOTPValidateDrift(t *testing.T) {
+	secret, err := GenerateTOTPSecret(20)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	otp := NewTOTP(secret)
+	now := time.Now()
+
+	prev, _ := otp.Code(now.Add(-30 * time.Second))
+	if !otp.Validate(prev, now) {
+		t.Fatalf("expected previous step to be accepted")
+	}
+
+	old, _ := otp.Code(now.Add(-2 * time.Minute))
+	if otp.Validate(old, now) {
+		t.Fatalf("expected code outside skew to be rejected")
+	}
+
+	// 零值使用默认偏移 1，负数不允许偏移
+	if !(&TOTP{Secret: secret}).Validate(prev, now) {
+		t.Fatalf("expected zero Skew to default to 1")
+	}
+	if (&TOTP{Secret: secret, Skew: -1}).Validate(prev, now) {
+		t.Fatalf("expected negative Skew to disable drift")
+	}
+
+	uri := otp.ProvisioningURI("go-kit", "alice@example.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/go-kit:alice@example.com?") || !strings.Contains(uri, "secret="+secret) {
+		t.Fatalf("unexpected uri %s", uri)
+	}
+}
+
+func TestTOTPInvalidParams(t *testing.T) {
+	secret, _ := GenerateTOTPSecret(20)
+	for name, otp := range map[string]*TOTP{
+		"sub-second period": {Secret: secret, Period: 500 * time.Millisecond},
+		"too many digits":   {Secret: secret, Digits: 10},
+	} {
+		if _, err := otp.Code(time.Now()); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if otp.Validate("000000", time.Now()) {
+			t.Fatalf("%s: expected validation to fail", name)
+		}
+	}
+
+	nine := &TOTP{Secret: secret, Digits: 9}
+	code, err := nine.Code(time.Now())
+	if err != nil || len(code) != 9 || !nine.Validate(code, time.Now()) {
+		t.Fatalf("unexpected 9-digit code %q (%v)", code, err)
+	}
+}
@@ -0,0 +1,164 @@
+package collections
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(2, 3, 4)
+
+	sorted := func(s Set[int]) []int {
+		items := s.Items()
+		sort.Ints(items)
+		return items
+	}
+	if got := sorted(a.Union(b)); !reflect.DeepEqual(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("union %v", got)
+	}
+	if got := sorted(a.Intersect(b)); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Fatalf("intersect %v", got)
+	}
+	if got := sorted(a.Difference(b)); !reflect.DeepEqual(got, []int{1}) {
+		t.Fatalf("difference %v", got)
+	}
+	a.Remove(1)
+	if a.Contains(1) || a.Len() != 2 {
+		t.Fatalf("remove failed: %v", a)
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	m := NewOrderedMap[string, int]()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 3)
+	m.Set("b", 10)
+	m.Delete("a")
+
+	if got := m.Keys(); !reflect.DeepEqual(got, []string{"b", "c"}) {
+		t.Fatalf("keys %v", got)
+	}
+	if v, ok := m.Get("b"); !ok || v != 10 {
+		t.Fatalf("get b = %d, %v", v, ok)
+	}
+	var visited []string
+	m.Range(func(k string, _ int) bool {
+		visited = append(visited, k)
+		return false
+	})
+	if len(visited) != 1 {
+		t.Fatalf("range did not stop: %v", visited)
+	}
+}
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		q.Push(v)
+	}
+	if v, _ := q.Peek(); v != 1 {
+		t.Fatalf("peek %d", v)
+	}
+	var got []int
+	for q.Len() > 0 {
+		v, _ := q.Pop()
+		got = append(got, v)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5}) {
+		t.Fatalf("pop order %v", got)
+	}
+	if _, ok := q.Pop(); ok {
+		t.Fatal("pop from empty queue")
+	}
+}
+
+func TestRing(t *testing.T) {
+	r := NewRing[int](3)
+	for i := 1; i <= 4; i++ {
+		evicted, ok := r.Push(i)
+		if i == 4 && (!ok || evicted != 1) {
+			t.Fatalf("expected 1 evicted, got %d %v", evicted, ok)
+		}
+	}
+	if got := r.Items(); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Fatalf("items %v", got)
+	}
+	if v, _ := r.Pop(); v != 2 || r.Len() != 2 {
+		t.Fatalf("pop %d len %d", v, r.Len())
+	}
+}
+
+func TestSliceHelpers(t *testing.T) {
+	chunks := Chunk([]int{1, 2, 3, 4, 5}, 2)
+	if !reflect.DeepEqual(chunks, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Fatalf("chunk %v", chunks)
+	}
+	// 分块容量被截断，append 不会覆盖下一块
+	chunks[0] = append(chunks[0], 99)
+	if chunks[1][0] != 3 {
+		t.Fatal("chunk append overwrote neighbour")
+	}
+
+	if got := Unique([]string{"b", "a", "b", "c", "a"}); !reflect.DeepEqual(got, []string{"b", "a", "c"}) {
+		t.Fatalf("unique %v", got)
+	}
+	groups := GroupBy([]int{1, 2, 3, 4, 5}, func(v int) bool { return v%2 == 0 })
+	if !reflect.DeepEqual(groups[true], []int{2, 4}) || !reflect.DeepEqual(groups[false], []int{1, 3, 5}) {
+		t.Fatalf("group %v", groups)
+	}
+	if got := Filter(Map([]int{1, 2, 3}, func(v int) int { return v * 10 }), func(v int) bool { return v > 10 }); !reflect.DeepEqual(got, []int{20, 30}) {
+		t.Fatalf("map/filter %v", got)
+	}
+}
+
+func BenchmarkUnique(b *testing.B) {
+	s := make([]int, 10000)
+	for i := range s {
+		s[i] = i % 1000
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Unique(s)
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	s := make([]int, 10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Chunk(s, 100)
+	}
+}
+
+func BenchmarkOrderedMapSet(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		m := NewOrderedMap[int, int]()
+		for j := 0; j < 1000; j++ {
+			m.Set(j, j)
+		}
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		q.Push(b.N - i)
+		if q.Len() > 1000 {
+			q.Pop()
+		}
+	}
+}
+
+func BenchmarkRingPush(b *testing.B) {
+	r := NewRing[int](1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Push(i)
+	}
+}
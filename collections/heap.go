@@ -0,0 +1,59 @@
+package collections
+
+import "container/heap"
+
+// PriorityQueue 优先队列，less(a, b) 为 true 时 a 先出队；非并发安全
+type PriorityQueue[T any] struct {
+	h *heapSlice[T]
+}
+
+// NewPriorityQueue 创建优先队列，传入 func(a, b int) bool { return a < b } 即为最小堆
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: &heapSlice[T]{less: less}}
+}
+
+// Push 入队
+func (q *PriorityQueue[T]) Push(v T) {
+	heap.Push(q.h, v)
+}
+
+// Pop 出队优先级最高的元素，队列为空时 ok 为 false
+func (q *PriorityQueue[T]) Pop() (v T, ok bool) {
+	if q.h.Len() == 0 {
+		return v, false
+	}
+	return heap.Pop(q.h).(T), true
+}
+
+// Peek 查看优先级最高的元素但不出队
+func (q *PriorityQueue[T]) Peek() (v T, ok bool) {
+	if q.h.Len() == 0 {
+		return v, false
+	}
+	return q.h.items[0], true
+}
+
+// Len 元素个数
+func (q *PriorityQueue[T]) Len() int {
+	return q.h.Len()
+}
+
+type heapSlice[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *heapSlice[T]) Len() int           { return len(h.items) }
+func (h *heapSlice[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *heapSlice[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *heapSlice[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+
+func (h *heapSlice[T]) Pop() any {
+	n := len(h.items) - 1
+	v := h.items[n]
+	var zero T
+	// 清空引用，便于 GC
+	h.items[n] = zero
+	h.items = h.items[:n]
+	return v
+}
@@ -0,0 +1,69 @@
+package collections
+
+import "container/list"
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// OrderedMap 按插入顺序遍历的 map，更新已有键不改变其位置；非并发安全
+type OrderedMap[K comparable, V any] struct {
+	items map[K]*list.Element
+	order *list.List
+}
+
+// NewOrderedMap 创建有序 map
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{items: make(map[K]*list.Element), order: list.New()}
+}
+
+// Set 设置键值
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if e, ok := m.items[key]; ok {
+		e.Value.(*entry[K, V]).value = value
+		return
+	}
+	m.items[key] = m.order.PushBack(&entry[K, V]{key: key, value: value})
+}
+
+// Get 获取值
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := m.items[key]; ok {
+		return e.Value.(*entry[K, V]).value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete 删除键
+func (m *OrderedMap[K, V]) Delete(key K) {
+	if e, ok := m.items[key]; ok {
+		m.order.Remove(e)
+		delete(m.items, key)
+	}
+}
+
+// Len 键数量
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.items)
+}
+
+// Keys 按插入顺序返回全部键
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.items))
+	for e := m.order.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Value.(*entry[K, V]).key)
+	}
+	return keys
+}
+
+// Range 按插入顺序遍历，fn 返回 false 时停止；遍历中不可修改 map
+func (m *OrderedMap[K, V]) Range(fn func(key K, value V) bool) {
+	for e := m.order.Front(); e != nil; e = e.Next() {
+		en := e.Value.(*entry[K, V])
+		if !fn(en.key, en.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package collections
+
+// Ring 固定容量的环形缓冲区，写满后覆盖最旧的元素，适合保存最近 N 条记录；非并发安全
+type Ring[T any] struct {
+	buf   []T
+	start int
+	size  int
+}
+
+// NewRing 创建容量为 capacity 的环形缓冲区
+func NewRing[T any](capacity int) *Ring[T] {
+	if capacity <= 0 {
+		panic("collections: ring capacity must be positive")
+	}
+	return &Ring[T]{buf: make([]T, capacity)}
+}
+
+// Push 写入元素，缓冲区已满时返回被覆盖的元素
+func (r *Ring[T]) Push(v T) (evicted T, ok bool) {
+	if r.size < len(r.buf) {
+		r.buf[(r.start+r.size)%len(r.buf)] = v
+		r.size++
+		return evicted, false
+	}
+	evicted = r.buf[r.start]
+	r.buf[r.start] = v
+	r.start = (r.start + 1) % len(r.buf)
+	return evicted, true
+}
+
+// Pop 取出最旧的元素
+func (r *Ring[T]) Pop() (v T, ok bool) {
+	if r.size == 0 {
+		return v, false
+	}
+	var zero T
+	v = r.buf[r.start]
+	r.buf[r.start] = zero
+	r.start = (r.start + 1) % len(r.buf)
+	r.size--
+	return v, true
+}
+
+// Len 当前元素个数
+func (r *Ring[T]) Len() int {
+	return r.size
+}
+
+// Cap 容量
+func (r *Ring[T]) Cap() int {
+	return len(r.buf)
+}
+
+// Items 按从旧到新的顺序返回元素副本
+func (r *Ring[T]) Items() []T {
+	items := make([]T, r.size)
+	for i := range items {
+		items[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return items
+}
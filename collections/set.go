@@ -0,0 +1,79 @@
+package collections
+
+// Set 基于 map 的集合，非并发安全
+type Set[T comparable] map[T]struct{}
+
+// NewSet 创建集合
+func NewSet[T comparable](items ...T) Set[T] {
+	s := make(Set[T], len(items))
+	s.Add(items...)
+	return s
+}
+
+// Add 添加元素
+func (s Set[T]) Add(items ...T) {
+	for _, v := range items {
+		s[v] = struct{}{}
+	}
+}
+
+// Remove 删除元素
+func (s Set[T]) Remove(items ...T) {
+	for _, v := range items {
+		delete(s, v)
+	}
+}
+
+// Contains 是否包含
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+// Len 元素个数
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Items 返回全部元素，顺序不固定
+func (s Set[T]) Items() []T {
+	return Keys(s)
+}
+
+// Union 并集
+func (s Set[T]) Union(o Set[T]) Set[T] {
+	r := make(Set[T], len(s)+len(o))
+	for v := range s {
+		r[v] = struct{}{}
+	}
+	for v := range o {
+		r[v] = struct{}{}
+	}
+	return r
+}
+
+// Intersect 交集
+func (s Set[T]) Intersect(o Set[T]) Set[T] {
+	small, large := s, o
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	r := make(Set[T])
+	for v := range small {
+		if large.Contains(v) {
+			r[v] = struct{}{}
+		}
+	}
+	return r
+}
+
+// Difference 差集，属于 s 但不属于 o
+func (s Set[T]) Difference(o Set[T]) Set[T] {
+	r := make(Set[T])
+	for v := range s {
+		if !o.Contains(v) {
+			r[v] = struct{}{}
+		}
+	}
+	return r
+}
@@ -0,0 +1,78 @@
+package collections
+
+// Chunk 将切片按 size 分块，最后一块可能不足 size；返回的分块共享原切片底层数组
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic("collections: chunk size must be positive")
+	}
+	chunks := make([][]T, 0, (len(s)+size-1)/size)
+	for size < len(s) {
+		s, chunks = s[size:], append(chunks, s[:size:size])
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// Unique 去重并保持首次出现的顺序
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	r := make([]T, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		r = append(r, v)
+	}
+	return r
+}
+
+// GroupBy 按 key 分组，组内保持原顺序
+func GroupBy[T any, K comparable](s []T, key func(T) K) map[K][]T {
+	r := make(map[K][]T)
+	for _, v := range s {
+		k := key(v)
+		r[k] = append(r[k], v)
+	}
+	return r
+}
+
+// Map 对每个元素应用 fn
+func Map[T, R any](s []T, fn func(T) R) []R {
+	r := make([]R, len(s))
+	for i, v := range s {
+		r[i] = fn(v)
+	}
+	return r
+}
+
+// Filter 保留 fn 返回 true 的元素
+func Filter[T any](s []T, fn func(T) bool) []T {
+	r := make([]T, 0, len(s))
+	for _, v := range s {
+		if fn(v) {
+			r = append(r, v)
+		}
+	}
+	return r
+}
+
+// Keys 返回 map 的全部键，顺序不固定
+func Keys[K comparable, V any](m map[K]V) []K {
+	r := make([]K, 0, len(m))
+	for k := range m {
+		r = append(r, k)
+	}
+	return r
+}
+
+// Values 返回 map 的全部值，顺序不固定
+func Values[K comparable, V any](m map[K]V) []V {
+	r := make([]V, 0, len(m))
+	for _, v := range m {
+		r = append(r, v)
+	}
+	return r
+}
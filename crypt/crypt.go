@@ -0,0 +1,178 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNoPrimaryKey   = errors.New("crypt: no primary key configured")
+	ErrUnknownVersion = errors.New("crypt: unknown key version")
+	ErrMalformed      = errors.New("crypt: malformed ciphertext")
+)
+
+// Keyring 按版本号管理 AES 密钥，新数据使用主密钥加密，旧版本密钥仅用于解密
+type Keyring struct {
+	mu      sync.RWMutex
+	keys    map[string]cipher.AEAD
+	primary string
+}
+
+// NewKeyring 创建空的密钥环
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]cipher.AEAD)}
+}
+
+// Add 添加一个版本的密钥，key 长度必须为 16/24/32 字节；第一个添加的密钥默认作为主密钥
+func (k *Keyring) Add(version string, key []byte) error {
+	if version == "" || strings.Contains(version, ":") {
+		return fmt.Errorf("crypt: invalid key version %q", version)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("crypt: key %s: %w", version, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return fmt.Errorf("crypt: key %s: %w", version, err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[version] = aead
+	if k.primary == "" {
+		k.primary = version
+	}
+	return nil
+}
+
+// SetPrimary 切换加密使用的主密钥版本，用于密钥轮换
+func (k *Keyring) SetPrimary(version string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if _, ok := k.keys[version]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownVersion, version)
+	}
+	k.primary = version
+	return nil
+}
+
+// Primary 返回当前主密钥版本
+func (k *Keyring) Primary() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.primary
+}
+
+// Encrypt 使用主密钥加密，输出格式为 "<version>:<base64(nonce|ciphertext)>"
+func (k *Keyring) Encrypt(plaintext []byte) (string, error) {
+	k.mu.RLock()
+	version := k.primary
+	aead := k.keys[version]
+	k.mu.RUnlock()
+
+	if aead == nil {
+		return "", ErrNoPrimaryKey
+	}
+
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("crypt: generate nonce: %w", err)
+	}
+
+	// 版本号作为附加数据参与认证，防止密文被篡改为其他版本
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(version))
+	return version + ":" + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt 根据密文中的版本前缀选择密钥解密
+func (k *Keyring) Decrypt(ciphertext string) ([]byte, error) {
+	version, payload, ok := strings.Cut(ciphertext, ":")
+	if !ok {
+		return nil, ErrMalformed
+	}
+
+	k.mu.RLock()
+	aead := k.keys[version]
+	k.mu.RUnlock()
+
+	if aead == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownVersion, version)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(data) < aead.NonceSize() {
+		return nil, ErrMalformed
+	}
+
+	nonce, sealed := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, []byte(version))
+	if err != nil {
+		return nil, fmt.Errorf("crypt: decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// NeedsRotation 判断密文是否由非主密钥加密，可用于后台重新加密旧数据
+func (k *Keyring) NeedsRotation(ciphertext string) bool {
+	version, _, _ := strings.Cut(ciphertext, ":")
+	return version != k.Primary()
+}
+
+var (
+	defaultMu      sync.RWMutex
+	defaultKeyring *Keyring
+)
+
+// SetDefault 设置包级 Encrypt/Decrypt 使用的密钥环
+func SetDefault(k *Keyring) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultKeyring = k
+}
+
+func getDefault() (*Keyring, error) {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	if defaultKeyring == nil {
+		return nil, ErrNoPrimaryKey
+	}
+	return defaultKeyring, nil
+}
+
+// Encrypt 使用默认密钥环加密
+func Encrypt(plaintext []byte) (string, error) {
+	k, err := getDefault()
+	if err != nil {
+		return "", err
+	}
+	return k.Encrypt(plaintext)
+}
+
+// Decrypt 使用默认密钥环解密
+func Decrypt(ciphertext string) ([]byte, error) {
+	k, err := getDefault()
+	if err != nil {
+		return nil, err
+	}
+	return k.Decrypt(ciphertext)
+}
+
+// EncryptString 加密字符串的便捷方法
+func EncryptString(s string) (string, error) {
+	return Encrypt([]byte(s))
+}
+
+// DecryptString 解密为字符串的便捷方法
+func DecryptString(ciphertext string) (string, error) {
+	b, err := Decrypt(ciphertext)
+	return string(b), err
+}
@@ -0,0 +1,88 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecryptWithRotation(t *testing.T) {
+	k := NewKeyring()
+	if err := k.Add("v1", bytes.Repeat([]byte{1}, 32)); err != nil {
+		t.Fatalf("add v1: %v", err)
+	}
+
+	old, err := k.Encrypt([]byte("secret token"))
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if !strings.HasPrefix(old, "v1:") {
+		t.Fatalf("expected v1 prefix, got %s", old)
+	}
+
+	if err := k.Add("v2", bytes.Repeat([]byte{2}, 16)); err != nil {
+		t.Fatalf("add v2: %v", err)
+	}
+	if err := k.SetPrimary("v2"); err != nil {
+		t.Fatalf("set primary: %v", err)
+	}
+
+	if !k.NeedsRotation(old) {
+		t.Fatalf("expected v1 ciphertext to need rotation")
+	}
+
+	plain, err := k.Decrypt(old)
+	if err != nil || string(plain) != "secret token" {
+		t.Fatalf("decrypt old: %q %v", plain, err)
+	}
+
+	fresh, _ := k.Encrypt([]byte("secret token"))
+	if !strings.HasPrefix(fresh, "v2:") {
+		t.Fatalf("expected v2 prefix, got %s", fresh)
+	}
+}
+
+func TestDecryptRejectsTampering(t *testing.T) {
+	k := NewKeyring()
+	_ = k.Add("v1", bytes.Repeat([]byte{1}, 32))
+	_ = k.Add("v2", bytes.Repeat([]byte{1}, 32))
+
+	ct, _ := k.Encrypt([]byte("data"))
+	// 相同密钥但不同版本号，附加数据校验应失败
+	swapped := "v2" + strings.TrimPrefix(ct, "v1")
+	if _, err := k.Decrypt(swapped); err == nil {
+		t.Fatalf("expected error when version prefix is swapped")
+	}
+
+	if _, err := k.Decrypt("v9:abc"); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+func TestLoadFromEnv(t *testing.T) {
+	t.Setenv("APP_KEY_V1", "AQEBAQEBAQEBAQEBAQEBAQ==")
+	t.Setenv("APP_KEY_V2", "02020202020202020202020202020202")
+
+	k, err := LoadFromEnv("APP")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if k.Primary() != "v2" {
+		t.Fatalf("expected primary v2, got %s", k.Primary())
+	}
+}
+
+func TestLoadFromEnvNumericVersions(t *testing.T) {
+	t.Setenv("NUM_KEY_V9", "AQEBAQEBAQEBAQEBAQEBAQ==")
+	t.Setenv("NUM_KEY_V10", "02020202020202020202020202020202")
+	t.Setenv("NUM_KEY_V2", "03030303030303030303030303030303")
+
+	k, err := LoadFromEnv("NUM")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if k.Primary() != "v10" {
+		t.Fatalf("expected primary v10, got %s", k.Primary())
+	}
+}
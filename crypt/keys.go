@@ -0,0 +1,245 @@
+package crypt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// PEM 块类型
+const (
+	PEMTypeRSAPrivate  = "RSA PRIVATE KEY"
+	PEMTypeECPrivate   = "EC PRIVATE KEY"
+	PEMTypePrivate     = "PRIVATE KEY"
+	PEMTypeRSAPublic   = "RSA PUBLIC KEY"
+	PEMTypePublic      = "PUBLIC KEY"
+	PEMTypeCertificate = "CERTIFICATE"
+)
+
+var ErrNoPEMBlock = errors.New("crypt: no PEM block found")
+
+// GenerateRSAKey 生成 RSA 私钥，bits 小于 2048 时使用 2048
+func GenerateRSAKey(bits int) (*rsa.PrivateKey, error) {
+	if bits < 2048 {
+		bits = 2048
+	}
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: generate rsa key: %w", err)
+	}
+	return key, nil
+}
+
+// GenerateECDSAKey 生成 ECDSA 私钥，curve 为 nil 时使用 P-256
+func GenerateECDSAKey(curve elliptic.Curve) (*ecdsa.PrivateKey, error) {
+	if curve == nil {
+		curve = elliptic.P256()
+	}
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: generate ecdsa key: %w", err)
+	}
+	return key, nil
+}
+
+// MarshalPrivateKeyPEM 以 PKCS8 格式编码私钥
+func MarshalPrivateKeyPEM(key crypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: marshal private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMTypePrivate, Bytes: der}), nil
+}
+
+// MarshalPKCS1PrivateKeyPEM 以 PKCS1 格式编码 RSA 私钥
+func MarshalPKCS1PrivateKeyPEM(key *rsa.PrivateKey) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: PEMTypeRSAPrivate, Bytes: x509.MarshalPKCS1PrivateKey(key)})
+}
+
+// MarshalSEC1PrivateKeyPEM 以 SEC1 格式编码 ECDSA 私钥
+func MarshalSEC1PrivateKeyPEM(key *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: marshal ec private key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMTypeECPrivate, Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM 以 PKIX 格式编码公钥
+func MarshalPublicKeyPEM(key crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: marshal public key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: PEMTypePublic, Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM 解析 PKCS1/PKCS8/SEC1 格式的私钥；
+// 跳过私钥之前的其它块，如 openssl ecparam 生成的 "EC PARAMETERS"
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	var skipped []string
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		switch block.Type {
+		case PEMTypeRSAPrivate, PEMTypeECPrivate, PEMTypePrivate, "ENCRYPTED PRIVATE KEY":
+			return parsePrivateBlock(block)
+		}
+		skipped = append(skipped, block.Type)
+		data = rest
+	}
+	if len(skipped) == 0 {
+		return nil, ErrNoPEMBlock
+	}
+	return nil, fmt.Errorf("crypt: no private key in PEM blocks %q", skipped)
+}
+
+func parsePrivateBlock(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case PEMTypeRSAPrivate:
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: parse PKCS1 private key: %w", err)
+		}
+		return key, nil
+	case PEMTypeECPrivate:
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: parse SEC1 private key: %w", err)
+		}
+		return key, nil
+	case PEMTypePrivate:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: parse PKCS8 private key: %w", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("crypt: unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("crypt: encrypted private keys are not supported, decrypt it first (openssl pkcs8)")
+	}
+}
+
+// ParsePublicKeyPEM 解析 PKIX/PKCS1 公钥，也接受证书并返回其公钥
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrNoPEMBlock
+	}
+
+	switch block.Type {
+	case PEMTypePublic:
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: parse PKIX public key: %w", err)
+		}
+		return key, nil
+	case PEMTypeRSAPublic:
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: parse PKCS1 public key: %w", err)
+		}
+		return key, nil
+	case PEMTypeCertificate:
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("crypt: parse certificate: %w", err)
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("crypt: unexpected PEM block %q, want a public key", block.Type)
+	}
+}
+
+// LoadPrivateKey 从文件加载私钥
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: read private key: %w", err)
+	}
+	key, err := ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadPublicKey 从文件加载公钥
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: read public key: %w", err)
+	}
+	key, err := ParsePublicKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadCertificates 从文件加载一个或多个 PEM 证书（证书链）
+func LoadCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("crypt: read certificate: %w", err)
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != PEMTypeCertificate {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%s: crypt: parse certificate: %w", path, err)
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("%s: %w", path, ErrNoPEMBlock)
+	}
+	return certs, nil
+}
+
+// LoadCertPool 加载 CA 证书构建证书池
+func LoadCertPool(paths ...string) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	for _, p := range paths {
+		certs, err := LoadCertificates(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range certs {
+			pool.AddCert(c)
+		}
+	}
+	return pool, nil
+}
+
+// LoadKeyPair 加载证书与私钥，并在不匹配时给出明确提示
+func LoadKeyPair(certFile, keyFile string) (tls.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("crypt: load key pair (cert=%s, key=%s): %w", certFile, keyFile, err)
+	}
+	return pair, nil
+}
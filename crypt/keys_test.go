@@ -0,0 +1,53 @@
+package crypt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+func TestKeyPEMRoundTrip(t *testing.T) {
+	rsaKey, err := GenerateRSAKey(2048)
+	if err != nil {
+		t.Fatalf("generate rsa: %v", err)
+	}
+	ecKey, err := GenerateECDSAKey(nil)
+	if err != nil {
+		t.Fatalf("generate ecdsa: %v", err)
+	}
+
+	pkcs1 := MarshalPKCS1PrivateKeyPEM(rsaKey)
+	if k, err := ParsePrivateKeyPEM(pkcs1); err != nil || !k.(*rsa.PrivateKey).Equal(rsaKey) {
+		t.Fatalf("pkcs1 round trip failed: %v", err)
+	}
+
+	sec1, _ := MarshalSEC1PrivateKeyPEM(ecKey)
+	if k, err := ParsePrivateKeyPEM(sec1); err != nil || !k.(*ecdsa.PrivateKey).Equal(ecKey) {
+		t.Fatalf("sec1 round trip failed: %v", err)
+	}
+
+	pkcs8, _ := MarshalPrivateKeyPEM(ecKey)
+	if k, err := ParsePrivateKeyPEM(pkcs8); err != nil || !k.(*ecdsa.PrivateKey).Equal(ecKey) {
+		t.Fatalf("pkcs8 round trip failed: %v", err)
+	}
+
+	// openssl ecparam -genkey 的输出在私钥前带有 EC PARAMETERS 块
+	params := pem.EncodeToMemory(&pem.Block{Type: "EC PARAMETERS", Bytes: []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}})
+	if k, err := ParsePrivateKeyPEM(append(params, sec1...)); err != nil || !k.(*ecdsa.PrivateKey).Equal(ecKey) {
+		t.Fatalf("sec1 with EC PARAMETERS failed: %v", err)
+	}
+
+	pub, _ := MarshalPublicKeyPEM(rsaKey.Public())
+	if k, err := ParsePublicKeyPEM(pub); err != nil || !k.(*rsa.PublicKey).Equal(rsaKey.Public()) {
+		t.Fatalf("public key round trip failed: %v", err)
+	}
+
+	if _, err := ParsePrivateKeyPEM(pub); err == nil {
+		t.Fatalf("expected error parsing public key as private key")
+	}
+	if _, err := ParsePrivateKeyPEM([]byte("garbage")); !errors.Is(err, ErrNoPEMBlock) {
+		t.Fatalf("expected ErrNoPEMBlock, got %v", err)
+	}
+}
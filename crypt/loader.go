@@ -0,0 +1,124 @@
+package crypt
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// KMS 密钥管理服务抽象，用于信封加密：数据密钥以密文形式保存，启动时由 KMS 解密
+type KMS interface {
+	Decrypt(ctx context.Context, wrappedKey []byte) ([]byte, error)
+}
+
+// DecodeKey 解析 base64（标准或 URL 编码）或 hex 格式的密钥
+func DecodeKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if b, err := hex.DecodeString(s); err == nil && validKeyLen(len(b)) {
+		return b, nil
+	}
+	for _, enc := range []*base64.Encoding{
+		base64.StdEncoding, base64.RawStdEncoding,
+		base64.URLEncoding, base64.RawURLEncoding,
+	} {
+		if b, err := enc.DecodeString(s); err == nil && validKeyLen(len(b)) {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("crypt: key must be base64 or hex encoded 16/24/32 bytes")
+}
+
+func validKeyLen(n int) bool {
+	return n == 16 || n == 24 || n == 32
+}
+
+// LoadFromEnv 从环境变量加载密钥：<prefix>_KEY_<VERSION>=<key>，
+// 主密钥版本由 <prefix>_PRIMARY 指定，未指定时使用最大的版本，末尾的数字按数值比较（v10 大于 v9）
+func LoadFromEnv(prefix string) (*Keyring, error) {
+	k := NewKeyring()
+	keyPrefix := prefix + "_KEY_"
+
+	var versions []string
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(name, keyPrefix) {
+			continue
+		}
+		version := strings.ToLower(strings.TrimPrefix(name, keyPrefix))
+		key, err := DecodeKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if err := k.Add(version, key); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("crypt: no keys found with prefix %s", keyPrefix)
+	}
+
+	primary := strings.ToLower(os.Getenv(prefix + "_PRIMARY"))
+	if primary == "" {
+		sort.Slice(versions, func(i, j int) bool { return versionLess(versions[i], versions[j]) })
+		primary = versions[len(versions)-1]
+	}
+	if err := k.SetPrimary(primary); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// versionLess 先比较去掉末尾数字后的前缀，再按数值比较末尾数字，没有数字的版本排在前面
+func versionLess(a, b string) bool {
+	ap, an := splitVersion(a)
+	bp, bn := splitVersion(b)
+	if ap != bp {
+		return ap < bp
+	}
+	// 去掉前导零后按长度与字典序比较，避免超长数字溢出
+	an, bn = strings.TrimLeft(an, "0"), strings.TrimLeft(bn, "0")
+	if len(an) != len(bn) {
+		return len(an) < len(bn)
+	}
+	if an != bn {
+		return an < bn
+	}
+	return a < b
+}
+
+// splitVersion 拆分为前缀与末尾的数字
+func splitVersion(v string) (prefix, num string) {
+	i := len(v)
+	for i > 0 && v[i-1] >= '0' && v[i-1] <= '9' {
+		i--
+	}
+	return v[:i], v[i:]
+}
+
+// LoadFile 从文件读取指定版本的密钥并加入密钥环
+func (k *Keyring) LoadFile(version, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("crypt: read key file: %w", err)
+	}
+	key, err := DecodeKey(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return k.Add(version, key)
+}
+
+// LoadKMS 通过 KMS 解密数据密钥并加入密钥环
+func (k *Keyring) LoadKMS(ctx context.Context, kms KMS, version string, wrappedKey []byte) error {
+	key, err := kms.Decrypt(ctx, wrappedKey)
+	if err != nil {
+		return fmt.Errorf("crypt: kms decrypt key %s: %w", version, err)
+	}
+	return k.Add(version, key)
+}
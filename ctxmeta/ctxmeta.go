@@ -0,0 +1,160 @@
+package ctxmeta
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+// 日志字段名
+const (
+	KeyUserID        = "user_id"
+	KeyTenant        = "tenant"
+	KeyLocale        = "locale"
+	KeyClientIP      = "client_ip"
+	KeyUserAgent     = "user_agent"
+	KeyClientVersion = "client_version"
+	KeyPlatform      = "platform"
+	KeyDeviceID      = "device_id"
+)
+
+// ClientInfo 客户端信息
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Version   string
+	Platform  string
+	DeviceID  string
+}
+
+// Meta 请求元数据
+type Meta struct {
+	UserID string
+	Tenant string
+	Locale string
+	Client ClientInfo
+}
+
+type metaKey struct{}
+
+// From 返回 ctx 中的元数据，不存在时返回零值
+func From(ctx context.Context) Meta {
+	if m, ok := ctx.Value(metaKey{}).(*Meta); ok {
+		return *m
+	}
+	return Meta{}
+}
+
+// With 将元数据整体写入 ctx
+func With(ctx context.Context, m Meta) context.Context {
+	return context.WithValue(ctx, metaKey{}, &m)
+}
+
+// update 复制后修改，避免影响父 context
+func update(ctx context.Context, fn func(*Meta)) context.Context {
+	m := From(ctx)
+	fn(&m)
+	return With(ctx, m)
+}
+
+// WithUserID 设置用户 ID
+func WithUserID(ctx context.Context, id string) context.Context {
+	return update(ctx, func(m *Meta) { m.UserID = id })
+}
+
+// UserID 返回用户 ID
+func UserID(ctx context.Context) string {
+	return From(ctx).UserID
+}
+
+// WithTenant 设置租户
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return update(ctx, func(m *Meta) { m.Tenant = tenant })
+}
+
+// Tenant 返回租户
+func Tenant(ctx context.Context) string {
+	return From(ctx).Tenant
+}
+
+// WithLocale 设置语言，如 zh-CN
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return update(ctx, func(m *Meta) { m.Locale = locale })
+}
+
+// Locale 返回语言
+func Locale(ctx context.Context) string {
+	return From(ctx).Locale
+}
+
+// WithClient 设置客户端信息
+func WithClient(ctx context.Context, c ClientInfo) context.Context {
+	return update(ctx, func(m *Meta) { m.Client = c })
+}
+
+// Client 返回客户端信息
+func Client(ctx context.Context) ClientInfo {
+	return From(ctx).Client
+}
+
+// LogKeys Logger、Fields 与 logger.FromContext 默认输出的字段，按需在启动时修改
+var LogKeys = []string{KeyUserID, KeyTenant, KeyClientIP}
+
+func init() {
+	logger.RegisterContextFields(func(ctx context.Context) []zap.Field {
+		return Fields(ctx)
+	})
+}
+
+// Fields 将指定字段转换为日志字段，keys 为空时使用 LogKeys，空值跳过
+func Fields(ctx context.Context, keys ...string) []zap.Field {
+	if len(keys) == 0 {
+		keys = LogKeys
+	}
+	m := From(ctx)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		if v := m.value(k); v != "" {
+			fields = append(fields, zap.String(k, v))
+		}
+	}
+	return fields
+}
+
+func (m Meta) value(key string) string {
+	switch key {
+	case KeyUserID:
+		return m.UserID
+	case KeyTenant:
+		return m.Tenant
+	case KeyLocale:
+		return m.Locale
+	case KeyClientIP:
+		return m.Client.IP
+	case KeyUserAgent:
+		return m.Client.UserAgent
+	case KeyClientVersion:
+		return m.Client.Version
+	case KeyPlatform:
+		return m.Client.Platform
+	case KeyDeviceID:
+		return m.Client.DeviceID
+	}
+	return ""
+}
+
+// Logger 返回附带 LogKeys 字段的日志，log 为 nil 时基于全局日志 logger.S()
+//
+//	ctxmeta.Logger(ctx, nil).Infow("order created", "order_id", id)
+func Logger(ctx context.Context, log *zap.SugaredLogger) *zap.SugaredLogger {
+	if log == nil {
+		log = logger.S()
+	}
+	fields := Fields(ctx)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.Desugar().With(fields...).Sugar()
+}
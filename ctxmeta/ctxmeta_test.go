@@ -0,0 +1,140 @@
+package ctxmeta
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/abs2free/go-kit/testkit"
+)
+
+func TestSettersDoNotLeakToParent(t *testing.T) {
+	parent := WithUserID(context.Background(), "u1")
+	child := WithTenant(parent, "acme")
+
+	if Tenant(parent) != "" {
+		t.Fatal("child value leaked into parent")
+	}
+	if UserID(child) != "u1" || Tenant(child) != "acme" {
+		t.Fatalf("unexpected child meta %+v", From(child))
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	var got Meta
+	h := HTTPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = From(r.Context())
+	}))
+
+	req := testkit.NewRequest(http.MethodGet, "/").
+		Header(HeaderUserID, "spoofed").
+		Header("Accept-Language", "zh-CN,zh;q=0.9,en;q=0.8").
+		Header(HeaderPlatform, "ios").
+		Header("User-Agent", "app/1.0").
+		Build()
+	testkit.Serve(t, h, req)
+
+	if got.UserID != "" {
+		t.Fatal("identity headers must be ignored by default")
+	}
+	if got.Locale != "zh-CN" || got.Client.Platform != "ios" || got.Client.UserAgent != "app/1.0" || got.Client.IP != "192.0.2.1" {
+		t.Fatalf("unexpected meta %+v", got)
+	}
+
+	h = HTTPMiddleware(WithIdentityHeaders())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = From(r.Context())
+	}))
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodGet, "/").Header(HeaderUserID, "u9").Header(HeaderLocale, "en").Build())
+	if got.UserID != "u9" || got.Locale != "en" {
+		t.Fatalf("unexpected meta %+v", got)
+	}
+
+	// 请求未携带身份头时保留上游已写入的用户
+	authed := HTTPMiddleware(WithIdentityHeaders())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = From(r.Context())
+	}))
+	h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authed.ServeHTTP(w, r.WithContext(WithUserID(r.Context(), "from-auth")))
+	})
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodGet, "/").Build())
+	if got.UserID != "from-auth" {
+		t.Fatalf("existing user overwritten: %+v", got)
+	}
+}
+
+func TestGRPCInterceptors(t *testing.T) {
+	ctx := With(context.Background(), Meta{UserID: "u1", Tenant: "acme", Locale: "en"})
+
+	var outgoing metadata.MD
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	}
+	if err := UnaryClientInterceptor()(ctx, "/svc/M", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+
+	in := metadata.NewIncomingContext(context.Background(), outgoing)
+	in = peer.NewContext(in, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.5"), Port: 5000}})
+	var got Meta
+	_, err := UnaryServerInterceptor(WithIdentityHeaders())(in, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		got = From(ctx)
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != "u1" || got.Tenant != "acme" || got.Locale != "en" || got.Client.IP != "10.0.0.5" {
+		t.Fatalf("unexpected meta %+v", got)
+	}
+
+	// 流式拦截器
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		outgoing, _ = metadata.FromOutgoingContext(ctx)
+		return nil, nil
+	}
+	if _, err := StreamClientInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/svc/S", streamer); err != nil {
+		t.Fatal(err)
+	}
+	got = Meta{}
+	ss := fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), outgoing)}
+	err = StreamServerInterceptor(WithIdentityHeaders())(nil, ss, &grpc.StreamServerInfo{}, func(srv any, ss grpc.ServerStream) error {
+		got = From(ss.Context())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != "u1" || got.Tenant != "acme" || got.Locale != "en" {
+		t.Fatalf("unexpected stream meta %+v", got)
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestLogger(t *testing.T) {
+	logs := testkit.Logger(t)
+	ctx := WithClient(WithUserID(context.Background(), "u1"), ClientInfo{IP: "1.2.3.4"})
+
+	Logger(ctx, nil).Infow("order created", "order_id", 7)
+	logs.AssertField("order created", KeyUserID, "u1")
+	logs.AssertField("order created", KeyClientIP, "1.2.3.4")
+
+	if fields := Fields(ctx, KeyTenant); len(fields) != 0 {
+		t.Fatalf("empty values should be skipped: %v", fields)
+	}
+	if fields := Fields(ctx, KeyUserID); len(fields) != 1 || fields[0] != zap.String(KeyUserID, "u1") {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+}
@@ -0,0 +1,185 @@
+package ctxmeta
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/abs2free/go-kit/netutil"
+)
+
+// 请求头，gRPC metadata 使用对应的小写形式
+const (
+	HeaderUserID        = "X-User-Id"
+	HeaderTenant        = "X-Tenant-Id"
+	HeaderLocale        = "X-Locale"
+	HeaderClientVersion = "X-Client-Version"
+	HeaderPlatform      = "X-Platform"
+	HeaderDeviceID      = "X-Device-Id"
+)
+
+type options struct {
+	identity bool
+	trusted  *netutil.Allowlist
+}
+
+// Option 中间件配置选项
+type Option func(*options)
+
+// WithIdentityHeaders 从 X-User-Id、X-Tenant-Id 读取身份。
+// 这两个头可被客户端伪造，只应在网关完成认证并覆盖这些头的内部服务上开启
+func WithIdentityHeaders() Option {
+	return func(o *options) {
+		o.identity = true
+	}
+}
+
+// WithTrustedProxies 可信代理，来自这些地址的请求使用 X-Forwarded-For 确定客户端 IP
+func WithTrustedProxies(a *netutil.Allowlist) Option {
+	return func(o *options) {
+		o.trusted = a
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// HTTPMiddleware 从请求中提取语言与客户端信息写入 context；
+// 语言优先取 X-Locale，其次为 Accept-Language 的首选项。
+// 用户、租户与语言只在请求中存在时设置，不覆盖上游中间件（如认证）已写入的值
+func HTTPMiddleware(opts ...Option) func(http.Handler) http.Handler {
+	o := newOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			m := From(r.Context())
+			if o.identity {
+				setIfPresent(&m.UserID, r.Header.Get(HeaderUserID))
+				setIfPresent(&m.Tenant, r.Header.Get(HeaderTenant))
+			}
+			locale := r.Header.Get(HeaderLocale)
+			if locale == "" {
+				locale = firstLanguage(r.Header.Get("Accept-Language"))
+			}
+			setIfPresent(&m.Locale, locale)
+			m.Client = ClientInfo{
+				IP:        netutil.ClientIP(r, o.trusted),
+				UserAgent: r.UserAgent(),
+				Version:   r.Header.Get(HeaderClientVersion),
+				Platform:  r.Header.Get(HeaderPlatform),
+				DeviceID:  r.Header.Get(HeaderDeviceID),
+			}
+			next.ServeHTTP(w, r.WithContext(With(r.Context(), m)))
+		})
+	}
+}
+
+// firstLanguage 返回 Accept-Language 中的第一项，如 "zh-CN,zh;q=0.9" -> "zh-CN"
+func firstLanguage(accept string) string {
+	first, _, _ := strings.Cut(accept, ",")
+	tag, _, _ := strings.Cut(first, ";")
+	return strings.TrimSpace(tag)
+}
+
+func setIfPresent(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+func mdGet(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// UnaryServerInterceptor 从 gRPC metadata 提取元数据，客户端 IP 取自连接对端地址
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(o.incoming(ctx), req)
+	}
+}
+
+// StreamServerInterceptor 流式版本的 UnaryServerInterceptor
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: o.incoming(ss.Context())})
+	}
+}
+
+// serverStream 替换 ServerStream 的 Context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// incoming 从入站 metadata 提取元数据写入 ctx，与 HTTPMiddleware 一样只设置存在的身份与语言
+func (o *options) incoming(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	m := From(ctx)
+	if o.identity {
+		setIfPresent(&m.UserID, mdGet(md, HeaderUserID))
+		setIfPresent(&m.Tenant, mdGet(md, HeaderTenant))
+	}
+	setIfPresent(&m.Locale, mdGet(md, HeaderLocale))
+	m.Client = ClientInfo{
+		UserAgent: mdGet(md, "user-agent"),
+		Version:   mdGet(md, HeaderClientVersion),
+		Platform:  mdGet(md, HeaderPlatform),
+		DeviceID:  mdGet(md, HeaderDeviceID),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			m.Client.IP = host
+		}
+	}
+	return With(ctx, m)
+}
+
+// UnaryClientInterceptor 将用户、租户与语言透传给下游服务
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 流式版本的 UnaryClientInterceptor
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoing(ctx context.Context) context.Context {
+	m := From(ctx)
+	var kv []string
+	for header, v := range map[string]string{
+		HeaderUserID: m.UserID,
+		HeaderTenant: m.Tenant,
+		HeaderLocale: m.Locale,
+	} {
+		if v != "" {
+			kv = append(kv, strings.ToLower(header), v)
+		}
+	}
+	if len(kv) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, kv...)
+	}
+	return ctx
+}
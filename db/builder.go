@@ -0,0 +1,600 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidIdentifier = errors.New("db: invalid identifier")
+	ErrMissingParam      = errors.New("db: missing named parameter")
+	ErrEmptyIn           = errors.New("db: empty slice for IN expansion")
+	ErrNoWhere           = errors.New("db: update/delete without where clause")
+)
+
+// Dialect 决定占位符形式
+type Dialect int
+
+const (
+	// MySQL 使用 ? 占位符，同样适用于 SQLite
+	MySQL Dialect = iota
+	// Postgres 使用 $1、$2 占位符
+	Postgres
+)
+
+// Params 命名参数，SQL 片段中以 :name 引用；紧跟在 IN ( 之后的切片值展开为 IN 列表，其他位置整体绑定为一个参数
+type Params map[string]any
+
+// Raw 不做标识符校验的 SQL 表达式，只能用于常量表达式（如 COUNT(*)），不可拼接用户输入
+type Raw string
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_*][A-Za-z0-9_]*)?$`)
+
+// ident 校验表名、列名；表名与列名无法参数化，只能通过白名单格式防止注入
+func ident(name string) (string, error) {
+	if name == "*" || identRe.MatchString(name) {
+		return name, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidIdentifier, name)
+}
+
+func column(c any) (string, error) {
+	switch v := c.(type) {
+	case Raw:
+		return string(v), nil
+	case string:
+		return ident(v)
+	}
+	return "", fmt.Errorf("%w: %v", ErrInvalidIdentifier, c)
+}
+
+// fragment 带命名参数的 SQL 片段
+type fragment struct {
+	expr   string
+	params Params
+}
+
+func newFragment(expr string, params []Params) fragment {
+	merged := Params{}
+	for _, p := range params {
+		for k, v := range p {
+			merged[k] = v
+		}
+	}
+	return fragment{expr: expr, params: merged}
+}
+
+// compiler 将命名参数替换为占位符
+type compiler struct {
+	dialect Dialect
+	sb      strings.Builder
+	args    []any
+}
+
+func (c *compiler) placeholder() string {
+	if c.dialect == Postgres {
+		return "$" + strconv.Itoa(len(c.args))
+	}
+	return "?"
+}
+
+func (c *compiler) bind(v any) {
+	c.args = append(c.args, v)
+	c.sb.WriteString(c.placeholder())
+}
+
+// write 编译片段：跳过引号内文本与 PostgreSQL 的 :: 类型转换，:name 替换为占位符
+func (c *compiler) write(f fragment) error {
+	s := f.expr
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\'' || ch == '"' || ch == '`':
+			end := strings.IndexByte(s[i+1:], ch)
+			if end < 0 {
+				c.sb.WriteString(s[i:])
+				return nil
+			}
+			c.sb.WriteString(s[i : i+end+2])
+			i += end + 1
+		case ch == ':' && i+1 < len(s) && s[i+1] == ':':
+			c.sb.WriteString("::")
+			i++
+		case ch == ':' && i+1 < len(s) && isNameStart(s[i+1]):
+			j := i + 1
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			name := s[i+1 : j]
+			v, ok := f.params[name]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrMissingParam, name)
+			}
+			if err := c.bindValue(name, v); err != nil {
+				return err
+			}
+			i = j - 1
+		default:
+			c.sb.WriteByte(ch)
+		}
+	}
+	return nil
+}
+
+var inRe = regexp.MustCompile(`(?i)\bIN\s*\(\s*$`)
+
+// bindValue IN ( 之后的切片（[]byte 除外）展开为 ?, ?, ?，其他值绑定为单个参数
+func (c *compiler) bindValue(name string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 || !inRe.MatchString(c.sb.String()) {
+		c.bind(v)
+		return nil
+	}
+	if rv.Len() == 0 {
+		return fmt.Errorf("%w: %s", ErrEmptyIn, name)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			c.sb.WriteString(", ")
+		}
+		c.bind(rv.Index(i).Interface())
+	}
+	return nil
+}
+
+func isNameStart(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isNameChar(b byte) bool {
+	return isNameStart(b) || (b >= '0' && b <= '9')
+}
+
+// conditions WHERE / HAVING 子句，多个条件各自加括号后以 AND 连接
+type conditions []fragment
+
+func (c *compiler) where(conds conditions) error {
+	return c.conditions(" WHERE ", conds)
+}
+
+func (c *compiler) conditions(keyword string, conds conditions) error {
+	if len(conds) == 0 {
+		return nil
+	}
+	c.sb.WriteString(keyword)
+	for i, f := range conds {
+		if i > 0 {
+			c.sb.WriteString(" AND ")
+		}
+		if len(conds) > 1 {
+			c.sb.WriteByte('(')
+		}
+		if err := c.write(f); err != nil {
+			return err
+		}
+		if len(conds) > 1 {
+			c.sb.WriteByte(')')
+		}
+	}
+	return nil
+}
+
+var orderRe = regexp.MustCompile(`(?i)^\s*([A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)?)(?:\s+(ASC|DESC))?\s*$`)
+
+// SelectBuilder SELECT 语句
+//
+//	q, args, err := db.Select("id", "name").From("users").
+//		Where("status = :status", db.Params{"status": 1}).
+//		WhereIf(kw != "", "name LIKE :kw", db.Params{"kw": "%" + kw + "%"}).
+//		Where("id IN (:ids)", db.Params{"ids": ids}).
+//		OrderBy("id DESC").Limit(20).Build()
+type SelectBuilder struct {
+	dialect Dialect
+	columns []any
+	table   string
+	joins   []fragment
+	conds   conditions
+	groupBy []string
+	having  conditions
+	orderBy []string
+	limit   int
+	offset  int
+	err     error
+}
+
+// Select 创建 SELECT，列名需为合法标识符，表达式使用 Raw
+func Select(columns ...any) *SelectBuilder {
+	return &SelectBuilder{columns: columns}
+}
+
+// Dialect 设置占位符方言，默认 MySQL
+func (b *SelectBuilder) Dialect(d Dialect) *SelectBuilder {
+	b.dialect = d
+	return b
+}
+
+// From 设置表名
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.table = table
+	return b
+}
+
+// Join 追加 JOIN 子句，如 Join("LEFT JOIN orders o ON o.user_id = u.id")；子句中的值须使用命名参数
+func (b *SelectBuilder) Join(clause string, params ...Params) *SelectBuilder {
+	b.joins = append(b.joins, newFragment(clause, params))
+	return b
+}
+
+// Where 追加条件，多个条件以 AND 连接
+func (b *SelectBuilder) Where(expr string, params ...Params) *SelectBuilder {
+	b.conds = append(b.conds, newFragment(expr, params))
+	return b
+}
+
+// WhereIf ok 为 true 时追加条件，用于可选的筛选项
+func (b *SelectBuilder) WhereIf(ok bool, expr string, params ...Params) *SelectBuilder {
+	if ok {
+		b.Where(expr, params...)
+	}
+	return b
+}
+
+// GroupBy 设置分组列
+func (b *SelectBuilder) GroupBy(columns ...string) *SelectBuilder {
+	b.groupBy = append(b.groupBy, columns...)
+	return b
+}
+
+// Having 追加 HAVING 条件
+func (b *SelectBuilder) Having(expr string, params ...Params) *SelectBuilder {
+	b.having = append(b.having, newFragment(expr, params))
+	return b
+}
+
+// OrderBy 追加排序，格式为 "column [ASC|DESC]"，不符合格式时 Build 返回 ErrInvalidIdentifier
+func (b *SelectBuilder) OrderBy(orders ...string) *SelectBuilder {
+	for _, o := range orders {
+		m := orderRe.FindStringSubmatch(o)
+		if m == nil {
+			b.err = fmt.Errorf("%w: order by %q", ErrInvalidIdentifier, o)
+			continue
+		}
+		clause := m[1]
+		if m[2] != "" {
+			clause += " " + strings.ToUpper(m[2])
+		}
+		b.orderBy = append(b.orderBy, clause)
+	}
+	return b
+}
+
+// Limit 设置返回行数，<= 0 表示不限制
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// Offset 设置偏移
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.offset = n
+	return b
+}
+
+// Build 生成 SQL 与参数
+func (b *SelectBuilder) Build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	c := &compiler{dialect: b.dialect}
+	c.sb.WriteString("SELECT ")
+	if len(b.columns) == 0 {
+		c.sb.WriteString("*")
+	}
+	for i, col := range b.columns {
+		name, err := column(col)
+		if err != nil {
+			return "", nil, err
+		}
+		if i > 0 {
+			c.sb.WriteString(", ")
+		}
+		c.sb.WriteString(name)
+	}
+
+	table, err := tableName(b.table)
+	if err != nil {
+		return "", nil, err
+	}
+	c.sb.WriteString(" FROM " + table)
+	for _, j := range b.joins {
+		c.sb.WriteByte(' ')
+		if err := c.write(j); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := c.where(b.conds); err != nil {
+		return "", nil, err
+	}
+	if len(b.groupBy) > 0 {
+		for _, g := range b.groupBy {
+			if _, err := ident(g); err != nil {
+				return "", nil, err
+			}
+		}
+		c.sb.WriteString(" GROUP BY " + strings.Join(b.groupBy, ", "))
+	}
+	if err := c.conditions(" HAVING ", b.having); err != nil {
+		return "", nil, err
+	}
+	if len(b.orderBy) > 0 {
+		c.sb.WriteString(" ORDER BY " + strings.Join(b.orderBy, ", "))
+	}
+	if b.limit > 0 {
+		c.sb.WriteString(" LIMIT ")
+		c.bind(b.limit)
+	}
+	if b.offset > 0 {
+		c.sb.WriteString(" OFFSET ")
+		c.bind(b.offset)
+	}
+	return c.sb.String(), c.args, nil
+}
+
+// tableName 允许 "users" 或带别名的 "users u"
+func tableName(t string) (string, error) {
+	parts := strings.Fields(t)
+	if len(parts) == 0 || len(parts) > 2 {
+		return "", fmt.Errorf("%w: table %q", ErrInvalidIdentifier, t)
+	}
+	for _, p := range parts {
+		if _, err := ident(p); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// InsertBuilder INSERT 语句，支持多行
+type InsertBuilder struct {
+	dialect Dialect
+	table   string
+	columns []string
+	rows    [][]any
+	err     error
+}
+
+// Insert 创建 INSERT
+func Insert(table string) *InsertBuilder {
+	return &InsertBuilder{table: table}
+}
+
+// Dialect 设置占位符方言
+func (b *InsertBuilder) Dialect(d Dialect) *InsertBuilder {
+	b.dialect = d
+	return b
+}
+
+// Columns 设置列
+func (b *InsertBuilder) Columns(columns ...string) *InsertBuilder {
+	b.columns = columns
+	return b
+}
+
+// Values 追加一行，值的个数需与列数一致
+func (b *InsertBuilder) Values(values ...any) *InsertBuilder {
+	if len(values) != len(b.columns) {
+		b.err = fmt.Errorf("db: insert expects %d values, got %d", len(b.columns), len(values))
+	}
+	b.rows = append(b.rows, values)
+	return b
+}
+
+// SetMap 以 map 设置单行数据，列按名称排序保证 SQL 稳定
+func (b *InsertBuilder) SetMap(values map[string]any) *InsertBuilder {
+	cols := make([]string, 0, len(values))
+	for k := range values {
+		cols = append(cols, k)
+	}
+	sort.Strings(cols)
+	row := make([]any, len(cols))
+	for i, k := range cols {
+		row[i] = values[k]
+	}
+	b.columns = cols
+	b.rows = [][]any{row}
+	return b
+}
+
+// Build 生成 SQL 与参数
+func (b *InsertBuilder) Build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if len(b.rows) == 0 {
+		return "", nil, errors.New("db: insert without values")
+	}
+	table, err := ident(b.table)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, col := range b.columns {
+		if _, err := ident(col); err != nil {
+			return "", nil, err
+		}
+	}
+
+	c := &compiler{dialect: b.dialect}
+	c.sb.WriteString("INSERT INTO " + table + " (" + strings.Join(b.columns, ", ") + ") VALUES ")
+	for i, row := range b.rows {
+		if i > 0 {
+			c.sb.WriteString(", ")
+		}
+		c.sb.WriteByte('(')
+		for j, v := range row {
+			if j > 0 {
+				c.sb.WriteString(", ")
+			}
+			c.bind(v)
+		}
+		c.sb.WriteByte(')')
+	}
+	return c.sb.String(), c.args, nil
+}
+
+// UpdateBuilder UPDATE 语句
+type UpdateBuilder struct {
+	dialect Dialect
+	table   string
+	sets    []fragment
+	conds   conditions
+	all     bool
+	err     error
+}
+
+// Update 创建 UPDATE
+func Update(table string) *UpdateBuilder {
+	return &UpdateBuilder{table: table}
+}
+
+// Dialect 设置占位符方言
+func (b *UpdateBuilder) Dialect(d Dialect) *UpdateBuilder {
+	b.dialect = d
+	return b
+}
+
+// Set 设置列值
+func (b *UpdateBuilder) Set(col string, value any) *UpdateBuilder {
+	if _, err := ident(col); err != nil {
+		b.err = err
+		return b
+	}
+	b.sets = append(b.sets, fragment{expr: col + " = :v", params: Params{"v": value}})
+	return b
+}
+
+// SetIf ok 为 true 时设置列值，用于部分更新
+func (b *UpdateBuilder) SetIf(ok bool, col string, value any) *UpdateBuilder {
+	if ok {
+		b.Set(col, value)
+	}
+	return b
+}
+
+// SetExpr 以表达式设置列，如 SetExpr("stock = stock - :n", db.Params{"n": 1})
+func (b *UpdateBuilder) SetExpr(expr string, params ...Params) *UpdateBuilder {
+	b.sets = append(b.sets, newFragment(expr, params))
+	return b
+}
+
+// Where 追加条件
+func (b *UpdateBuilder) Where(expr string, params ...Params) *UpdateBuilder {
+	b.conds = append(b.conds, newFragment(expr, params))
+	return b
+}
+
+// WhereIf ok 为 true 时追加条件
+func (b *UpdateBuilder) WhereIf(ok bool, expr string, params ...Params) *UpdateBuilder {
+	if ok {
+		b.Where(expr, params...)
+	}
+	return b
+}
+
+// All 显式允许无条件更新整表，否则缺少 Where 时 Build 返回 ErrNoWhere
+func (b *UpdateBuilder) All() *UpdateBuilder {
+	b.all = true
+	return b
+}
+
+// Build 生成 SQL 与参数
+func (b *UpdateBuilder) Build() (string, []any, error) {
+	if b.err != nil {
+		return "", nil, b.err
+	}
+	if len(b.sets) == 0 {
+		return "", nil, errors.New("db: update without set")
+	}
+	if len(b.conds) == 0 && !b.all {
+		return "", nil, ErrNoWhere
+	}
+	table, err := ident(b.table)
+	if err != nil {
+		return "", nil, err
+	}
+
+	c := &compiler{dialect: b.dialect}
+	c.sb.WriteString("UPDATE " + table + " SET ")
+	for i, s := range b.sets {
+		if i > 0 {
+			c.sb.WriteString(", ")
+		}
+		if err := c.write(s); err != nil {
+			return "", nil, err
+		}
+	}
+	if err := c.where(b.conds); err != nil {
+		return "", nil, err
+	}
+	return c.sb.String(), c.args, nil
+}
+
+// DeleteBuilder DELETE 语句
+type DeleteBuilder struct {
+	dialect Dialect
+	table   string
+	conds   conditions
+	all     bool
+}
+
+// Delete 创建 DELETE
+func Delete(table string) *DeleteBuilder {
+	return &DeleteBuilder{table: table}
+}
+
+// Dialect 设置占位符方言
+func (b *DeleteBuilder) Dialect(d Dialect) *DeleteBuilder {
+	b.dialect = d
+	return b
+}
+
+// Where 追加条件
+func (b *DeleteBuilder) Where(expr string, params ...Params) *DeleteBuilder {
+	b.conds = append(b.conds, newFragment(expr, params))
+	return b
+}
+
+// WhereIf ok 为 true 时追加条件
+func (b *DeleteBuilder) WhereIf(ok bool, expr string, params ...Params) *DeleteBuilder {
+	if ok {
+		b.Where(expr, params...)
+	}
+	return b
+}
+
+// All 显式允许删除整表
+func (b *DeleteBuilder) All() *DeleteBuilder {
+	b.all = true
+	return b
+}
+
+// Build 生成 SQL 与参数
+func (b *DeleteBuilder) Build() (string, []any, error) {
+	if len(b.conds) == 0 && !b.all {
+		return "", nil, ErrNoWhere
+	}
+	table, err := ident(b.table)
+	if err != nil {
+		return "", nil, err
+	}
+	c := &compiler{dialect: b.dialect}
+	c.sb.WriteString("DELETE FROM " + table)
+	if err := c.where(b.conds); err != nil {
+		return "", nil, err
+	}
+	return c.sb.String(), c.args, nil
+}
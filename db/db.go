@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type options struct {
+	reg   prometheus.Registerer
+	namer func(query string) string
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithMetrics 注册指标：
+//   - db_query_duration_seconds{driver, statement}
+//   - db_query_errors_total{driver, statement}
+//   - db_rows_affected_total{driver, statement}
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(o *options) {
+		o.reg = reg
+	}
+}
+
+// WithStatementNamer 自定义从 SQL 推导 statement 标签的方法，需保证返回值数量有限
+func WithStatementNamer(fn func(query string) string) Option {
+	return func(o *options) {
+		o.namer = fn
+	}
+}
+
+type statementKey struct{}
+
+// WithStatement 为 ctx 中执行的查询指定 statement 标签，优先级最高
+//
+//	row := db.QueryRowContext(db.WithStatement(ctx, "get_user"), "SELECT ...", id)
+func WithStatement(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, statementKey{}, name)
+}
+
+var nameComment = regexp.MustCompile(`^\s*/\*\s*name:\s*([\w.-]+)\s*\*/`)
+
+// StatementName 默认的标签推导：SQL 开头的 /* name: get_user */ 注释，否则为首个关键字（select、insert 等）
+func StatementName(query string) string {
+	if m := nameComment.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	switch kw := strings.ToLower(fields[0]); kw {
+	case "select", "insert", "update", "delete", "replace", "with", "begin", "commit", "rollback":
+		return kw
+	}
+	return "other"
+}
+
+type metrics struct {
+	driver   string
+	namer    func(string) string
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	rows     *prometheus.CounterVec
+}
+
+func newMetrics(driverName string, opts []Option) *metrics {
+	o := &options{namer: StatementName}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	m := &metrics{
+		driver: driverName,
+		namer:  o.namer,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "Latency of database queries.",
+			Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+		}, []string{"driver", "statement"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_query_errors_total",
+			Help: "Number of failed database queries.",
+		}, []string{"driver", "statement"}),
+		rows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "db_rows_affected_total",
+			Help: "Number of rows affected by exec statements.",
+		}, []string{"driver", "statement"}),
+	}
+	if o.reg != nil {
+		// 同一注册表被多个驱动共用时复用已注册的指标
+		m.duration = registerOrExisting(o.reg, m.duration)
+		m.errors = registerOrExisting(o.reg, m.errors)
+		m.rows = registerOrExisting(o.reg, m.rows)
+	}
+	return m
+}
+
+func registerOrExisting[T prometheus.Collector](reg prometheus.Registerer, c T) T {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(T)
+		}
+		panic(err)
+	}
+	return c
+}
+
+// observe 记录一次查询；driver.ErrSkip 表示驱动不支持该快捷路径，database/sql 会改走预编译，不计入指标
+func (m *metrics) observe(ctx context.Context, query string, start time.Time, err error) {
+	if err == driver.ErrSkip {
+		return
+	}
+	name, _ := ctx.Value(statementKey{}).(string)
+	if name == "" {
+		name = m.namer(query)
+	}
+	m.duration.WithLabelValues(m.driver, name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.errors.WithLabelValues(m.driver, name).Inc()
+	}
+}
+
+func (m *metrics) observeResult(ctx context.Context, query string, res driver.Result) {
+	if res == nil {
+		return
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n <= 0 {
+		return
+	}
+	name, _ := ctx.Value(statementKey{}).(string)
+	if name == "" {
+		name = m.namer(query)
+	}
+	m.rows.WithLabelValues(m.driver, name).Add(float64(n))
+}
+
+var (
+	mu         sync.Mutex
+	registered = make(map[string]string)
+)
+
+// Instrument 以 "<driverName>-instrumented" 注册包装后的驱动并返回新名称，
+// 任何基于 database/sql 的库（gorm、sqlx 等）使用该名称打开连接即可获得指标：
+//
+//	name, _ := db.Instrument("mysql", db.WithMetrics(monitor.Registry))
+//	conn, _ := sql.Open(name, dsn)
+//
+// 同一驱动重复调用返回已注册的名称，后续调用的选项被忽略
+func Instrument(driverName string, opts ...Option) (string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if name, ok := registered[driverName]; ok {
+		return name, nil
+	}
+
+	if err := lookupDriver(driverName); err != nil {
+		return "", err
+	}
+	name := driverName + "-instrumented"
+	sql.Register(name, &wrappedDriver{name: driverName, m: newMetrics(driverName, opts)})
+	registered[driverName] = name
+	return name, nil
+}
+
+// Open 不注册全局驱动，直接返回带指标的 *sql.DB
+func Open(driverName, dsn string, opts ...Option) (*sql.DB, error) {
+	if err := lookupDriver(driverName); err != nil {
+		return nil, err
+	}
+	c, err := (&wrappedDriver{name: driverName, m: newMetrics(driverName, opts)}).OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(c), nil
+}
+
+func lookupDriver(name string) error {
+	if !slices.Contains(sql.Drivers(), name) {
+		return fmt.Errorf("db: unknown driver %q (forgotten import?)", name)
+	}
+	return nil
+}
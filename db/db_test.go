@@ -0,0 +1,252 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeDriver 最小实现：Exec 返回影响 3 行，查询 "fail" 时报错
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query: query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if query == "fail" {
+		return nil, errors.New("boom")
+	}
+	return driver.RowsAffected(3), nil
+}
+
+type fakeStmt struct{ query string }
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+
+type fakeRows struct{ done bool }
+
+func (*fakeRows) Columns() []string { return []string{"n"} }
+func (*fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(42)
+	return nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+func TestStatementName(t *testing.T) {
+	cases := map[string]string{
+		"/* name: get_user */ SELECT * FROM users": "get_user",
+		"  select 1":               "select",
+		"INSERT INTO t VALUES (1)": "insert",
+		"SHOW TABLES":              "other",
+		"":                         "unknown",
+	}
+	for q, want := range cases {
+		if got := StatementName(q); got != want {
+			t.Errorf("StatementName(%q) = %q, want %q", q, got, want)
+		}
+	}
+}
+
+func TestInstrument(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	name, err := Instrument("fake", WithMetrics(reg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := Instrument("fake"); again != name {
+		t.Fatalf("expected same name, got %s and %s", name, again)
+	}
+	if _, err := Instrument("missing"); err == nil {
+		t.Fatal("expected error for unknown driver")
+	}
+
+	conn, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	if _, err := conn.ExecContext(WithStatement(ctx, "bulk_update"), "UPDATE t SET x = 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.ExecContext(ctx, "fail"); err == nil {
+		t.Fatal("expected exec error")
+	}
+	// 驱动未实现 QueryerContext，走预编译语句路径
+	var n int
+	if err := conn.QueryRowContext(ctx, "/* name: count */ SELECT 42").Scan(&n); err != nil || n != 42 {
+		t.Fatalf("query: %d, %v", n, err)
+	}
+
+	if got := metricValue(t, reg, "db_rows_affected_total", "bulk_update"); got != 3 {
+		t.Fatalf("rows affected = %v", got)
+	}
+	if got := metricValue(t, reg, "db_query_errors_total", "other"); got != 1 {
+		t.Fatalf("errors = %v", got)
+	}
+	if got := metricValue(t, reg, "db_query_duration_seconds", "count"); got != 1 {
+		t.Fatalf("count observations = %v", got)
+	}
+	if got := testutil.CollectAndCount(reg, "db_query_duration_seconds"); got != 3 {
+		t.Fatalf("expected 3 duration series, got %d", got)
+	}
+}
+
+// metricValue 返回 statement 标签为 stmt 的计数器值或直方图样本数
+func metricValue(t *testing.T, reg *prometheus.Registry, name, stmt string) float64 {
+	t.Helper()
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() != "statement" || l.GetValue() != stmt {
+					continue
+				}
+				if h := m.GetHistogram(); h != nil {
+					return float64(h.GetSampleCount())
+				}
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestSelectBuilder(t *testing.T) {
+	kw := ""
+	q, args, err := Select("u.id", "u.name", Raw("COUNT(o.id) AS orders")).
+		From("users u").
+		Join("LEFT JOIN orders o ON o.user_id = u.id AND o.status = :st", Params{"st": "paid"}).
+		Where("u.tenant = :tenant", Params{"tenant": "acme"}).
+		WhereIf(kw != "", "u.name LIKE :kw", Params{"kw": kw}).
+		Where("u.id IN (:ids) OR u.note = ':ids'", Params{"ids": []int{1, 2, 3}}).
+		GroupBy("u.id", "u.name").
+		OrderBy("u.id desc").
+		Limit(10).Offset(20).
+		Dialect(Postgres).
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	want := "SELECT u.id, u.name, COUNT(o.id) AS orders FROM users u " +
+		"LEFT JOIN orders o ON o.user_id = u.id AND o.status = $1 " +
+		"WHERE (u.tenant = $2) AND (u.id IN ($3, $4, $5) OR u.note = ':ids') " +
+		"GROUP BY u.id, u.name ORDER BY u.id DESC LIMIT $6 OFFSET $7"
+	if q != want {
+		t.Fatalf("unexpected sql:\n%s\nwant:\n%s", q, want)
+	}
+	if !reflect.DeepEqual(args, []any{"paid", "acme", 1, 2, 3, 10, 20}) {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	for name, b := range map[string]*SelectBuilder{
+		"identifier": Select("id; DROP TABLE users").From("users"),
+		"order":      Select().From("users").OrderBy("id; --"),
+		"table":      Select().From("users;"),
+	} {
+		if _, _, err := b.Build(); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("%s: expected ErrInvalidIdentifier, got %v", name, err)
+		}
+	}
+	if _, _, err := Select().From("users").Where("id IN (:ids)", Params{"ids": []int{}}).Build(); !errors.Is(err, ErrEmptyIn) {
+		t.Fatalf("expected ErrEmptyIn, got %v", err)
+	}
+	if _, _, err := Select().From("users").Where("id = :id").Build(); !errors.Is(err, ErrMissingParam) {
+		t.Fatalf("expected ErrMissingParam, got %v", err)
+	}
+
+	// 多个 HAVING 与 WHERE 一样加括号，OR 不会与 AND 混合
+	q, args, err = Select("user_id").From("orders").GroupBy("user_id").
+		Having("COUNT(*) > :n OR SUM(amount) > :amount", Params{"n": 10, "amount": 1000}).
+		Having("MAX(status) = :st", Params{"st": 1}).
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	want = "SELECT user_id FROM orders GROUP BY user_id HAVING (COUNT(*) > ? OR SUM(amount) > ?) AND (MAX(status) = ?)"
+	if q != want || !reflect.DeepEqual(args, []any{10, 1000, 1}) {
+		t.Fatalf("unexpected sql %s %v", q, args)
+	}
+}
+
+func TestWriteBuilders(t *testing.T) {
+	q, args, err := Insert("users").Columns("name", "age").Values("a", 1).Values("b", 2).Build()
+	if err != nil || q != "INSERT INTO users (name, age) VALUES (?, ?), (?, ?)" || len(args) != 4 {
+		t.Fatalf("insert: %q %v %v", q, args, err)
+	}
+	q, _, _ = Insert("users").SetMap(map[string]any{"name": "a", "age": 1}).Build()
+	if q != "INSERT INTO users (age, name) VALUES (?, ?)" {
+		t.Fatalf("insert map: %q", q)
+	}
+
+	q, args, err = Update("goods").
+		Set("name", "x").
+		SetIf(false, "price", 0).
+		SetExpr("stock = stock - :n", Params{"n": 1}).
+		Where("id = :id", Params{"id": 7}).
+		Build()
+	if err != nil || q != "UPDATE goods SET name = ?, stock = stock - ? WHERE id = ?" ||
+		!reflect.DeepEqual(args, []any{"x", 1, 7}) {
+		t.Fatalf("update: %q %v %v", q, args, err)
+	}
+	// 切片列整体绑定为一个参数，只有 IN ( 之后才展开
+	tags := []string{"a", "b"}
+	q, args, err = Update("goods").
+		Set("tags", tags).
+		SetIf(true, "codes", []int{1, 2}).
+		Where("id IN (:ids)", Params{"ids": []int{7, 8}}).
+		Build()
+	if err != nil || q != "UPDATE goods SET tags = ?, codes = ? WHERE id IN (?, ?)" ||
+		!reflect.DeepEqual(args, []any{tags, []int{1, 2}, 7, 8}) {
+		t.Fatalf("update slice: %q %v %v", q, args, err)
+	}
+	if _, _, err := Update("goods").Set("name", "x").Build(); !errors.Is(err, ErrNoWhere) {
+		t.Fatalf("expected ErrNoWhere, got %v", err)
+	}
+
+	q, args, err = Delete("sessions").Where("expires_at < :now::timestamptz", Params{"now": "2024-01-01"}).Dialect(Postgres).Build()
+	if err != nil || q != "DELETE FROM sessions WHERE expires_at < $1::timestamptz" || len(args) != 1 {
+		t.Fatalf("delete: %q %v %v", q, args, err)
+	}
+	if _, _, err := Delete("sessions").Build(); !errors.Is(err, ErrNoWhere) {
+		t.Fatalf("expected ErrNoWhere, got %v", err)
+	}
+	if q, _, _ := Delete("sessions").All().Build(); q != "DELETE FROM sessions" {
+		t.Fatalf("delete all: %q", q)
+	}
+}
@@ -0,0 +1,77 @@
+package discovery
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var ErrNoInstance = errors.New("discovery: no available instance")
+
+// Balancer 负载均衡策略
+type Balancer interface {
+	// Pick 从实例列表中选择一个实例
+	Pick(instances []Instance) (Instance, error)
+}
+
+// RoundRobin 轮询
+type RoundRobin struct {
+	next atomic.Uint64
+}
+
+// NewRoundRobin 创建轮询负载均衡
+func NewRoundRobin() *RoundRobin {
+	return &RoundRobin{}
+}
+
+func (b *RoundRobin) Pick(instances []Instance) (Instance, error) {
+	if len(instances) == 0 {
+		return Instance{}, ErrNoInstance
+	}
+	n := b.next.Add(1) - 1
+	return instances[n%uint64(len(instances))], nil
+}
+
+// WeightedRoundRobin 平滑加权轮询（与 nginx 相同的算法），权重取自 metadata["weight"]
+type WeightedRoundRobin struct {
+	mu      sync.Mutex
+	current map[string]int
+}
+
+// NewWeightedRoundRobin 创建加权轮询负载均衡
+func NewWeightedRoundRobin() *WeightedRoundRobin {
+	return &WeightedRoundRobin{current: make(map[string]int)}
+}
+
+func (b *WeightedRoundRobin) Pick(instances []Instance) (Instance, error) {
+	if len(instances) == 0 {
+		return Instance{}, ErrNoInstance
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	total := 0
+	best := -1
+	seen := make(map[string]struct{}, len(instances))
+	for i, ins := range instances {
+		w := ins.Weight()
+		key := ins.key()
+		seen[key] = struct{}{}
+
+		b.current[key] += w
+		total += w
+		if best < 0 || b.current[key] > b.current[instances[best].key()] {
+			best = i
+		}
+	}
+	b.current[instances[best].key()] -= total
+
+	// 清理已下线实例的状态
+	for key := range b.current {
+		if _, ok := seen[key]; !ok {
+			delete(b.current, key)
+		}
+	}
+	return instances[best], nil
+}
@@ -0,0 +1,227 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// ConsulRegistry 基于 Consul agent TTL 健康检查的服务注册
+type ConsulRegistry struct {
+	client *api.Client
+	opts   *options
+
+	mu      sync.Mutex
+	workers map[string]*worker
+}
+
+// NewConsulRegistry 创建 Consul 注册中心
+func NewConsulRegistry(client *api.Client, opts ...Option) *ConsulRegistry {
+	return &ConsulRegistry{
+		client:  client,
+		opts:    newOptions(opts),
+		workers: make(map[string]*worker),
+	}
+}
+
+func checkID(ins Instance) string {
+	return "service:" + ins.key()
+}
+
+// Register 注册服务及 TTL 检查，并定期上报心跳；agent 重启等原因丢失注册时由心跳重新注册
+func (r *ConsulRegistry) Register(ctx context.Context, ins Instance) error {
+	host, portStr, err := net.SplitHostPort(ins.Addr)
+	if err != nil {
+		return fmt.Errorf("discovery: invalid addr %q: %w", ins.Addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return fmt.Errorf("discovery: invalid port %q: %w", portStr, err)
+	}
+
+	reg := &api.AgentServiceRegistration{
+		ID:      ins.key(),
+		Name:    ins.Name,
+		Address: host,
+		Port:    port,
+		Meta:    ins.Metadata,
+		Check: &api.AgentServiceCheck{
+			CheckID: checkID(ins),
+			TTL:     r.opts.ttl.String(),
+			// 心跳长时间中断（进程被强杀）时由 Consul 自动清理
+			DeregisterCriticalServiceAfter: (r.opts.ttl * 10).String(),
+		},
+	}
+
+	// 重复注册时先停掉旧的心跳协程，避免它用旧数据重新注册
+	r.mu.Lock()
+	old := r.workers[ins.key()]
+	delete(r.workers, ins.key())
+	r.mu.Unlock()
+	if old != nil {
+		old.stop()
+	}
+
+	if err := r.register(ctx, reg); err != nil {
+		return err
+	}
+	if err := r.pass(ctx, ins); err != nil {
+		r.opts.log.Warnf("discovery: consul initial heartbeat for %s failed: %v", ins.key(), err)
+	}
+
+	w := startWorker(func(ctx context.Context) { r.heartbeat(ctx, ins, reg) })
+	r.mu.Lock()
+	r.workers[ins.key()] = w
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *ConsulRegistry) register(ctx context.Context, reg *api.AgentServiceRegistration) error {
+	opts := (&api.ServiceRegisterOpts{}).WithContext(ctx)
+	if err := r.client.Agent().ServiceRegisterOpts(reg, opts); err != nil {
+		return fmt.Errorf("discovery: consul register %s: %w", reg.ID, err)
+	}
+	return nil
+}
+
+func (r *ConsulRegistry) pass(ctx context.Context, ins Instance) error {
+	q := (&api.QueryOptions{}).WithContext(ctx)
+	return r.client.Agent().UpdateTTLOpts(checkID(ins), "", api.HealthPassing, q)
+}
+
+func (r *ConsulRegistry) heartbeat(ctx context.Context, ins Instance, reg *api.AgentServiceRegistration) {
+	ticker := time.NewTicker(r.opts.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := r.pass(ctx, ins)
+			if err == nil || ctx.Err() != nil {
+				continue
+			}
+			if !unknownCheck(err) {
+				r.opts.log.Warnf("discovery: consul heartbeat for %s failed: %v", ins.key(), err)
+				continue
+			}
+			r.opts.log.Warnf("discovery: consul agent lost %s, re-registering", ins.key())
+			if err := r.register(ctx, reg); err != nil {
+				if ctx.Err() == nil {
+					r.opts.log.Errorf("discovery: re-register %s failed: %v", ins.key(), err)
+				}
+				continue
+			}
+			if err := r.pass(ctx, ins); err != nil && ctx.Err() == nil {
+				r.opts.log.Warnf("discovery: consul heartbeat for %s failed: %v", ins.key(), err)
+			}
+		}
+	}
+}
+
+// unknownCheck agent 不认识该检查（agent 重启丢失了注册或检查已被清理），新版本返回 404，旧版本返回 500
+func unknownCheck(err error) bool {
+	var se api.StatusError
+	if !errors.As(err, &se) {
+		return false
+	}
+	return se.Code == http.StatusNotFound || strings.Contains(se.Body, "Unknown check") ||
+		strings.Contains(se.Body, "does not have associated TTL")
+}
+
+// Deregister 停止心跳并从 Consul 注销，等待心跳协程退出后再注销，避免注销后又被重新注册
+func (r *ConsulRegistry) Deregister(ctx context.Context, ins Instance) error {
+	r.mu.Lock()
+	w, ok := r.workers[ins.key()]
+	delete(r.workers, ins.key())
+	r.mu.Unlock()
+
+	if !ok {
+		return ErrNotRegistered
+	}
+	w.stop()
+
+	q := (&api.QueryOptions{}).WithContext(ctx)
+	if err := r.client.Agent().ServiceDeregisterOpts(ins.key(), q); err != nil {
+		return fmt.Errorf("discovery: consul deregister %s: %w", ins.key(), err)
+	}
+	return nil
+}
+
+// GetService 查询通过健康检查的实例
+func (r *ConsulRegistry) GetService(ctx context.Context, name string) ([]Instance, error) {
+	entries, _, err := r.client.Health().Service(name, "", true, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("discovery: consul health %s: %w", name, err)
+	}
+	return consulInstances(entries), nil
+}
+
+// Watch 使用 Consul 阻塞查询监听健康实例变化
+func (r *ConsulRegistry) Watch(ctx context.Context, name string) (<-chan []Instance, error) {
+	entries, meta, err := r.client.Health().Service(name, "", true, (&api.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("discovery: consul health %s: %w", name, err)
+	}
+
+	ch := make(chan []Instance, 1)
+	ch <- consulInstances(entries)
+
+	go func() {
+		defer close(ch)
+		index := meta.LastIndex
+		for ctx.Err() == nil {
+			q := (&api.QueryOptions{WaitIndex: index, WaitTime: 5 * time.Minute}).WithContext(ctx)
+			entries, meta, err := r.client.Health().Service(name, "", true, q)
+			if err != nil {
+				if ctx.Err() == nil {
+					r.opts.log.Warnf("discovery: consul watch %s: %v", name, err)
+					time.Sleep(time.Second)
+				}
+				continue
+			}
+			// 索引回退时重置，避免阻塞查询立即返回形成空转
+			if meta.LastIndex < index {
+				index = 0
+				continue
+			}
+			if meta.LastIndex == index {
+				continue
+			}
+			index = meta.LastIndex
+
+			select {
+			case ch <- consulInstances(entries):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func consulInstances(entries []*api.ServiceEntry) []Instance {
+	instances := make([]Instance, 0, len(entries))
+	for _, e := range entries {
+		addr := e.Service.Address
+		if addr == "" {
+			addr = e.Node.Address
+		}
+		instances = append(instances, Instance{
+			ID:       e.Service.ID,
+			Name:     e.Service.Service,
+			Addr:     net.JoinHostPort(addr, strconv.Itoa(e.Service.Port)),
+			Metadata: e.Service.Meta,
+		})
+	}
+	return instances
+}
@@ -0,0 +1,110 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/consul/api"
+)
+
+// fakeAgent 模拟 Consul agent 的注册、心跳和注销接口
+type fakeAgent struct {
+	mu         sync.Mutex
+	registered map[string]api.AgentServiceRegistration
+	passes     int
+}
+
+func (f *fakeAgent) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/v1/agent/service/register":
+		var reg api.AgentServiceRegistration
+		_ = json.NewDecoder(r.Body).Decode(&reg)
+		f.registered[reg.ID] = reg
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/check/update/"):
+		// agent 重启后不认识检查，返回 404
+		id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/v1/agent/check/update/"), "service:")
+		if _, ok := f.registered[id]; !ok {
+			http.Error(w, "Unknown check ID", http.StatusNotFound)
+			return
+		}
+		f.passes++
+	case strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+		delete(f.registered, strings.TrimPrefix(r.URL.Path, "/v1/agent/service/deregister/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestConsulRegistryLifecycle(t *testing.T) {
+	agent := &fakeAgent{registered: make(map[string]api.AgentServiceRegistration)}
+	srv := httptest.NewServer(agent)
+	defer srv.Close()
+
+	client, err := api.NewClient(&api.Config{Address: strings.TrimPrefix(srv.URL, "http://")})
+	if err != nil {
+		t.Fatalf("client: %v", err)
+	}
+
+	reg := NewConsulRegistry(client, WithTTL(30*time.Millisecond))
+	ins := Instance{Name: "order", Addr: "10.0.0.1:8080", Metadata: map[string]string{"zone": "a"}}
+
+	if err := reg.Register(context.Background(), ins); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	agent.mu.Lock()
+	got, ok := agent.registered["order-10.0.0.1:8080"]
+	passes := agent.passes
+	agent.mu.Unlock()
+	if !ok || got.Port != 8080 || got.Check == nil || got.Check.TTL == "" {
+		t.Fatalf("unexpected registration %+v", got)
+	}
+	if passes < 2 {
+		t.Fatalf("expected heartbeats, got %d", passes)
+	}
+
+	// 模拟 agent 重启丢失注册，心跳应重新注册
+	agent.mu.Lock()
+	clear(agent.registered)
+	agent.mu.Unlock()
+	time.Sleep(60 * time.Millisecond)
+	agent.mu.Lock()
+	_, ok = agent.registered["order-10.0.0.1:8080"]
+	agent.mu.Unlock()
+	if !ok {
+		t.Fatalf("expected instance to be re-registered")
+	}
+
+	if err := reg.Deregister(context.Background(), ins); err != nil {
+		t.Fatalf("deregister: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	agent.mu.Lock()
+	remaining := len(agent.registered)
+	agent.mu.Unlock()
+	if remaining != 0 {
+		t.Fatalf("expected instance to be removed")
+	}
+	if err := reg.Deregister(context.Background(), ins); err != ErrNotRegistered {
+		t.Fatalf("expected ErrNotRegistered, got %v", err)
+	}
+}
+
+func TestWithTTL(t *testing.T) {
+	if got := newOptions([]Option{WithTTL(0)}).ttl; got != 15*time.Second {
+		t.Fatalf("expected zero ttl to be ignored, got %s", got)
+	}
+	if got := newOptions([]Option{WithTTL(2 * time.Nanosecond)}).ttl; got != 15*time.Second {
+		t.Fatalf("expected tiny ttl to be ignored, got %s", got)
+	}
+}
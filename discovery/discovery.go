@@ -0,0 +1,119 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrNotRegistered = errors.New("discovery: instance not registered")
+
+// Instance 服务实例
+type Instance struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Addr     string            `json:"addr"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// key 返回实例唯一标识，未设置 ID 时使用 name-addr
+func (ins Instance) key() string {
+	if ins.ID != "" {
+		return ins.ID
+	}
+	return ins.Name + "-" + ins.Addr
+}
+
+// Registry 服务注册：启动时注册并维持心跳，优雅退出时注销
+//
+//	if err := reg.Register(ctx, ins); err != nil { ... }
+//	defer reg.Deregister(context.Background(), ins)
+type Registry interface {
+	Register(ctx context.Context, ins Instance) error
+	Deregister(ctx context.Context, ins Instance) error
+}
+
+type options struct {
+	ttl    time.Duration
+	prefix string
+	log    *zap.SugaredLogger
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithTTL 设置租约/健康检查 TTL，心跳间隔为 TTL 的三分之一，默认 15s；小于 1ms 时忽略。
+// etcd 租约以秒为单位，不足 1 秒的部分向上取整
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl >= time.Millisecond {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithPrefix 设置 etcd 中的 key 前缀，默认 /services
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithLogger 设置日志，默认不输出
+func WithLogger(log *zap.SugaredLogger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		ttl:    15 * time.Second,
+		prefix: "/services",
+		log:    zap.NewNop().Sugar(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// worker 注册后维持心跳或续约的后台协程
+type worker struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func startWorker(fn func(ctx context.Context)) *worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &worker{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		fn(ctx)
+	}()
+	return w
+}
+
+// stop 取消并等待协程退出，之后不会再有心跳或重新注册
+func (w *worker) stop() {
+	w.cancel()
+	<-w.done
+}
+
+// Weight 读取 metadata 中的 weight，未设置或非法时为 1
+func (ins Instance) Weight() int {
+	if w, err := strconv.Atoi(ins.Metadata["weight"]); err == nil && w > 0 {
+		return w
+	}
+	return 1
+}
+
+// Discovery 服务发现：查询与监听服务的健康实例列表
+type Discovery interface {
+	GetService(ctx context.Context, name string) ([]Instance, error)
+	// Watch 持续推送最新的完整实例列表，ctx 取消后 channel 关闭
+	Watch(ctx context.Context, name string) (<-chan []Instance, error)
+}
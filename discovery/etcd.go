@@ -0,0 +1,250 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdRegistry 基于 etcd 租约的服务注册，key 为 <prefix>/<name>/<id>，value 为实例 JSON
+type EtcdRegistry struct {
+	client *clientv3.Client
+	opts   *options
+
+	mu   sync.Mutex
+	regs map[string]*etcdRegistration
+}
+
+// etcdRegistration 一次注册的续约协程与当前租约，租约在重新注册时更新
+type etcdRegistration struct {
+	worker *worker
+	lease  clientv3.LeaseID
+}
+
+// NewEtcdRegistry 创建 etcd 注册中心
+func NewEtcdRegistry(client *clientv3.Client, opts ...Option) *EtcdRegistry {
+	return &EtcdRegistry{
+		client: client,
+		opts:   newOptions(opts),
+		regs:   make(map[string]*etcdRegistration),
+	}
+}
+
+func (r *EtcdRegistry) instanceKey(ins Instance) string {
+	return path.Join(r.opts.prefix, ins.Name, ins.key())
+}
+
+// Register 创建租约写入实例信息并保持续约，租约丢失时自动重新注册
+func (r *EtcdRegistry) Register(ctx context.Context, ins Instance) error {
+	// 重复注册时先停掉旧的续约协程，避免它用旧数据覆盖新注册
+	r.mu.Lock()
+	old := r.regs[ins.key()]
+	delete(r.regs, ins.key())
+	r.mu.Unlock()
+	if old != nil {
+		old.worker.stop()
+	}
+
+	leaseID, err := r.put(ctx, ins)
+	if err != nil {
+		return err
+	}
+
+	reg := &etcdRegistration{lease: leaseID}
+	r.mu.Lock()
+	reg.worker = startWorker(func(ctx context.Context) { r.keepAlive(ctx, ins, reg) })
+	r.regs[ins.key()] = reg
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *EtcdRegistry) put(ctx context.Context, ins Instance) (clientv3.LeaseID, error) {
+	value, err := json.Marshal(ins)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl := int64((r.opts.ttl + time.Second - 1) / time.Second)
+	lease, err := r.client.Grant(ctx, ttl)
+	if err != nil {
+		return 0, fmt.Errorf("discovery: etcd grant lease: %w", err)
+	}
+	if _, err := r.client.Put(ctx, r.instanceKey(ins), string(value), clientv3.WithLease(lease.ID)); err != nil {
+		return 0, fmt.Errorf("discovery: etcd put %s: %w", r.instanceKey(ins), err)
+	}
+	return lease.ID, nil
+}
+
+func (r *EtcdRegistry) keepAlive(ctx context.Context, ins Instance, reg *etcdRegistration) {
+	r.mu.Lock()
+	leaseID := reg.lease
+	r.mu.Unlock()
+
+	initial := min(time.Second, r.opts.ttl/3)
+	backoff := initial
+	for {
+		ch, err := r.client.KeepAlive(ctx, leaseID)
+		if err == nil {
+			backoff = initial
+			for range ch {
+				// 消费续约响应，channel 关闭说明租约失效或连接断开
+			}
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		r.opts.log.Warnf("discovery: etcd lease for %s lost, re-registering in %s", r.instanceKey(ins), backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, r.opts.ttl)
+
+		putCtx, cancel := context.WithTimeout(ctx, r.opts.ttl)
+		id, err := r.put(putCtx, ins)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil {
+				r.opts.log.Errorf("discovery: re-register %s failed: %v", r.instanceKey(ins), err)
+			}
+			continue
+		}
+		leaseID = id
+
+		r.mu.Lock()
+		reg.lease = id
+		r.mu.Unlock()
+		r.opts.log.Infof("discovery: re-registered %s", r.instanceKey(ins))
+	}
+}
+
+// Deregister 停止续约并撤销租约，实例 key 随租约一起删除。先等待续约协程退出再读取租约，
+// 避免撤销的是重新注册前的旧租约
+func (r *EtcdRegistry) Deregister(ctx context.Context, ins Instance) error {
+	r.mu.Lock()
+	reg, ok := r.regs[ins.key()]
+	delete(r.regs, ins.key())
+	r.mu.Unlock()
+
+	if !ok {
+		return ErrNotRegistered
+	}
+	reg.worker.stop()
+	r.mu.Lock()
+	leaseID := reg.lease
+	r.mu.Unlock()
+
+	if _, err := r.client.Revoke(ctx, leaseID); err != nil {
+		// 撤销失败时直接删除 key，租约到期后也会自动清理
+		if _, delErr := r.client.Delete(ctx, r.instanceKey(ins)); delErr != nil {
+			return fmt.Errorf("discovery: etcd deregister %s: %w", r.instanceKey(ins), err)
+		}
+	}
+	return nil
+}
+
+// GetService 读取 <prefix>/<name>/ 下的全部实例
+func (r *EtcdRegistry) GetService(ctx context.Context, name string) ([]Instance, error) {
+	resp, err := r.client.Get(ctx, r.servicePrefix(name), clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("discovery: etcd get %s: %w", name, err)
+	}
+	return decodeInstances(resp.Kvs, r.opts), nil
+}
+
+// watchRetry etcd watch 断开后重新建立的初始间隔，按指数退避至 30 秒
+var watchRetry = time.Second
+
+// Watch 监听服务前缀，任何变更后重新读取完整列表推送。watch 因连接断开等原因关闭时按退避
+// 从最后处理的 revision 之后重新建立；历史已被压缩时先全量读取再继续监听
+func (r *EtcdRegistry) Watch(ctx context.Context, name string) (<-chan []Instance, error) {
+	resp, err := r.client.Get(ctx, r.servicePrefix(name), clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("discovery: etcd get %s: %w", name, err)
+	}
+
+	ch := make(chan []Instance, 1)
+	ch <- decodeInstances(resp.Kvs, r.opts)
+
+	go func() {
+		defer close(ch)
+		rev := resp.Header.Revision
+		backoff := watchRetry
+		for {
+			compacted := false
+			wch := r.client.Watch(ctx, r.servicePrefix(name), clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+			for wresp := range wch {
+				if wresp.CompactRevision != 0 {
+					compacted = true
+				}
+				if err := wresp.Err(); err != nil {
+					r.opts.log.Warnf("discovery: etcd watch %s: %v", name, err)
+					continue
+				}
+				backoff = watchRetry
+				rev = wresp.Header.Revision
+				if !r.push(ctx, ch, name, &rev) {
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			r.opts.log.Warnf("discovery: etcd watch %s closed at revision %d, re-watching in %s", name, rev, backoff)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+
+			if compacted && !r.push(ctx, ch, name, &rev) {
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// push 读取完整实例列表推送到 ch，并将 rev 更新为读取时的 revision；ctx 取消时返回 false
+func (r *EtcdRegistry) push(ctx context.Context, ch chan<- []Instance, name string, rev *int64) bool {
+	resp, err := r.client.Get(ctx, r.servicePrefix(name), clientv3.WithPrefix())
+	if err != nil {
+		r.opts.log.Warnf("discovery: etcd get %s: %v", name, err)
+		return ctx.Err() == nil
+	}
+	*rev = max(*rev, resp.Header.Revision)
+	select {
+	case ch <- decodeInstances(resp.Kvs, r.opts):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (r *EtcdRegistry) servicePrefix(name string) string {
+	return path.Join(r.opts.prefix, name) + "/"
+}
+
+func decodeInstances(kvs []*mvccpb.KeyValue, opts *options) []Instance {
+	instances := make([]Instance, 0, len(kvs))
+	for _, kv := range kvs {
+		var ins Instance
+		if err := json.Unmarshal(kv.Value, &ins); err != nil {
+			opts.log.Warnf("discovery: skip malformed instance %s: %v", kv.Key, err)
+			continue
+		}
+		instances = append(instances, ins)
+	}
+	return instances
+}
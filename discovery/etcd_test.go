@@ -0,0 +1,287 @@
+package discovery
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeEtcd 内存版 etcd，只实现注册中心用到的 KV/Lease 方法；
+// Put 绑定最近一次 Grant 的租约（注册总是先 Grant 再 Put）
+type fakeEtcd struct {
+	clientv3.KV
+	clientv3.Lease
+	clientv3.Watcher
+
+	mu        sync.Mutex
+	rev       int64
+	nextLease clientv3.LeaseID
+	kvs       map[string]fakeKV
+	leases    map[clientv3.LeaseID]chan struct{}
+	revoked   []clientv3.LeaseID
+	watches   chan fakeWatch
+}
+
+// fakeWatch 一次 Watch 调用，测试通过 ch 推送事件或关闭模拟断开
+type fakeWatch struct {
+	rev int64
+	ch  chan clientv3.WatchResponse
+}
+
+type fakeKV struct {
+	value string
+	lease clientv3.LeaseID
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{
+		kvs:     make(map[string]fakeKV),
+		leases:  make(map[clientv3.LeaseID]chan struct{}),
+		watches: make(chan fakeWatch, 8),
+	}
+}
+
+func (f *fakeEtcd) client() *clientv3.Client {
+	return &clientv3.Client{KV: f, Lease: f, Watcher: f}
+}
+
+func (f *fakeEtcd) Close() error { return nil }
+
+func (f *fakeEtcd) Grant(_ context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextLease++
+	f.leases[f.nextLease] = make(chan struct{})
+	return &clientv3.LeaseGrantResponse{ID: f.nextLease, TTL: ttl}, nil
+}
+
+func (f *fakeEtcd) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	f.mu.Lock()
+	expired, ok := f.leases[id]
+	f.mu.Unlock()
+
+	ch := make(chan *clientv3.LeaseKeepAliveResponse)
+	if !ok {
+		close(ch)
+		return ch, nil
+	}
+	go func() {
+		defer close(ch)
+		select {
+		case <-ctx.Done():
+		case <-expired:
+		}
+	}()
+	return ch, nil
+}
+
+func (f *fakeEtcd) Revoke(_ context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	f.expire(id)
+	f.mu.Lock()
+	f.revoked = append(f.revoked, id)
+	f.mu.Unlock()
+	return &clientv3.LeaseRevokeResponse{}, nil
+}
+
+// expire 模拟租约过期，删除绑定的 key 并关闭续约 channel
+func (f *fakeEtcd) expire(id clientv3.LeaseID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ch, ok := f.leases[id]; ok {
+		close(ch)
+		delete(f.leases, id)
+	}
+	for k, kv := range f.kvs {
+		if kv.lease == id {
+			delete(f.kvs, k)
+			f.rev++
+		}
+	}
+}
+
+func (f *fakeEtcd) Put(_ context.Context, key, val string, _ ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rev++
+	f.kvs[key] = fakeKV{value: val, lease: f.nextLease}
+	return &clientv3.PutResponse{Header: &pb.ResponseHeader{Revision: f.rev}}, nil
+}
+
+func (f *fakeEtcd) Get(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: f.rev}}
+	for k, kv := range f.kvs {
+		if strings.HasPrefix(k, key) {
+			resp.Kvs = append(resp.Kvs, &mvccpb.KeyValue{Key: []byte(k), Value: []byte(kv.value), Lease: int64(kv.lease)})
+		}
+	}
+	sort.Slice(resp.Kvs, func(i, j int) bool { return string(resp.Kvs[i].Key) < string(resp.Kvs[j].Key) })
+	return resp, nil
+}
+
+func (f *fakeEtcd) Delete(_ context.Context, key string, _ ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.kvs, key)
+	f.rev++
+	return &clientv3.DeleteResponse{Header: &pb.ResponseHeader{Revision: f.rev}}, nil
+}
+
+// Watch 转发测试推送的事件，测试关闭 ch 或 ctx 取消时关闭返回的 channel
+func (f *fakeEtcd) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	w := fakeWatch{rev: clientv3.OpGet(key, opts...).Rev(), ch: make(chan clientv3.WatchResponse)}
+	f.watches <- w
+
+	out := make(chan clientv3.WatchResponse)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case wresp, ok := <-w.ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- wresp:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+func (f *fakeEtcd) lookup(key string) (fakeKV, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	kv, ok := f.kvs[key]
+	return kv, ok
+}
+
+func TestEtcdRegistryLifecycle(t *testing.T) {
+	etcd := newFakeEtcd()
+	reg := NewEtcdRegistry(etcd.client(), WithTTL(30*time.Millisecond))
+	ins := Instance{Name: "order", Addr: "10.0.0.1:8080", Metadata: map[string]string{"zone": "a"}}
+	key := "/services/order/order-10.0.0.1:8080"
+	ctx := context.Background()
+
+	if err := reg.Register(ctx, ins); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	kv, ok := etcd.lookup(key)
+	if !ok || kv.lease == 0 {
+		t.Fatalf("expected %s to be written with a lease, got %+v", key, kv)
+	}
+
+	got, err := reg.GetService(ctx, "order")
+	if err != nil || len(got) != 1 || got[0].Addr != ins.Addr || got[0].Metadata["zone"] != "a" {
+		t.Fatalf("unexpected instances %+v, err %v", got, err)
+	}
+
+	// 租约过期后应自动重新注册并换用新租约
+	first := kv.lease
+	etcd.expire(first)
+	deadline := time.Now().Add(time.Second)
+	for {
+		if kv, ok = etcd.lookup(key); ok && kv.lease != first {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %s to be re-registered", key)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 注销撤销的是当前租约，且之后不会再被续约协程写回
+	if err := reg.Deregister(ctx, ins); err != nil {
+		t.Fatalf("deregister: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if _, ok := etcd.lookup(key); ok {
+		t.Fatalf("expected %s to be removed", key)
+	}
+	etcd.mu.Lock()
+	revoked := etcd.revoked
+	etcd.mu.Unlock()
+	if len(revoked) != 1 || revoked[0] != kv.lease {
+		t.Fatalf("expected lease %d to be revoked, got %v", kv.lease, revoked)
+	}
+	if err := reg.Deregister(ctx, ins); err != ErrNotRegistered {
+		t.Fatalf("expected ErrNotRegistered, got %v", err)
+	}
+}
+
+func TestEtcdWatchResumes(t *testing.T) {
+	watchRetry = time.Millisecond
+	defer func() { watchRetry = time.Second }()
+
+	etcd := newFakeEtcd()
+	reg := NewEtcdRegistry(etcd.client())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := reg.Watch(ctx, "order")
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if list := <-ch; len(list) != 0 {
+		t.Fatalf("expected empty list, got %+v", list)
+	}
+
+	nextWatch := func() fakeWatch {
+		t.Helper()
+		select {
+		case w := <-etcd.watches:
+			return w
+		case <-time.After(time.Second):
+			t.Fatalf("watch not established")
+			return fakeWatch{}
+		}
+	}
+	w := nextWatch()
+	if w.rev != 1 {
+		t.Fatalf("expected watch from revision 1, got %d", w.rev)
+	}
+
+	put := func(addr string) int64 {
+		resp, _ := etcd.Put(ctx, "/services/order/order-"+addr, `{"name":"order","addr":"`+addr+`"}`)
+		return resp.Header.Revision
+	}
+	rev := put("10.0.0.1:80")
+	w.ch <- clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: rev}}
+	if list := <-ch; len(list) != 1 {
+		t.Fatalf("expected 1 instance, got %+v", list)
+	}
+
+	// 连接断开后从最后的 revision 之后继续监听
+	close(w.ch)
+	if w = nextWatch(); w.rev != rev+1 {
+		t.Fatalf("expected resume from %d, got %d", rev+1, w.rev)
+	}
+
+	// 历史被压缩时全量读取后继续监听
+	rev = put("10.0.0.2:80")
+	w.ch <- clientv3.WatchResponse{CompactRevision: rev}
+	close(w.ch)
+	if list := <-ch; len(list) != 2 {
+		t.Fatalf("expected resync with 2 instances, got %+v", list)
+	}
+	if w = nextWatch(); w.rev != rev+1 {
+		t.Fatalf("expected resume from %d after compaction, got %d", rev+1, w.rev)
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,139 @@
+package discovery
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+// Scheme gRPC 目标地址的 scheme，用法：grpc.NewClient("discovery:///order-service", ...)
+const Scheme = "discovery"
+
+// BalancerName 按实例权重平滑加权轮询的 gRPC 负载均衡策略，与 WeightedRoundRobin 算法相同，导入本包时注册
+const BalancerName = "discovery_weighted_round_robin"
+
+func init() {
+	balancer.Register(base.NewBalancerBuilder(BalancerName, weightedPickerBuilder{}, base.Config{HealthCheck: true}))
+}
+
+// weightKey 实例权重在 resolver.Address 属性中的 key
+type weightKey struct{}
+
+// NewGRPCBuilder 创建 gRPC resolver.Builder，通过 grpc.WithResolvers 注册。
+// 负载均衡由 gRPC 完成，按实例权重分配时使用 BalancerName 策略（round_robin 会忽略权重）：
+//
+//	grpc.WithDefaultServiceConfig(`{"loadBalancingPolicy":"discovery_weighted_round_robin"}`)
+func NewGRPCBuilder(d Discovery, opts ...Option) resolver.Builder {
+	return &grpcBuilder{discovery: d, opts: newOptions(opts)}
+}
+
+type grpcBuilder struct {
+	discovery Discovery
+	opts      *options
+}
+
+func (b *grpcBuilder) Scheme() string {
+	return Scheme
+}
+
+func (b *grpcBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	name := strings.TrimPrefix(target.Endpoint(), "/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := b.discovery.Watch(ctx, name)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	r := &grpcResolver{cancel: cancel}
+	go func() {
+		var last []Instance
+		for list := range ch {
+			logChanges(b.opts, name, last, list)
+			last = list
+
+			addrs := make([]resolver.Address, 0, len(list))
+			for _, ins := range list {
+				addrs = append(addrs, resolver.Address{
+					Addr:       ins.Addr,
+					Attributes: attributes.New(weightKey{}, ins.Weight()),
+				})
+			}
+			if err := cc.UpdateState(resolver.State{Addresses: addrs}); err != nil {
+				b.opts.log.Warnf("discovery: grpc update state for %s: %v", name, err)
+			}
+		}
+	}()
+	return r, nil
+}
+
+type grpcResolver struct {
+	cancel context.CancelFunc
+}
+
+func (r *grpcResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (r *grpcResolver) Close() {
+	r.cancel()
+}
+
+// AddressWeight 读取 resolver.Address 上的实例权重，BalancerName 策略与自定义 gRPC balancer 使用
+func AddressWeight(addr resolver.Address) int {
+	if w, ok := addr.Attributes.Value(weightKey{}).(int); ok {
+		return w
+	}
+	return 1
+}
+
+type weightedPickerBuilder struct{}
+
+func (weightedPickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+	p := &weightedPicker{}
+	for sc, sci := range info.ReadySCs {
+		w := AddressWeight(sci.Address)
+		p.items = append(p.items, &weightedSubConn{sc: sc, addr: sci.Address.Addr, weight: w})
+		p.total += w
+	}
+	// 固定顺序，相同权重时轮询结果可预期
+	sort.Slice(p.items, func(i, j int) bool { return p.items[i].addr < p.items[j].addr })
+	return p
+}
+
+type weightedSubConn struct {
+	sc      balancer.SubConn
+	addr    string
+	weight  int
+	current int
+}
+
+// weightedPicker 平滑加权轮询，连接状态变化时 gRPC 会重建 picker
+type weightedPicker struct {
+	mu    sync.Mutex
+	items []*weightedSubConn
+	total int
+}
+
+func (p *weightedPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *weightedSubConn
+	for _, it := range p.items {
+		it.current += it.weight
+		if best == nil || it.current > best.current {
+			best = it
+		}
+	}
+	best.current -= p.total
+	return balancer.PickResult{SubConn: best.sc}, nil
+}
@@ -0,0 +1,535 @@
+package discovery
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrConfigNotFound Nacos 中不存在该配置
+var ErrConfigNotFound = errors.New("discovery: nacos config not found")
+
+// NacosConfig Nacos 连接配置
+type NacosConfig struct {
+	// Addr 服务地址，例如 http://127.0.0.1:8848
+	Addr      string
+	Namespace string
+	// Group 默认 DEFAULT_GROUP
+	Group    string
+	Username string
+	Password string
+	// PollInterval Watch 服务列表的轮询间隔，默认 5 秒
+	PollInterval time.Duration
+	Client       *http.Client
+}
+
+// Nacos 通过 Nacos Open API 同时提供服务注册发现与配置中心能力
+type Nacos struct {
+	cfg  NacosConfig
+	opts *options
+
+	tokenMu     sync.Mutex
+	token       string
+	tokenExpire time.Time
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+// NewNacos 创建 Nacos 客户端
+func NewNacos(cfg NacosConfig, opts ...Option) *Nacos {
+	cfg.Addr = strings.TrimRight(cfg.Addr, "/")
+	if cfg.Group == "" {
+		cfg.Group = "DEFAULT_GROUP"
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.Client == nil {
+		// 配置长轮询最长 30 秒，超时需要留出余量
+		cfg.Client = &http.Client{Timeout: 40 * time.Second}
+	}
+
+	// 心跳间隔为 TTL/3，默认 15 秒对应 Nacos 默认的 5 秒心跳
+	return &Nacos{cfg: cfg, opts: newOptions(opts), cancels: make(map[string]context.CancelFunc)}
+}
+
+// Register 注册临时实例并定期发送心跳
+func (n *Nacos) Register(ctx context.Context, ins Instance) error {
+	if err := n.register(ctx, ins); err != nil {
+		return err
+	}
+
+	hbCtx, cancel := context.WithCancel(context.Background())
+	n.mu.Lock()
+	if old, ok := n.cancels[ins.key()]; ok {
+		old()
+	}
+	n.cancels[ins.key()] = cancel
+	n.mu.Unlock()
+
+	go n.heartbeat(hbCtx, ins)
+	return nil
+}
+
+func (n *Nacos) register(ctx context.Context, ins Instance) error {
+	q, err := n.instanceQuery(ins)
+	if err != nil {
+		return err
+	}
+	meta, _ := json.Marshal(ins.Metadata)
+	q.Set("metadata", string(meta))
+	q.Set("weight", strconv.Itoa(ins.Weight()))
+	q.Set("healthy", "true")
+	q.Set("enabled", "true")
+
+	if _, err := n.do(ctx, http.MethodPost, "/nacos/v1/ns/instance", q, nil); err != nil {
+		return fmt.Errorf("discovery: nacos register %s: %w", ins.key(), err)
+	}
+	return nil
+}
+
+func (n *Nacos) heartbeat(ctx context.Context, ins Instance) {
+	interval := n.opts.ttl / 3
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		next, err := n.beat(ctx, ins)
+		if err != nil && ctx.Err() == nil {
+			n.opts.log.Warnf("discovery: nacos heartbeat for %s failed: %v", ins.key(), err)
+		}
+		if next <= 0 {
+			next = interval
+		}
+		timer.Reset(next)
+	}
+}
+
+// beat 发送心跳，实例已被摘除（20404）时重新注册；返回服务端建议的心跳间隔
+func (n *Nacos) beat(ctx context.Context, ins Instance) (time.Duration, error) {
+	q, err := n.instanceQuery(ins)
+	if err != nil {
+		return 0, err
+	}
+	host, port, _ := net.SplitHostPort(ins.Addr)
+	p, _ := strconv.Atoi(port)
+	beat, _ := json.Marshal(map[string]any{
+		"ip":          host,
+		"port":        p,
+		"serviceName": n.cfg.Group + "@@" + ins.Name,
+		"metadata":    ins.Metadata,
+		"weight":      ins.Weight(),
+		"scheduled":   true,
+	})
+	q.Set("beat", string(beat))
+
+	body, err := n.do(ctx, http.MethodPut, "/nacos/v1/ns/instance/beat", q, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var resp struct {
+		ClientBeatInterval int64 `json:"clientBeatInterval"`
+		Code               int   `json:"code"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	if resp.Code == 20404 {
+		n.opts.log.Infof("discovery: nacos instance %s missing, re-registering", ins.key())
+		if err := n.register(ctx, ins); err != nil {
+			return 0, err
+		}
+	}
+	return time.Duration(resp.ClientBeatInterval) * time.Millisecond, nil
+}
+
+// Deregister 停止心跳并注销实例
+func (n *Nacos) Deregister(ctx context.Context, ins Instance) error {
+	n.mu.Lock()
+	cancel, ok := n.cancels[ins.key()]
+	delete(n.cancels, ins.key())
+	n.mu.Unlock()
+
+	if !ok {
+		return ErrNotRegistered
+	}
+	cancel()
+
+	q, err := n.instanceQuery(ins)
+	if err != nil {
+		return err
+	}
+	if _, err := n.do(ctx, http.MethodDelete, "/nacos/v1/ns/instance", q, nil); err != nil {
+		return fmt.Errorf("discovery: nacos deregister %s: %w", ins.key(), err)
+	}
+	return nil
+}
+
+func (n *Nacos) instanceQuery(ins Instance) (url.Values, error) {
+	host, port, err := net.SplitHostPort(ins.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: invalid addr %q: %w", ins.Addr, err)
+	}
+	q := n.baseQuery()
+	q.Set("serviceName", ins.Name)
+	q.Set("ip", host)
+	q.Set("port", port)
+	q.Set("ephemeral", "true")
+	return q, nil
+}
+
+func (n *Nacos) baseQuery() url.Values {
+	q := url.Values{}
+	q.Set("groupName", n.cfg.Group)
+	if n.cfg.Namespace != "" {
+		q.Set("namespaceId", n.cfg.Namespace)
+	}
+	return q
+}
+
+// GetService 查询健康实例
+func (n *Nacos) GetService(ctx context.Context, name string) ([]Instance, error) {
+	q := n.baseQuery()
+	q.Set("serviceName", name)
+	q.Set("healthyOnly", "true")
+
+	body, err := n.do(ctx, http.MethodGet, "/nacos/v1/ns/instance/list", q, nil)
+	if err != nil {
+		return nil, fmt.Errorf("discovery: nacos list %s: %w", name, err)
+	}
+
+	var resp struct {
+		Hosts []struct {
+			InstanceID string            `json:"instanceId"`
+			IP         string            `json:"ip"`
+			Port       int               `json:"port"`
+			Weight     float64           `json:"weight"`
+			Healthy    bool              `json:"healthy"`
+			Enabled    bool              `json:"enabled"`
+			Metadata   map[string]string `json:"metadata"`
+		} `json:"hosts"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("discovery: nacos decode %s: %w", name, err)
+	}
+
+	instances := make([]Instance, 0, len(resp.Hosts))
+	for _, h := range resp.Hosts {
+		if !h.Healthy || !h.Enabled {
+			continue
+		}
+		meta := h.Metadata
+		if meta == nil {
+			meta = map[string]string{}
+		}
+		if _, ok := meta["weight"]; !ok && h.Weight > 0 {
+			meta["weight"] = strconv.Itoa(int(h.Weight))
+		}
+		instances = append(instances, Instance{
+			ID:       h.InstanceID,
+			Name:     name,
+			Addr:     net.JoinHostPort(h.IP, strconv.Itoa(h.Port)),
+			Metadata: meta,
+		})
+	}
+	return instances, nil
+}
+
+// Watch 按 PollInterval 轮询实例列表，列表变化时推送
+func (n *Nacos) Watch(ctx context.Context, name string) (<-chan []Instance, error) {
+	list, err := n.GetService(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan []Instance, 1)
+	ch <- list
+
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(n.cfg.PollInterval)
+		defer ticker.Stop()
+
+		last := list
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			list, err := n.GetService(ctx, name)
+			if err != nil {
+				if ctx.Err() == nil {
+					n.opts.log.Warnf("discovery: %v", err)
+				}
+				continue
+			}
+			if reflect.DeepEqual(list, last) {
+				continue
+			}
+			last = list
+
+			select {
+			case ch <- list:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// GetConfig 读取配置内容，配置不存在时返回 ErrConfigNotFound
+func (n *Nacos) GetConfig(ctx context.Context, dataID string) (string, error) {
+	body, err := n.do(ctx, http.MethodGet, "/nacos/v1/cs/configs", n.configQuery(dataID), nil)
+	if err != nil {
+		var se *statusError
+		if errors.As(err, &se) && se.code == http.StatusNotFound {
+			return "", fmt.Errorf("%w: %s", ErrConfigNotFound, dataID)
+		}
+		return "", fmt.Errorf("discovery: nacos get config %s: %w", dataID, err)
+	}
+	return string(body), nil
+}
+
+// PublishConfig 发布配置
+func (n *Nacos) PublishConfig(ctx context.Context, dataID, content string) error {
+	form := n.configQuery(dataID)
+	form.Set("content", content)
+	if _, err := n.do(ctx, http.MethodPost, "/nacos/v1/cs/configs", nil, form); err != nil {
+		return fmt.Errorf("discovery: nacos publish config %s: %w", dataID, err)
+	}
+	return nil
+}
+
+// WatchConfig 通过长轮询监听配置变化，首个元素为当前内容；配置尚不存在时等待其被创建后再推送，
+// 配置被删除时不推送，等待重新创建
+func (n *Nacos) WatchConfig(ctx context.Context, dataID string) (<-chan string, error) {
+	content, err := n.GetConfig(ctx, dataID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, ErrConfigNotFound) {
+		return nil, err
+	}
+
+	ch := make(chan string, 1)
+	if exists {
+		ch <- content
+	}
+
+	go func() {
+		defer close(ch)
+		for ctx.Err() == nil {
+			changed, err := n.listen(ctx, dataID, content, exists)
+			if err != nil {
+				if ctx.Err() == nil {
+					n.opts.log.Warnf("discovery: nacos listen config %s: %v", dataID, err)
+					time.Sleep(time.Second)
+				}
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			latest, err := n.GetConfig(ctx, dataID)
+			if errors.Is(err, ErrConfigNotFound) {
+				n.opts.log.Warnf("discovery: nacos config %s deleted, waiting for it to be recreated", dataID)
+				content, exists = "", false
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					n.opts.log.Warnf("discovery: %v", err)
+				}
+				continue
+			}
+			content, exists = latest, true
+
+			select {
+			case ch <- latest:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// listen 长轮询接口：Listening-Configs = dataId^2group^2md5[^2tenant]^1，
+// 配置不存在时 md5 为空，配置被创建后返回变更
+func (n *Nacos) listen(ctx context.Context, dataID, content string, exists bool) (bool, error) {
+	var digest string
+	if exists {
+		sum := md5.Sum([]byte(content))
+		digest = hex.EncodeToString(sum[:])
+	}
+	parts := []string{dataID, n.cfg.Group, digest}
+	if n.cfg.Namespace != "" {
+		parts = append(parts, n.cfg.Namespace)
+	}
+
+	form := url.Values{}
+	form.Set("Listening-Configs", strings.Join(parts, "\x02")+"\x01")
+
+	body, err := n.doWithHeader(ctx, http.MethodPost, "/nacos/v1/cs/configs/listener", nil, form,
+		http.Header{"Long-Pulling-Timeout": {"30000"}})
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(string(body)) != "", nil
+}
+
+func (n *Nacos) configQuery(dataID string) url.Values {
+	q := url.Values{}
+	q.Set("dataId", dataID)
+	q.Set("group", n.cfg.Group)
+	if n.cfg.Namespace != "" {
+		q.Set("tenant", n.cfg.Namespace)
+	}
+	return q
+}
+
+func (n *Nacos) do(ctx context.Context, method, path string, query, form url.Values) ([]byte, error) {
+	return n.doWithHeader(ctx, method, path, query, form, nil)
+}
+
+// doWithHeader 发送请求；开启鉴权时遇到 403（token 过期或被服务端重置）清除 token 重新登录并重试一次
+func (n *Nacos) doWithHeader(ctx context.Context, method, path string, query, form url.Values, header http.Header) ([]byte, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	for retried := false; ; retried = true {
+		token, err := n.accessToken(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			query.Set("accessToken", token)
+		}
+
+		data, err := n.send(ctx, method, path, query, form, header)
+		var se *statusError
+		if token != "" && !retried && errors.As(err, &se) && se.code == http.StatusForbidden {
+			n.resetToken(token)
+			continue
+		}
+		return data, err
+	}
+}
+
+func (n *Nacos) send(ctx context.Context, method, path string, query, form url.Values, header http.Header) ([]byte, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, n.cfg.Addr+path+"?"+query.Encode(), body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := n.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, &statusError{code: resp.StatusCode, body: strings.TrimSpace(string(data))}
+	}
+	return data, nil
+}
+
+// statusError Nacos 返回的非 2xx 响应
+type statusError struct {
+	code int
+	body string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.code, e.body)
+}
+
+// resetToken 清除已失效的 token；并发请求已换过新 token 时不清除
+func (n *Nacos) resetToken(token string) {
+	n.tokenMu.Lock()
+	defer n.tokenMu.Unlock()
+	if n.token == token {
+		n.token = ""
+	}
+}
+
+// accessToken 开启鉴权时登录获取 token，并在过期前刷新
+func (n *Nacos) accessToken(ctx context.Context) (string, error) {
+	if n.cfg.Username == "" {
+		return "", nil
+	}
+
+	n.tokenMu.Lock()
+	defer n.tokenMu.Unlock()
+
+	if n.token != "" && time.Now().Before(n.tokenExpire) {
+		return n.token, nil
+	}
+
+	form := url.Values{}
+	form.Set("username", n.cfg.Username)
+	form.Set("password", n.cfg.Password)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.Addr+"/nacos/v1/auth/login", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.cfg.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("discovery: nacos login: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		AccessToken string `json:"accessToken"`
+		TokenTTL    int64  `json:"tokenTtl"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("discovery: nacos login: status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.AccessToken == "" {
+		return "", errors.New("discovery: nacos login: invalid response")
+	}
+
+	n.token = result.AccessToken
+	// 提前 10% 刷新
+	n.tokenExpire = time.Now().Add(time.Duration(result.TokenTTL) * time.Second * 9 / 10)
+	return n.token, nil
+}
@@ -0,0 +1,262 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeNacos 模拟 Nacos 的实例与配置接口
+type fakeNacos struct {
+	mu      sync.Mutex
+	hosts   map[string]map[string]any
+	beats   int
+	config  string
+	missing bool
+	changed chan struct{}
+	// token 当前有效的 token，修改后旧 token 返回 403 模拟过期
+	token  string
+	logins int
+}
+
+func (f *fakeNacos) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	_ = r.ParseForm()
+	q := r.Form
+
+	f.mu.Lock()
+	token := f.token
+	f.mu.Unlock()
+	if q.Get("accessToken") != token && r.URL.Path != "/nacos/v1/auth/login" {
+		http.Error(w, "token expired", http.StatusForbidden)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/nacos/v1/auth/login":
+		f.mu.Lock()
+		f.logins++
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"accessToken": token, "tokenTtl": 18000})
+	case "/nacos/v1/ns/instance":
+		f.mu.Lock()
+		key := q.Get("ip") + ":" + q.Get("port")
+		if r.Method == http.MethodDelete {
+			delete(f.hosts, key)
+		} else {
+			var meta map[string]string
+			_ = json.Unmarshal([]byte(q.Get("metadata")), &meta)
+			f.hosts[key] = map[string]any{
+				"instanceId": key, "ip": q.Get("ip"), "port": 8080,
+				"weight": 3.0, "healthy": true, "enabled": true, "metadata": meta,
+				"group": q.Get("groupName"), "namespace": q.Get("namespaceId"),
+			}
+		}
+		f.mu.Unlock()
+		_, _ = w.Write([]byte("ok"))
+	case "/nacos/v1/ns/instance/beat":
+		f.mu.Lock()
+		f.beats++
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"clientBeatInterval": 10, "code": 10200})
+	case "/nacos/v1/ns/instance/list":
+		f.mu.Lock()
+		hosts := make([]map[string]any, 0, len(f.hosts))
+		for _, h := range f.hosts {
+			hosts = append(hosts, h)
+		}
+		f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"hosts": hosts})
+	case "/nacos/v1/cs/configs":
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if r.Method == http.MethodPost {
+			f.config, f.missing = q.Get("content"), false
+			close(f.changed)
+			_, _ = w.Write([]byte("true"))
+			return
+		}
+		if f.missing {
+			http.Error(w, "config data not exist", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(f.config))
+	case "/nacos/v1/cs/configs/listener":
+		f.mu.Lock()
+		changed, missing := f.changed, f.missing
+		f.mu.Unlock()
+		// 配置不存在时客户端以空 md5 监听
+		prefix := "app.yaml\x02G1\x02"
+		if missing {
+			prefix += "\x02"
+		}
+		if !strings.HasPrefix(q.Get("Listening-Configs"), prefix) {
+			http.Error(w, "bad listener", http.StatusBadRequest)
+			return
+		}
+		select {
+		case <-changed:
+			_, _ = w.Write([]byte("app.yaml%02G1%02ns%01\n"))
+		case <-time.After(100 * time.Millisecond):
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newFakeNacos(t *testing.T) (*fakeNacos, *Nacos) {
+	fake := &fakeNacos{hosts: map[string]map[string]any{}, config: "v1", changed: make(chan struct{}), token: "token"}
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	n := NewNacos(NacosConfig{
+		Addr:         srv.URL,
+		Namespace:    "ns",
+		Group:        "G1",
+		Username:     "nacos",
+		Password:     "nacos",
+		PollInterval: 20 * time.Millisecond,
+	}, WithTTL(30*time.Millisecond))
+	return fake, n
+}
+
+func TestNacosRegistry(t *testing.T) {
+	fake, n := newFakeNacos(t)
+	ctx := context.Background()
+	ins := Instance{Name: "order", Addr: "10.0.0.1:8080", Metadata: map[string]string{"zone": "a"}}
+
+	if err := n.Register(ctx, ins); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	fake.mu.Lock()
+	beats := fake.beats
+	host := fake.hosts["10.0.0.1:8080"]
+	fake.mu.Unlock()
+	if beats == 0 {
+		t.Fatalf("expected heartbeats")
+	}
+	if host["group"] != "G1" || host["namespace"] != "ns" {
+		t.Fatalf("unexpected group/namespace %+v", host)
+	}
+
+	list, err := n.GetService(ctx, "order")
+	if err != nil {
+		t.Fatalf("get service: %v", err)
+	}
+	if len(list) != 1 || list[0].Addr != "10.0.0.1:8080" || list[0].Weight() != 3 || list[0].Metadata["zone"] != "a" {
+		t.Fatalf("unexpected instances %+v", list)
+	}
+
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch, err := n.Watch(wctx, "order")
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	<-ch
+
+	if err := n.Deregister(ctx, ins); err != nil {
+		t.Fatalf("deregister: %v", err)
+	}
+	select {
+	case list := <-ch:
+		if len(list) != 0 {
+			t.Fatalf("expected empty list, got %+v", list)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("watch did not observe deregistration")
+	}
+
+	if err := n.Deregister(ctx, ins); err != ErrNotRegistered {
+		t.Fatalf("expected ErrNotRegistered, got %v", err)
+	}
+}
+
+func TestNacosConfig(t *testing.T) {
+	_, n := newFakeNacos(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := n.WatchConfig(ctx, "app.yaml")
+	if err != nil {
+		t.Fatalf("watch config: %v", err)
+	}
+	if got := <-ch; got != "v1" {
+		t.Fatalf("initial config = %q", got)
+	}
+
+	if err := n.PublishConfig(ctx, "app.yaml", "v2"); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	select {
+	case got := <-ch:
+		if got != "v2" {
+			t.Fatalf("updated config = %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("config change not observed")
+	}
+}
+
+func TestNacosConfigCreated(t *testing.T) {
+	fake, n := newFakeNacos(t)
+	fake.missing = true
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if _, err := n.GetConfig(ctx, "app.yaml"); !errors.Is(err, ErrConfigNotFound) {
+		t.Fatalf("expected ErrConfigNotFound, got %v", err)
+	}
+
+	ch, err := n.WatchConfig(ctx, "app.yaml")
+	if err != nil {
+		t.Fatalf("watch missing config: %v", err)
+	}
+	select {
+	case got := <-ch:
+		t.Fatalf("unexpected content %q before creation", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := n.PublishConfig(ctx, "app.yaml", "created"); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	select {
+	case got := <-ch:
+		if got != "created" {
+			t.Fatalf("created config = %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("config creation not observed")
+	}
+}
+
+func TestNacosTokenRefresh(t *testing.T) {
+	fake, n := newFakeNacos(t)
+	ctx := context.Background()
+
+	if _, err := n.GetConfig(ctx, "app.yaml"); err != nil {
+		t.Fatalf("get config: %v", err)
+	}
+
+	// 服务端 token 失效（过期或重启）后 403，客户端重新登录并重试
+	fake.mu.Lock()
+	fake.token = "token2"
+	fake.mu.Unlock()
+	if got, err := n.GetConfig(ctx, "app.yaml"); err != nil || got != "v1" {
+		t.Fatalf("get config after token expiry: %q %v", got, err)
+	}
+	fake.mu.Lock()
+	logins := fake.logins
+	fake.mu.Unlock()
+	if logins != 2 {
+		t.Fatalf("expected re-login, got %d logins", logins)
+	}
+}
@@ -0,0 +1,135 @@
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// Resolver 监听服务实例变化并在本地做负载均衡，可用于任意 HTTP 客户端
+type Resolver struct {
+	name      string
+	balancer  Balancer
+	opts      *options
+	instances atomic.Pointer[[]Instance]
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+// NewResolver 创建并启动解析器，返回前已获取到首批实例
+func NewResolver(d Discovery, name string, balancer Balancer, opts ...Option) (*Resolver, error) {
+	if balancer == nil {
+		balancer = NewRoundRobin()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch, err := d.Watch(ctx, name)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	r := &Resolver{
+		name:     name,
+		balancer: balancer,
+		opts:     newOptions(opts),
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	empty := []Instance{}
+	r.instances.Store(&empty)
+
+	// Watch 保证首个元素为当前列表
+	if first, ok := <-ch; ok {
+		r.update(first)
+	}
+
+	go func() {
+		defer close(r.done)
+		for list := range ch {
+			r.update(list)
+		}
+		if ctx.Err() == nil {
+			r.opts.log.Errorf("discovery: watch %s stopped, keeping last %d instances", name, len(r.Instances()))
+		}
+	}()
+	return r, nil
+}
+
+func (r *Resolver) update(list []Instance) {
+	old := *r.instances.Load()
+	r.instances.Store(&list)
+	logChanges(r.opts, r.name, old, list)
+}
+
+// logChanges 记录实例上下线
+func logChanges(opts *options, name string, old, list []Instance) {
+	before := make(map[string]bool, len(old))
+	for _, ins := range old {
+		before[ins.key()] = true
+	}
+	after := make(map[string]bool, len(list))
+	for _, ins := range list {
+		after[ins.key()] = true
+		if !before[ins.key()] {
+			opts.log.Infof("discovery: %s instance added: %s (%s)", name, ins.key(), ins.Addr)
+		}
+	}
+	for _, ins := range old {
+		if !after[ins.key()] {
+			opts.log.Infof("discovery: %s instance removed: %s (%s)", name, ins.key(), ins.Addr)
+		}
+	}
+	if len(list) == 0 && len(old) > 0 {
+		opts.log.Warnf("discovery: %s has no available instance", name)
+	}
+}
+
+// Instances 返回当前实例列表
+func (r *Resolver) Instances() []Instance {
+	return *r.instances.Load()
+}
+
+// Pick 按负载均衡策略选择实例
+func (r *Resolver) Pick() (Instance, error) {
+	ins, err := r.balancer.Pick(r.Instances())
+	if err != nil {
+		return ins, fmt.Errorf("%w: %s", err, r.name)
+	}
+	return ins, nil
+}
+
+// Close 停止监听
+func (r *Resolver) Close() {
+	r.cancel()
+	<-r.done
+}
+
+// Transport 返回 RoundTripper，将 host 为服务名的请求（http://<name>/path）改写为选中的实例地址
+func (r *Resolver) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != r.name {
+			return base.RoundTrip(req)
+		}
+
+		ins, err := r.Pick()
+		if err != nil {
+			return nil, err
+		}
+
+		req = req.Clone(req.Context())
+		req.URL.Host = ins.Addr
+		req.Host = ins.Addr
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
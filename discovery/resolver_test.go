@@ -0,0 +1,135 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+)
+
+type fakeDiscovery struct {
+	ch chan []Instance
+}
+
+func (f *fakeDiscovery) GetService(context.Context, string) ([]Instance, error) {
+	return nil, nil
+}
+
+func (f *fakeDiscovery) Watch(ctx context.Context, name string) (<-chan []Instance, error) {
+	out := make(chan []Instance)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case list := <-f.ch:
+				out <- list
+			}
+		}
+	}()
+	return out, nil
+}
+
+func TestWeightedRoundRobin(t *testing.T) {
+	instances := []Instance{
+		{ID: "a", Metadata: map[string]string{"weight": "5"}},
+		{ID: "b", Metadata: map[string]string{"weight": "1"}},
+		{ID: "c", Metadata: map[string]string{"weight": "1"}},
+	}
+
+	b := NewWeightedRoundRobin()
+	counts := map[string]int{}
+	var seq []string
+	for i := 0; i < 7; i++ {
+		ins, _ := b.Pick(instances)
+		counts[ins.ID]++
+		seq = append(seq, ins.ID)
+	}
+
+	if counts["a"] != 5 || counts["b"] != 1 || counts["c"] != 1 {
+		t.Fatalf("unexpected distribution %v", counts)
+	}
+	// 平滑加权：a 不会连续出现 5 次
+	if strings.Join(seq, "") != "aabacaa" {
+		t.Fatalf("unexpected sequence %v", seq)
+	}
+}
+
+// fakeSubConn 只用于区分 picker 的选择结果
+type fakeSubConn struct {
+	balancer.SubConn
+	id string
+}
+
+func TestGRPCWeightedPicker(t *testing.T) {
+	if balancer.Get(BalancerName) == nil {
+		t.Fatalf("balancer %s not registered", BalancerName)
+	}
+
+	info := base.PickerBuildInfo{ReadySCs: map[balancer.SubConn]base.SubConnInfo{}}
+	for id, w := range map[string]int{"a": 5, "b": 1, "c": 1} {
+		addr := resolver.Address{Addr: id, Attributes: attributes.New(weightKey{}, w)}
+		info.ReadySCs[&fakeSubConn{id: id}] = base.SubConnInfo{Address: addr}
+	}
+	picker := weightedPickerBuilder{}.Build(info)
+
+	var seq []string
+	for i := 0; i < 7; i++ {
+		res, err := picker.Pick(balancer.PickInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		seq = append(seq, res.SubConn.(*fakeSubConn).id)
+	}
+	if strings.Join(seq, "") != "aabacaa" {
+		t.Fatalf("unexpected sequence %v", seq)
+	}
+
+	if _, err := (weightedPickerBuilder{}).Build(base.PickerBuildInfo{}).Pick(balancer.PickInfo{}); err != balancer.ErrNoSubConnAvailable {
+		t.Fatalf("expected ErrNoSubConnAvailable, got %v", err)
+	}
+}
+
+func TestResolverTransport(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("pong"))
+	}))
+	defer backend.Close()
+
+	d := &fakeDiscovery{ch: make(chan []Instance)}
+	go func() { d.ch <- nil }()
+
+	r, err := NewResolver(d, "order", NewRoundRobin())
+	if err != nil {
+		t.Fatalf("resolver: %v", err)
+	}
+	defer r.Close()
+
+	if _, err := r.Pick(); err == nil {
+		t.Fatalf("expected error with no instances")
+	}
+
+	d.ch <- []Instance{{Name: "order", Addr: strings.TrimPrefix(backend.URL, "http://")}}
+	deadline := time.Now().Add(time.Second)
+	for len(r.Instances()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	client := &http.Client{Transport: r.Transport(nil)}
+	resp, err := client.Get("http://order/ping")
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,71 @@
+package experiment
+
+import (
+	"hash/fnv"
+	"sort"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+// ExposureEvent 曝光日志的 event 字段值，分析侧按此过滤并与业务事件关联
+const ExposureEvent = "experiment_exposure"
+
+// Weights 各分组权重，例如 Weights{"control": 50, "treatment": 50}
+type Weights map[string]int
+
+// Assign 将用户确定性地分配到某个分组，不记录曝光。
+// 相同 userID 与 experiment 总是得到相同结果；权重全为 0 时返回空字符串
+func Assign(userID, experiment string, weights Weights) string {
+	names := make([]string, 0, len(weights))
+	total := 0
+	for name, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		names = append(names, name)
+		total += w
+	}
+	if total == 0 {
+		return ""
+	}
+	// map 遍历无序，按名称排序保证分桶稳定
+	sort.Strings(names)
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(experiment))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(userID))
+	point := int(h.Sum32() % uint32(total))
+
+	for _, name := range names {
+		point -= weights[name]
+		if point < 0 {
+			return name
+		}
+	}
+	return names[len(names)-1]
+}
+
+// Variant 分配分组并通过 logger 记录曝光事件：
+//
+//	switch experiment.Variant(userID, "checkout_v2", experiment.Weights{"control": 90, "v2": 10}) {
+//	case "v2":
+//		...
+//	}
+func Variant(userID, experiment string, weights Weights) string {
+	variant := Assign(userID, experiment, weights)
+	if variant != "" {
+		LogExposure(userID, experiment, variant)
+	}
+	return variant
+}
+
+// LogExposure 通过全局日志记录一次曝光，全局日志未设置时不输出
+func LogExposure(userID, experiment, variant string) {
+	logger.S().Infow("experiment exposure",
+		"event", ExposureEvent,
+		"experiment", experiment,
+		"variant", variant,
+		"user_id", userID,
+	)
+}
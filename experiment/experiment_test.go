@@ -0,0 +1,47 @@
+package experiment
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/abs2free/go-kit/testkit"
+)
+
+func TestAssignDistribution(t *testing.T) {
+	weights := Weights{"control": 80, "v2": 20, "off": 0}
+
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		id := strconv.Itoa(i)
+		v := Assign(id, "checkout_v2", weights)
+		if v != Assign(id, "checkout_v2", weights) {
+			t.Fatalf("assignment not stable for %s", id)
+		}
+		counts[v]++
+	}
+	if counts["off"] != 0 {
+		t.Fatalf("zero-weight variant was assigned")
+	}
+	if counts["v2"] < 1700 || counts["v2"] > 2300 {
+		t.Fatalf("expected ~20%% in v2, got %v", counts)
+	}
+
+	if v := Assign("u1", "x", Weights{}); v != "" {
+		t.Fatalf("expected empty variant, got %q", v)
+	}
+}
+
+func TestVariantLogsExposure(t *testing.T) {
+	logs := testkit.Logger(t)
+
+	v := Variant("u42", "checkout_v2", Weights{"control": 1, "v2": 1})
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 exposure log, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["event"] != ExposureEvent || fields["experiment"] != "checkout_v2" || fields["variant"] != v || fields["user_id"] != "u42" {
+		t.Fatalf("unexpected exposure fields %v", fields)
+	}
+}
@@ -0,0 +1,95 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSV 将 rows 逐行写入 w，内存占用与行数无关
+func CSV[T any](w io.Writer, cols []Column[T], rows iter.Seq[T], opts ...Option) error {
+	o := newOptions(opts)
+	if o.bom {
+		if _, err := io.WriteString(w, "\ufeff"); err != nil {
+			return fmt.Errorf("export: write bom: %w", err)
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	flusher, _ := w.(http.Flusher)
+	if err := cw.Write(headers(cols)); err != nil {
+		return fmt.Errorf("export: write header: %w", err)
+	}
+
+	record := make([]string, len(cols))
+	n := 0
+	for row := range rows {
+		n++
+		if err := checkRows(o, n); err != nil {
+			return err
+		}
+		for i, c := range cols {
+			record[i] = formatCSV(c.Value(row), o.timeLayout)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("export: write row %d: %w", n, err)
+		}
+		if o.flushEvery > 0 && n%o.flushEvery == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return fmt.Errorf("export: flush: %w", err)
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("export: flush: %w", err)
+	}
+	return nil
+}
+
+// ServeCSV 以附件形式导出 CSV
+func ServeCSV[T any](w http.ResponseWriter, filename string, cols []Column[T], rows iter.Seq[T], opts ...Option) error {
+	setDownloadHeaders(w, filename, "text/csv; charset=utf-8")
+	return CSV(w, cols, rows, opts...)
+}
+
+func formatCSV(v any, timeLayout string) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(x)
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(timeLayout)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case fmt.Stringer:
+		return escapeFormula(x.String())
+	}
+	return fmt.Sprint(v)
+}
+
+// escapeFormula 以 = + - @ 制表符或回车开头的文本在 Excel 中会被当作公式执行（CSV 注入），
+// 加 ' 前缀按文本显示；-12.5 等数字不受影响
+func escapeFormula(s string) string {
+	if s == "" || !strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return s
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s
+	}
+	return "'" + s
+}
@@ -0,0 +1,118 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"mime"
+	"net/http"
+	"time"
+)
+
+var ErrTooManyRows = errors.New("export: too many rows")
+
+// Column 列定义
+type Column[T any] struct {
+	Header string
+	// Width XLSX 列宽，0 表示默认
+	Width float64
+	Value func(row T) any
+}
+
+type options struct {
+	maxRows    int
+	bom        bool
+	sheet      string
+	flushEvery int
+	timeLayout string
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithMaxRows 限制导出行数（不含表头），超出时返回 ErrTooManyRows；默认 CSV 不限制，XLSX 为单表上限 1048575
+func WithMaxRows(n int) Option {
+	return func(o *options) {
+		o.maxRows = n
+	}
+}
+
+// WithBOM CSV 是否写入 UTF-8 BOM，默认写入以便 Excel 正确识别中文
+func WithBOM(bom bool) Option {
+	return func(o *options) {
+		o.bom = bom
+	}
+}
+
+// WithSheetName 设置 XLSX 工作表名，默认 Sheet1
+func WithSheetName(name string) Option {
+	return func(o *options) {
+		o.sheet = name
+	}
+}
+
+// WithFlushEvery CSV 每写入 n 行刷新一次，下载端可以尽早收到数据，默认 1000
+func WithFlushEvery(n int) Option {
+	return func(o *options) {
+		o.flushEvery = n
+	}
+}
+
+// WithTimeLayout 设置 time.Time 的输出格式，默认 2006-01-02 15:04:05
+func WithTimeLayout(layout string) Option {
+	return func(o *options) {
+		o.timeLayout = layout
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{bom: true, sheet: "Sheet1", flushEvery: 1000, timeLayout: time.DateTime}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Chan 将通道适配为迭代器，便于从生产者协程中导出；提前结束导出时调用方需自行停止生产者
+func Chan[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Slice 将切片适配为迭代器
+func Slice[T any](s []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+func headers[T any](cols []Column[T]) []string {
+	h := make([]string, len(cols))
+	for i, c := range cols {
+		h[i] = c.Header
+	}
+	return h
+}
+
+// setDownloadHeaders 设置下载响应头，文件名按 RFC 6266 编码以支持中文
+func setDownloadHeaders(w http.ResponseWriter, filename, contentType string) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	w.Header().Set("Cache-Control", "no-store")
+}
+
+func checkRows(o *options, n int) error {
+	if o.maxRows > 0 && n > o.maxRows {
+		return fmt.Errorf("%w: limit %d", ErrTooManyRows, o.maxRows)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/abs2free/go-kit/mathutil"
+)
+
+type order struct {
+	ID      int
+	Name    string
+	Amount  mathutil.Money
+	Created time.Time
+}
+
+var columns = []Column[order]{
+	{Header: "订单号", Value: func(o order) any { return o.ID }},
+	{Header: "商品", Width: 30, Value: func(o order) any { return o.Name }},
+	{Header: "金额", Value: func(o order) any { return o.Amount }},
+	{Header: "下单时间", Value: func(o order) any { return o.Created }},
+}
+
+func orders(n int) []order {
+	rows := make([]order, n)
+	for i := range rows {
+		rows[i] = order{
+			ID:      i + 1,
+			Name:    "商品, \"特价\"",
+			Amount:  mathutil.New(int64(100*(i+1)), mathutil.CNY),
+			Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	}
+	return rows
+}
+
+func TestServeCSV(t *testing.T) {
+	ch := make(chan order)
+	go func() {
+		defer close(ch)
+		for _, o := range orders(3) {
+			ch <- o
+		}
+	}()
+
+	rec := httptest.NewRecorder()
+	if err := ServeCSV(rec, "订单.csv", columns, Chan(ch), WithFlushEvery(1)); err != nil {
+		t.Fatal(err)
+	}
+	if cd := rec.Header().Get("Content-Disposition"); !strings.Contains(cd, "filename*=utf-8''%E8%AE%A2%E5%8D%95.csv") {
+		t.Fatalf("unexpected content disposition %q", cd)
+	}
+	if !rec.Flushed {
+		t.Fatal("expected response to be flushed while streaming")
+	}
+
+	body := strings.TrimPrefix(rec.Body.String(), "\ufeff")
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 4 || records[0][0] != "订单号" {
+		t.Fatalf("unexpected records %v", records)
+	}
+	if got := records[2]; got[1] != "商品, \"特价\"" || got[2] != "2.00" || got[3] != "2024-01-02 03:04:05" {
+		t.Fatalf("unexpected row %v", got)
+	}
+}
+
+func TestCSVFormulaInjection(t *testing.T) {
+	cols := []Column[string]{{Header: "备注", Value: func(s string) any { return s }}}
+	rows := []string{"=HYPERLINK(\"http://evil\")", "+1+1", "-2+3", "@SUM(A1)", "\tcmd", "\rcmd", "-12.5", "a=b"}
+	var buf bytes.Buffer
+	if err := CSV(&buf, cols, Slice(rows)); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"'=HYPERLINK(\"http://evil\")", "'+1+1", "'-2+3", "'@SUM(A1)", "'\tcmd", "'\rcmd", "-12.5", "a=b"}
+	for i, w := range want {
+		if got := records[i+1][0]; got != w {
+			t.Errorf("row %d: got %q, want %q", i, got, w)
+		}
+	}
+}
+
+func TestMaxRows(t *testing.T) {
+	var buf bytes.Buffer
+	if err := CSV(&buf, columns, Slice(orders(3)), WithMaxRows(2)); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows, got %v", err)
+	}
+	if err := XLSX(&buf, columns, Slice(orders(3)), WithMaxRows(2)); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("expected ErrTooManyRows, got %v", err)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := XLSX(&buf, columns, Slice(orders(100)), WithSheetName("订单")); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows("订单")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 101 || rows[0][1] != "商品" || rows[100][0] != "100" || rows[100][2] != "100.00" {
+		t.Fatalf("unexpected sheet content: %d rows, last %v", len(rows), rows[len(rows)-1])
+	}
+}
@@ -0,0 +1,97 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// maxXLSXRows 单个工作表最多 1048576 行，减去表头
+const maxXLSXRows = 1048575
+
+// XLSX 使用 excelize 的流式写入导出，行数据超过内存阈值后暂存到临时文件，
+// 因此大数据量导出时内存占用有上限；文件需在全部写完后才能输出到 w
+func XLSX[T any](w io.Writer, cols []Column[T], rows iter.Seq[T], opts ...Option) error {
+	o := newOptions(opts)
+	if o.maxRows <= 0 || o.maxRows > maxXLSXRows {
+		o.maxRows = maxXLSXRows
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+	if o.sheet != "Sheet1" {
+		if err := f.SetSheetName("Sheet1", o.sheet); err != nil {
+			return fmt.Errorf("export: rename sheet: %w", err)
+		}
+	}
+
+	sw, err := f.NewStreamWriter(o.sheet)
+	if err != nil {
+		return fmt.Errorf("export: new stream writer: %w", err)
+	}
+	for i, c := range cols {
+		if c.Width > 0 {
+			if err := sw.SetColWidth(i+1, i+1, c.Width); err != nil {
+				return fmt.Errorf("export: set column width: %w", err)
+			}
+		}
+	}
+
+	header := make([]any, len(cols))
+	for i, h := range headers(cols) {
+		header[i] = h
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return fmt.Errorf("export: write header: %w", err)
+	}
+
+	record := make([]any, len(cols))
+	n := 0
+	for row := range rows {
+		n++
+		if err := checkRows(o, n); err != nil {
+			return err
+		}
+		for i, c := range cols {
+			record[i] = formatXLSX(c.Value(row), o.timeLayout)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, n+1)
+		if err := sw.SetRow(cell, record); err != nil {
+			return fmt.Errorf("export: write row %d: %w", n, err)
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return fmt.Errorf("export: flush: %w", err)
+	}
+	if _, err := f.WriteTo(w); err != nil {
+		return fmt.Errorf("export: write xlsx: %w", err)
+	}
+	return nil
+}
+
+// ServeXLSX 以附件形式导出 XLSX
+func ServeXLSX[T any](w http.ResponseWriter, filename string, cols []Column[T], rows iter.Seq[T], opts ...Option) error {
+	setDownloadHeaders(w, filename, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	return XLSX(w, cols, rows, opts...)
+}
+
+// formatXLSX 数值与布尔保持原类型，便于在 Excel 中计算；时间按 timeLayout 输出为文本
+func formatXLSX(v any, timeLayout string) any {
+	switch x := v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return x
+	case time.Time:
+		if x.IsZero() {
+			return ""
+		}
+		return x.Format(timeLayout)
+	case fmt.Stringer:
+		return x.String()
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,215 @@
+package feature
+
+import (
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Target 被评估的对象，通常为当前用户
+type Target struct {
+	// ID 百分比灰度的哈希依据，相同 ID 在同一开关下结果稳定
+	ID    string
+	Attrs map[string]string
+}
+
+// attr 读取属性，"id" 指向 Target.ID
+func (t Target) attr(name string) (string, bool) {
+	if name == "id" {
+		return t.ID, t.ID != ""
+	}
+	v, ok := t.Attrs[name]
+	return v, ok
+}
+
+// Rule 属性规则，命中时直接返回 Serve
+//
+//	{"attribute": "country", "op": "in", "values": ["CN", "SG"], "serve": true}
+type Rule struct {
+	Attribute string   `json:"attribute"`
+	Op        string   `json:"op"`
+	Values    []string `json:"values"`
+	Serve     bool     `json:"serve"`
+}
+
+// 规则操作符
+const (
+	OpEq       = "eq"
+	OpNeq      = "neq"
+	OpIn       = "in"
+	OpNotIn    = "not_in"
+	OpPrefix   = "prefix"
+	OpSuffix   = "suffix"
+	OpContains = "contains"
+	OpGt       = "gt"
+	OpLt       = "lt"
+)
+
+func (r Rule) match(t Target) bool {
+	v, ok := t.attr(r.Attribute)
+	if !ok {
+		// 属性缺失时只有否定类规则成立
+		return r.Op == OpNeq || r.Op == OpNotIn
+	}
+
+	switch r.Op {
+	case OpEq, OpIn, "":
+		return contains(r.Values, v)
+	case OpNeq, OpNotIn:
+		return !contains(r.Values, v)
+	case OpPrefix:
+		return anyOf(r.Values, func(s string) bool { return strings.HasPrefix(v, s) })
+	case OpSuffix:
+		return anyOf(r.Values, func(s string) bool { return strings.HasSuffix(v, s) })
+	case OpContains:
+		return anyOf(r.Values, func(s string) bool { return strings.Contains(v, s) })
+	case OpGt, OpLt:
+		if len(r.Values) == 0 {
+			return false
+		}
+		a, err1 := strconv.ParseFloat(v, 64)
+		b, err2 := strconv.ParseFloat(r.Values[0], 64)
+		if err1 != nil || err2 != nil {
+			return false
+		}
+		if r.Op == OpGt {
+			return a > b
+		}
+		return a < b
+	}
+	return false
+}
+
+func contains(values []string, v string) bool {
+	return anyOf(values, func(s string) bool { return s == v })
+}
+
+func anyOf(values []string, fn func(string) bool) bool {
+	for _, s := range values {
+		if fn(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Flag 功能开关。评估顺序：Enabled 为 false 时关闭；依次匹配 Rules，
+// 命中即返回规则结果；设置了 Percentage 时按 Target.ID 哈希灰度；否则开启
+type Flag struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	// Percentage 灰度比例 0-100，nil 表示全量
+	Percentage *float64 `json:"percentage,omitempty"`
+	Rules      []Rule   `json:"rules,omitempty"`
+}
+
+// Evaluate 评估开关对目标是否开启
+func (f Flag) Evaluate(t Target) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, r := range f.Rules {
+		if r.match(t) {
+			return r.Serve
+		}
+	}
+	if f.Percentage != nil {
+		return Bucket(f.Name, t.ID) < *f.Percentage
+	}
+	return true
+}
+
+// Bucket 返回 key 在 salt 下的稳定分桶值，范围 [0, 100)，精度 0.01
+func Bucket(salt, key string) float64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(salt))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum32()%10000) / 100
+}
+
+// Flags 开关集合，可从文件、环境变量和远程配置加载，并发安全
+type Flags struct {
+	mu    sync.RWMutex
+	flags map[string]Flag
+	evals *prometheus.CounterVec
+}
+
+// Option 配置选项
+type Option func(*Flags)
+
+// WithMetrics 注册 feature_evaluations_total 指标（按 flag 和结果统计评估次数）
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(f *Flags) {
+		f.evals = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "feature_evaluations_total",
+			Help: "Number of feature flag evaluations by flag and result.",
+		}, []string{"flag", "result"})
+		reg.MustRegister(f.evals)
+	}
+}
+
+// New 创建开关集合
+func New(opts ...Option) *Flags {
+	f := &Flags{flags: make(map[string]Flag)}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// Set 新增或覆盖开关
+func (f *Flags) Set(flags ...Flag) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, flag := range flags {
+		f.flags[flag.Name] = flag
+	}
+}
+
+// replace 在同一把锁内删除 removed 并写入 flags，读者不会看到中间状态
+func (f *Flags) replace(removed []string, flags []Flag) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, name := range removed {
+		delete(f.flags, name)
+	}
+	for _, flag := range flags {
+		f.flags[flag.Name] = flag
+	}
+}
+
+// Get 返回开关定义
+func (f *Flags) Get(name string) (Flag, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	flag, ok := f.flags[name]
+	return flag, ok
+}
+
+// Enabled 评估开关，未定义的开关视为关闭
+func (f *Flags) Enabled(name string, t Target) bool {
+	flag, ok := f.Get(name)
+	result := ok && flag.Evaluate(t)
+
+	if f.evals != nil {
+		f.evals.WithLabelValues(name, strconv.FormatBool(result)).Inc()
+	}
+	return result
+}
+
+// Bool 评估与用户无关的开关
+func (f *Flags) Bool(name string) bool {
+	return f.Enabled(name, Target{})
+}
+
+// Default 包级开关集合
+var Default = New()
+
+// Enabled 使用 Default 评估开关
+func Enabled(name string, t Target) bool {
+	return Default.Enabled(name, t)
+}
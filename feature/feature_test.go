@@ -0,0 +1,149 @@
+package feature
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestPercentageRolloutIsStable(t *testing.T) {
+	pct := 30.0
+	flag := Flag{Name: "new_checkout", Enabled: true, Percentage: &pct}
+
+	on := 0
+	for i := 0; i < 10000; i++ {
+		target := Target{ID: strconv.Itoa(i)}
+		first := flag.Evaluate(target)
+		if first != flag.Evaluate(target) {
+			t.Fatalf("evaluation not stable for %d", i)
+		}
+		if first {
+			on++
+		}
+	}
+	if on < 2700 || on > 3300 {
+		t.Fatalf("expected ~30%% enabled, got %d/10000", on)
+	}
+}
+
+func TestRules(t *testing.T) {
+	zero := 0.0
+	flag := Flag{
+		Name:       "beta",
+		Enabled:    true,
+		Percentage: &zero,
+		Rules: []Rule{
+			{Attribute: "id", Op: OpIn, Values: []string{"blocked"}, Serve: false},
+			{Attribute: "country", Op: OpIn, Values: []string{"CN", "SG"}, Serve: true},
+			{Attribute: "age", Op: OpGt, Values: []string{"60"}, Serve: true},
+		},
+	}
+
+	cases := []struct {
+		target Target
+		want   bool
+	}{
+		{Target{ID: "u1", Attrs: map[string]string{"country": "CN"}}, true},
+		{Target{ID: "blocked", Attrs: map[string]string{"country": "CN"}}, false},
+		{Target{ID: "u2", Attrs: map[string]string{"country": "US"}}, false},
+		{Target{ID: "u3", Attrs: map[string]string{"age": "65"}}, true},
+		{Target{ID: "u4"}, false},
+	}
+	for i, c := range cases {
+		if got := flag.Evaluate(c.target); got != c.want {
+			t.Errorf("case %d: got %v, want %v", i, got, c.want)
+		}
+	}
+
+	flag.Enabled = false
+	if flag.Evaluate(cases[0].target) {
+		t.Fatalf("disabled flag must evaluate to false")
+	}
+}
+
+func TestLoadFileAndEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	data := `{"dark_mode": {"enabled": true}, "new_checkout": {"enabled": false, "rules": [{"attribute": "id", "values": ["vip"], "serve": true}]}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f := New()
+	if err := f.LoadFile(path); err != nil {
+		t.Fatalf("load file: %v", err)
+	}
+	if !f.Bool("dark_mode") || f.Enabled("new_checkout", Target{ID: "vip"}) {
+		t.Fatalf("unexpected flags from file")
+	}
+
+	t.Setenv("FF_NEW_CHECKOUT", "100%")
+	t.Setenv("FF_DARK_MODE", "off")
+	if err := f.LoadEnv("FF"); err != nil {
+		t.Fatalf("load env: %v", err)
+	}
+	if f.Bool("dark_mode") {
+		t.Fatalf("env should disable dark_mode")
+	}
+	if flag, _ := f.Get("new_checkout"); len(flag.Rules) != 1 {
+		t.Fatalf("env override should keep rules")
+	}
+	if !f.Enabled("new_checkout", Target{ID: "someone"}) {
+		t.Fatalf("env should enable new_checkout")
+	}
+
+	t.Setenv("FF_DARK_MODE", "on")
+	t.Setenv("FF_BROKEN", "maybe")
+	if err := f.LoadEnv("FF"); err == nil {
+		t.Fatalf("expected error for invalid env value")
+	}
+	if f.Bool("dark_mode") {
+		t.Fatalf("invalid env should not apply any variable")
+	}
+}
+
+func TestWatchAndMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	f := New(WithMetrics(reg))
+	f.Set(Flag{Name: "local", Enabled: true})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := make(chan string, 1)
+	f.Watch(ctx, updates, nil)
+
+	updates <- `[{"name": "remote", "enabled": true}]`
+	deadline := time.Now().Add(time.Second)
+	for !f.Bool("remote") {
+		if time.Now().After(deadline) {
+			t.Fatalf("remote update not applied")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 新快照中不存在的远程开关被删除，本地开关保留
+	updates <- `[{"name": "other", "enabled": true}]`
+	for f.Bool("remote") {
+		if time.Now().After(deadline) {
+			t.Fatalf("remote flag not removed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !f.Bool("other") || !f.Bool("local") {
+		t.Fatalf("expected other and local flags to be kept")
+	}
+	if _, err := Parse([]byte(`{"bad": {"enabled": true, "percentage": 120}}`)); err == nil {
+		t.Fatalf("expected invalid percentage error")
+	}
+
+	f.Bool("missing")
+
+	if got := testutil.ToFloat64(f.evals.WithLabelValues("missing", "false")); got != 1 {
+		t.Fatalf("expected 1 evaluation of missing flag, got %v", got)
+	}
+}
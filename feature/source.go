@@ -0,0 +1,162 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Parse 解析 JSON 格式的开关定义，支持对象（key 为开关名）或数组两种形式：
+//
+//	{"new_checkout": {"enabled": true, "percentage": 30}}
+//	[{"name": "new_checkout", "enabled": true}]
+//
+// 任一开关名为空或灰度比例不在 0-100 时整体返回错误
+func Parse(data []byte) ([]Flag, error) {
+	data = []byte(strings.TrimSpace(string(data)))
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if data[0] == '[' {
+		var flags []Flag
+		if err := json.Unmarshal(data, &flags); err != nil {
+			return nil, fmt.Errorf("feature: parse flags: %w", err)
+		}
+		return flags, validate(flags)
+	}
+
+	var m map[string]Flag
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("feature: parse flags: %w", err)
+	}
+	flags := make([]Flag, 0, len(m))
+	for name, flag := range m {
+		flag.Name = name
+		flags = append(flags, flag)
+	}
+	return flags, validate(flags)
+}
+
+func validate(flags []Flag) error {
+	for _, flag := range flags {
+		if flag.Name == "" {
+			return fmt.Errorf("feature: parse flags: empty flag name")
+		}
+		if p := flag.Percentage; p != nil && (*p < 0 || *p > 100) {
+			return fmt.Errorf("feature: parse flags: %s: invalid percentage %v", flag.Name, *p)
+		}
+	}
+	return nil
+}
+
+// LoadFile 从 JSON 文件加载开关
+func (f *Flags) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("feature: read %s: %w", path, err)
+	}
+	flags, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	f.Set(flags...)
+	return nil
+}
+
+// LoadEnv 从环境变量加载开关，用于临时覆盖：
+//
+//	<prefix>_NEW_CHECKOUT=true   -> new_checkout 全量开启
+//	<prefix>_NEW_CHECKOUT=30%    -> new_checkout 灰度 30%
+//
+// 变量名去掉前缀后转为小写作为开关名；环境变量只覆盖开关状态与比例，保留已有规则。
+// 任一变量无效时返回错误且不应用任何变量
+func (f *Flags) LoadEnv(prefix string) error {
+	prefix = strings.TrimSuffix(prefix, "_") + "_"
+
+	var flags []Flag
+	for _, kv := range os.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if !strings.HasPrefix(key, prefix) || key == prefix {
+			continue
+		}
+		name := strings.ToLower(strings.TrimPrefix(key, prefix))
+
+		flag, _ := f.Get(name)
+		flag.Name = name
+		if err := applyEnv(&flag, value); err != nil {
+			return fmt.Errorf("feature: env %s: %w", key, err)
+		}
+		flags = append(flags, flag)
+	}
+	f.Set(flags...)
+	return nil
+}
+
+func applyEnv(flag *Flag, value string) error {
+	value = strings.TrimSpace(value)
+	if pct, ok := strings.CutSuffix(value, "%"); ok {
+		p, err := strconv.ParseFloat(pct, 64)
+		if err != nil || p < 0 || p > 100 {
+			return fmt.Errorf("invalid percentage %q", value)
+		}
+		flag.Enabled = true
+		flag.Percentage = &p
+		return nil
+	}
+
+	switch strings.ToLower(value) {
+	case "on":
+		value = "true"
+	case "off":
+		value = "false"
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("invalid value %q", value)
+	}
+	flag.Enabled = b
+	flag.Percentage = nil
+	return nil
+}
+
+// Watch 消费远程配置推送（如 discovery.Nacos.WatchConfig 返回的 channel），
+// 每次推送视为完整快照：覆盖同名开关，并删除上一次快照中有而本次没有的开关
+// （文件、环境变量加载的开关不受影响）；解析失败时保留旧值并通过 onError 回调通知
+func (f *Flags) Watch(ctx context.Context, updates <-chan string, onError func(error)) {
+	go func() {
+		var prev map[string]struct{}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case content, ok := <-updates:
+				if !ok {
+					return
+				}
+				flags, err := Parse([]byte(content))
+				if err != nil {
+					if onError != nil {
+						onError(err)
+					}
+					continue
+				}
+				current := make(map[string]struct{}, len(flags))
+				for _, flag := range flags {
+					current[flag.Name] = struct{}{}
+				}
+				var removed []string
+				for name := range prev {
+					if _, ok := current[name]; !ok {
+						removed = append(removed, name)
+					}
+				}
+				f.replace(removed, flags)
+				prev = current
+			}
+		}
+	}()
+}
@@ -0,0 +1,116 @@
+package fileutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+var ErrChecksumMismatch = errors.New("fileutil: checksum mismatch")
+
+// AtomicWrite 原子写文件：写入同目录临时文件并 fsync 后 rename 覆盖目标，
+// 读者只会看到旧内容或完整的新内容，进程中途崩溃也不会留下半个文件
+func AtomicWrite(path string, data []byte, perm os.FileMode) error {
+	return AtomicWriteFunc(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// AtomicWriteFunc 与 AtomicWrite 相同，内容由 fn 流式写入
+func AtomicWriteFunc(path string, perm os.FileMode, fn func(w io.Writer) error) (err error) {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("fileutil: create temp: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err = fn(tmp); err != nil {
+		return fmt.Errorf("fileutil: write %s: %w", path, err)
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("fileutil: chmod %s: %w", path, err)
+	}
+	if err = tmp.Sync(); err != nil {
+		return fmt.Errorf("fileutil: sync %s: %w", path, err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("fileutil: close %s: %w", path, err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("fileutil: rename %s: %w", path, err)
+	}
+	return syncDir(dir)
+}
+
+// syncDir 持久化目录项，保证 rename 在掉电后依然可见
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("fileutil: open dir %s: %w", dir, err)
+	}
+	defer d.Close()
+	// 部分平台（如 Windows）不支持目录 fsync，忽略该错误
+	_ = d.Sync()
+	return nil
+}
+
+// Checksum 计算文件 SHA-256，返回十六进制字符串
+func Checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("fileutil: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("fileutil: read %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// CopyWithChecksum 原子复制文件并返回源文件 SHA-256；
+// 写入后重新读取目标文件校验，不一致时删除目标并返回 ErrChecksumMismatch
+func CopyWithChecksum(src, dst string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("fileutil: open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return "", fmt.Errorf("fileutil: stat %s: %w", src, err)
+	}
+
+	h := sha256.New()
+	err = AtomicWriteFunc(dst, info.Mode().Perm(), func(w io.Writer) error {
+		_, err := io.Copy(io.MultiWriter(w, h), in)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	got, err := Checksum(dst)
+	if err != nil {
+		return "", err
+	}
+	if got != sum {
+		_ = os.Remove(dst)
+		return "", fmt.Errorf("%w: %s", ErrChecksumMismatch, dst)
+	}
+	return sum, nil
+}
@@ -0,0 +1,176 @@
+package fileutil
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAtomicWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+
+	if err := AtomicWrite(path, []byte("v1"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := AtomicWrite(path, []byte("v2"), 0o600); err != nil {
+		t.Fatalf("overwrite: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "v2" {
+		t.Fatalf("unexpected content %q", data)
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("unexpected perm %v", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("temp files left behind: %v", entries)
+	}
+}
+
+func TestCopyWithChecksum(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	if err := os.WriteFile(src, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sum, err := CopyWithChecksum(src, dst)
+	if err != nil {
+		t.Fatalf("copy: %v", err)
+	}
+	// sha256("hello")
+	if sum != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+		t.Fatalf("unexpected checksum %s", sum)
+	}
+	if got, _ := Checksum(dst); got != sum {
+		t.Fatalf("destination checksum %s != %s", got, sum)
+	}
+
+	if _, err := CopyWithChecksum(filepath.Join(dir, "missing"), dst); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected not exist error, got %v", err)
+	}
+}
+
+func TestTailFollowRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("old line\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tail, err := TailFollow(path, WithPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("tail: %v", err)
+	}
+	defer tail.Close()
+
+	lines := make(chan string, 10)
+	go func() {
+		scanner := bufio.NewScanner(tail)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	appendLine := func(s string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = f.WriteString(s + "\n")
+		_ = f.Close()
+	}
+	expect := func(want string) {
+		select {
+		case got := <-lines:
+			if got != want {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %q", want)
+		}
+	}
+
+	appendLine("first")
+	expect("first")
+
+	// 模拟 lumberjack 轮转：重命名后新建
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendLine("after rotate")
+	expect("after rotate")
+
+	// 截断
+	if err := os.Truncate(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	appendLine("after truncate")
+	expect("after truncate")
+
+	_ = tail.Close()
+	if _, ok := <-lines; ok {
+		t.Fatalf("expected reader to stop after Close")
+	}
+}
+
+func TestTempManager(t *testing.T) {
+	tm, err := NewTempManager(t.TempDir(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scope := tm.Scope()
+	f, err := scope.CreateTemp("upload-*")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := f.WriteString("12345678"); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := io.Copy(f, strings.NewReader("abc")); !errors.Is(err, ErrTempQuotaExceeded) {
+		t.Fatalf("expected ErrTempQuotaExceeded, got %v", err)
+	}
+	dir, err := scope.MkdirTemp("work-*")
+	if err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := scope.Close(); err != nil {
+		t.Fatalf("close scope: %v", err)
+	}
+	if tm.Used() != 0 {
+		t.Fatalf("quota not released: %d", tm.Used())
+	}
+	for _, path := range []string{f.Name(), dir} {
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("%s not removed", path)
+		}
+	}
+
+	other := tm.Scope()
+	if _, err := other.CreateTemp("x-*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if _, err := os.Stat(tm.Root()); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("root not removed")
+	}
+	if _, err := other.CreateTemp("y-*"); !errors.Is(err, ErrTempClosed) {
+		t.Fatalf("expected ErrTempClosed, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package fileutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Tail 持续读取文件追加内容的 io.ReadCloser（类似 tail -F），
+// 文件被轮转（重命名后新建）或截断时自动切换到新文件。
+// 可直接配合 logger 按大小轮转的日志文件使用：
+//
+//	t, _ := fileutil.TailFollow("logs/zap.log")
+//	defer t.Close()
+//	scanner := bufio.NewScanner(t)
+//	for scanner.Scan() { ... }
+type Tail struct {
+	path     string
+	interval time.Duration
+
+	mu     sync.Mutex
+	file   *os.File
+	info   os.FileInfo
+	offset int64
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+type tailOptions struct {
+	interval  time.Duration
+	fromStart bool
+}
+
+// TailOption 配置选项
+type TailOption func(*tailOptions)
+
+// WithPollInterval 设置无新数据时的轮询间隔，默认 250ms
+func WithPollInterval(d time.Duration) TailOption {
+	return func(o *tailOptions) {
+		o.interval = d
+	}
+}
+
+// WithFromStart 从文件开头读取，默认从末尾开始只读新增内容
+func WithFromStart() TailOption {
+	return func(o *tailOptions) {
+		o.fromStart = true
+	}
+}
+
+// TailFollow 打开文件并跟随读取，文件尚不存在时等待其被创建
+func TailFollow(path string, opts ...TailOption) (*Tail, error) {
+	o := &tailOptions{interval: 250 * time.Millisecond}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	t := &Tail{path: path, interval: o.interval, closed: make(chan struct{})}
+	if err := t.open(!o.fromStart); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Tail) open(seekEnd bool) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fileutil: stat %s: %w", t.path, err)
+	}
+
+	var offset int64
+	if seekEnd {
+		if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			_ = f.Close()
+			return fmt.Errorf("fileutil: seek %s: %w", t.path, err)
+		}
+	}
+
+	if t.file != nil {
+		_ = t.file.Close()
+	}
+	t.file, t.info, t.offset = f, info, offset
+	return nil
+}
+
+// Read 读取新增内容，无数据时阻塞直到有数据或 Close；Close 后返回 io.EOF
+func (t *Tail) Read(p []byte) (int, error) {
+	for {
+		t.mu.Lock()
+		select {
+		case <-t.closed:
+			t.mu.Unlock()
+			return 0, io.EOF
+		default:
+		}
+		n, err := t.read(p)
+		t.mu.Unlock()
+		if n > 0 || err != nil {
+			return n, err
+		}
+
+		select {
+		case <-t.closed:
+			return 0, io.EOF
+		case <-time.After(t.interval):
+		}
+	}
+}
+
+// read 读取当前文件；读到末尾时检查轮转与截断
+func (t *Tail) read(p []byte) (int, error) {
+	if t.file == nil {
+		// 文件尚未创建，或轮转间隙中新文件还未出现
+		if err := t.open(false); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return 0, nil
+			}
+			return 0, err
+		}
+	}
+
+	n, err := t.file.Read(p)
+	t.offset += int64(n)
+	if n > 0 {
+		return n, nil
+	}
+	if err != nil && err != io.EOF {
+		return 0, fmt.Errorf("fileutil: read %s: %w", t.path, err)
+	}
+
+	info, err := os.Stat(t.path)
+	if err != nil {
+		// 旧文件已被重命名、新文件还未创建，继续等待
+		return 0, nil
+	}
+	if !os.SameFile(t.info, info) {
+		// 已轮转：旧文件已读完，切换到新文件从头读取
+		if err := t.open(false); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return 0, err
+		}
+		return 0, nil
+	}
+	if info.Size() < t.offset {
+		// 原地截断（copytruncate），从头读取
+		if _, err := t.file.Seek(0, io.SeekStart); err != nil {
+			return 0, fmt.Errorf("fileutil: seek %s: %w", t.path, err)
+		}
+		t.offset = 0
+	}
+	return 0, nil
+}
+
+// Close 停止跟随并关闭文件，阻塞中的 Read 返回 io.EOF
+func (t *Tail) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
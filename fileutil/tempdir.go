@@ -0,0 +1,231 @@
+package fileutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+var (
+	ErrTempQuotaExceeded = errors.New("fileutil: temp quota exceeded")
+	ErrTempClosed        = errors.New("fileutil: temp manager closed")
+)
+
+// TempManager 管理进程内的临时文件：所有文件位于同一根目录下，
+// 按作用域跟踪并统计总写入量，超过上限时写入失败。
+// 作用域关闭时删除其创建的文件，Shutdown 时删除整个根目录：
+//
+//	tm, _ := fileutil.NewTempManager("", 2<<30)
+//	defer tm.Shutdown(context.Background())
+//
+//	scope := tm.Scope()
+//	defer scope.Close()
+//	f, _ := scope.CreateTemp("upload-*.zip")
+type TempManager struct {
+	root    string
+	maxSize int64
+
+	mu     sync.Mutex
+	used   int64
+	scopes map[*TempScope]struct{}
+	closed bool
+}
+
+// NewTempManager 在 parent（为空时使用系统临时目录）下创建根目录；maxSize <= 0 表示不限制
+func NewTempManager(parent string, maxSize int64) (*TempManager, error) {
+	root, err := os.MkdirTemp(parent, "gokit-tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("fileutil: create temp root: %w", err)
+	}
+	return &TempManager{root: root, maxSize: maxSize, scopes: make(map[*TempScope]struct{})}, nil
+}
+
+// Root 返回根目录
+func (m *TempManager) Root() string {
+	return m.root
+}
+
+// Used 返回当前已占用字节数
+func (m *TempManager) Used() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.used
+}
+
+func (m *TempManager) reserve(n int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return ErrTempClosed
+	}
+	if m.maxSize > 0 && m.used+n > m.maxSize {
+		return fmt.Errorf("%w: %d + %d > %d", ErrTempQuotaExceeded, m.used, n, m.maxSize)
+	}
+	m.used += n
+	return nil
+}
+
+func (m *TempManager) release(n int64) {
+	m.mu.Lock()
+	m.used -= n
+	m.mu.Unlock()
+}
+
+// Scope 创建作用域，通常对应一次请求或一个任务
+func (m *TempManager) Scope() *TempScope {
+	s := &TempScope{manager: m}
+	m.mu.Lock()
+	m.scopes[s] = struct{}{}
+	m.mu.Unlock()
+	return s
+}
+
+// Shutdown 关闭所有作用域并删除根目录，可注册到进程退出流程
+func (m *TempManager) Shutdown(context.Context) error {
+	m.mu.Lock()
+	m.closed = true
+	scopes := make([]*TempScope, 0, len(m.scopes))
+	for s := range m.scopes {
+		scopes = append(scopes, s)
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for _, s := range scopes {
+		errs = append(errs, s.Close())
+	}
+	if err := os.RemoveAll(m.root); err != nil {
+		errs = append(errs, fmt.Errorf("fileutil: remove temp root: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// TempScope 临时文件作用域
+type TempScope struct {
+	manager *TempManager
+
+	mu     sync.Mutex
+	files  []*TempFile
+	dirs   []string
+	closed bool
+}
+
+// CreateTemp 创建临时文件，pattern 规则同 os.CreateTemp
+func (s *TempScope) CreateTemp(pattern string) (*TempFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrTempClosed
+	}
+
+	f, err := os.CreateTemp(s.manager.root, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("fileutil: create temp: %w", err)
+	}
+	tf := &TempFile{File: f, scope: s}
+	s.files = append(s.files, tf)
+	return tf, nil
+}
+
+// MkdirTemp 创建临时目录；目录内文件不计入配额，适合第三方工具直接写入的场景
+func (s *TempScope) MkdirTemp(pattern string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return "", ErrTempClosed
+	}
+
+	dir, err := os.MkdirTemp(s.manager.root, pattern)
+	if err != nil {
+		return "", fmt.Errorf("fileutil: mkdir temp: %w", err)
+	}
+	s.dirs = append(s.dirs, dir)
+	return dir, nil
+}
+
+// Close 删除作用域内创建的全部文件与目录并归还配额，可重复调用
+func (s *TempScope) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	files, dirs := s.files, s.dirs
+	s.files, s.dirs = nil, nil
+	s.mu.Unlock()
+
+	var errs []error
+	for _, f := range files {
+		_ = f.File.Close()
+		if err := os.Remove(f.Name()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+		s.manager.release(f.size())
+	}
+	for _, dir := range dirs {
+		if err := os.RemoveAll(dir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.manager.mu.Lock()
+	delete(s.manager.scopes, s)
+	s.manager.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// TempFile 计入配额的临时文件，写入前检查剩余配额
+type TempFile struct {
+	*os.File
+	scope *TempScope
+
+	mu      sync.Mutex
+	written int64
+}
+
+func (f *TempFile) Write(p []byte) (int, error) {
+	return f.write(p, f.File.Write)
+}
+
+func (f *TempFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+// WriteAt 按写入长度计入配额（覆盖写也会计入，配额统计偏保守）
+func (f *TempFile) WriteAt(p []byte, off int64) (int, error) {
+	return f.write(p, func(b []byte) (int, error) { return f.File.WriteAt(b, off) })
+}
+
+// write 先预占配额再写入，未写入的部分归还
+func (f *TempFile) write(p []byte, fn func([]byte) (int, error)) (int, error) {
+	if err := f.scope.manager.reserve(int64(len(p))); err != nil {
+		return 0, err
+	}
+	n, err := fn(p)
+	f.mu.Lock()
+	f.written += int64(n)
+	f.mu.Unlock()
+	if n < len(p) {
+		f.scope.manager.release(int64(len(p) - n))
+	}
+	return n, err
+}
+
+// ReadFrom 覆盖 *os.File 的实现，保证 io.Copy 也经过配额检查
+func (f *TempFile) ReadFrom(r io.Reader) (int64, error) {
+	return io.Copy(writerOnly{f}, r)
+}
+
+type writerOnly struct {
+	io.Writer
+}
+
+func (f *TempFile) size() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.written
+}
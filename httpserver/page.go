@@ -0,0 +1,224 @@
+package httpserver
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPageParam = errors.New("httpserver: invalid page parameter")
+
+// SortField 排序字段
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// Page 分页与排序参数
+type Page struct {
+	Page int
+	Size int
+	Sort []SortField
+}
+
+// Offset 返回 SQL OFFSET，手动构造的 Page 溢出时截断为 math.MaxInt
+func (p Page) Offset() int {
+	if p.Page < 1 || p.Size < 1 {
+		return 0
+	}
+	if p.Page-1 > math.MaxInt/p.Size {
+		return math.MaxInt
+	}
+	return (p.Page - 1) * p.Size
+}
+
+// Limit 返回 LIMIT/OFFSET 片段
+func (p Page) Limit() string {
+	return fmt.Sprintf("LIMIT %d OFFSET %d", p.Size, p.Offset())
+}
+
+// OrderBy 返回 ORDER BY 片段，字段均来自白名单映射后的列名，可安全拼接；无排序时返回空字符串
+func (p Page) OrderBy() string {
+	return orderBy(p.Sort)
+}
+
+func orderBy(sort []SortField) string {
+	if len(sort) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(sort))
+	for _, s := range sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, s.Field+" "+dir)
+	}
+	return "ORDER BY " + strings.Join(parts, ", ")
+}
+
+// PageOptions 解析规则
+type PageOptions struct {
+	// DefaultSize 默认每页条数，默认 20
+	DefaultSize int
+	// MaxSize 每页条数上限，超出时截断，默认 100
+	MaxSize int
+	// MaxPage 页码上限，超出时返回 ErrInvalidPageParam，默认 10000
+	MaxPage int
+	// SortFields 允许排序的字段白名单：请求参数名 -> 数据库列名
+	SortFields map[string]string
+	// DefaultSort 未指定排序时使用，如 "-created_at"
+	DefaultSort string
+}
+
+func (o *PageOptions) withDefaults() PageOptions {
+	opts := PageOptions{DefaultSize: 20, MaxSize: 100, MaxPage: 10000}
+	if o != nil {
+		opts.SortFields = o.SortFields
+		opts.DefaultSort = o.DefaultSort
+		if o.DefaultSize > 0 {
+			opts.DefaultSize = o.DefaultSize
+		}
+		if o.MaxSize > 0 {
+			opts.MaxSize = o.MaxSize
+		}
+		if o.MaxPage > 0 {
+			opts.MaxPage = o.MaxPage
+		}
+	}
+	return opts
+}
+
+// ParsePage 解析 page、size、sort 查询参数。
+// sort 形如 "-created_at,name"，"-" 前缀表示降序；不在白名单内的字段返回 ErrInvalidPageParam
+func ParsePage(r *http.Request, o *PageOptions) (Page, error) {
+	opts := o.withDefaults()
+	q := r.URL.Query()
+
+	page, err := intParam(q.Get("page"), 1)
+	if err != nil || page < 1 || page > opts.MaxPage {
+		return Page{}, fmt.Errorf("%w: page=%q", ErrInvalidPageParam, q.Get("page"))
+	}
+	size, err := parseSize(q.Get("size"), opts)
+	if err != nil {
+		return Page{}, err
+	}
+	sort, err := parseSort(q.Get("sort"), opts)
+	if err != nil {
+		return Page{}, err
+	}
+	return Page{Page: page, Size: size, Sort: sort}, nil
+}
+
+func intParam(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+	return strconv.Atoi(s)
+}
+
+func parseSize(s string, opts PageOptions) (int, error) {
+	size, err := intParam(s, opts.DefaultSize)
+	if err != nil || size < 1 {
+		return 0, fmt.Errorf("%w: size=%q", ErrInvalidPageParam, s)
+	}
+	if size > opts.MaxSize {
+		size = opts.MaxSize
+	}
+	return size, nil
+}
+
+func parseSort(s string, opts PageOptions) ([]SortField, error) {
+	if s == "" {
+		s = opts.DefaultSort
+	}
+	if s == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		desc := false
+		if strings.HasPrefix(part, "-") {
+			desc, part = true, part[1:]
+		} else {
+			part = strings.TrimPrefix(part, "+")
+		}
+
+		column, ok := opts.SortFields[part]
+		if !ok {
+			return nil, fmt.Errorf("%w: sort field %q not allowed", ErrInvalidPageParam, part)
+		}
+		fields = append(fields, SortField{Field: column, Desc: desc})
+	}
+	return fields, nil
+}
+
+// Cursor 游标分页参数。游标为服务端下发的不透明字符串（上一页最后一条记录的排序键）
+type Cursor struct {
+	// After 解码后的游标值，首页为 nil
+	After map[string]any
+	Size  int
+	Sort  []SortField
+}
+
+// ParseCursor 解析 cursor、size、sort 查询参数
+func ParseCursor(r *http.Request, o *PageOptions) (Cursor, error) {
+	opts := o.withDefaults()
+	q := r.URL.Query()
+
+	size, err := parseSize(q.Get("size"), opts)
+	if err != nil {
+		return Cursor{}, err
+	}
+	sort, err := parseSort(q.Get("sort"), opts)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	c := Cursor{Size: size, Sort: sort}
+	if s := q.Get("cursor"); s != "" {
+		if c.After, err = DecodeCursor(s); err != nil {
+			return Cursor{}, err
+		}
+	}
+	return c, nil
+}
+
+// OrderBy 返回 ORDER BY 片段
+func (c Cursor) OrderBy() string {
+	return orderBy(c.Sort)
+}
+
+// Limit 多取一条用于判断是否还有下一页
+func (c Cursor) Limit() string {
+	return fmt.Sprintf("LIMIT %d", c.Size+1)
+}
+
+// EncodeCursor 将排序键编码为游标
+func EncodeCursor(values map[string]any) string {
+	data, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解码游标
+func DecodeCursor(s string) (map[string]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor", ErrInvalidPageParam)
+	}
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("%w: cursor", ErrInvalidPageParam)
+	}
+	return values, nil
+}
@@ -0,0 +1,66 @@
+package httpserver
+
+import (
+	"errors"
+	"math"
+	"net/http/httptest"
+	"testing"
+)
+
+var pageOpts = &PageOptions{
+	MaxSize:     50,
+	SortFields:  map[string]string{"created": "created_at", "name": "name"},
+	DefaultSort: "-created",
+}
+
+func TestParsePage(t *testing.T) {
+	r := httptest.NewRequest("GET", "/users?page=3&size=500&sort=name,-created", nil)
+	p, err := ParsePage(r, pageOpts)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if p.Size != 50 || p.Offset() != 100 {
+		t.Fatalf("unexpected page %+v", p)
+	}
+	if got := p.OrderBy(); got != "ORDER BY name ASC, created_at DESC" {
+		t.Fatalf("unexpected order by %q", got)
+	}
+	if got := p.Limit(); got != "LIMIT 50 OFFSET 100" {
+		t.Fatalf("unexpected limit %q", got)
+	}
+
+	p, _ = ParsePage(httptest.NewRequest("GET", "/users", nil), pageOpts)
+	if p.Page != 1 || p.Size != 20 || p.OrderBy() != "ORDER BY created_at DESC" {
+		t.Fatalf("unexpected defaults %+v", p)
+	}
+
+	for _, q := range []string{"page=0", "page=10001", "page=9223372036854775807", "size=-1", "sort=password", "sort=name%3Bdrop%20table"} {
+		if _, err := ParsePage(httptest.NewRequest("GET", "/users?"+q, nil), pageOpts); !errors.Is(err, ErrInvalidPageParam) {
+			t.Errorf("%s: expected ErrInvalidPageParam, got %v", q, err)
+		}
+	}
+
+	p, err = ParsePage(httptest.NewRequest("GET", "/users?page=200", nil), &PageOptions{MaxPage: 100})
+	if !errors.Is(err, ErrInvalidPageParam) {
+		t.Fatalf("expected MaxPage to be enforced, got %+v %v", p, err)
+	}
+	if off := (Page{Page: math.MaxInt, Size: 100}).Offset(); off != math.MaxInt {
+		t.Fatalf("expected clamped offset, got %d", off)
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	cursor := EncodeCursor(map[string]any{"id": 42})
+	r := httptest.NewRequest("GET", "/feed?size=10&cursor="+cursor, nil)
+	c, err := ParseCursor(r, pageOpts)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if c.After["id"] != float64(42) || c.Limit() != "LIMIT 11" {
+		t.Fatalf("unexpected cursor %+v", c)
+	}
+
+	if _, err := ParseCursor(httptest.NewRequest("GET", "/feed?cursor=!!", nil), pageOpts); !errors.Is(err, ErrInvalidPageParam) {
+		t.Fatalf("expected ErrInvalidPageParam, got %v", err)
+	}
+}
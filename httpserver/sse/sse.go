@@ -0,0 +1,156 @@
+package sse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var ErrStreamingUnsupported = errors.New("sse: streaming unsupported")
+
+// Event 服务端推送事件
+type Event struct {
+	// ID 事件 ID，客户端重连时通过 Last-Event-ID 请求头带回
+	ID string
+	// Event 事件类型，为空时客户端按 message 处理
+	Event string
+	// Data 事件内容，多行内容会拆分为多个 data 字段
+	Data string
+	// Retry 建议客户端的重连间隔
+	Retry time.Duration
+}
+
+// JSON 将 v 序列化为事件内容
+func JSON(event string, v any) (Event, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Event{}, fmt.Errorf("sse: marshal %s: %w", event, err)
+	}
+	return Event{Event: event, Data: string(data)}, nil
+}
+
+// WriteTo 按 text/event-stream 格式写出事件
+func (e Event) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + oneLine(e.ID) + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + oneLine(e.Event) + "\n")
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", e.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(e.Data, "\r\n", "\n"), "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// LastEventID 返回客户端重连时携带的最后事件 ID，用于补发断线期间的事件
+func LastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	// EventSource polyfill 无法设置请求头时通过查询参数传递
+	return r.URL.Query().Get("lastEventId")
+}
+
+type options struct {
+	heartbeat time.Duration
+	retry     time.Duration
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithHeartbeat 设置心跳间隔，默认 15 秒；心跳为注释行，防止代理因空闲断开连接
+func WithHeartbeat(d time.Duration) Option {
+	return func(o *options) {
+		o.heartbeat = d
+	}
+}
+
+// WithRetry 连接建立时下发客户端重连间隔
+func WithRetry(d time.Duration) Option {
+	return func(o *options) {
+		o.retry = d
+	}
+}
+
+// Stream 将 ch 中的事件推送给客户端，阻塞直到 ch 关闭（返回 nil）或客户端断开（返回 ctx 错误）：
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		ch := subscribe(r.Context(), sse.LastEventID(r))
+//		_ = sse.Stream(w, r, ch)
+//	}
+func Stream(w http.ResponseWriter, r *http.Request, ch <-chan Event, opts ...Option) error {
+	o := &options{heartbeat: 15 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	rc := http.NewResponseController(w)
+	// 长连接不受服务端 WriteTimeout 限制
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	// 关闭 nginx 缓冲
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if o.retry > 0 {
+		if _, err := fmt.Fprintf(w, "retry: %d\n\n", o.retry.Milliseconds()); err != nil {
+			return err
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		if errors.Is(err, http.ErrNotSupported) {
+			return ErrStreamingUnsupported
+		}
+		return err
+	}
+
+	var tick <-chan time.Time
+	if o.heartbeat > 0 {
+		ticker := time.NewTicker(o.heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	ctx := r.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if _, err := ev.WriteTo(w); err != nil {
+				return err
+			}
+		case <-tick:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return err
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return err
+		}
+	}
+}
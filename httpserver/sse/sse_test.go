@@ -0,0 +1,85 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEventFormat(t *testing.T) {
+	var b strings.Builder
+	ev := Event{ID: "7", Event: "update", Data: "line1\nline2", Retry: 3 * time.Second}
+	if _, err := ev.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "id: 7\nevent: update\nretry: 3000\ndata: line1\ndata: line2\n\n"
+	if b.String() != want {
+		t.Fatalf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestStream(t *testing.T) {
+	ch := make(chan Event)
+	result := make(chan error, 1)
+	lastID := make(chan string, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastID <- LastEventID(r)
+		result <- Stream(w, r, ch, WithHeartbeat(10*time.Millisecond))
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	req.Header.Set("Last-Event-ID", "41")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	readLine := func() string {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return strings.TrimSuffix(line, "\n")
+	}
+
+	if line := readLine(); line != ": ping" {
+		t.Fatalf("expected heartbeat, got %q", line)
+	}
+	readLine()
+
+	go func() { ch <- Event{ID: "42", Data: "hello"} }()
+	for line := readLine(); line != "id: 42"; line = readLine() {
+		if line != ": ping" && line != "" {
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+	if line := readLine(); line != "data: hello" {
+		t.Fatalf("unexpected data %q", line)
+	}
+	if id := <-lastID; id != "41" {
+		t.Fatalf("unexpected Last-Event-ID %q", id)
+	}
+
+	cancel()
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("stream did not detect disconnect")
+	}
+}
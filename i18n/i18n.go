@@ -0,0 +1,224 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+
+	"github.com/abs2free/go-kit/ctxmeta"
+)
+
+// message 单条消息，普通消息只有 other；复数消息按 CLDR 类别（zero/one/two/few/many/other）区分
+type message map[string]string
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*m = message{"other": s}
+		return nil
+	}
+	var forms map[string]string
+	if err := json.Unmarshal(data, &forms); err != nil {
+		return err
+	}
+	*m = forms
+	return nil
+}
+
+// Bundle 各语言的消息集合
+//
+// 消息文件为 JSON，文件名即语言标签（zh-CN.json、en.json），值为字符串或复数形式：
+//
+//	{
+//	  "welcome": "你好，{name}",
+//	  "cart.items": {"one": "{count} item", "other": "{count} items"}
+//	}
+type Bundle struct {
+	fallback language.Tag
+
+	mu       sync.RWMutex
+	tags     []language.Tag
+	messages map[language.Tag]map[string]message
+	matcher  language.Matcher
+}
+
+// NewBundle 创建消息集合，fallback 为找不到匹配语言或消息时使用的语言
+func NewBundle(fallback string) *Bundle {
+	tag := language.Make(fallback)
+	b := &Bundle{fallback: tag, messages: make(map[language.Tag]map[string]message)}
+	b.tags = []language.Tag{tag}
+	b.matcher = language.NewMatcher(b.tags)
+	return b
+}
+
+// Add 添加某个语言的消息，已有 ID 会被覆盖
+func (b *Bundle) Add(lang string, msgs map[string]string) error {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return fmt.Errorf("i18n: parse language %q: %w", lang, err)
+	}
+	converted := make(map[string]message, len(msgs))
+	for id, s := range msgs {
+		converted[id] = message{"other": s}
+	}
+	b.add(tag, converted)
+	return nil
+}
+
+func (b *Bundle) add(tag language.Tag, msgs map[string]message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	existing, ok := b.messages[tag]
+	if !ok {
+		existing = make(map[string]message, len(msgs))
+		b.messages[tag] = existing
+		if tag != b.fallback {
+			b.tags = append(b.tags, tag)
+		}
+		// 首个 tag 为匹配失败时的默认值
+		b.matcher = language.NewMatcher(b.tags)
+	}
+	for id, m := range msgs {
+		existing[id] = m
+	}
+}
+
+// LoadFS 加载 fsys 中 dir 目录下的全部 *.json 文件，支持 embed.FS
+//
+//	//go:embed locales/*.json
+//	var locales embed.FS
+//	bundle.LoadFS(locales, "locales")
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: read dir %s: %w", dir, err)
+	}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		lang := strings.TrimSuffix(e.Name(), ".json")
+		tag, err := language.Parse(lang)
+		if err != nil {
+			return fmt.Errorf("i18n: %s: invalid language tag: %w", e.Name(), err)
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("i18n: read %s: %w", e.Name(), err)
+		}
+		var msgs map[string]message
+		if err := json.Unmarshal(data, &msgs); err != nil {
+			return fmt.Errorf("i18n: parse %s: %w", e.Name(), err)
+		}
+		b.add(tag, msgs)
+	}
+	return nil
+}
+
+// LoadDir 加载本地目录下的消息文件
+func (b *Bundle) LoadDir(dir string) error {
+	return b.LoadFS(os.DirFS(dir), ".")
+}
+
+// Match 根据 Accept-Language 或语言标签列表选择最合适的已加载语言
+func (b *Bundle) Match(langs ...string) language.Tag {
+	var prefs []language.Tag
+	for _, l := range langs {
+		tags, _, err := language.ParseAcceptLanguage(l)
+		if err == nil {
+			prefs = append(prefs, tags...)
+		}
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	_, idx, conf := b.matcher.Match(prefs...)
+	if conf == language.No {
+		return b.fallback
+	}
+	return b.tags[idx]
+}
+
+// Localizer 返回指定语言的翻译器，参数同 Match
+func (b *Bundle) Localizer(langs ...string) *Localizer {
+	return &Localizer{bundle: b, tag: b.Match(langs...)}
+}
+
+// FromContext 按 ctxmeta.Locale 选择语言，配合 ctxmeta.HTTPMiddleware 使用
+func (b *Bundle) FromContext(ctx context.Context) *Localizer {
+	return b.Localizer(ctxmeta.Locale(ctx))
+}
+
+// Message 查找未渲染的消息模板，可作为错误码等外部消息表的数据源：
+// 指定语言缺失时回退到 fallback 语言，仍缺失时 ok 为 false
+func (b *Bundle) Message(lang, id string) (string, bool) {
+	m, ok := b.lookup(b.Match(lang), id)
+	if !ok {
+		return "", false
+	}
+	return m["other"], true
+}
+
+func (b *Bundle) lookup(tag language.Tag, id string) (message, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if m, ok := b.messages[tag][id]; ok {
+		return m, true
+	}
+	m, ok := b.messages[b.fallback][id]
+	return m, ok
+}
+
+// Localizer 某个语言的翻译器
+type Localizer struct {
+	bundle *Bundle
+	tag    language.Tag
+}
+
+// Language 返回匹配到的语言
+func (l *Localizer) Language() language.Tag {
+	return l.tag
+}
+
+// T 翻译消息，args 为交替的键值对，替换消息中的 {key}；消息不存在时返回 id
+//
+//	l.T("welcome", "name", user.Name)
+func (l *Localizer) T(id string, args ...any) string {
+	m, ok := l.bundle.lookup(l.tag, id)
+	if !ok {
+		return id
+	}
+	return render(m["other"], args)
+}
+
+// N 翻译复数消息，按 count 与语言的复数规则选择形式，count 可通过 {count} 引用
+func (l *Localizer) N(id string, count int, args ...any) string {
+	m, ok := l.bundle.lookup(l.tag, id)
+	if !ok {
+		return id
+	}
+	s, ok := m[pluralCategory(l.tag, count)]
+	if !ok {
+		s = m["other"]
+	}
+	return render(s, append([]any{"count", count}, args...))
+}
+
+func render(s string, args []any) string {
+	if len(args) < 2 || !strings.Contains(s, "{") {
+		return s
+	}
+	pairs := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		pairs = append(pairs, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
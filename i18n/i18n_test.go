@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"context"
+	"embed"
+	"testing"
+
+	"github.com/abs2free/go-kit/ctxmeta"
+)
+
+//go:embed testdata/*.json
+var testdata embed.FS
+
+func newTestBundle(t *testing.T) *Bundle {
+	t.Helper()
+	b := NewBundle("en")
+	if err := b.LoadFS(testdata, "testdata"); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestMatch(t *testing.T) {
+	b := newTestBundle(t)
+	cases := map[string]string{
+		"zh-CN,zh;q=0.9,en;q=0.8": "zh-CN",
+		"zh":                      "zh-CN",
+		"ru-RU":                   "ru",
+		"de-DE":                   "en",
+		"":                        "en",
+	}
+	for accept, want := range cases {
+		if got := b.Match(accept).String(); got != want {
+			t.Errorf("Match(%q) = %s, want %s", accept, got, want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	b := newTestBundle(t)
+
+	zh := b.Localizer("zh-CN")
+	if got := zh.T("welcome", "name", "小明"); got != "你好，小明" {
+		t.Fatalf("zh welcome: %q", got)
+	}
+	if got := zh.N("cart.items", 1); got != "购物车中有 1 件商品" {
+		t.Fatalf("zh plural: %q", got)
+	}
+	if got := zh.T("only.en"); got != "English only" {
+		t.Fatalf("fallback: %q", got)
+	}
+	if got := zh.T("missing.id"); got != "missing.id" {
+		t.Fatalf("missing: %q", got)
+	}
+
+	en := b.Localizer("en-US")
+	if got := en.N("cart.items", 1); got != "1 item in cart" {
+		t.Fatalf("en one: %q", got)
+	}
+	if got := en.N("cart.items", 3); got != "3 items in cart" {
+		t.Fatalf("en other: %q", got)
+	}
+
+	ru := b.Localizer("ru")
+	for n, want := range map[int]string{1: "1 товар", 3: "3 товара", 5: "5 товаров", 21: "21 товар", 12: "12 товаров"} {
+		if got := ru.N("cart.items", n); got != want {
+			t.Errorf("ru N(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestContextAndMessage(t *testing.T) {
+	b := newTestBundle(t)
+	if err := b.Add("ja", map[string]string{"welcome": "こんにちは、{name}"}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := ctxmeta.WithLocale(context.Background(), "ja-JP")
+	if got := b.FromContext(ctx).T("welcome", "name", "Taro"); got != "こんにちは、Taro" {
+		t.Fatalf("context localizer: %q", got)
+	}
+
+	if msg, ok := b.Message("zh-CN", "welcome"); !ok || msg != "你好，{name}" {
+		t.Fatalf("message lookup: %q %v", msg, ok)
+	}
+	if _, ok := b.Message("zh-CN", "nope"); ok {
+		t.Fatal("expected missing message")
+	}
+}
@@ -0,0 +1,51 @@
+package i18n
+
+import "golang.org/x/text/language"
+
+// pluralCategory 返回整数 n 的 CLDR 复数类别，覆盖常用语言，其余按英语规则处理
+func pluralCategory(tag language.Tag, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	base, _ := tag.Base()
+	switch base.String() {
+	case "zh", "ja", "ko", "vi", "th", "id", "ms":
+		// 无复数变化
+		return "other"
+	case "fr", "pt":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+	case "ru", "uk":
+		mod10, mod100 := n%10, n%100
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "ar":
+		mod100 := n % 100
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case mod100 >= 3 && mod100 <= 10:
+			return "few"
+		case mod100 >= 11:
+			return "many"
+		default:
+			return "other"
+		}
+	}
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
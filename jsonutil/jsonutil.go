@@ -0,0 +1,100 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// MustMarshal 序列化失败时 panic，用于确定可序列化的常量或测试数据
+func MustMarshal(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("jsonutil: marshal %T: %v", v, err))
+	}
+	return data
+}
+
+// MarshalString 序列化为字符串，失败时返回空字符串，适合日志输出
+func MarshalString(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Unmarshal 解析 JSON，数字解析为 json.Number 而非 float64，避免大整数与金额丢失精度
+func Unmarshal(data []byte, v any) error {
+	return Decode(bytes.NewReader(data), v)
+}
+
+// Decode 从 r 解析 JSON，行为同 Unmarshal
+func Decode(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("jsonutil: decode: %w", err)
+	}
+	return nil
+}
+
+// ArrayEncoder 流式写出 JSON 数组，逐个元素编码，适合导出大量数据时避免整体驻留内存：
+//
+//	enc := jsonutil.NewArrayEncoder(w)
+//	for rows.Next() { enc.Encode(row) }
+//	enc.Close()
+type ArrayEncoder struct {
+	w      io.Writer
+	count  int
+	closed bool
+}
+
+// NewArrayEncoder 创建数组编码器
+func NewArrayEncoder(w io.Writer) *ArrayEncoder {
+	return &ArrayEncoder{w: w}
+}
+
+// Encode 写出一个元素
+func (e *ArrayEncoder) Encode(v any) error {
+	if e.closed {
+		return fmt.Errorf("jsonutil: encoder closed")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("jsonutil: marshal %T: %w", v, err)
+	}
+
+	prefix := ","
+	if e.count == 0 {
+		prefix = "["
+	}
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	e.count++
+	return nil
+}
+
+// Count 返回已写出的元素数
+func (e *ArrayEncoder) Count() int {
+	return e.count
+}
+
+// Close 写出数组结尾，未写入任何元素时输出 []
+func (e *ArrayEncoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	end := "]"
+	if e.count == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
@@ -0,0 +1,115 @@
+package jsonutil
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+type upstream struct {
+	ID      String  `json:"id"`
+	Count   Int     `json:"count"`
+	Price   Float   `json:"price"`
+	Active  Bool    `json:"active"`
+	Amount  Decimal `json:"amount"`
+	Created Time    `json:"created"`
+}
+
+func TestTolerantTypes(t *testing.T) {
+	inputs := []string{
+		`{"id": 123, "count": "42", "price": "9.5", "active": 1, "amount": 0.1000000000000000055, "created": "2024-05-01 12:00:00"}`,
+		`{"id": "123", "count": 42.0, "price": 9.5, "active": "true", "amount": "0.1000000000000000055", "created": 1714536000}`,
+	}
+	for _, in := range inputs {
+		var u upstream
+		if err := json.Unmarshal([]byte(in), &u); err != nil {
+			t.Fatalf("unmarshal %s: %v", in, err)
+		}
+		if u.ID != "123" || u.Count != 42 || u.Price != 9.5 || !bool(u.Active) {
+			t.Fatalf("unexpected value %+v", u)
+		}
+		if u.Amount != "0.1000000000000000055" {
+			t.Fatalf("decimal precision lost: %s", u.Amount)
+		}
+		if u.Created.Year() != 2024 {
+			t.Fatalf("unexpected time %v", u.Created)
+		}
+	}
+
+	var u upstream
+	if err := json.Unmarshal([]byte(`{"count": "abc"}`), &u); err == nil {
+		t.Fatalf("expected error for invalid int")
+	}
+
+	out := MarshalString(struct {
+		Amount Decimal `json:"amount"`
+		When   Time    `json:"when"`
+	}{Amount: "12.30"})
+	if out != `{"amount":12.30,"when":null}` {
+		t.Fatalf("unexpected marshal %s", out)
+	}
+}
+
+func TestDecimalStrictGrammar(t *testing.T) {
+	for _, in := range []string{`"NaN"`, `"Inf"`, `"-Infinity"`, `"0x1p-2"`, `"+1"`, `".5"`, `"1."`, `"01"`, `"1_000"`} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(in), &d); err == nil {
+			t.Errorf("%s: expected error, got %s", in, d)
+		}
+	}
+	for _, in := range []string{`-0.5`, `"12.30"`, `1e400`, `"2E-3"`, `0`} {
+		var d Decimal
+		if err := json.Unmarshal([]byte(in), &d); err != nil {
+			t.Errorf("%s: %v", in, err)
+		}
+	}
+	if _, err := json.Marshal(Decimal("NaN")); err == nil {
+		t.Fatal("expected marshal error for NaN")
+	}
+}
+
+func TestParseTimeMillis(t *testing.T) {
+	got, err := ParseTime("1714536000123")
+	if err != nil || got.UnixMilli() != 1714536000123 {
+		t.Fatalf("unexpected %v %v", got, err)
+	}
+	got, err = ParseTime("20240501")
+	if err != nil {
+		t.Fatalf("parse date: %v", err)
+	}
+	if got.Month() != time.May {
+		t.Fatalf("unexpected %v", got)
+	}
+}
+
+func TestUnmarshalUseNumber(t *testing.T) {
+	var m map[string]any
+	if err := Unmarshal([]byte(`{"id": 9007199254740993}`), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["id"].(json.Number).String() != "9007199254740993" {
+		t.Fatalf("big int precision lost: %v", m["id"])
+	}
+}
+
+func TestArrayEncoder(t *testing.T) {
+	var b strings.Builder
+	enc := NewArrayEncoder(&b)
+	_ = enc.Close()
+	if b.String() != "[]" {
+		t.Fatalf("unexpected empty array %q", b.String())
+	}
+
+	b.Reset()
+	enc = NewArrayEncoder(&b)
+	for i := 0; i < 3; i++ {
+		if err := enc.Encode(map[string]int{"n": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = enc.Close()
+	if b.String() != `[{"n":0},{"n":1},{"n":2}]` {
+		t.Fatalf("unexpected array %q", b.String())
+	}
+}
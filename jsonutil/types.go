@@ -0,0 +1,193 @@
+package jsonutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unquote 去掉字符串两侧引号；null 与空字符串返回空
+func unquote(data []byte) (string, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(s), nil
+	}
+	return string(data), nil
+}
+
+// Int 兼容数字与数字字符串（"123"）的整数，null 与 "" 解析为 0
+type Int int64
+
+func (i *Int) UnmarshalJSON(data []byte) error {
+	s, err := unquote(data)
+	if err != nil || s == "" {
+		*i = 0
+		return err
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// 上游偶尔返回 "12.0" 这类整数值浮点
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return fmt.Errorf("jsonutil: invalid int %s", data)
+		}
+		n = int64(f)
+	}
+	*i = Int(n)
+	return nil
+}
+
+// Float 兼容数字与数字字符串的浮点数
+type Float float64
+
+func (f *Float) UnmarshalJSON(data []byte) error {
+	s, err := unquote(data)
+	if err != nil || s == "" {
+		*f = 0
+		return err
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("jsonutil: invalid float %s", data)
+	}
+	*f = Float(v)
+	return nil
+}
+
+// String 兼容数字、布尔与字符串，统一转为字符串（如 ID 字段时而为数字时而为字符串）
+type String string
+
+func (s *String) UnmarshalJSON(data []byte) error {
+	v, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	*s = String(v)
+	return nil
+}
+
+// Bool 兼容 true/false、1/0、"true"/"1"/"yes" 等写法
+type Bool bool
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	s, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	switch strings.ToLower(s) {
+	case "", "0", "false", "no", "off", "n":
+		*b = false
+	case "1", "true", "yes", "on", "y":
+		*b = true
+	default:
+		return fmt.Errorf("jsonutil: invalid bool %s", data)
+	}
+	return nil
+}
+
+// TimeFormats Time 解析时依次尝试的格式
+var TimeFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.000",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+	"20060102150405",
+	"20060102",
+}
+
+// TimeLayout Time 序列化格式
+var TimeLayout = time.RFC3339
+
+// Time 兼容多种格式与 Unix 时间戳（秒或毫秒，数字或字符串）的时间；
+// 不带时区的格式按本地时区解析
+type Time struct {
+	time.Time
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	s, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	if s == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := ParseTime(s)
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+	return nil
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.Format(TimeLayout) + `"`), nil
+}
+
+// ParseTime 按 TimeFormats 解析时间，纯数字视为 Unix 时间戳（超过 1e12 视为毫秒）
+func ParseTime(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && len(s) != 8 && len(s) != 14 {
+		if n > 1e12 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	for _, layout := range TimeFormats {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("jsonutil: invalid time %q", s)
+}
+
+// Decimal 保留原始字面量的十进制数，避免金额等字段经 float64 转换丢失精度；
+// 兼容数字与字符串输入，序列化为数字。只接受 JSON 数字语法，NaN、Inf、十六进制等会被拒绝
+type Decimal string
+
+// decimalRe JSON 数字语法（RFC 8259 第 6 节）
+var decimalRe = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s, err := unquote(data)
+	if err != nil {
+		return err
+	}
+	if s != "" && !decimalRe.MatchString(s) {
+		return fmt.Errorf("jsonutil: invalid decimal %s", data)
+	}
+	*d = Decimal(s)
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	if !decimalRe.MatchString(string(d)) {
+		return nil, fmt.Errorf("jsonutil: invalid decimal %q", string(d))
+	}
+	return []byte(d), nil
+}
+
+// String 返回原始字面量
+func (d Decimal) String() string {
+	return string(d)
+}
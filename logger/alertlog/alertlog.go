@@ -0,0 +1,309 @@
+// Package alertlog 将 Error 及以上级别的日志汇总后通过 notify 渠道（钉钉、企业微信、飞书、Slack）告警
+package alertlog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/abs2free/go-kit/logger"
+	"github.com/abs2free/go-kit/notify"
+)
+
+// Item 汇总中的一类日志（相同 logger 名与消息）
+type Item struct {
+	Level   string
+	Logger  string
+	Message string
+	Caller  string
+	// Error 首条日志的 error 字段
+	Error string
+	Count int
+	First time.Time
+}
+
+// Summary 一个发送周期内的告警汇总，作为模板数据
+type Summary struct {
+	App   string
+	Host  string
+	Total int
+	Items []Item
+	// Omitted 超过 WithMaxItems 未列出的类别数
+	Omitted int
+	Since   time.Time
+	Until   time.Time
+}
+
+// DefaultTemplate 默认告警模板（Markdown），钉钉、企业微信、飞书、Slack 均按各自格式渲染
+var DefaultTemplate = notify.MustTemplate(
+	`[{{.App}}] {{.Total}} error logs`,
+	`**host**: {{.Host}}
+**period**: {{.Since.Format "15:04:05"}} - {{.Until.Format "15:04:05"}}
+{{range .Items}}
+- **{{.Count}}×** [{{.Level}}] {{if .Logger}}{{.Logger}}: {{end}}{{.Message}}{{if .Error}} — {{.Error}}{{end}}{{if .Caller}} ({{.Caller}}){{end}}{{end}}
+{{if .Omitted}}
+… and {{.Omitted}} more{{end}}`,
+	notify.FormatMarkdown,
+)
+
+type options struct {
+	level    zapcore.Level
+	interval time.Duration
+	maxItems int
+	app      string
+	template *notify.Template
+}
+
+// Option 告警选项
+type Option func(*options)
+
+// WithLevel 设置触发告警的最低级别，默认 Error
+func WithLevel(level zapcore.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithInterval 设置两次告警的最小间隔，期间的日志合并为一条汇总，默认 1 分钟
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithMaxItems 设置单条汇总最多列出的日志类别数，默认 10
+func WithMaxItems(n int) Option {
+	return func(o *options) {
+		o.maxItems = n
+	}
+}
+
+// WithApp 设置标题中的服务名，默认为进程名
+func WithApp(app string) Option {
+	return func(o *options) {
+		o.app = app
+	}
+}
+
+// WithTemplate 自定义告警模板，模板数据为 Summary
+func WithTemplate(t *notify.Template) Option {
+	return func(o *options) {
+		o.template = t
+	}
+}
+
+// New 创建告警 core，与其他 core 并列使用，不影响原有输出。
+// 空闲时第一条错误立即发送，之后每个周期最多发送一条汇总，相同消息合并计数；Panic、Fatal 不受限制立即发送：
+//
+//	log, _ := logger.NewWithCore(
+//		logger.WithFileCore(),
+//		alertlog.New(notify.NewDingTalk(webhook, notify.WithSecret(secret)), alertlog.WithApp("order")),
+//	)
+//
+// 企业微信、飞书、Slack 分别使用 notify.NewWeCom、notify.NewFeishu、notify.NewSlackWebhook；
+// 发送失败时输出到 stderr，不会重试
+func New(n notify.Notifier, opts ...Option) logger.CoreBuilder {
+	return func(core *zapcore.Core) {
+		o := &options{
+			level:    zapcore.ErrorLevel,
+			interval: time.Minute,
+			maxItems: 10,
+			app:      filepath.Base(os.Args[0]),
+			template: DefaultTemplate,
+		}
+		for _, opt := range opts {
+			opt(o)
+		}
+		a := newAlerter(n, o)
+		logger.WithCore("alert", func(level zapcore.LevelEnabler) zapcore.Core {
+			return &alertCore{LevelEnabler: level, alerter: a}
+		}, logger.WithLogLevel(o.level))(core)
+	}
+}
+
+// alertCore 将条目交给 alerter 汇总
+type alertCore struct {
+	zapcore.LevelEnabler
+	alerter *alerter
+	fields  []zapcore.Field
+}
+
+func (c *alertCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *alertCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *alertCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	item := Item{
+		Level:   ent.Level.CapitalString(),
+		Logger:  ent.LoggerName,
+		Message: ent.Message,
+		First:   ent.Time,
+	}
+	if ent.Caller.Defined {
+		item.Caller = ent.Caller.TrimmedPath()
+	}
+	for _, fs := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range fs {
+			if err, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+				item.Error = err.Error()
+			}
+		}
+	}
+	c.alerter.add(item)
+	// Panic、Fatal 之后进程可能退出，立即发送
+	if ent.Level >= zapcore.PanicLevel {
+		c.alerter.flush()
+	}
+	return nil
+}
+
+func (c *alertCore) Sync() error {
+	return nil
+}
+
+// Close 随 Logger.Close 发送剩余汇总
+func (c *alertCore) Close() error {
+	return c.alerter.Close()
+}
+
+// alerter 按周期汇总并发送告警
+type alerter struct {
+	notifier notify.Notifier
+	opts     *options
+	host     string
+
+	mu      sync.Mutex
+	items   map[[2]string]*Item
+	order   []*Item
+	total   int
+	since   time.Time
+	last    time.Time
+	closed  bool
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func newAlerter(n notify.Notifier, o *options) *alerter {
+	host, _ := os.Hostname()
+	a := &alerter{
+		notifier: n,
+		opts:     o,
+		host:     host,
+		items:    make(map[[2]string]*Item),
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go a.run()
+	return a
+}
+
+func (a *alerter) add(item Item) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.closed {
+		return
+	}
+	key := [2]string{item.Logger, item.Message}
+	if it, ok := a.items[key]; ok {
+		it.Count++
+	} else {
+		item.Count = 1
+		a.items[key] = &item
+		a.order = append(a.order, &item)
+	}
+	if a.total == 0 {
+		a.since = item.First
+	}
+	a.total++
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (a *alerter) run() {
+	defer close(a.stopped)
+	for {
+		select {
+		case <-a.wake:
+		case <-a.done:
+			return
+		}
+		a.mu.Lock()
+		wait := a.opts.interval - time.Since(a.last)
+		a.mu.Unlock()
+		if wait > 0 {
+			// 周期内的后续日志继续累积
+			select {
+			case <-time.After(wait):
+			case <-a.done:
+				return
+			}
+		}
+		a.flush()
+	}
+}
+
+func (a *alerter) flush() {
+	a.mu.Lock()
+	if a.total == 0 {
+		a.mu.Unlock()
+		return
+	}
+	summary := Summary{App: a.opts.app, Host: a.host, Total: a.total, Since: a.since, Until: time.Now()}
+	items := a.order
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Count > items[j].Count })
+	if len(items) > a.opts.maxItems {
+		summary.Omitted = len(items) - a.opts.maxItems
+		items = items[:a.opts.maxItems]
+	}
+	for _, it := range items {
+		summary.Items = append(summary.Items, *it)
+	}
+	a.items = make(map[[2]string]*Item)
+	a.order, a.total = nil, 0
+	a.last = time.Now()
+	a.mu.Unlock()
+
+	msg, err := a.opts.template.Render(summary)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = a.notifier.Send(ctx, msg)
+		cancel()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s logger: alert via %s: %v\n", time.Now().Format(time.RFC3339), a.notifier.Name(), err)
+	}
+}
+
+// Close 立即发送未发出的汇总并停止后台协程
+func (a *alerter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.done)
+	a.mu.Unlock()
+	<-a.stopped
+	a.flush()
+	return nil
+}
@@ -0,0 +1,68 @@
+package alertlog
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/logger"
+	"github.com/abs2free/go-kit/notify"
+)
+
+type fakeNotifier struct {
+	mu   sync.Mutex
+	msgs []notify.Message
+}
+
+func (n *fakeNotifier) Name() string { return "fake" }
+
+func (n *fakeNotifier) Send(ctx context.Context, msg notify.Message) error {
+	n.mu.Lock()
+	n.msgs = append(n.msgs, msg)
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *fakeNotifier) sent() []notify.Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]notify.Message(nil), n.msgs...)
+}
+
+func TestNew(t *testing.T) {
+	n := &fakeNotifier{}
+	log, err := logger.NewWithCore(New(n, WithApp("order"), WithInterval(time.Hour), WithMaxItems(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Warn("ignored")
+	log.Errorw("charge failed", zap.Error(errors.New("timeout")))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(n.sent()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	// 周期内的错误合并，Close 时发送汇总
+	for i := 0; i < 3; i++ {
+		log.Error("db down")
+	}
+	log.Error("cache down")
+	_ = log.Close()
+
+	msgs := n.sent()
+	if len(msgs) != 2 {
+		t.Fatalf("expected 2 alerts, got %d: %+v", len(msgs), msgs)
+	}
+	if msgs[0].Title != "[order] 1 error logs" || !strings.Contains(msgs[0].Content, "charge failed — timeout") {
+		t.Fatalf("unexpected first alert %+v", msgs[0])
+	}
+	if msgs[1].Title != "[order] 4 error logs" || !strings.Contains(msgs[1].Content, "**3×** [ERROR] db down") ||
+		!strings.Contains(msgs[1].Content, "and 1 more") || msgs[1].Format != notify.FormatMarkdown {
+		t.Fatalf("unexpected summary %+v", msgs[1])
+	}
+}
@@ -0,0 +1,445 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// 归档文件的压缩格式
+const (
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// ErrArchiveKey 归档加密密钥长度不是 16、24 或 32 字节
+var ErrArchiveKey = errors.New("logger: archive key must be 16, 24 or 32 bytes")
+
+// ArchiveConfig 轮转后归档文件的处理
+type ArchiveConfig struct {
+	// Compression 压缩格式，gzip 或 zstd，为空时不压缩
+	Compression string
+	// Key AES 密钥，为空时不加密
+	Key []byte
+}
+
+// WithArchive 轮转出的文件先按 compression 压缩（zstd 的压缩率与速度均明显优于 lumberjack 的 gzip），
+// key 不为空时再以 AES-GCM 加密，文件名依次追加 .zst / .gz 与 .enc，MaxAge、MaxBackups 按处理后的文件计算。
+// 处理在后台进行，失败时保留原文件并输出到 stderr。compression 与 key 均为空时不做处理。加密的归档文件用 OpenArchive 读取：
+//
+//	logger.WithFileCore(logger.WithArchive(logger.CompressionZstd, key))
+func WithArchive(compression string, key []byte) Option {
+	return func(cfg *LoggerConfig) {
+		if compression == "" && len(key) == 0 {
+			cfg.Archive = nil
+			return
+		}
+		cfg.Archive = &ArchiveConfig{Compression: compression, Key: key}
+	}
+}
+
+// validate 校验配置，不压缩也不加密时处理后的文件名与原文件相同，会覆盖后删除备份，因此同样拒绝
+func (c *ArchiveConfig) validate() error {
+	if c.Compression == "" && len(c.Key) == 0 {
+		return errors.New("logger: archive requires compression or key")
+	}
+	switch c.Compression {
+	case "", CompressionGzip, CompressionZstd:
+	default:
+		return fmt.Errorf("logger: unknown compression %q", c.Compression)
+	}
+	if len(c.Key) > 0 {
+		if _, err := aes.NewCipher(c.Key); err != nil {
+			return ErrArchiveKey
+		}
+	}
+	return nil
+}
+
+// ext 处理后追加的扩展名
+func (c *ArchiveConfig) ext() string {
+	var ext string
+	switch c.Compression {
+	case CompressionGzip:
+		ext = ".gz"
+	case CompressionZstd:
+		ext = ".zst"
+	}
+	if len(c.Key) > 0 {
+		ext += ".enc"
+	}
+	return ext
+}
+
+// lumberjackBackupLayout lumberjack 备份文件名中的时间格式，如 app-2024-05-01T10-00-00.000.log
+const lumberjackBackupLayout = "2006-01-02T15-04-05.000"
+
+// archiver 处理 lumberjack 按大小轮转出的备份文件
+type archiver struct {
+	cfg  *ArchiveConfig
+	perm FilePerm
+	// retain 是否按 maxAge、maxBackups 清理处理后的文件，按时间轮转时由 timeRotator 清理
+	retain     bool
+	maxAge     int
+	maxBackups int
+	localTime  bool
+
+	mu sync.Mutex
+}
+
+// sweep 处理 filename 的所有未处理备份，包括上次进程退出前未完成的
+func (a *archiver) sweep(filename string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ext := filepath.Ext(filename)
+	prefix := strings.TrimSuffix(filepath.Base(filename), ext) + "-"
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		return
+	}
+	type backup struct {
+		path string
+		t    time.Time
+	}
+	var done []backup
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(name, prefix)
+		if len(rest) < len(lumberjackBackupLayout) || !strings.HasPrefix(rest[len(lumberjackBackupLayout):], ext) {
+			continue
+		}
+		t, err := time.ParseInLocation(lumberjackBackupLayout, rest[:len(lumberjackBackupLayout)], a.location())
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(filepath.Dir(filename), name)
+		switch rest[len(lumberjackBackupLayout):] {
+		case ext:
+			if err := archiveFile(path, a.cfg, a.perm); err != nil {
+				fmt.Fprintf(os.Stderr, "logger: archive %s: %v\n", path, err)
+				continue
+			}
+			path += a.cfg.ext()
+		case ext + a.cfg.ext():
+		default:
+			continue
+		}
+		done = append(done, backup{path: path, t: t})
+	}
+	if !a.retain {
+		return
+	}
+
+	sort.Slice(done, func(i, j int) bool { return done[i].t.After(done[j].t) })
+	cutoff := time.Now().Add(-time.Duration(a.maxAge) * 24 * time.Hour)
+	for i, b := range done {
+		expired := a.maxAge > 0 && b.t.Before(cutoff)
+		overflow := a.maxBackups > 0 && i >= a.maxBackups
+		if expired || overflow {
+			_ = os.Remove(b.path)
+		}
+	}
+}
+
+func (a *archiver) location() *time.Location {
+	if a.localTime {
+		return time.Local
+	}
+	return time.UTC
+}
+
+// archiveFile 将 path 压缩、加密为 path+cfg.ext() 并删除原文件，权限与原文件一致；
+// 先写临时文件，失败时不留下不完整的归档
+func archiveFile(path string, cfg *ArchiveConfig, perm FilePerm) (err error) {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := path + cfg.ext()
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(dstPath)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if err := copyPerm(tmp.Name(), src, perm); err != nil {
+		return err
+	}
+
+	buf := bufio.NewWriter(tmp)
+	var w io.Writer = buf
+	var closers []io.Closer
+	if len(cfg.Key) > 0 {
+		ew, err := newEncryptWriter(w, cfg.Key)
+		if err != nil {
+			return err
+		}
+		w = ew
+		closers = append(closers, ew)
+	}
+	switch cfg.Compression {
+	case CompressionGzip:
+		zw := gzip.NewWriter(w)
+		w = zw
+		closers = append(closers, zw)
+	case CompressionZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		w = zw
+		closers = append(closers, zw)
+	}
+	if _, err := io.Copy(w, src); err != nil {
+		return err
+	}
+	// 由外向内关闭：先刷出压缩数据，再写入最后一个加密块
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			return err
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), dstPath); err != nil {
+		return err
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+// OpenArchive 打开 WithArchive 处理后的文件，按扩展名解密、解压，返回原始日志内容：
+//
+//	r, err := logger.OpenArchive("logs/app-2024-05-01T10-00-00.000.log.zst.enc", key)
+func OpenArchive(path string, key []byte) (io.ReadCloser, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = bufio.NewReader(f)
+	closers := []func(){func() { _ = f.Close() }}
+	name := path
+	if strings.HasSuffix(name, ".enc") {
+		dr, err := newDecryptReader(r, key)
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		r = dr
+		name = strings.TrimSuffix(name, ".enc")
+	}
+	switch filepath.Ext(name) {
+	case ".gz":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("logger: open archive: %w", err)
+		}
+		r = zr
+	case ".zst":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("logger: open archive: %w", err)
+		}
+		r = zr
+		closers = append(closers, zr.Close)
+	}
+	return &archiveReader{Reader: r, closers: closers}, nil
+}
+
+type archiveReader struct {
+	io.Reader
+	closers []func()
+}
+
+func (r *archiveReader) Close() error {
+	for i := len(r.closers) - 1; i >= 0; i-- {
+		r.closers[i]()
+	}
+	return nil
+}
+
+// 加密格式：4 字节标识与 7 字节随机 nonce 前缀，之后为若干数据块，每块为 4 字节密文长度与 AES-GCM 密文。
+// 第 i 块的 nonce 为前缀、4 字节序号与 1 字节结束标记，最后一块标记为 1，截断或调换顺序均无法通过校验
+const (
+	encMagic     = "GKE1"
+	encPrefixLen = 7
+	encChunkSize = 64 << 10
+)
+
+// ErrArchiveCorrupted 加密归档被截断、篡改或密钥错误
+var ErrArchiveCorrupted = errors.New("logger: archive is corrupted or the key is wrong")
+
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix [encPrefixLen]byte
+	seq    uint32
+	buf    []byte
+}
+
+func newEncryptWriter(w io.Writer, key []byte) (*encryptWriter, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	ew := &encryptWriter{w: w, aead: aead, buf: make([]byte, 0, encChunkSize)}
+	if _, err := rand.Read(ew.prefix[:]); err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, encMagic); err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(ew.prefix[:]); err != nil {
+		return nil, err
+	}
+	return ew, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrArchiveKey
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, seq uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[encPrefixLen:], seq)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func (w *encryptWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// 缓冲区满且还有数据时才写出，保证最后一块由 Close 写出
+		if len(w.buf) == encChunkSize {
+			if err := w.flush(false); err != nil {
+				return n - len(p), err
+			}
+		}
+		m := copy(w.buf[len(w.buf):encChunkSize], p)
+		w.buf = w.buf[:len(w.buf)+m]
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func (w *encryptWriter) flush(last bool) error {
+	sealed := w.aead.Seal(nil, chunkNonce(w.prefix[:], w.seq, last), w.buf, nil)
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(sealed)))
+	if _, err := w.w.Write(size[:]); err != nil {
+		return err
+	}
+	if _, err := w.w.Write(sealed); err != nil {
+		return err
+	}
+	w.seq++
+	w.buf = w.buf[:0]
+	return nil
+}
+
+// Close 写出最后一块
+func (w *encryptWriter) Close() error {
+	return w.flush(true)
+}
+
+type decryptReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	seq    uint32
+	buf    []byte
+	done   bool
+}
+
+func newDecryptReader(r io.Reader, key []byte) (*decryptReader, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(encMagic)+encPrefixLen)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(encMagic)]) != encMagic {
+		return nil, ErrArchiveCorrupted
+	}
+	return &decryptReader{r: r, aead: aead, prefix: header[len(encMagic):]}, nil
+}
+
+func (r *decryptReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// next 读取并解密下一块，先按普通块尝试，失败时按最后一块尝试
+func (r *decryptReader) next() error {
+	var size [4]byte
+	if _, err := io.ReadFull(r.r, size[:]); err != nil {
+		return ErrArchiveCorrupted
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > encChunkSize+uint32(r.aead.Overhead()) {
+		return ErrArchiveCorrupted
+	}
+	sealed := make([]byte, n)
+	if _, err := io.ReadFull(r.r, sealed); err != nil {
+		return ErrArchiveCorrupted
+	}
+	plain, err := r.aead.Open(nil, chunkNonce(r.prefix, r.seq, false), sealed, nil)
+	if err != nil {
+		plain, err = r.aead.Open(nil, chunkNonce(r.prefix, r.seq, true), sealed, nil)
+		if err != nil {
+			return ErrArchiveCorrupted
+		}
+		r.done = true
+	}
+	r.seq++
+	r.buf = plain
+	return nil
+}
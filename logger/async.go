@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// AsyncWriteConfig 异步缓冲写配置
+type AsyncWriteConfig struct {
+	// BufferSize 缓冲区字节数，写满时同步刷出，默认 256KB
+	BufferSize int
+	// FlushInterval 定时刷出间隔，默认 30s
+	FlushInterval time.Duration
+}
+
+// WithAsyncWrite 为 core 的输出加上内存缓冲，由后台定时或缓冲区写满时批量写入，减少热路径上的磁盘 IO。
+// Sync、Logger.Close 以及 Fatal/Panic 前都会刷出缓冲区；进程被 SIGKILL 等强制结束时最多丢失一个刷新周期的日志：
+//
+//	logger.WithFileCore(logger.WithAsyncWrite(512<<10, time.Second))
+func WithAsyncWrite(bufferSize int, flushInterval time.Duration) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.AsyncWrite = &AsyncWriteConfig{BufferSize: bufferSize, FlushInterval: flushInterval}
+	}
+}
+
+// asyncWriter 缓冲写，Close 时先刷出缓冲区再关闭底层输出
+type asyncWriter struct {
+	*zapcore.BufferedWriteSyncer
+}
+
+func newAsyncWriter(ws zapcore.WriteSyncer, cfg *AsyncWriteConfig) *asyncWriter {
+	return &asyncWriter{&zapcore.BufferedWriteSyncer{WS: ws, Size: cfg.BufferSize, FlushInterval: cfg.FlushInterval}}
+}
+
+func (w *asyncWriter) Close() error {
+	err := w.Stop()
+	if closer, ok := w.WS.(io.Closer); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
@@ -0,0 +1,238 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	// ErrAuditTampered 审计日志的哈希链校验失败，记录被修改、删除或插入
+	ErrAuditTampered = errors.New("logger: audit log has been tampered with")
+	// ErrAuditKey 审计日志的 HMAC 密钥为空
+	ErrAuditKey = errors.New("logger: audit key is required")
+)
+
+// AuditEvent 审计事件，字段固定，便于合规检索
+type AuditEvent struct {
+	// Actor 操作者，如用户 ID、服务账号
+	Actor string
+	// Action 操作，如 user.delete
+	Action string
+	// Resource 操作对象，如 user/42
+	Resource string
+	// Result 结果，如 success、denied、failed
+	Result string
+	// Time 发生时间，零值时为写入时间
+	Time time.Time
+	// Details 附加信息
+	Details map[string]any
+}
+
+// auditRecord 写入文件的格式，字段顺序固定，hash 为去掉 hash 字段后整行的 HMAC-SHA256
+type auditRecord struct {
+	Time     string         `json:"time"`
+	Actor    string         `json:"actor"`
+	Action   string         `json:"action"`
+	Resource string         `json:"resource"`
+	Result   string         `json:"result"`
+	Details  map[string]any `json:"details,omitempty"`
+	PrevHash string         `json:"prev_hash"`
+}
+
+// AuditLogger 审计日志，独立于应用日志的文件与级别
+type AuditLogger struct {
+	mu   sync.Mutex
+	ws   zapcore.WriteSyncer
+	key  []byte
+	prev string
+
+	closers []io.Closer
+}
+
+// NewAudit 创建审计日志，默认写入 logs/audit.log，文件路径、轮转、保留时间、归档等使用与 WithFileCore 相同的选项，
+// 级别相关的选项不生效，每个事件都会写入。每条记录带前一条的哈希（prev_hash）与本条以 key 计算的 HMAC（hash），
+// 重启后从已有文件的最后一条完整记录接续（崩溃时写了一半的末行会被截掉），不知道 key 时修改、删除或插入任一条
+// 都会使之后的校验失败，见 VerifyAudit。key 应与日志文件分开保存，如密钥管理服务或环境变量。
+//
+// 只截掉末尾若干条无法从文件本身发现，需要时定期将 LastHash 保存到文件之外（如数据库、另一台机器），
+// 校验时比对最后一条的哈希：
+//
+//	audit, err := logger.NewAudit(key, logger.WithLogFilePath("/var/log/app/audit.log"), logger.WithRotateSettings(100, 365, true))
+//	err = audit.Log(logger.AuditEvent{Actor: "u1", Action: "user.delete", Resource: "user/42", Result: "success"})
+func NewAudit(key []byte, options ...Option) (*AuditLogger, error) {
+	if len(key) == 0 {
+		return nil, ErrAuditKey
+	}
+	cfg := DefaultConfig.clone()
+	cfg.Rotate.Filename = "logs/audit.log"
+	for _, opt := range options {
+		opt(cfg)
+	}
+	cfg.Rotate.Filename = expandFilename(cfg.Rotate.Filename)
+
+	prev, err := lastAuditHash(cfg.Rotate.Filename, key)
+	if err != nil {
+		return nil, err
+	}
+	ws, closers := cfg.writeSyncer("audit", newFileWriter(cfg))
+	return &AuditLogger{ws: ws, key: bytes.Clone(key), prev: prev, closers: closers}, nil
+}
+
+// Log 写入事件，审计日志不能静默丢失，写入失败时返回错误
+func (a *AuditLogger) Log(ev AuditEvent) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	line, hash, err := encodeAudit(a.key, auditRecord{
+		Time:     ev.Time.UTC().Format(time.RFC3339Nano),
+		Actor:    ev.Actor,
+		Action:   ev.Action,
+		Resource: ev.Resource,
+		Result:   ev.Result,
+		Details:  ev.Details,
+		PrevHash: a.prev,
+	})
+	if err != nil {
+		return fmt.Errorf("logger: audit: %w", err)
+	}
+	if _, err := a.ws.Write(line); err != nil {
+		return fmt.Errorf("logger: audit: %w", err)
+	}
+	a.prev = hash
+	return nil
+}
+
+// LastHash 返回最后一条记录的哈希，保存到日志文件之外后可用于发现末尾记录被截掉
+func (a *AuditLogger) LastHash() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.prev
+}
+
+// Close 关闭审计日志文件
+func (a *AuditLogger) Close() error {
+	_ = a.ws.Sync()
+	return closeAll(a.closers)
+}
+
+// encodeAudit 编码为一行 JSON，hash 字段追加在末尾，校验时去掉即可还原参与哈希的内容
+func encodeAudit(key []byte, rec auditRecord) ([]byte, string, error) {
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := hex.EncodeToString(auditMAC(key, body))
+	line := append(body[:len(body)-1], `,"hash":"`...)
+	line = append(line, hash...)
+	line = append(line, "\"}\n"...)
+	return line, hash, nil
+}
+
+// auditHashSuffixLen `,"hash":"` 与 64 位十六进制哈希及 `"}` 的长度
+const auditHashSuffixLen = len(`,"hash":""}`) + sha256.Size*2
+
+func auditMAC(key, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// decodeAudit 校验一行记录本身的哈希，返回记录的 prev_hash 与 hash
+func decodeAudit(key, line []byte) (prev, hash string, err error) {
+	n := len(line)
+	if n < auditHashSuffixLen+2 || !bytes.HasPrefix(line[n-auditHashSuffixLen:], []byte(`,"hash":"`)) {
+		return "", "", ErrAuditTampered
+	}
+	hash = string(line[n-sha256.Size*2-2 : n-2])
+	body := append(line[:n-auditHashSuffixLen:n-auditHashSuffixLen], '}')
+	sum, err := hex.DecodeString(hash)
+	if err != nil || !hmac.Equal(sum, auditMAC(key, body)) {
+		return "", "", ErrAuditTampered
+	}
+	var rec auditRecord
+	if err := json.Unmarshal(body, &rec); err != nil {
+		return "", "", ErrAuditTampered
+	}
+	return rec.PrevHash, hash, nil
+}
+
+// VerifyAudit 以写入时的 key 校验 r 中审计记录的哈希链，prev 为上一个文件最后一条的哈希，从第一个文件开始校验时为空，
+// 返回最后一条的哈希用于校验下一个轮转出的文件，可与保存在外部的 LastHash 比对。校验失败时返回 ErrAuditTampered 并指出行号：
+//
+//	last, err := logger.VerifyAudit(f, key, "")
+func VerifyAudit(r io.Reader, key []byte, prev string) (string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for n := 1; sc.Scan(); n++ {
+		recPrev, hash, err := decodeAudit(key, sc.Bytes())
+		if err == nil && recPrev != prev {
+			err = ErrAuditTampered
+		}
+		if err != nil {
+			return prev, fmt.Errorf("line %d: %w", n, err)
+		}
+		prev = hash
+	}
+	if err := sc.Err(); err != nil {
+		return prev, fmt.Errorf("logger: verify audit: %w", err)
+	}
+	return prev, nil
+}
+
+// lastAuditHash 读取已有文件最后一条完整记录的哈希，文件不存在或为空时为空。
+// 末行没有换行符说明上次写入时崩溃，截掉这一行后从上一条接续
+func lastAuditHash(path string, key []byte) (string, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("logger: audit: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("logger: audit: %w", err)
+	}
+	// 单条记录不会超过 1MB，只读取文件末尾
+	off := max(info.Size()-1<<20, 0)
+	data := make([]byte, info.Size()-off)
+	if _, err := f.ReadAt(data, off); err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("logger: audit: %w", err)
+	}
+	if n := len(data); n > 0 && data[n-1] != '\n' {
+		end := bytes.LastIndexByte(data, '\n') + 1
+		if end == 0 && off > 0 {
+			return "", fmt.Errorf("logger: audit: last record of %s: %w", path, ErrAuditTampered)
+		}
+		if err := f.Truncate(off + int64(end)); err != nil {
+			return "", fmt.Errorf("logger: audit: truncate partial record: %w", err)
+		}
+		data = data[:end]
+	}
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return "", nil
+	}
+	_, hash, err := decodeAudit(key, data[bytes.LastIndexByte(data, '\n')+1:])
+	if err != nil {
+		return "", fmt.Errorf("logger: audit: last record of %s: %w", path, err)
+	}
+	return hash, nil
+}
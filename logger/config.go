@@ -0,0 +1,505 @@
+package logger
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 声明式日志配置，由 YAML / JSON / TOML 文件（LoadConfig）或环境变量（ConfigFromEnv）加载，
+// 日志的输出、级别、轮转都来自配置而不必编译进程序：
+//
+//	level: info
+//	stacktrace_level: panic
+//	outputs:
+//	  - type: console
+//	  - type: file
+//	    path: logs/{app}.log
+//	    max_size: 100
+//	    compress: true
+//	    modules: {mysql: debug}
+//	  - type: loki
+//	    url: http://loki:3100
+//	    labels: {service: order}
+type Config struct {
+	// Level 各输出的默认级别，默认 info
+	Level zapcore.Level `json:"level" yaml:"level" toml:"level"`
+	// Caller 是否记录调用位置，默认记录
+	Caller *bool `json:"caller" yaml:"caller" toml:"caller"`
+	// CallerSkip 调用位置额外跳过的层数
+	CallerSkip int `json:"caller_skip" yaml:"caller_skip" toml:"caller_skip"`
+	// StacktraceLevel 记录调用栈的最低级别，none 表示不记录，默认 error
+	StacktraceLevel string `json:"stacktrace_level" yaml:"stacktrace_level" toml:"stacktrace_level"`
+	// Service、Env 非空时附加服务信息字段，见 WithServiceInfo
+	Service string `json:"service" yaml:"service" toml:"service"`
+	Env     string `json:"env" yaml:"env" toml:"env"`
+	// Fields 每条日志都附加的字段
+	Fields map[string]string `json:"fields" yaml:"fields" toml:"fields"`
+	// Outputs 输出列表，至少一个
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs" toml:"outputs"`
+}
+
+// OutputConfig 单个输出的配置，Type 决定使用哪些字段
+type OutputConfig struct {
+	// Type 输出类型：console、file、syslog、journald、loki、elasticsearch、kafka、fluent、network，
+	// 以及子包通过 RegisterOutput 注册的类型，如 otlp（导入 logger/otellog）
+	Type string `json:"type" yaml:"type" toml:"type"`
+	// Name core 名称，用于运行时调整级别，默认同 Type
+	Name string `json:"name" yaml:"name" toml:"name"`
+	// Level 本输出的级别，为空时使用 Config.Level
+	Level *zapcore.Level `json:"level" yaml:"level" toml:"level"`
+	// Modules 按模块覆盖级别
+	Modules map[string]zapcore.Level `json:"modules" yaml:"modules" toml:"modules"`
+	// Encoding 编码格式：json、console、logfmt、pretty，见 WithEncoding
+	Encoding string `json:"encoding" yaml:"encoding" toml:"encoding"`
+	// Color 控制台是否彩色输出
+	Color *bool `json:"color" yaml:"color" toml:"color"`
+	// StderrLevel 控制台中该级别及以上写入标准错误
+	StderrLevel *zapcore.Level `json:"stderr_level" yaml:"stderr_level" toml:"stderr_level"`
+
+	// Path 文件路径，支持 {hostname}、{pid}、{app} 占位符
+	Path       string `json:"path" yaml:"path" toml:"path"`
+	MaxSize    int    `json:"max_size" yaml:"max_size" toml:"max_size"`
+	MaxAge     int    `json:"max_age" yaml:"max_age" toml:"max_age"`
+	MaxBackups int    `json:"max_backups" yaml:"max_backups" toml:"max_backups"`
+	Compress   bool   `json:"compress" yaml:"compress" toml:"compress"`
+	LocalTime  bool   `json:"local_time" yaml:"local_time" toml:"local_time"`
+	// Rotate 轮转策略：size（默认）、time、both
+	Rotate string `json:"rotate" yaml:"rotate" toml:"rotate"`
+	// RotateInterval 按时间轮转的周期：daily（默认）、hourly
+	RotateInterval string `json:"rotate_interval" yaml:"rotate_interval" toml:"rotate_interval"`
+	// Compression 轮转后文件的压缩格式：gzip、zstd，设置后忽略 Compress
+	Compression string `json:"compression" yaml:"compression" toml:"compression"`
+	// EncryptionKey 十六进制的 AES 密钥，设置后轮转后的文件加密保存，建议通过环境变量传入
+	EncryptionKey string `json:"encryption_key" yaml:"encryption_key" toml:"encryption_key"`
+	// FileMode、DirMode 八进制的文件与目录权限，如 0640、0750
+	FileMode string `json:"file_mode" yaml:"file_mode" toml:"file_mode"`
+	DirMode  string `json:"dir_mode" yaml:"dir_mode" toml:"dir_mode"`
+	// FileGroup 日志文件的属组，组名或 GID
+	FileGroup string `json:"file_group" yaml:"file_group" toml:"file_group"`
+	// ReopenOnSIGHUP 收到 SIGHUP 时重新打开文件，配合外部 logrotate 使用
+	ReopenOnSIGHUP bool `json:"reopen_on_sighup" yaml:"reopen_on_sighup" toml:"reopen_on_sighup"`
+
+	// SamplingInitial、SamplingThereafter 均为 0 时不采样，SamplingTick 默认 1s
+	SamplingInitial    int    `json:"sampling_initial" yaml:"sampling_initial" toml:"sampling_initial"`
+	SamplingThereafter int    `json:"sampling_thereafter" yaml:"sampling_thereafter" toml:"sampling_thereafter"`
+	SamplingTick       string `json:"sampling_tick" yaml:"sampling_tick" toml:"sampling_tick"`
+	// Dedup 重复日志合并窗口，如 10s
+	Dedup string `json:"dedup" yaml:"dedup" toml:"dedup"`
+
+	// Network、Addr 用于 syslog、network，Facility 用于 syslog，Addr 也用于 fluent
+	Network  string `json:"network" yaml:"network" toml:"network"`
+	Addr     string `json:"addr" yaml:"addr" toml:"addr"`
+	Facility int    `json:"facility" yaml:"facility" toml:"facility"`
+	// URL 用于 loki、elasticsearch，也是 otlp 的地址，为空时读取 OTEL_EXPORTER_OTLP_ENDPOINT
+	URL    string            `json:"url" yaml:"url" toml:"url"`
+	Labels map[string]string `json:"labels" yaml:"labels" toml:"labels"`
+	Index  string            `json:"index" yaml:"index" toml:"index"`
+	// Brokers、Topic 用于 kafka
+	Brokers []string `json:"brokers" yaml:"brokers" toml:"brokers"`
+	Topic   string   `json:"topic" yaml:"topic" toml:"topic"`
+	// Tag 用于 fluent
+	Tag string `json:"tag" yaml:"tag" toml:"tag"`
+	// Protocol 用于 otlp：grpc（默认）、http
+	Protocol string `json:"protocol" yaml:"protocol" toml:"protocol"`
+}
+
+// NewFromFile 按配置文件创建日志，格式由扩展名决定（.yaml / .yml / .json / .toml）
+func NewFromFile(path string) (*Logger, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Build()
+}
+
+// NewFromEnv 按环境变量创建日志，变量名见 ConfigFromEnv
+func NewFromEnv(prefix string) (*Logger, error) {
+	cfg, err := ConfigFromEnv(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Build()
+}
+
+// LoadConfig 读取配置文件，未知字段视为错误以便发现拼写问题
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("logger: read config: %w", err)
+	}
+	cfg := &Config{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(cfg)
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(cfg)
+	default:
+		return nil, fmt.Errorf("logger: unsupported config format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("logger: parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ConfigFromEnv 从环境变量读取配置。全局字段为 <prefix>_<FIELD>，如 APP_LOG_LEVEL、APP_LOG_STACKTRACE_LEVEL；
+// <prefix>_OUTPUTS 为逗号分隔的输出类型，各输出的字段为 <prefix>_<TYPE>_<FIELD>，
+// FIELD 为配置文件中字段名的大写形式。列表以逗号分隔，map 为 k=v,k2=v2：
+//
+//	APP_LOG_LEVEL=info
+//	APP_LOG_OUTPUTS=console,file
+//	APP_LOG_FILE_PATH=/var/log/app.log
+//	APP_LOG_FILE_MODULES=mysql=debug,http=warn
+func ConfigFromEnv(prefix string) (*Config, error) {
+	prefix = strings.TrimSuffix(prefix, "_")
+	cfg := &Config{}
+	if err := loadEnv(reflect.ValueOf(cfg).Elem(), prefix+"_"); err != nil {
+		return nil, err
+	}
+	for _, typ := range strings.Split(os.Getenv(prefix+"_OUTPUTS"), ",") {
+		typ = strings.TrimSpace(typ)
+		if typ == "" {
+			continue
+		}
+		out := OutputConfig{Type: typ}
+		if err := loadEnv(reflect.ValueOf(&out).Elem(), prefix+"_"+strings.ToUpper(typ)+"_"); err != nil {
+			return nil, err
+		}
+		cfg.Outputs = append(cfg.Outputs, out)
+	}
+	return cfg, nil
+}
+
+// loadEnv 按 json 标签的大写形式读取环境变量并赋值给结构体字段
+func loadEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		if tag == "" || tag == "outputs" || tag == "type" {
+			continue
+		}
+		name := prefix + strings.ToUpper(tag)
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setField(v.Field(i), strings.TrimSpace(s)); err != nil {
+			return fmt.Errorf("logger: %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var levelType = reflect.TypeOf(zapcore.Level(0))
+
+func setField(f reflect.Value, s string) error {
+	if f.Kind() == reflect.Pointer {
+		f.Set(reflect.New(f.Type().Elem()))
+		f = f.Elem()
+	}
+	if f.Type() == levelType {
+		return f.Addr().Interface().(*zapcore.Level).UnmarshalText([]byte(s))
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		f.Set(reflect.ValueOf(items))
+	case reflect.Map:
+		m := reflect.MakeMap(f.Type())
+		for _, item := range strings.Split(s, ",") {
+			k, val, ok := strings.Cut(strings.TrimSpace(item), "=")
+			if !ok {
+				if item = strings.TrimSpace(item); item == "" {
+					continue
+				}
+				return fmt.Errorf("invalid item %q, want k=v", item)
+			}
+			elem := reflect.New(f.Type().Elem()).Elem()
+			if err := setField(elem, strings.TrimSpace(val)); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)), elem)
+		}
+		f.Set(m)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
+
+// compile 将配置转为 Logger 级别的选项与各输出的 CoreBuilder
+func (c *Config) compile() ([]Option, []CoreBuilder, error) {
+	if len(c.Outputs) == 0 {
+		return nil, nil, fmt.Errorf("logger: no outputs configured")
+	}
+	var opts []Option
+	if c.Caller != nil {
+		opts = append(opts, WithCaller(*c.Caller))
+	}
+	if c.CallerSkip != 0 {
+		opts = append(opts, WithCallerSkip(c.CallerSkip))
+	}
+	if c.Service != "" || c.Env != "" {
+		opts = append(opts, WithServiceInfo(c.Service, c.Env))
+	}
+	if len(c.Fields) > 0 {
+		keys := make([]string, 0, len(c.Fields))
+		for k := range c.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]zap.Field, len(keys))
+		for i, k := range keys {
+			fields[i] = zap.String(k, c.Fields[k])
+		}
+		opts = append(opts, WithGlobalFields(fields...))
+	}
+	switch s := strings.ToLower(c.StacktraceLevel); s {
+	case "":
+	case "none", "off":
+		opts = append(opts, WithoutStacktrace())
+	default:
+		level, err := zapcore.ParseLevel(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("logger: stacktrace_level: %w", err)
+		}
+		opts = append(opts, WithStacktraceLevel(level))
+	}
+
+	builders := make([]CoreBuilder, 0, len(c.Outputs))
+	for i := range c.Outputs {
+		b, err := c.Outputs[i].builder(c.Level)
+		if err != nil {
+			return nil, nil, fmt.Errorf("logger: outputs[%d] %s: %w", i, c.Outputs[i].Type, err)
+		}
+		builders = append(builders, b)
+	}
+	return opts, builders, nil
+}
+
+func (o *OutputConfig) builder(level zapcore.Level) (CoreBuilder, error) {
+	opts, err := o.options(level)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(o.Type) {
+	case "console":
+		return WithConsoleCore(opts...), nil
+	case "file":
+		return WithFileCore(opts...), nil
+	case "syslog":
+		return WithSyslogCore(o.Network, o.Addr, SyslogFacility(o.Facility), opts...), nil
+	case "journald":
+		return WithJournaldCore(opts...), nil
+	case "loki":
+		if o.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return WithLokiCore(o.URL, o.Labels, opts...), nil
+	case "elasticsearch":
+		if o.URL == "" || o.Index == "" {
+			return nil, fmt.Errorf("url and index are required")
+		}
+		return WithElasticsearchCore(o.URL, o.Index, opts...), nil
+	case "kafka":
+		if len(o.Brokers) == 0 || o.Topic == "" {
+			return nil, fmt.Errorf("brokers and topic are required")
+		}
+		return WithKafkaCore(o.Brokers, o.Topic, opts...), nil
+	case "network":
+		if o.Network == "" || o.Addr == "" {
+			return nil, fmt.Errorf("network and addr are required")
+		}
+		return WithNetworkCore(o.Network, o.Addr, opts...), nil
+	case "fluent":
+		if o.Addr == "" || o.Tag == "" {
+			return nil, fmt.Errorf("addr and tag are required")
+		}
+		return WithFluentCore(o.Addr, o.Tag, opts...), nil
+	default:
+		typ := strings.ToLower(o.Type)
+		outputsMu.RLock()
+		factory, ok := outputs[typ]
+		outputsMu.RUnlock()
+		if ok {
+			return factory(o, opts)
+		}
+		if pkg, ok := outputPackages[typ]; ok {
+			return nil, fmt.Errorf("output type %q requires importing %s", o.Type, pkg)
+		}
+		return nil, fmt.Errorf("unknown output type %q", o.Type)
+	}
+}
+
+// OutputFactory 由输出配置创建 CoreBuilder，opts 为按级别、编码、文件等通用字段生成的选项
+type OutputFactory func(o *OutputConfig, opts []Option) (CoreBuilder, error)
+
+var (
+	outputsMu sync.RWMutex
+	outputs   = map[string]OutputFactory{}
+	// outputPackages 由子包注册的输出类型，未导入时在错误中提示
+	outputPackages = map[string]string{"otlp": "github.com/abs2free/go-kit/logger/otellog"}
+)
+
+// RegisterOutput 注册配置中的输出类型，通常在子包的 init 中调用，依赖较重的输出不必编译进所有使用 logger 的程序：
+//
+//	import _ "github.com/abs2free/go-kit/logger/otellog" // 注册 otlp
+func RegisterOutput(typ string, factory OutputFactory) {
+	outputsMu.Lock()
+	defer outputsMu.Unlock()
+	outputs[strings.ToLower(typ)] = factory
+}
+
+func (o *OutputConfig) options(level zapcore.Level) ([]Option, error) {
+	if o.Level != nil {
+		level = *o.Level
+	}
+	opts := []Option{WithLogLevel(level)}
+	if o.Name != "" {
+		opts = append(opts, WithName(o.Name))
+	}
+	if len(o.Modules) > 0 {
+		opts = append(opts, WithModuleLevels(o.Modules))
+	}
+	if o.Encoding != "" {
+		if !validEncoding(o.Encoding) {
+			return nil, fmt.Errorf("unknown encoding %q", o.Encoding)
+		}
+		opts = append(opts, WithEncoding(o.Encoding))
+	}
+	if o.Color != nil {
+		opts = append(opts, WithColorOutput(*o.Color))
+	}
+	if o.StderrLevel != nil {
+		opts = append(opts, WithStderrLevel(*o.StderrLevel))
+	}
+
+	if o.Path != "" {
+		opts = append(opts, WithLogFilePath(o.Path))
+	}
+	if o.MaxSize > 0 || o.MaxAge > 0 || o.Compress {
+		maxSize := cmp.Or(o.MaxSize, DefaultConfig.Rotate.MaxSize)
+		maxAge := cmp.Or(o.MaxAge, DefaultConfig.Rotate.MaxAge)
+		opts = append(opts, WithRotateSettings(maxSize, maxAge, o.Compress))
+	}
+	if o.LocalTime {
+		opts = append(opts, WithLocalTime(true))
+	}
+	if o.MaxBackups > 0 {
+		opts = append(opts, WithMaxBackups(o.MaxBackups))
+	}
+	if o.ReopenOnSIGHUP {
+		opts = append(opts, WithReopenSignal())
+	}
+	if o.FileMode != "" {
+		mode, err := strconv.ParseUint(o.FileMode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_mode %q", o.FileMode)
+		}
+		opts = append(opts, WithFileMode(os.FileMode(mode)))
+	}
+	if o.DirMode != "" {
+		mode, err := strconv.ParseUint(o.DirMode, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dir_mode %q", o.DirMode)
+		}
+		opts = append(opts, WithDirMode(os.FileMode(mode)))
+	}
+	if o.FileGroup != "" {
+		gid, err := lookupGroup(o.FileGroup)
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_group %q: %w", o.FileGroup, err)
+		}
+		opts = append(opts, WithFileGroup(gid))
+	}
+	if o.Compression != "" || o.EncryptionKey != "" {
+		key, err := hex.DecodeString(o.EncryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption_key: %w", err)
+		}
+		archive := &ArchiveConfig{Compression: o.Compression, Key: key}
+		if err := archive.validate(); err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithArchive(o.Compression, key))
+	}
+	switch strings.ToLower(o.Rotate) {
+	case "", "size":
+	case "time":
+		opts = append(opts, WithRotatePolicy(ByTime))
+	case "both":
+		opts = append(opts, WithRotatePolicy(Both))
+	default:
+		return nil, fmt.Errorf("unknown rotate policy %q", o.Rotate)
+	}
+	switch strings.ToLower(o.RotateInterval) {
+	case "", "daily":
+	case "hourly":
+		opts = append(opts, WithRotateInterval(Hourly))
+	default:
+		return nil, fmt.Errorf("unknown rotate interval %q", o.RotateInterval)
+	}
+
+	if o.SamplingInitial > 0 || o.SamplingThereafter > 0 {
+		tick := time.Second
+		if o.SamplingTick != "" {
+			d, err := time.ParseDuration(o.SamplingTick)
+			if err != nil {
+				return nil, fmt.Errorf("sampling_tick: %w", err)
+			}
+			tick = d
+		}
+		opts = append(opts, WithSampling(o.SamplingInitial, o.SamplingThereafter, tick))
+	}
+	if o.Dedup != "" {
+		d, err := time.ParseDuration(o.Dedup)
+		if err != nil {
+			return nil, fmt.Errorf("dedup: %w", err)
+		}
+		opts = append(opts, WithDedup(d))
+	}
+	return opts, nil
+}
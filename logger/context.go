@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// 上下文日志字段名
+const (
+	KeyTraceID   = "trace_id"
+	KeySpanID    = "span_id"
+	KeyRequestID = "request_id"
+)
+
+// HeaderRequestID 请求 ID 头
+const HeaderRequestID = "X-Request-Id"
+
+type (
+	loggerKey    struct{}
+	requestIDKey struct{}
+	fieldsKey    struct{}
+)
+
+// ContextFields 从 context 提取日志字段，由其他包（如 ctxmeta）注册
+type ContextFields func(ctx context.Context) []zap.Field
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []ContextFields
+)
+
+// RegisterContextFields 注册字段提取函数，FromContext 时依次调用，通常在 init 中注册
+func RegisterContextFields(fn ContextFields) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, fn)
+}
+
+// WithContext 将日志绑定到 ctx；应传入未附加请求字段的日志，字段在 FromContext 时统一附加
+func WithContext(ctx context.Context, log *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, log)
+}
+
+// WithFields 向 ctx 追加日志字段，之后 FromContext 返回的日志均携带这些字段
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	old, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	merged := make([]zap.Field, 0, len(old)+len(fields))
+	merged = append(merged, old...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// WithRequestID 将请求 ID 写入 ctx
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 返回 ctx 中的请求 ID
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext 返回 ctx 绑定的日志（未绑定时为全局日志），并附加 trace_id、span_id、request_id、
+// WithFields 追加的字段以及已注册的提取函数返回的字段（如 ctxmeta 的 user_id）：
+//
+//	logger.FromContext(ctx).Infow("order created", "order_id", id)
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	log, _ := ctx.Value(loggerKey{}).(*zap.SugaredLogger)
+	if log == nil {
+		log = S()
+	}
+
+	fields := ContextFieldsOf(ctx)
+	if len(fields) == 0 {
+		return log
+	}
+	return log.Desugar().With(fields...).Sugar()
+}
+
+// ContextFieldsOf 返回 FromContext 会附加的全部字段，便于直接使用 *zap.Logger 的场景
+func ContextFieldsOf(ctx context.Context) []zap.Field {
+	var fields []zap.Field
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		fields = append(fields,
+			zap.String(KeyTraceID, sc.TraceID().String()),
+			zap.String(KeySpanID, sc.SpanID().String()),
+		)
+	}
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, zap.String(KeyRequestID, id))
+	}
+	if extra, ok := ctx.Value(fieldsKey{}).([]zap.Field); ok {
+		fields = append(fields, extra...)
+	}
+
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+	for _, fn := range extractors {
+		fields = append(fields, fn(ctx)...)
+	}
+	return fields
+}
+
+// NewRequestID 生成 32 位十六进制的请求 ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
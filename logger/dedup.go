@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// dedupMaxKeys 同时跟踪的消息数上限，超过后新消息不再去重
+const dedupMaxKeys = 10000
+
+// WithDedup 合并 window 内重复的日志（级别、logger 名、消息均相同，不比较字段）：
+// 第一条正常输出，其余被抑制，窗口结束后输出一条带 repeated=N 字段的汇总，N 为被抑制的条数。
+// 用于避免错误死循环在几秒内写满多个轮转文件：
+//
+//	logger.WithFileCore(logger.WithDedup(10 * time.Second))
+func WithDedup(window time.Duration) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Dedup = window
+	}
+}
+
+type dedupState struct {
+	start time.Time
+	count int
+	ent   zapcore.Entry
+	// core 第一条日志所在的 core，汇总沿用其上下文字段
+	core zapcore.Core
+}
+
+// deduper 各 With 副本共享的去重状态，后台定期输出到期窗口的汇总
+type deduper struct {
+	window time.Duration
+
+	mu     sync.Mutex
+	states map[dedupKey]*dedupState
+	done   chan struct{}
+	once   sync.Once
+}
+
+type dedupKey struct {
+	level   zapcore.Level
+	logger  string
+	message string
+}
+
+func newDeduper(window time.Duration) *deduper {
+	d := &deduper{window: window, states: make(map[dedupKey]*dedupState), done: make(chan struct{})}
+	go d.run()
+	return d
+}
+
+func (d *deduper) run() {
+	ticker := time.NewTicker(d.window / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			d.flush(now, false)
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// flush 输出已到期（all 为 true 时为全部）窗口的汇总
+func (d *deduper) flush(now time.Time, all bool) {
+	var pending []*dedupState
+	d.mu.Lock()
+	for key, st := range d.states {
+		if !all && now.Sub(st.start) < d.window {
+			continue
+		}
+		delete(d.states, key)
+		if st.count > 0 {
+			pending = append(pending, st)
+		}
+	}
+	d.mu.Unlock()
+	for _, st := range pending {
+		writeRepeated(st, now)
+	}
+}
+
+func writeRepeated(st *dedupState, now time.Time) {
+	ent := st.ent
+	ent.Time = now
+	ent.Caller = zapcore.EntryCaller{}
+	ent.Stack = ""
+	if ce := st.core.Check(ent, nil); ce != nil {
+		ce.Write(zap.Int("repeated", st.count))
+	}
+}
+
+// Close 停止后台协程并输出剩余汇总
+func (d *deduper) Close() error {
+	d.once.Do(func() {
+		close(d.done)
+		d.flush(time.Now(), true)
+	})
+	return nil
+}
+
+// dedupCore 在 Check 阶段抑制重复日志
+type dedupCore struct {
+	zapcore.Core
+	d *deduper
+}
+
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	return &dedupCore{Core: c.Core.With(fields), d: c.d}
+}
+
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// DPanic 及以上不合并，保证 panic / 退出等行为不受影响
+	if !c.Enabled(ent.Level) || ent.Level > zapcore.ErrorLevel {
+		return c.Core.Check(ent, ce)
+	}
+	key := dedupKey{level: ent.Level, logger: ent.LoggerName, message: ent.Message}
+
+	c.d.mu.Lock()
+	st, ok := c.d.states[key]
+	if ok && ent.Time.Sub(st.start) < c.d.window {
+		st.count++
+		c.d.mu.Unlock()
+		return ce
+	}
+	if ok {
+		delete(c.d.states, key)
+	}
+	if len(c.d.states) < dedupMaxKeys {
+		c.d.states[key] = &dedupState{start: ent.Time, ent: ent, core: c.Core}
+	}
+	c.d.mu.Unlock()
+
+	// 上一个窗口的汇总先于本条输出
+	if ok && st.count > 0 {
+		writeRepeated(st, ent.Time)
+	}
+	return c.Core.Check(ent, ce)
+}
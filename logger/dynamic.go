@@ -0,0 +1,38 @@
+package logger
+
+// WithDynamicCores 创建的日志可在运行时增减输出（见 AttachCore、DetachCore），
+// 写入时多一次原子读取，仅在 New、NewWithOptions 中生效
+func WithDynamicCores() Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Dynamic = true
+	}
+}
+
+// AttachCore 在运行时加入输出，如故障排查期间临时写入 debug 文件或发往网络收集器，
+// 已派生的日志（Named、With）同样生效。返回新输出的级别名称，名称与已有输出重复时追加序号，
+// 用于 SetLevel 与 DetachCore。日志需由 Config 或 WithDynamicCores 创建：
+//
+//	name, err := log.AttachCore(logger.WithFileCore(logger.WithLogFilePath("logs/debug.log"), logger.WithLogLevel(zap.DebugLevel), logger.WithName("debug")))
+//	defer log.DetachCore(name)
+func (l *Logger) AttachCore(builder CoreBuilder) (string, error) {
+	if l.swap == nil {
+		return "", ErrNotReloadable
+	}
+	cores, err := buildCores([]CoreBuilder{builder})
+	if err != nil {
+		return "", err
+	}
+	names := l.swap.attach(cores[0])
+	if len(names) == 0 {
+		return "", nil
+	}
+	return names[0], nil
+}
+
+// DetachCore 移除名为 name 的输出（Levels 中的名称）并关闭其资源，一个 CoreBuilder 产生多个输出时一并移除
+func (l *Logger) DetachCore(name string) error {
+	if l.swap == nil {
+		return ErrNotReloadable
+	}
+	return l.swap.detach(name)
+}
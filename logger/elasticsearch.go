@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithElasticsearchCore 将日志批量写入 Elasticsearch（_bulk API，op_type=create，兼容 data stream）。
+// index 支持 {date}（日志时间，UTC，2006.01.02）以及 {app}、{hostname} 占位符，如 "logs-order-{date}"；
+// 时间字段为 @timestamp（ISO8601）。429、5xx 及批内被拒的可重试条目按 WithRetry 退避重试，
+// 仍失败的日志写入 WithDeadLetter 指定的文件，认证用 WithHTTPHeader("Authorization", "ApiKey ...")：
+//
+//	logger.WithElasticsearchCore("http://es:9200", "logs-order-{date}",
+//		logger.WithBatch(1000, 2*time.Second), logger.WithDeadLetter("logs/es-dead-letter.log"))
+func WithElasticsearchCore(url, index string, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		bulk := strings.TrimRight(url, "/")
+		if !strings.HasSuffix(bulk, "/_bulk") {
+			bulk += "/_bulk"
+		}
+		index = expandFilename(index)
+		client := &http.Client{}
+		headers := cfg.Sink.Headers
+		w := newBatchWriter("elasticsearch", cfg.Sink, func(ctx context.Context, batch []sinkRecord) error {
+			resp, err := postHTTPBody(ctx, client, bulk, "application/x-ndjson", esBulkPayload(index, batch), headers, 64<<20)
+			if err != nil {
+				return err
+			}
+			return esBulkResult(resp, batch)
+		})
+
+		enc := cfg.Encoder
+		enc.TimeKey = "@timestamp"
+		enc.EncodeTime = zapcore.ISO8601TimeEncoder
+		*core = cfg.newCore("elasticsearch", zapcore.NewJSONEncoder(enc), w)
+	}
+}
+
+func esBulkPayload(index string, batch []sinkRecord) []byte {
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		name := strings.ReplaceAll(index, "{date}", rec.time.UTC().Format("2006.01.02"))
+		action, _ := json.Marshal(map[string]map[string]string{"create": {"_index": name}})
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(rec.line)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+type esBulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// esBulkResult 解析 _bulk 响应：429 / 5xx 的条目重试，其余失败条目（如 mapping 错误）不再重试
+func esBulkResult(body []byte, batch []sinkRecord) error {
+	var resp esBulkResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return errPermanent{fmt.Errorf("decode bulk response: %w", err)}
+	}
+	if !resp.Errors {
+		return nil
+	}
+	var (
+		partial errPartial
+		first   string
+	)
+	for i, item := range resp.Items {
+		if i >= len(batch) {
+			break
+		}
+		for _, r := range item {
+			if r.Status < 300 {
+				continue
+			}
+			if first == "" {
+				first = fmt.Sprintf("status %d: %s: %s", r.Status, r.Error.Type, r.Error.Reason)
+			}
+			if r.Status == http.StatusTooManyRequests || r.Status >= 500 {
+				partial.retry = append(partial.retry, batch[i])
+			} else {
+				partial.rejected = append(partial.rejected, batch[i])
+			}
+		}
+	}
+	partial.error = fmt.Errorf("bulk: %d retryable, %d rejected, first error %s", len(partial.retry), len(partial.rejected), first)
+	return partial
+}
@@ -0,0 +1,57 @@
+package logger
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+
+	zaplogfmt "github.com/sykesm/zap-logfmt"
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志编码格式，见 WithEncoding
+const (
+	EncodingJSON    = "json"
+	EncodingConsole = "console"
+	EncodingLogfmt  = "logfmt"
+)
+
+// WithEncoding 设置文件、控制台输出的编码格式：json（文件默认）、console（控制台默认）、logfmt 或 pretty。
+// logfmt 即 key=value 形式，适用于 Heroku 风格及 Grafana Agent 等采集管道；
+// pretty 将字段逐行列在消息下方，便于本地查看字段较多的日志；
+// 控制台使用 json 或 logfmt 时不输出颜色。Loki、Kafka 等网络输出固定为 JSON，不受影响：
+//
+//	logger.WithConsoleCore(logger.WithEncoding(logger.EncodingLogfmt))
+func WithEncoding(encoding string) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Encoding = encoding
+	}
+}
+
+func validEncoding(encoding string) bool {
+	switch encoding {
+	case "", EncodingJSON, EncodingConsole, EncodingLogfmt, EncodingPretty:
+		return true
+	}
+	return false
+}
+
+// newEncoder 按 Encoding 创建编码器，未设置时使用 def
+func (c *LoggerConfig) newEncoder(def string) zapcore.Encoder {
+	encoding := cmp.Or(c.Encoding, def)
+	if !validEncoding(encoding) {
+		fmt.Fprintf(os.Stderr, "logger: unknown encoding %q, using %s\n", encoding, def)
+		encoding = def
+	}
+	switch encoding {
+	case EncodingConsole:
+		return zapcore.NewConsoleEncoder(c.Encoder)
+	case EncodingPretty:
+		return newPrettyEncoder(c.Color)
+	case EncodingLogfmt:
+		c.Encoder.EncodeTime = zapcore.ISO8601TimeEncoder
+		return zaplogfmt.NewEncoder(c.Encoder)
+	default:
+		return newJSONEncoder(c)
+	}
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxErrorDepth 展开错误链的最大层数，避免自引用的错误导致死循环
+const maxErrorDepth = 32
+
+// ErrorFields 将 err 展开为结构化字段：error（消息）、error_chain（逐层的类型与消息，errors.Join 按深度优先展开）、
+// error_cause（沿第一条链到底的根因）、error_stack（最内层带调用栈的错误的栈帧，支持 pkg/errors 等实现了
+// StackTrace 或 Callers 方法的错误）。没有包装的错误只有 error 字段，err 为 nil 时返回空：
+//
+//	log.Desugar().Error("save order", logger.ErrorFields(err)...)
+func ErrorFields(err error) []zap.Field {
+	if err == nil {
+		return nil
+	}
+	fields := []zap.Field{zap.Error(err)}
+
+	var chain []errorLink
+	var stack []uintptr
+	walkError(err, 0, func(e error) {
+		chain = append(chain, errorLink{typ: fmt.Sprintf("%T", e), msg: e.Error()})
+		if pcs := errorStack(e); len(pcs) > 0 {
+			stack = pcs
+		}
+	})
+	if len(chain) > 1 {
+		fields = append(fields, zap.Objects("error_chain", chain), zap.String("error_cause", rootCause(err).Error()))
+	}
+	if len(stack) > 0 {
+		fields = append(fields, zap.Strings("error_stack", formatFrames(stack)))
+	}
+	return fields
+}
+
+// ErrorE 以 Error 级别记录 msg，并附加 ErrorFields 展开的错误链、根因与调用栈：
+//
+//	log.ErrorE(err, "save order failed", "order_id", id)
+func (l *Logger) ErrorE(err error, msg string, keysAndValues ...any) {
+	l.logE(zapcore.ErrorLevel, err, msg, keysAndValues)
+}
+
+// WarnE 与 ErrorE 相同，级别为 Warn，用于可恢复的错误
+func (l *Logger) WarnE(err error, msg string, keysAndValues ...any) {
+	l.logE(zapcore.WarnLevel, err, msg, keysAndValues)
+}
+
+func (l *Logger) logE(level zapcore.Level, err error, msg string, keysAndValues []any) {
+	// 跳过 ErrorE / WarnE 与 logE 两层
+	s := l.SugaredLogger.WithOptions(zap.AddCallerSkip(2))
+	if !s.Level().Enabled(level) {
+		return
+	}
+	args := make([]any, 0, len(keysAndValues)+4)
+	for _, f := range ErrorFields(err) {
+		args = append(args, f)
+	}
+	s.Logw(level, msg, append(args, keysAndValues...)...)
+}
+
+// walkError 深度优先遍历错误链
+func walkError(err error, depth int, fn func(error)) {
+	if err == nil || depth >= maxErrorDepth {
+		return
+	}
+	fn(err)
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		walkError(e.Unwrap(), depth+1, fn)
+	case interface{ Unwrap() []error }:
+		for _, child := range e.Unwrap() {
+			walkError(child, depth+1, fn)
+		}
+	}
+}
+
+// rootCause 沿第一条链找到最内层的错误
+func rootCause(err error) error {
+	for i := 0; i < maxErrorDepth; i++ {
+		var next error
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			next = e.Unwrap()
+		case interface{ Unwrap() []error }:
+			if errs := e.Unwrap(); len(errs) > 0 {
+				next = errs[0]
+			}
+		}
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return err
+}
+
+// errorStack 读取错误携带的调用栈。pkg/errors 的 StackTrace 返回元素为 uintptr 的自定义类型，
+// 不引入该依赖，通过反射读取
+func errorStack(err error) []uintptr {
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		return c.Callers()
+	}
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	v := m.Call(nil)[0]
+	pcs := make([]uintptr, v.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(v.Index(i).Uint())
+	}
+	return pcs
+}
+
+// formatFrames 输出为“函数 文件:行号”。pkg/errors 记录的是返回地址，与 runtime.Callers 相同，由 CallersFrames 处理
+func formatFrames(pcs []uintptr) []string {
+	frames := runtime.CallersFrames(pcs)
+	out := make([]string, 0, len(pcs))
+	for {
+		f, more := frames.Next()
+		if f.Function != "" || f.File != "" {
+			out = append(out, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// errorLink 错误链中的一层
+type errorLink struct {
+	typ string
+	msg string
+}
+
+func (l errorLink) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("type", l.typ)
+	enc.AddString("msg", l.msg)
+	return nil
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/version"
+)
+
+// 服务信息字段名，各服务保持一致便于集中检索
+const (
+	KeyHost    = "host"
+	KeyPID     = "pid"
+	KeyService = "service"
+	KeyEnv     = "env"
+)
+
+// WithGlobalFields 每条日志都附加的字段，多次使用时累加，仅在 New、NewWithOptions 中生效
+func WithGlobalFields(fields ...zap.Field) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Fields = append(cfg.Fields[:len(cfg.Fields):len(cfg.Fields)], fields...)
+	}
+}
+
+// WithServiceInfo 每条日志附加 host、pid、service、env 以及 version、commit（见 version.Fields），
+// service 为空时使用可执行文件名，env 为空时读取环境变量 APP_ENV，仍为空则不附加，仅在 New、NewWithOptions 中生效：
+//
+//	log, err := logger.NewWithOptions([]logger.Option{logger.WithServiceInfo("order", "production")}, logger.WithFileCore())
+func WithServiceInfo(service, env string) Option {
+	host, _ := os.Hostname()
+	if service == "" {
+		service = filepath.Base(os.Args[0])
+	}
+	if env == "" {
+		env = os.Getenv("APP_ENV")
+	}
+	fields := []zap.Field{
+		zap.String(KeyHost, host),
+		zap.Int(KeyPID, os.Getpid()),
+		zap.String(KeyService, service),
+	}
+	if env != "" {
+		fields = append(fields, zap.String(KeyEnv, env))
+	}
+	return WithGlobalFields(append(fields, version.Fields()...)...)
+}
+
+// 常用的强类型字段，键名与 HTTP、gRPC 访问日志一致，配合 Desugar 得到的 *zap.Logger 使用
+
+// Method 请求方法
+func Method(method string) zap.Field {
+	return zap.String("method", method)
+}
+
+// Path 请求路径
+func Path(path string) zap.Field {
+	return zap.String("path", path)
+}
+
+// Status 响应状态码
+func Status(code int) zap.Field {
+	return zap.Int("status", code)
+}
+
+// Duration 耗时
+func Duration(d time.Duration) zap.Field {
+	return zap.Duration("duration", d)
+}
+
+// ClientIP 客户端地址
+func ClientIP(ip string) zap.Field {
+	return zap.String("client_ip", ip)
+}
+
+// UserID 用户 ID
+func UserID(id string) zap.Field {
+	return zap.String("user_id", id)
+}
+
+// TraceID 链路 ID，通常由 FromContext 自动附加，无 context 时手动指定
+func TraceID(id string) zap.Field {
+	return zap.String(KeyTraceID, id)
+}
+
+// Component 组件名，用于区分同一模块内的不同部分
+func Component(name string) zap.Field {
+	return zap.String("component", name)
+}
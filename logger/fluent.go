@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFluentAck 要求 Fluentd 确认收到每批日志（forward 协议 ack 模式），未确认的批次按 WithRetry 重发
+func WithFluentAck() Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Sink.RequireAck = true
+	}
+}
+
+// WithFluentCore 通过 Fluentd forward 协议（msgpack over TCP）将日志批量发往 fluentd / fluent-bit，
+// 每条日志为一个 record，时间为纳秒精度的 EventTime。addr 为 host:port（默认端口 24224）
+// 或 unix:///path/to/socket，tag 用于 Fluentd 路由：
+//
+//	logger.WithFluentCore("fluent-bit:24224", "app.order", logger.WithFluentAck(), logger.WithBatch(500, time.Second))
+func WithFluentCore(addr, tag string, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		f := newFluentForwarder(addr, tag, cfg.Sink.RequireAck)
+		w := newBatchWriter("fluent", cfg.Sink, f.forward)
+		w.onClose = f.Close
+		*core = cfg.newCore("fluent", newJSONEncoder(cfg), w)
+	}
+}
+
+// fluentForwarder 维护到 Fluentd 的连接，连接错误时关闭并在下次发送时重连
+type fluentForwarder struct {
+	network string
+	addr    string
+	tag     string
+	ack     bool
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newFluentForwarder(addr, tag string, ack bool) *fluentForwarder {
+	f := &fluentForwarder{network: "tcp", addr: addr, tag: tag, ack: ack}
+	if path, ok := strings.CutPrefix(addr, "unix://"); ok {
+		f.network, f.addr = "unix", path
+	} else if _, _, err := net.SplitHostPort(addr); err != nil {
+		f.addr = net.JoinHostPort(addr, "24224")
+	}
+	return f
+}
+
+func (f *fluentForwarder) forward(ctx context.Context, batch []sinkRecord) error {
+	chunk := ""
+	if f.ack {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+		chunk = base64.StdEncoding.EncodeToString(b)
+	}
+	msg, err := fluentMessage(f.tag, batch, chunk)
+	if err != nil {
+		return errPermanent{err}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, f.network, f.addr)
+		if err != nil {
+			return fmt.Errorf("fluent: dial %s: %w", f.addr, err)
+		}
+		f.conn = conn
+	}
+	if err := f.send(ctx, msg, chunk); err != nil {
+		_ = f.conn.Close()
+		f.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (f *fluentForwarder) send(ctx context.Context, msg []byte, chunk string) error {
+	deadline, _ := ctx.Deadline()
+	_ = f.conn.SetDeadline(deadline)
+	if _, err := f.conn.Write(msg); err != nil {
+		return fmt.Errorf("fluent: write: %w", err)
+	}
+	if chunk == "" {
+		return nil
+	}
+	var resp struct {
+		Ack string `msgpack:"ack"`
+	}
+	if err := msgpack.NewDecoder(bufio.NewReader(f.conn)).Decode(&resp); err != nil {
+		return fmt.Errorf("fluent: read ack: %w", err)
+	}
+	if resp.Ack != chunk {
+		return fmt.Errorf("fluent: ack mismatch: %q != %q", resp.Ack, chunk)
+	}
+	return nil
+}
+
+// Close 关闭连接
+func (f *fluentForwarder) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conn == nil {
+		return nil
+	}
+	err := f.conn.Close()
+	f.conn = nil
+	return err
+}
+
+// fluentMessage 编码 Forward 模式消息：[tag, [[EventTime, record], ...], {"size": n, "chunk": id}]
+func fluentMessage(tag string, batch []sinkRecord, chunk string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	_ = enc.EncodeArrayLen(3)
+	_ = enc.EncodeString(tag)
+	_ = enc.EncodeArrayLen(len(batch))
+	for _, rec := range batch {
+		record, err := fluentRecord(rec.line)
+		if err != nil {
+			return nil, err
+		}
+		_ = enc.EncodeArrayLen(2)
+		// EventTime：ext type 0，秒与纳秒各 4 字节大端
+		_ = enc.EncodeExtHeader(0, 8)
+		var ts [8]byte
+		binary.BigEndian.PutUint32(ts[:4], uint32(rec.time.Unix()))
+		binary.BigEndian.PutUint32(ts[4:], uint32(rec.time.Nanosecond()))
+		buf.Write(ts[:])
+		if err := enc.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	option := map[string]any{"size": len(batch)}
+	if chunk != "" {
+		option["chunk"] = chunk
+	}
+	if err := enc.Encode(option); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fluentRecord 将 JSON 日志转为 map，整数保持为整数
+func fluentRecord(line []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var record map[string]any
+	if err := dec.Decode(&record); err != nil {
+		return nil, fmt.Errorf("fluent: decode entry: %w", err)
+	}
+	for k, v := range record {
+		record[k] = fluentValue(v)
+	}
+	return record, nil
+}
+
+func fluentValue(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, e := range v {
+			v[k] = fluentValue(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = fluentValue(e)
+		}
+	}
+	return v
+}
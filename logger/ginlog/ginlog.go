@@ -0,0 +1,118 @@
+// Package ginlog 提供基于 go-kit logger 的 Gin 访问日志中间件
+package ginlog
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+type options struct {
+	skipPaths   map[string]struct{}
+	maxBodySize int
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithSkipPaths 不记录的路径，如健康检查 /healthz、/metrics
+func WithSkipPaths(paths ...string) Option {
+	return func(o *options) {
+		for _, p := range paths {
+			o.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// WithRequestBody 记录请求体，超过 maxBytes 的部分截断；默认不记录。
+// 请求体可能包含敏感信息，开启前确认已配置脱敏
+func WithRequestBody(maxBytes int) Option {
+	return func(o *options) {
+		o.maxBodySize = maxBytes
+	}
+}
+
+// Middleware 返回访问日志中间件：读取或生成 X-Request-Id 并写入 context（logger.FromContext 可取到），
+// 请求结束后输出 method、path、status、latency、client_ip、request_id 等字段；
+// 5xx 记为 Error，4xx 记为 Warn，其余为 Info。log 为 nil 时使用全局日志
+//
+//	r := gin.New()
+//	r.Use(ginlog.Middleware(log, ginlog.WithSkipPaths("/healthz")))
+func Middleware(log *logger.Logger, opts ...Option) gin.HandlerFunc {
+	o := &options{skipPaths: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		id := c.GetHeader(logger.HeaderRequestID)
+		if id == "" || len(id) > 128 {
+			id = logger.NewRequestID()
+		}
+		c.Header(logger.HeaderRequestID, id)
+		ctx := logger.WithRequestID(c.Request.Context(), id)
+		if log != nil {
+			ctx = logger.WithContext(ctx, log.SugaredLogger)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		var body []byte
+		if o.maxBodySize > 0 && c.Request.Body != nil {
+			body, c.Request.Body = peekBody(c.Request.Body, o.maxBodySize)
+		}
+
+		c.Next()
+
+		if _, ok := o.skipPaths[path]; ok {
+			return
+		}
+
+		status := c.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", path),
+			zap.String("query", c.Request.URL.RawQuery),
+			zap.String("route", c.FullPath()),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+			zap.Int("bytes", c.Writer.Size()),
+		}
+		if body != nil {
+			fields = append(fields, zap.ByteString("body", body))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+
+		l := logger.FromContext(c.Request.Context()).Desugar()
+		switch {
+		case status >= 500:
+			l.Error("http request", fields...)
+		case status >= 400:
+			l.Warn("http request", fields...)
+		default:
+			l.Info("http request", fields...)
+		}
+	}
+}
+
+// peekBody 读取前 n 字节用于记录，并返回可完整读取原请求体的 ReadCloser
+func peekBody(body io.ReadCloser, n int) ([]byte, io.ReadCloser) {
+	buf := make([]byte, n)
+	read, _ := io.ReadFull(body, buf)
+	buf = buf[:read]
+	return buf, struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), body), body}
+}
@@ -0,0 +1,53 @@
+package ginlog
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.DebugLevel)
+	log := logger.Wrap(zap.New(core).Sugar())
+
+	r := gin.New()
+	r.Use(Middleware(log, WithSkipPaths("/healthz"), WithRequestBody(5)))
+	r.POST("/users/:id", func(c *gin.Context) {
+		data, _ := io.ReadAll(c.Request.Body)
+		logger.FromContext(c.Request.Context()).Infow("handler", "body_len", len(data))
+		c.String(http.StatusBadRequest, "bad")
+	})
+	r.GET("/healthz", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest("POST", "/users/7?x=1", strings.NewReader("hello world"))
+	req.Header.Set(logger.HeaderRequestID, "req-1")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+
+	if rec.Header().Get(logger.HeaderRequestID) != "req-1" {
+		t.Fatalf("request id not echoed")
+	}
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if got := entries[0].ContextMap(); got["body_len"] != int64(11) || got[logger.KeyRequestID] != "req-1" {
+		t.Fatalf("handler did not see full body or request id: %v", got)
+	}
+	access := entries[1]
+	fields := access.ContextMap()
+	if access.Level != zap.WarnLevel || fields["status"] != int64(400) || fields["route"] != "/users/:id" ||
+		fields["body"] != "hello" || fields[logger.KeyRequestID] != "req-1" || fields["bytes"] != int64(3) {
+		t.Fatalf("unexpected access log %v", fields)
+	}
+}
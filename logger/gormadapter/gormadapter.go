@@ -0,0 +1,209 @@
+// Package gormadapter 将 GORM 的日志输出到 go-kit logger
+package gormadapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+// 错误分类，写入 error_kind 字段便于统计与告警
+const (
+	KindNotFound  = "not_found"
+	KindDuplicate = "duplicate_key"
+	KindTimeout   = "timeout"
+	KindCanceled  = "canceled"
+	KindOther     = "other"
+)
+
+type options struct {
+	level                gormlogger.LogLevel
+	slowThreshold        time.Duration
+	maxSQLLength         int
+	ignoreRecordNotFound bool
+	parameterized        bool
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithLogLevel 设置 GORM 日志级别，默认 Warn（只输出慢查询与错误）
+func WithLogLevel(level gormlogger.LogLevel) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithSlowThreshold 设置慢查询阈值，默认 200ms，<= 0 关闭慢查询日志
+func WithSlowThreshold(d time.Duration) Option {
+	return func(o *options) {
+		o.slowThreshold = d
+	}
+}
+
+// WithMaxSQLLength SQL 超过该长度时截断，默认 2048，<= 0 不截断
+func WithMaxSQLLength(n int) Option {
+	return func(o *options) {
+		o.maxSQLLength = n
+	}
+}
+
+// WithIgnoreRecordNotFound 是否忽略 ErrRecordNotFound，默认 true
+func WithIgnoreRecordNotFound(ignore bool) Option {
+	return func(o *options) {
+		o.ignoreRecordNotFound = ignore
+	}
+}
+
+// WithParameterizedQueries 只记录带占位符的 SQL，不记录参数值，避免敏感数据进入日志
+func WithParameterizedQueries() Option {
+	return func(o *options) {
+		o.parameterized = true
+	}
+}
+
+// Logger 实现 gorm logger.Interface
+//
+//	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+//		Logger: gormadapter.New(log, gormadapter.WithSlowThreshold(time.Second)),
+//	})
+type Logger struct {
+	log  *zap.Logger
+	opts options
+}
+
+// New 创建适配器，log 为 nil 时使用全局日志；日志名为 gorm，可配合 WithModuleLevels 调整级别
+func New(log *logger.Logger, opts ...Option) *Logger {
+	o := options{
+		level:                gormlogger.Warn,
+		slowThreshold:        200 * time.Millisecond,
+		maxSQLLength:         2048,
+		ignoreRecordNotFound: true,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if log == nil {
+		log = logger.Default()
+	}
+	// 调用位置由 GORM 的 utils.FileWithLineNum 给出，关闭 zap 自身的 caller
+	z := log.Named("gorm").Desugar().WithOptions(zap.WithCaller(false))
+	return &Logger{log: z, opts: o}
+}
+
+// LogMode 实现 gorm logger.Interface
+func (l *Logger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	c := *l
+	c.opts.level = level
+	return &c
+}
+
+func (l *Logger) with(ctx context.Context) *zap.Logger {
+	fields := logger.ContextFieldsOf(ctx)
+	fields = append(fields, zap.String("caller", utils.FileWithLineNum()))
+	return l.log.With(fields...)
+}
+
+// Info 实现 gorm logger.Interface
+func (l *Logger) Info(ctx context.Context, msg string, args ...any) {
+	if l.opts.level >= gormlogger.Info {
+		l.with(ctx).Sugar().Infof(msg, args...)
+	}
+}
+
+// Warn 实现 gorm logger.Interface
+func (l *Logger) Warn(ctx context.Context, msg string, args ...any) {
+	if l.opts.level >= gormlogger.Warn {
+		l.with(ctx).Sugar().Warnf(msg, args...)
+	}
+}
+
+// Error 实现 gorm logger.Interface
+func (l *Logger) Error(ctx context.Context, msg string, args ...any) {
+	if l.opts.level >= gormlogger.Error {
+		l.with(ctx).Sugar().Errorf(msg, args...)
+	}
+}
+
+// Trace 实现 gorm logger.Interface：错误以 Error 输出，慢查询以 Warn 输出，其余在 Info 模式下输出
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.opts.level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	slow := l.opts.slowThreshold > 0 && elapsed > l.opts.slowThreshold
+
+	switch {
+	case err != nil && l.opts.level >= gormlogger.Error &&
+		!(l.opts.ignoreRecordNotFound && errors.Is(err, gorm.ErrRecordNotFound)):
+		l.with(ctx).Error("sql error", append(l.fields(fc, elapsed),
+			zap.Error(err),
+			zap.String("error_kind", Classify(err)),
+		)...)
+	case slow && l.opts.level >= gormlogger.Warn:
+		l.with(ctx).Warn("slow sql", append(l.fields(fc, elapsed),
+			zap.Duration("threshold", l.opts.slowThreshold),
+		)...)
+	case l.opts.level >= gormlogger.Info:
+		l.with(ctx).Info("sql", l.fields(fc, elapsed)...)
+	}
+}
+
+func (l *Logger) fields(fc func() (string, int64), elapsed time.Duration) []zap.Field {
+	sql, rows := fc()
+	fields := []zap.Field{
+		zap.String("sql", truncate(sql, l.opts.maxSQLLength)),
+		zap.Duration("elapsed", elapsed),
+	}
+	if rows >= 0 {
+		fields = append(fields, zap.Int64("rows", rows))
+	}
+	return fields
+}
+
+// ParamsFilter 实现 gorm 的 ParamsFilter 接口，开启 WithParameterizedQueries 时丢弃参数
+func (l *Logger) ParamsFilter(_ context.Context, sql string, params ...any) (string, []any) {
+	if l.opts.parameterized {
+		return sql, nil
+	}
+	return sql, params
+}
+
+// Classify 对数据库错误分类
+func Classify(err error) string {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return KindNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return KindDuplicate
+	case errors.Is(err, context.DeadlineExceeded):
+		return KindTimeout
+	case errors.Is(err, context.Canceled):
+		return KindCanceled
+	}
+	// 未开启 TranslateError 时按驱动的错误信息判断
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "duplicate entry"), strings.Contains(msg, "duplicate key"),
+		strings.Contains(msg, "unique constraint"):
+		return KindDuplicate
+	case strings.Contains(msg, "timeout"), strings.Contains(msg, "deadline exceeded"):
+		return KindTimeout
+	}
+	return KindOther
+}
+
+func truncate(s string, n int) string {
+	if n <= 0 || len(s) <= n {
+		return s
+	}
+	return s[:n] + "...(truncated)"
+}
@@ -0,0 +1,71 @@
+package gormadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+func TestTrace(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	l := New(logger.Wrap(zap.New(core).Sugar()), WithSlowThreshold(time.Second), WithMaxSQLLength(20))
+	ctx := logger.WithRequestID(context.Background(), "req-1")
+	query := func() (string, int64) { return "SELECT * FROM users WHERE id = 1", 1 }
+
+	l.Trace(ctx, time.Now(), query, nil)
+	l.Trace(ctx, time.Now(), query, gorm.ErrRecordNotFound)
+	if n := logs.Len(); n != 0 {
+		t.Fatalf("expected no logs in warn mode, got %d", n)
+	}
+
+	l.Trace(ctx, time.Now().Add(-2*time.Second), query, nil)
+	l.Trace(ctx, time.Now(), query, fmt.Errorf("exec: %w", errors.New("Error 1062: Duplicate entry 'a' for key 'name'")))
+	l.LogMode(gormlogger.Info).Trace(ctx, time.Now(), query, nil)
+
+	entries := logs.All()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	slow, failed, info := entries[0], entries[1], entries[2]
+	if slow.Level != zap.WarnLevel || slow.Message != "slow sql" || slow.LoggerName != "gorm" {
+		t.Fatalf("unexpected slow entry %+v", slow.Entry)
+	}
+	fields := slow.ContextMap()
+	if fields["sql"] != "SELECT * FROM users ...(truncated)" || fields[logger.KeyRequestID] != "req-1" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+	if !strings.Contains(fmt.Sprint(fields["caller"]), ".go:") {
+		t.Fatalf("unexpected caller %v", fields["caller"])
+	}
+	if failed.Level != zap.ErrorLevel || failed.ContextMap()["error_kind"] != KindDuplicate {
+		t.Fatalf("unexpected error entry %v", failed.ContextMap())
+	}
+	if info.Level != zap.InfoLevel || info.ContextMap()["rows"] != int64(1) {
+		t.Fatalf("unexpected info entry %v", info.ContextMap())
+	}
+}
+
+func TestClassify(t *testing.T) {
+	cases := map[error]string{
+		gorm.ErrRecordNotFound:                            KindNotFound,
+		fmt.Errorf("query: %w", context.DeadlineExceeded): KindTimeout,
+		context.Canceled:                                  KindCanceled,
+		errors.New(`pq: duplicate key value violates unique constraint "users_pkey"`): KindDuplicate,
+		errors.New("connection refused"):                                              KindOther,
+	}
+	for err, want := range cases {
+		if got := Classify(err); got != want {
+			t.Errorf("%v: got %s, want %s", err, got, want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Package grpclog 提供基于 go-kit logger 的 gRPC 服务端访问日志与 panic 恢复拦截器
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+type options struct {
+	skipMethods map[string]struct{}
+	noAccess    bool
+	log         *logger.Logger
+	payloads    bool
+}
+
+// Option 拦截器的配置选项
+type Option func(*options)
+
+// WithLogger 拦截器使用的日志，默认全局日志
+func WithLogger(log *logger.Logger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+// WithSkipMethods 不输出访问日志的方法，如 /grpc.health.v1.Health/Check
+func WithSkipMethods(methods ...string) Option {
+	return func(o *options) {
+		for _, m := range methods {
+			o.skipMethods[m] = struct{}{}
+		}
+	}
+}
+
+// WithoutAccessLog 只注入请求 ID 与日志并恢复 panic，不输出访问日志
+func WithoutAccessLog() Option {
+	return func(o *options) {
+		o.noAccess = true
+	}
+}
+
+// WithPayloads 在 unary 访问日志中记录请求与响应内容；内容可能包含敏感信息且体积较大，仅用于排查问题
+func WithPayloads() Option {
+	return func(o *options) {
+		o.payloads = true
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{skipMethods: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// UnaryServerInterceptor 输出 gRPC 访问日志（method、code、duration、peer），
+// 并捕获 handler 中的 panic：记录堆栈后返回 codes.Internal，避免进程退出：
+//
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpclog.UnaryServerInterceptor(grpclog.WithLogger(log))),
+//		grpc.ChainStreamInterceptor(grpclog.StreamServerInterceptor(grpclog.WithLogger(log))),
+//	)
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		start := time.Now()
+		ctx = o.grpcContext(ctx)
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ctx, info.FullMethod, p)
+			}
+			if o.skip(info.FullMethod) {
+				return
+			}
+			fields := grpcFields(ctx, info.FullMethod, start, err)
+			if o.payloads {
+				fields = append(fields, payloadField("request", req), payloadField("response", resp))
+			}
+			logGRPC(ctx, err, fields)
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 流式调用的访问日志与 panic 恢复，额外记录收发消息数
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		start := time.Now()
+		ws := &serverStream{ServerStream: ss, ctx: o.grpcContext(ss.Context())}
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(ws.ctx, info.FullMethod, p)
+			}
+			if o.skip(info.FullMethod) {
+				return
+			}
+			fields := append(grpcFields(ws.ctx, info.FullMethod, start, err),
+				zap.Int("sent", ws.sent),
+				zap.Int("received", ws.received),
+			)
+			logGRPC(ws.ctx, err, fields)
+		}()
+		return handler(srv, ws)
+	}
+}
+
+// grpcContext 绑定日志并从 metadata 读取 x-request-id（缺失时生成）
+func (o *options) grpcContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var id string
+	if v := md.Get(logger.HeaderRequestID); len(v) > 0 && len(v[0]) <= 128 {
+		id = v[0]
+	}
+	if id == "" {
+		id = logger.NewRequestID()
+	}
+	ctx = logger.WithRequestID(ctx, id)
+	if o.log != nil {
+		ctx = logger.WithContext(ctx, o.log.SugaredLogger)
+	}
+	return ctx
+}
+
+func (o *options) skip(method string) bool {
+	if o.noAccess {
+		return true
+	}
+	_, ok := o.skipMethods[method]
+	return ok
+}
+
+func recovered(ctx context.Context, method string, p any) error {
+	logger.FromContext(ctx).Desugar().Error("grpc handler panic",
+		zap.String("grpc.method", method),
+		zap.Any("panic", p),
+		zap.StackSkip("stack", 2),
+	)
+	return status.Error(codes.Internal, "internal error")
+}
+
+func grpcFields(ctx context.Context, fullMethod string, start time.Time, err error) []zap.Field {
+	service, method := path.Split(fullMethod)
+	fields := []zap.Field{
+		zap.String("grpc.service", path.Clean(service)[1:]),
+		zap.String("grpc.method", method),
+		zap.String("grpc.code", status.Code(err).String()),
+		zap.Duration("duration", time.Since(start)),
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr := p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+		fields = append(fields, zap.String("peer", addr))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	return fields
+}
+
+func logGRPC(ctx context.Context, err error, fields []zap.Field) {
+	l := logger.FromContext(ctx).Desugar()
+	if ce := l.Check(codeLevel(status.Code(err)), "grpc request"); ce != nil {
+		ce.Write(fields...)
+	}
+}
+
+// codeLevel 服务端错误记为 Error，调用方原因导致的错误记为 Warn
+func codeLevel(c codes.Code) zapcore.Level {
+	switch c {
+	case codes.OK:
+		return zapcore.InfoLevel
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func payloadField(key string, v any) zap.Field {
+	if m, ok := v.(proto.Message); ok {
+		return zap.String(key, protojson.Format(m))
+	}
+	if v == nil {
+		return zap.Skip()
+	}
+	return zap.String(key, fmt.Sprintf("%+v", v))
+}
+
+// serverStream 替换 context 并统计收发消息数
+type serverStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	sent     int
+	received int
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.sent++
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.received++
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+func TestGRPCInterceptors(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := logger.Wrap(zap.New(core).Sugar())
+	unary := UnaryServerInterceptor(WithLogger(log), WithPayloads())
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.v1.UserService/Get"}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+	resp, err := unary(ctx, wrapperspb.String("42"), info, func(ctx context.Context, req any) (any, error) {
+		return wrapperspb.String("alice"), nil
+	})
+	if err != nil || resp.(*wrapperspb.StringValue).GetValue() != "alice" {
+		t.Fatalf("unexpected result %v %v", resp, err)
+	}
+	_, err = unary(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		panic("nil map")
+	})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+
+	stream := StreamServerInterceptor(WithLogger(log))
+	err = stream(nil, &fakeStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: "/user.v1.UserService/Watch"},
+		func(srv any, ss grpc.ServerStream) error {
+			_ = ss.SendMsg("a")
+			_ = ss.SendMsg("b")
+			return status.Error(codes.NotFound, "no user")
+		})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("unexpected stream error %v", err)
+	}
+
+	entries := logs.All()
+	if len(entries) != 4 {
+		t.Fatalf("expected 4 entries, got %d", len(entries))
+	}
+	ok := entries[0].ContextMap()
+	if entries[0].Level != zap.InfoLevel || ok["grpc.service"] != "user.v1.UserService" || ok["grpc.method"] != "Get" ||
+		ok["grpc.code"] != "OK" || ok[logger.KeyRequestID] != "req-1" || !strings.Contains(fmt.Sprint(ok["response"]), "alice") {
+		t.Fatalf("unexpected access log %v", ok)
+	}
+	if entries[1].Message != "grpc handler panic" || entries[1].ContextMap()["stack"] == "" {
+		t.Fatalf("panic not logged: %+v", entries[1])
+	}
+	if entries[2].Level != zap.ErrorLevel || entries[2].ContextMap()["grpc.code"] != "Internal" {
+		t.Fatalf("unexpected panic access log %v", entries[2].ContextMap())
+	}
+	if entries[3].Level != zap.WarnLevel || entries[3].ContextMap()["sent"] != int64(2) {
+		t.Fatalf("unexpected stream log %v", entries[3].ContextMap())
+	}
+}
+
+type fakeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeStream) Context() context.Context { return s.ctx }
+func (s *fakeStream) SendMsg(any) error        { return nil }
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithFatalHook Fatal 日志写入后、进程退出前调用 fn，用于上报链路、关闭数据库连接或发送告警，
+// 多次使用时按顺序调用，fn 返回后进程以状态码 1 退出。fn 中的 panic 会被恢复并输出到 stderr，
+// 不影响后续 hook 与退出，仅在 New、NewWithOptions 中生效：
+//
+//	log, err := logger.NewWithOptions([]logger.Option{logger.WithFatalHook(func(zapcore.Entry) { tp.Shutdown(ctx) })}, logger.WithFileCore())
+func WithFatalHook(fn func(zapcore.Entry)) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.FatalHooks = append(cfg.FatalHooks[:len(cfg.FatalHooks):len(cfg.FatalHooks)], fn)
+	}
+}
+
+// WithPanicHook Panic 日志写入后、panic 之前调用 fn，多次使用时按顺序调用，仅在 New、NewWithOptions 中生效
+func WithPanicHook(fn func(zapcore.Entry)) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.PanicHooks = append(cfg.PanicHooks[:len(cfg.PanicHooks):len(cfg.PanicHooks)], fn)
+	}
+}
+
+// hookOptions 转换为 zap 的选项：Fatal 替换退出动作，Panic 在写入后回调
+func (c *LoggerConfig) hookOptions() []zap.Option {
+	var opts []zap.Option
+	if len(c.FatalHooks) > 0 {
+		opts = append(opts, zap.WithFatalHook(fatalHook(c.FatalHooks)))
+	}
+	if len(c.PanicHooks) > 0 {
+		hooks := c.PanicHooks
+		opts = append(opts, zap.Hooks(func(ent zapcore.Entry) error {
+			if ent.Level == zapcore.PanicLevel {
+				runHooks(hooks, ent)
+			}
+			return nil
+		}))
+	}
+	return opts
+}
+
+// fatalHook 依次调用 hook 后退出
+type fatalHook []func(zapcore.Entry)
+
+func (h fatalHook) OnWrite(ce *zapcore.CheckedEntry, fields []zapcore.Field) {
+	runHooks(h, ce.Entry)
+	zapcore.WriteThenFatal.OnWrite(ce, fields)
+}
+
+func runHooks(hooks []func(zapcore.Entry), ent zapcore.Entry) {
+	for _, fn := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					fmt.Fprintf(os.Stderr, "logger: %s hook panic: %v\n", ent.Level, r)
+				}
+			}()
+			fn(ent)
+		}()
+	}
+}
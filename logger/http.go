@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/netutil"
+)
+
+type middlewareOptions struct {
+	skipPaths map[string]struct{}
+	noAccess  bool
+	trusted   *netutil.Allowlist
+}
+
+// MiddlewareOption HTTP 中间件的配置选项
+type MiddlewareOption func(*middlewareOptions)
+
+// WithSkipPaths 不输出访问日志的路径，如 /healthz
+func WithSkipPaths(paths ...string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		for _, p := range paths {
+			o.skipPaths[p] = struct{}{}
+		}
+	}
+}
+
+// WithoutAccessLog 只注入请求 ID 与日志，不输出访问日志
+func WithoutAccessLog() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.noAccess = true
+	}
+}
+
+// WithTrustedProxies 来自可信代理的请求使用 X-Forwarded-For 作为 client_ip
+func WithTrustedProxies(a *netutil.Allowlist) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.trusted = a
+	}
+}
+
+func newMiddlewareOptions(opts []MiddlewareOption) *middlewareOptions {
+	o := &middlewareOptions{skipPaths: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// HTTPMiddleware 使用全局日志的访问日志中间件，见 NewHTTPMiddleware
+//
+//	http.ListenAndServe(addr, logger.HTTPMiddleware(mux))
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return NewHTTPMiddleware(nil)(next)
+}
+
+// NewHTTPMiddleware 读取 X-Request-Id（缺失时生成）写入 context 与响应头，将 log（为 nil 时使用全局日志）
+// 绑定到 context 供 FromContext 使用，请求结束后输出 status、duration、bytes 等访问日志；
+// 5xx 记为 Error，4xx 记为 Warn，其余为 Info
+func NewHTTPMiddleware(log *Logger, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	o := newMiddlewareOptions(opts)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := r.Header.Get(HeaderRequestID)
+			if id == "" || len(id) > 128 {
+				id = NewRequestID()
+			}
+			w.Header().Set(HeaderRequestID, id)
+
+			ctx := WithRequestID(r.Context(), id)
+			if log != nil {
+				ctx = WithContext(ctx, log.SugaredLogger)
+			}
+			r = r.WithContext(ctx)
+
+			if _, skip := o.skipPaths[r.URL.Path]; skip || o.noAccess {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw, r)
+
+			fields := []zap.Field{
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.String("query", r.URL.RawQuery),
+				zap.Int("status", rw.status),
+				zap.Duration("duration", time.Since(start)),
+				zap.Int64("bytes", rw.bytes),
+				zap.String("client_ip", netutil.ClientIP(r, o.trusted)),
+				zap.String("user_agent", r.UserAgent()),
+			}
+			l := FromContext(ctx).Desugar()
+			switch {
+			case rw.status >= 500:
+				l.Error("http request", fields...)
+			case rw.status >= 400:
+				l.Warn("http request", fields...)
+			default:
+				l.Info("http request", fields...)
+			}
+		})
+	}
+}
+
+// responseWriter 记录状态码与写入字节数
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush 支持 SSE 等流式响应
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,436 @@
+package logger
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithKafkaCore 将 JSON 格式的日志异步批量写入 Kafka 的 topic，
+// 每批轮询选择一个分区发往其 leader（acks=1，不压缩）。
+// 队列有界，写满时默认丢弃并计入 logger_sink_dropped_total{sink="kafka"}，
+// 批大小、重试、背压通过 WithBatch、WithRetry、WithBuffer 配置：
+//
+//	logger.WithKafkaCore([]string{"kafka-1:9092", "kafka-2:9092"}, "app-logs",
+//		logger.WithLogLevel(zap.InfoLevel), logger.WithBatch(1000, time.Second))
+//
+// 内置的是最小生产者实现，仅支持明文连接（不支持 SASL/TLS）
+func WithKafkaCore(brokers []string, topic string, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		p := newKafkaProducer(brokers, topic)
+		w := newBatchWriter("kafka", cfg.Sink, p.produce)
+		w.onClose = p.Close
+		*core = cfg.newCore("kafka", newJSONEncoder(cfg), w)
+	}
+}
+
+const (
+	kafkaAPIProduce  int16 = 0
+	kafkaAPIMetadata int16 = 3
+)
+
+// kafkaError Kafka 协议错误码
+type kafkaError int16
+
+func (e kafkaError) Error() string {
+	return "kafka: error code " + strconv.Itoa(int(e))
+}
+
+// retriable 分区迁移、leader 选举等临时错误，刷新元数据后重试
+func (e kafkaError) retriable() bool {
+	switch e {
+	case 2, 10, 87: // CORRUPT_MESSAGE, MESSAGE_TOO_LARGE, INVALID_RECORD
+		return false
+	}
+	return true
+}
+
+// kafkaProducer 最小 Kafka 生产者：Metadata v1 获取分区 leader，Produce v3 发送 RecordBatch v2
+type kafkaProducer struct {
+	brokers  []string
+	topic    string
+	clientID string
+	dialer   net.Dialer
+
+	mu         sync.Mutex
+	conns      map[string]*kafkaConn
+	leaders    map[int32]string
+	partitions []int32
+	next       int
+	corr       int32
+}
+
+func newKafkaProducer(brokers []string, topic string) *kafkaProducer {
+	return &kafkaProducer{
+		brokers:  brokers,
+		topic:    topic,
+		clientID: "go-kit-logger",
+		dialer:   net.Dialer{Timeout: 5 * time.Second},
+		conns:    make(map[string]*kafkaConn),
+	}
+}
+
+// produce 将一批日志发往下一个分区；连接或分区错误时丢弃元数据与连接，由 batchWriter 重试
+func (p *kafkaProducer) produce(ctx context.Context, batch []sinkRecord) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.partitions) == 0 {
+		if err := p.refresh(ctx); err != nil {
+			return err
+		}
+	}
+	partition := p.partitions[p.next%len(p.partitions)]
+	p.next++
+
+	addr := p.leaders[partition]
+	conn, err := p.conn(ctx, addr)
+	if err != nil {
+		p.reset()
+		return err
+	}
+	resp, err := conn.roundTrip(ctx, kafkaAPIProduce, 3, p.nextCorr(), p.clientID, p.produceRequest(partition, batch))
+	if err != nil {
+		p.reset()
+		return err
+	}
+	if err := parseProduceResponse(resp); err != nil {
+		var kerr kafkaError
+		if errors.As(err, &kerr) && !kerr.retriable() {
+			return errPermanent{err}
+		}
+		p.reset()
+		return err
+	}
+	return nil
+}
+
+// refresh 依次尝试各 broker 获取 topic 的分区与 leader
+func (p *kafkaProducer) refresh(ctx context.Context) error {
+	var errs []error
+	for _, addr := range p.brokers {
+		conn, err := p.conn(ctx, addr)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var req kafkaEncoder
+		req.int32(1)
+		req.string(p.topic)
+		resp, err := conn.roundTrip(ctx, kafkaAPIMetadata, 1, p.nextCorr(), p.clientID, req.buf)
+		if err != nil {
+			p.dropConn(addr)
+			errs = append(errs, err)
+			continue
+		}
+		leaders, partitions, err := parseMetadataResponse(resp, p.topic)
+		if err != nil {
+			return err
+		}
+		p.leaders, p.partitions = leaders, partitions
+		return nil
+	}
+	if len(errs) == 0 {
+		return errPermanent{errors.New("kafka: no brokers configured")}
+	}
+	return fmt.Errorf("kafka: metadata: %w", errors.Join(errs...))
+}
+
+func (p *kafkaProducer) conn(ctx context.Context, addr string) (*kafkaConn, error) {
+	if c, ok := p.conns[addr]; ok {
+		return c, nil
+	}
+	nc, err := p.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("kafka: dial %s: %w", addr, err)
+	}
+	c := &kafkaConn{conn: nc, r: bufio.NewReader(nc)}
+	p.conns[addr] = c
+	return c, nil
+}
+
+func (p *kafkaProducer) dropConn(addr string) {
+	if c, ok := p.conns[addr]; ok {
+		_ = c.conn.Close()
+		delete(p.conns, addr)
+	}
+}
+
+// reset 关闭全部连接并清空元数据，下次发送时重新发现 leader
+func (p *kafkaProducer) reset() {
+	for addr := range p.conns {
+		p.dropConn(addr)
+	}
+	p.leaders, p.partitions = nil, nil
+}
+
+func (p *kafkaProducer) nextCorr() int32 {
+	p.corr++
+	return p.corr
+}
+
+// Close 关闭到 broker 的连接
+func (p *kafkaProducer) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reset()
+	return nil
+}
+
+func (p *kafkaProducer) produceRequest(partition int32, batch []sinkRecord) []byte {
+	var req kafkaEncoder
+	req.int16(-1) // transactional_id = null
+	req.int16(1)  // acks
+	req.int32(int32(10 * time.Second / time.Millisecond))
+	req.int32(1)
+	req.string(p.topic)
+	req.int32(1)
+	req.int32(partition)
+	records := recordBatch(batch)
+	req.int32(int32(len(records)))
+	req.buf = append(req.buf, records...)
+	return req.buf
+}
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// recordBatch 编码 RecordBatch v2（magic 2），value 为日志行，无 key 与 header
+func recordBatch(batch []sinkRecord) []byte {
+	first := batch[0].time.UnixMilli()
+	maxTS := first
+	var records []byte
+	for i, rec := range batch {
+		ts := rec.time.UnixMilli()
+		if ts > maxTS {
+			maxTS = ts
+		}
+		var body []byte
+		body = append(body, 0) // attributes
+		body = binary.AppendVarint(body, ts-first)
+		body = binary.AppendVarint(body, int64(i))
+		body = binary.AppendVarint(body, -1) // key = null
+		body = binary.AppendVarint(body, int64(len(rec.line)))
+		body = append(body, rec.line...)
+		body = binary.AppendVarint(body, 0) // headers
+		records = binary.AppendVarint(records, int64(len(body)))
+		records = append(records, body...)
+	}
+
+	// attributes 到末尾的部分参与 CRC 计算
+	var tail kafkaEncoder
+	tail.int16(0) // attributes：不压缩、CreateTime
+	tail.int32(int32(len(batch) - 1))
+	tail.int64(first)
+	tail.int64(maxTS)
+	tail.int64(-1) // producer id
+	tail.int16(-1) // producer epoch
+	tail.int32(-1) // base sequence
+	tail.int32(int32(len(batch)))
+	tail.buf = append(tail.buf, records...)
+
+	var out kafkaEncoder
+	out.int64(0)                                // base offset
+	out.int32(int32(4 + 1 + 4 + len(tail.buf))) // batch length
+	out.int32(-1)                               // partition leader epoch
+	out.buf = append(out.buf, 2)                // magic
+	out.int32(int32(crc32.Checksum(tail.buf, crc32c)))
+	out.buf = append(out.buf, tail.buf...)
+	return out.buf
+}
+
+// kafkaConn 一个 broker 连接，请求串行发送
+type kafkaConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (c *kafkaConn) roundTrip(ctx context.Context, api, version int16, corr int32, clientID string, body []byte) ([]byte, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = c.conn.SetDeadline(deadline)
+	} else {
+		_ = c.conn.SetDeadline(time.Time{})
+	}
+	var req kafkaEncoder
+	req.int32(0) // size 占位
+	req.int16(api)
+	req.int16(version)
+	req.int32(corr)
+	req.string(clientID)
+	req.buf = append(req.buf, body...)
+	binary.BigEndian.PutUint32(req.buf, uint32(len(req.buf)-4))
+	if _, err := c.conn.Write(req.buf); err != nil {
+		return nil, fmt.Errorf("kafka: write: %w", err)
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(c.r, size[:]); err != nil {
+		return nil, fmt.Errorf("kafka: read: %w", err)
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n < 4 || n > 64<<20 {
+		return nil, fmt.Errorf("kafka: invalid response size %d", n)
+	}
+	resp := make([]byte, n)
+	if _, err := io.ReadFull(c.r, resp); err != nil {
+		return nil, fmt.Errorf("kafka: read: %w", err)
+	}
+	if got := int32(binary.BigEndian.Uint32(resp)); got != corr {
+		return nil, fmt.Errorf("kafka: correlation id mismatch: %d != %d", got, corr)
+	}
+	return resp[4:], nil
+}
+
+func parseMetadataResponse(b []byte, topic string) (map[int32]string, []int32, error) {
+	d := kafkaDecoder{buf: b}
+	brokers := make(map[int32]string)
+	for i, n := 0, d.int32(); i < int(n) && d.err == nil; i++ {
+		id := d.int32()
+		host := d.string()
+		port := d.int32()
+		d.string() // rack
+		brokers[id] = net.JoinHostPort(host, strconv.Itoa(int(port)))
+	}
+	d.int32() // controller id
+
+	leaders := make(map[int32]string)
+	var partitions []int32
+	for i, n := 0, d.int32(); i < int(n) && d.err == nil; i++ {
+		code := kafkaError(d.int16())
+		name := d.string()
+		d.int8() // is_internal
+		for j, m := 0, d.int32(); j < int(m) && d.err == nil; j++ {
+			pcode := kafkaError(d.int16())
+			id := d.int32()
+			leader := d.int32()
+			d.skipInt32Array() // replicas
+			d.skipInt32Array() // isr
+			if name != topic || pcode != 0 {
+				continue
+			}
+			if addr, ok := brokers[leader]; ok {
+				leaders[id] = addr
+				partitions = append(partitions, id)
+			}
+		}
+		if name == topic && code != 0 {
+			return nil, nil, fmt.Errorf("kafka: topic %s: %w", topic, code)
+		}
+	}
+	if d.err != nil {
+		return nil, nil, fmt.Errorf("kafka: decode metadata: %w", d.err)
+	}
+	if len(partitions) == 0 {
+		return nil, nil, fmt.Errorf("kafka: topic %s has no available partitions", topic)
+	}
+	return leaders, partitions, nil
+}
+
+func parseProduceResponse(b []byte) error {
+	d := kafkaDecoder{buf: b}
+	for i, n := 0, d.int32(); i < int(n) && d.err == nil; i++ {
+		d.string()
+		for j, m := 0, d.int32(); j < int(m) && d.err == nil; j++ {
+			d.int32() // partition
+			code := kafkaError(d.int16())
+			d.int64() // base offset
+			d.int64() // log append time
+			if code != 0 && d.err == nil {
+				return fmt.Errorf("kafka: produce: %w", code)
+			}
+		}
+	}
+	if d.err != nil {
+		return fmt.Errorf("kafka: decode produce response: %w", d.err)
+	}
+	return nil
+}
+
+type kafkaEncoder struct {
+	buf []byte
+}
+
+func (e *kafkaEncoder) int16(v int16) { e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(v)) }
+func (e *kafkaEncoder) int32(v int32) { e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(v)) }
+func (e *kafkaEncoder) int64(v int64) { e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(v)) }
+
+func (e *kafkaEncoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// kafkaDecoder 顺序解码，出错后后续读取均返回零值
+type kafkaDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *kafkaDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.buf) < n {
+		d.err = io.ErrUnexpectedEOF
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *kafkaDecoder) int8() int8 {
+	if b := d.next(1); b != nil {
+		return int8(b[0])
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *kafkaDecoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string 读取 STRING / NULLABLE_STRING，null 返回空串
+func (d *kafkaDecoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+func (d *kafkaDecoder) skipInt32Array() {
+	n := d.int32()
+	if n > 0 {
+		d.next(4 * int(n))
+	}
+}
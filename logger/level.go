@@ -0,0 +1,293 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrUnknownCore 指定的 core 名称不存在
+var ErrUnknownCore = errors.New("logger: unknown core")
+
+type namedLevel struct {
+	name  string
+	level zap.AtomicLevel
+}
+
+// leveledCore 记录 core 名称、运行时级别与需要关闭的资源，供 Logger 收集
+type leveledCore struct {
+	zapcore.Core
+	name    string
+	level   zap.AtomicLevel
+	closers []io.Closer
+}
+
+// newCore 按配置创建 core 并附加模块级别等包装，各 CoreBuilder 共用
+func (c *LoggerConfig) newCore(name string, enc zapcore.Encoder, ws zapcore.WriteSyncer) *leveledCore {
+	ws, closers := c.writeSyncer(name, ws)
+	return c.wrapCore(name, func(level zapcore.LevelEnabler) zapcore.Core {
+		return zapcore.NewCore(enc, ws, level)
+	}, closers...)
+}
+
+// writeSyncer 按配置包装输出（如异步缓冲）并统计写入字节数，返回包装后的输出与需要关闭的资源
+func (c *LoggerConfig) writeSyncer(name string, ws zapcore.WriteSyncer) (zapcore.WriteSyncer, []io.Closer) {
+	ws = newCountingWriter(c.name(name), ws)
+	if c.AsyncWrite != nil {
+		ws = newAsyncWriter(ws, c.AsyncWrite)
+	}
+	var closers []io.Closer
+	if closer, ok := ws.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+	return ws, closers
+}
+
+// wrapCore 与 newCore 相同，用于需要按条目处理级别等信息、不能只提供 WriteSyncer 的输出
+func (c *LoggerConfig) wrapCore(name string, build func(zapcore.LevelEnabler) zapcore.Core, closers ...io.Closer) *leveledCore {
+	level := c.atomicLevel()
+	core := build(level)
+	if c.Redaction != nil {
+		core = &redactCore{Core: core, cfg: c.Redaction}
+	}
+	if len(c.ModuleLevels) > 0 {
+		core = newModuleCore(core, level, c.ModuleLevels)
+	}
+	if c.Sampling != nil {
+		core = newSamplingCore(core, c.name(name), c.Sampling)
+	}
+	if c.Dedup > 0 {
+		d := newDeduper(c.Dedup)
+		core = &dedupCore{Core: core, d: d}
+		closers = append(closers, d)
+	}
+	return &leveledCore{Core: core, name: c.name(name), level: level, closers: closers}
+}
+
+// WithCore 使用自定义 core（如告警、审计），build 收到的 level 已按 options 中的级别配置，
+// 名称与运行时级别调整、模块级别等行为同内置 core；core 实现 io.Closer 时随 Logger.Close 关闭
+func WithCore(name string, build func(level zapcore.LevelEnabler) zapcore.Core, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		var built zapcore.Core
+		lc := cfg.wrapCore(name, func(level zapcore.LevelEnabler) zapcore.Core {
+			built = build(level)
+			return built
+		})
+		if closer, ok := built.(io.Closer); ok {
+			lc.closers = append(lc.closers, closer)
+		}
+		*core = lc
+	}
+}
+
+func (c *leveledCore) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledCore{Core: c.Core.With(fields), name: c.name, level: c.level, closers: c.closers}
+}
+
+func (c *leveledCore) coreClosers() []io.Closer {
+	return c.closers
+}
+
+func (c *leveledCore) coreLevels() []namedLevel {
+	return []namedLevel{{name: c.name, level: c.level}}
+}
+
+// leveledTee 由多个 leveledCore 组成，一个 CoreBuilder 需要输出多个 core 时使用
+type leveledTee struct {
+	zapcore.Core
+	children []*leveledCore
+}
+
+func newLeveledTee(children ...*leveledCore) zapcore.Core {
+	cores := make([]zapcore.Core, len(children))
+	for i, c := range children {
+		cores[i] = c
+	}
+	return &leveledTee{Core: zapcore.NewTee(cores...), children: children}
+}
+
+func (t *leveledTee) With(fields []zapcore.Field) zapcore.Core {
+	return &leveledTee{Core: t.Core.With(fields), children: t.children}
+}
+
+func (t *leveledTee) coreClosers() []io.Closer {
+	var closers []io.Closer
+	for _, c := range t.children {
+		closers = append(closers, c.closers...)
+	}
+	return closers
+}
+
+func (t *leveledTee) coreLevels() []namedLevel {
+	var levels []namedLevel
+	for _, c := range t.children {
+		levels = append(levels, c.coreLevels()...)
+	}
+	return levels
+}
+
+// levelProvider 可报告运行时级别的 core
+type levelProvider interface {
+	coreLevels() []namedLevel
+}
+
+// closerProvider 持有后台协程或连接等需要关闭的资源的 core
+type closerProvider interface {
+	coreClosers() []io.Closer
+}
+
+func collectClosers(cores []zapcore.Core) []io.Closer {
+	var closers []io.Closer
+	for _, core := range cores {
+		if cp, ok := core.(closerProvider); ok {
+			closers = append(closers, cp.coreClosers()...)
+		}
+	}
+	return closers
+}
+
+func (c *LoggerConfig) atomicLevel() zap.AtomicLevel {
+	if c.AtomicLevel == (zap.AtomicLevel{}) {
+		return zap.NewAtomicLevelAt(c.Level)
+	}
+	return c.AtomicLevel
+}
+
+func (c *LoggerConfig) name(def string) string {
+	if c.Name == "" {
+		return def
+	}
+	return c.Name
+}
+
+// collectLevels 收集各 core 的级别，重名时追加序号（file、file2）
+func collectLevels(cores []zapcore.Core) []namedLevel {
+	var levels []namedLevel
+	seen := make(map[string]int)
+	for _, core := range cores {
+		lp, ok := core.(levelProvider)
+		if !ok {
+			continue
+		}
+		for _, nl := range lp.coreLevels() {
+			seen[nl.name]++
+			if n := seen[nl.name]; n > 1 {
+				nl.name += strconv.Itoa(n)
+			}
+			levels = append(levels, nl)
+		}
+	}
+	return levels
+}
+
+// coreLevels 返回各 core 的运行时级别，可重新加载的日志返回当前配置的级别
+func (l *Logger) coreLevels() []namedLevel {
+	if l.swap != nil {
+		return l.swap.load().levels
+	}
+	return l.levels
+}
+
+// Levels 返回各 core 当前级别
+func (l *Logger) Levels() map[string]zapcore.Level {
+	levels := l.coreLevels()
+	m := make(map[string]zapcore.Level, len(levels))
+	for _, nl := range levels {
+		m[nl.name] = nl.level.Level()
+	}
+	return m
+}
+
+// AtomicLevel 返回指定 core 的运行时级别
+func (l *Logger) AtomicLevel(core string) (zap.AtomicLevel, bool) {
+	for _, nl := range l.coreLevels() {
+		if nl.name == core {
+			return nl.level, true
+		}
+	}
+	return zap.AtomicLevel{}, false
+}
+
+// SetLevel 修改指定 core 的级别，core 为空时修改全部 core
+func (l *Logger) SetLevel(core string, level zapcore.Level) error {
+	found := false
+	for _, nl := range l.coreLevels() {
+		if core == "" || nl.name == core {
+			nl.level.SetLevel(level)
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: %q", ErrUnknownCore, core)
+	}
+	return nil
+}
+
+type levelRequest struct {
+	Core  string `json:"core"`
+	Level string `json:"level"`
+}
+
+type levelResponse struct {
+	Levels map[string]string `json:"levels"`
+}
+
+// LevelHandler 查看与修改各 core 的级别：
+//
+//	GET  返回 {"levels":{"file":"info","console":"debug"}}
+//	PUT  {"level":"debug"} 修改全部 core，{"core":"file","level":"debug"} 只修改文件输出
+//
+// 该接口可改变线上日志量，应挂载在管理端口或加认证
+func (l *Logger) LevelHandler() http.Handler {
+	return levelHandler(func() *Logger { return l })
+}
+
+// LevelHandler 作用于全局日志的 LevelHandler，每次请求时读取 Default()
+func LevelHandler() http.Handler {
+	return levelHandler(Default)
+}
+
+func levelHandler(get func() *Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := get()
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			level, err := zapcore.ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := l.SetLevel(req.Core, level); err != nil {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp := levelResponse{Levels: make(map[string]string)}
+		for name, level := range l.Levels() {
+			resp.Levels[name] = level.String()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -2,15 +2,76 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/mattn/go-isatty"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-var Logger *zap.SugaredLogger
+// Logger 日志实例，内嵌 *zap.SugaredLogger，可直接调用 Infow、Errorf 等方法；
+// 各实例相互独立，可在同一进程中分别配置应用日志与审计日志
+type Logger struct {
+	*zap.SugaredLogger
+
+	// levels 各 core 的运行时级别，见 SetLevel
+	levels []namedLevel
+	// callerSkip 构造时附加的调用栈跳过层数，适配器需要抵消
+	callerSkip int
+	// swap 可重新加载的日志的输出，非空时级别从中读取，见 Reload
+	swap *swapRoot
+	// closers 网络输出等需要在退出时关闭的资源，见 Close
+	closers []io.Closer
+}
+
+// Wrap 将已有的 zap 日志包装为 Logger
+func Wrap(l *zap.SugaredLogger) *Logger {
+	return &Logger{SugaredLogger: l}
+}
+
+var (
+	globalMu sync.RWMutex
+	global   *Logger
+	nop      = Wrap(zap.NewNop().Sugar())
+)
+
+// SetDefault 设置全局日志，构造函数不会修改全局日志，需要时由 main 显式设置
+func SetDefault(l *Logger) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+	global = l
+}
+
+// Default 返回全局日志，未设置时返回不输出的日志，调用方无需判空
+func Default() *Logger {
+	globalMu.RLock()
+	defer globalMu.RUnlock()
+	if global == nil {
+		return nop
+	}
+	return global
+}
+
+// S 返回全局日志的 *zap.SugaredLogger
+func S() *zap.SugaredLogger {
+	return Default().SugaredLogger
+}
+
+// L 返回全局日志的 *zap.Logger，用于零分配的热路径；每次调用都会创建新实例，应保存后复用。
+// 单个实例使用 Logger.Desugar，调用位置与 Logger 一致：
+//
+//	z := log.Desugar()
+//	z.Info("order created", logger.UserID(uid), zap.Int64("order_id", id))
+func L() *zap.Logger {
+	return Default().Desugar()
+}
 
 // LoggerConfig 日志配置
 type LoggerConfig struct {
@@ -18,12 +79,66 @@ type LoggerConfig struct {
 	Rotate   lumberjack.Logger
 	Level    zapcore.Level
 	FilePath string
+	// Name core 名称，用于运行时按名称调整级别，默认 file / console
+	Name string
+	// AtomicLevel 运行时可调的级别，零值时按 Level 创建；多个 core 可共享同一个
+	AtomicLevel zap.AtomicLevel
+	// RotatePolicy 轮转策略，默认 BySize
+	RotatePolicy RotatePolicy
+	// RotateInterval 按时间轮转的周期，默认 Daily
+	RotateInterval RotateInterval
+	// ModuleLevels 按模块（Named 的名称）覆盖级别
+	ModuleLevels map[string]zapcore.Level
+	// Sink 网络输出的缓冲与重试配置
+	Sink SinkConfig
+	// Sampling 采样配置，为空时不采样
+	Sampling *SamplingConfig
+	// Dedup 重复日志合并窗口，0 表示不合并
+	Dedup time.Duration
+	// Redaction 脱敏配置，为空时不脱敏
+	Redaction *RedactionConfig
+	// AsyncWrite 异步缓冲写配置，为空时同步写
+	AsyncWrite *AsyncWriteConfig
+	// StderrLevel 控制台输出中该级别及以上写入标准错误，为空时全部写入标准输出
+	StderrLevel *zapcore.Level
+	// Encoding 文件、控制台输出的编码格式，为空时文件为 json、控制台为 console
+	Encoding string
+	// Color 是否输出颜色，控制台按终端自动检测，见 WithColorOutput
+	Color bool
+	// ReopenSignals 收到这些信号时重新打开日志文件，为空时不监听
+	ReopenSignals []os.Signal
+	// Archive 轮转后文件的压缩与加密，为空时按 Rotate.Compress 决定是否 gzip
+	Archive *ArchiveConfig
+	// Perm 日志目录与文件的权限
+	Perm FilePerm
+
+	// 以下为 Logger 级别的配置，仅在 New、NewWithOptions 中生效
+
+	// DisableCaller 不记录调用位置
+	DisableCaller bool
+	// CallerSkip 调用位置额外跳过的层数
+	CallerSkip int
+	// StacktraceLevel 记录调用栈的最低级别，默认 Error
+	StacktraceLevel zapcore.Level
+	// DisableStacktrace 不记录调用栈
+	DisableStacktrace bool
+	// Fields 每条日志都附加的字段
+	Fields []zap.Field
+	// FatalHooks Fatal 日志写入后、退出前依次调用
+	FatalHooks []func(zapcore.Entry)
+	// PanicHooks Panic 日志写入后、panic 前依次调用
+	PanicHooks []func(zapcore.Entry)
+	// Cores New 使用的输出，为空时为文件与控制台
+	Cores []CoreBuilder
+	// Dynamic 运行时可增减输出
+	Dynamic bool
 }
 
 // 默认日志配置
 var DefaultConfig = &LoggerConfig{
-	Level:    zap.InfoLevel,
-	FilePath: "logs/zap.log",
+	Level:           zap.InfoLevel,
+	FilePath:        "logs/zap.log",
+	StacktraceLevel: zap.ErrorLevel,
 	Rotate: lumberjack.Logger{
 		MaxSize:    20,
 		MaxAge:     30,
@@ -33,7 +148,7 @@ var DefaultConfig = &LoggerConfig{
 	},
 	Encoder: zapcore.EncoderConfig{
 		LevelKey:       "level",
-		NameKey:        "logs/zap.log",
+		NameKey:        "logger",
 		TimeKey:        "time",
 		MessageKey:     "msg",
 		CallerKey:      "caller",
@@ -46,6 +161,60 @@ var DefaultConfig = &LoggerConfig{
 	},
 }
 
+// clone 逐字段复制配置，lumberjack.Logger 含互斥锁不能整体复制
+func (c *LoggerConfig) clone() *LoggerConfig {
+	return &LoggerConfig{
+		Encoder:     c.Encoder,
+		Level:       c.Level,
+		FilePath:    c.FilePath,
+		Name:        c.Name,
+		AtomicLevel: c.AtomicLevel,
+		Rotate: lumberjack.Logger{
+			Filename:   c.Rotate.Filename,
+			MaxSize:    c.Rotate.MaxSize,
+			MaxAge:     c.Rotate.MaxAge,
+			MaxBackups: c.Rotate.MaxBackups,
+			LocalTime:  c.Rotate.LocalTime,
+			Compress:   c.Rotate.Compress,
+		},
+		RotatePolicy:   c.RotatePolicy,
+		RotateInterval: c.RotateInterval,
+		ModuleLevels:   c.ModuleLevels,
+		Sink:           c.Sink,
+		Sampling:       c.Sampling,
+		Dedup:          c.Dedup,
+		Redaction:      c.Redaction,
+		AsyncWrite:     c.AsyncWrite,
+		StderrLevel:    c.StderrLevel,
+		Encoding:       c.Encoding,
+		Color:          c.Color,
+		ReopenSignals:  c.ReopenSignals,
+		Archive:        c.Archive,
+		Perm:           c.Perm,
+		DisableCaller:  c.DisableCaller,
+		CallerSkip:     c.CallerSkip,
+
+		StacktraceLevel:   c.StacktraceLevel,
+		DisableStacktrace: c.DisableStacktrace,
+		Fields:            c.Fields,
+		FatalHooks:        c.FatalHooks,
+		PanicHooks:        c.PanicHooks,
+		Cores:             c.Cores,
+		Dynamic:           c.Dynamic,
+	}
+}
+
+func cloneRotate(l *lumberjack.Logger) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   l.Filename,
+		MaxSize:    l.MaxSize,
+		MaxAge:     l.MaxAge,
+		MaxBackups: l.MaxBackups,
+		LocalTime:  l.LocalTime,
+		Compress:   l.Compress,
+	}
+}
+
 // CustomLevelEncoder 自定义日志级别编码器
 func CustomLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	var coloredLevel string
@@ -75,7 +244,11 @@ func CustomTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString("\x1b[36m" + t.Format("2006-01-02 15:04:05.000") + "\x1b[0m") // 青色
 }
 
-// Option 配置选项
+// Option 配置选项。选项分为两类：
+//   - core 级别（级别、文件、编码、采样、网络输出等）：传给各 core 构造函数，New 会转给默认的文件与控制台输出
+//   - Logger 级别（WithCaller、WithCallerSkip、WithStacktraceLevel、WithoutStacktrace、WithGlobalFields、
+//     WithServiceInfo、WithFatalHook、WithPanicHook、WithCores、WithDynamicCores）：仅在 New、NewWithOptions 中生效，
+//     传给 WithFileCore 等 core 构造函数时被忽略
 type Option func(*LoggerConfig)
 
 func WithLogLevel(level zapcore.Level) Option {
@@ -84,11 +257,26 @@ func WithLogLevel(level zapcore.Level) Option {
 	}
 }
 
+// WithName 设置 core 名称，同类 core 有多个时用于区分
+func WithName(name string) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Name = name
+	}
+}
+
+// WithAtomicLevel 使用外部的 AtomicLevel，便于与 runtimecfg 等组件联动
+func WithAtomicLevel(level zap.AtomicLevel) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.AtomicLevel = level
+	}
+}
+
+// WithLogFilePath 设置日志文件路径，支持 {hostname}、{pid}、{app} 占位符，
+// 如 "logs/{app}-{hostname}.log"，多实例共享目录时避免写同一个文件
 func WithLogFilePath(filePath string) Option {
 	return func(cfg *LoggerConfig) {
 		cfg.FilePath = filePath
 		cfg.Rotate.Filename = filePath
-		cfg.Encoder.NameKey = filePath
 	}
 }
 
@@ -98,6 +286,7 @@ func WithLogFormat(format zapcore.EncoderConfig) Option {
 	}
 }
 
+// WithRotateSettings 设置单文件大小上限（MB）、保留天数与是否压缩
 func WithRotateSettings(maxSize, maxAge int, compress bool) Option {
 	return func(cfg *LoggerConfig) {
 		cfg.Rotate.MaxSize = maxSize
@@ -106,62 +295,185 @@ func WithRotateSettings(maxSize, maxAge int, compress bool) Option {
 	}
 }
 
+// WithMaxBackups 设置保留的历史文件个数，0 表示不限制（仍受 MaxAge 约束）
+func WithMaxBackups(n int) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Rotate.MaxBackups = n
+	}
+}
+
+// WithLocalTime 历史文件名与按时间轮转的日期使用本地时间，默认 UTC
+func WithLocalTime(enabled bool) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Rotate.LocalTime = enabled
+	}
+}
+
+// WithRotate 整体覆盖轮转配置；Filename 为空时保留当前路径
+func WithRotate(rotate *lumberjack.Logger) Option {
+	return func(cfg *LoggerConfig) {
+		if rotate.Filename != "" {
+			cfg.FilePath = rotate.Filename
+			cfg.Rotate.Filename = rotate.Filename
+		}
+		cfg.Rotate.MaxSize = rotate.MaxSize
+		cfg.Rotate.MaxAge = rotate.MaxAge
+		cfg.Rotate.MaxBackups = rotate.MaxBackups
+		cfg.Rotate.LocalTime = rotate.LocalTime
+		cfg.Rotate.Compress = rotate.Compress
+	}
+}
+
+// CoreBuilder 构造一个输出，只使用 core 级别的选项，Logger 级别的选项见 Option
 type CoreBuilder func(*zapcore.Core)
 
 func WithFileCore(options ...Option) CoreBuilder {
 	return func(core *zapcore.Core) {
-		cfg := *DefaultConfig
+		cfg := DefaultConfig.clone()
 		for _, opt := range options {
-			opt(&cfg)
+			opt(cfg)
 		}
 
-		cfg.Rotate.Filename = cfg.Encoder.NameKey
+		cfg.Rotate.Filename = expandFilename(cfg.Rotate.Filename)
 
-		*core = zapcore.NewCore(
-			newJSONEncoder(&cfg),
-			newFileWriter(&cfg),
-			cfg.Level,
-		)
+		*core = cfg.newCore("file", cfg.newEncoder(EncodingJSON), newFileWriter(cfg))
 	}
 }
 
+// WithConsoleCore 输出到标准输出（WithStderrLevel 时较高级别写入标准错误）。
+// 输出为终端且未设置 NO_COLOR 环境变量时使用彩色，重定向到文件或由容器平台采集时输出纯文本，
+// WithColorOutput 可显式指定
 func WithConsoleCore(options ...Option) CoreBuilder {
 	return func(core *zapcore.Core) {
-		cfg := *DefaultConfig
-
-		// 为控制台设置彩色编码器
-		cfg.Encoder.EncodeLevel = CustomLevelEncoder
-		cfg.Encoder.EncodeTime = CustomTimeEncoder
+		cfg := DefaultConfig.clone()
+		WithColorOutput(colorEnabled(os.Stdout))(cfg)
 
 		for _, opt := range options {
-			opt(&cfg)
+			opt(cfg)
 		}
 
-		*core = zapcore.NewCore(
-			zapcore.NewConsoleEncoder(cfg.Encoder),
-			zapcore.AddSync(os.Stdout),
-			cfg.Level,
-		)
+		if cfg.Encoding == EncodingJSON || cfg.Encoding == EncodingLogfmt {
+			// 供程序解析的格式不带颜色
+			cfg.Encoder.EncodeLevel = zapcore.LowercaseLevelEncoder
+		}
+		enc := cfg.newEncoder(EncodingConsole)
+		if cfg.StderrLevel == nil {
+			*core = cfg.newCore("console", enc, zapcore.Lock(os.Stdout))
+			return
+		}
+		*core = cfg.newStreamSplitCore("console", enc, *cfg.StderrLevel)
 	}
 }
 
-// 添加颜色控制选项
+// WithWriterCore 输出到任意 io.Writer（内存缓冲、管道、自定义传输等），enc 为 nil 时按 WithEncoding 选择，默认 JSON。
+// 写入时加锁，w 无需并发安全；w 由调用方管理，Logger.Close 不会关闭它：
+//
+//	var buf bytes.Buffer
+//	log, _ := logger.NewWithCore(logger.WithWriterCore(&buf, nil, zap.DebugLevel))
+func WithWriterCore(w io.Writer, enc zapcore.Encoder, level zapcore.Level, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		cfg.Level = level
+		for _, opt := range options {
+			opt(cfg)
+		}
+		if enc == nil {
+			enc = cfg.newEncoder(EncodingJSON)
+		}
+		*core = cfg.newCore("writer", enc, zapcore.Lock(zapcore.AddSync(w)))
+	}
+}
+
+// WithColorOutput 控制台是否使用彩色，覆盖自动检测的结果
 func WithColorOutput(enabled bool) Option {
 	return func(cfg *LoggerConfig) {
-		if !enabled {
-			cfg.Encoder.EncodeLevel = zapcore.LowercaseLevelEncoder
-			cfg.Encoder.EncodeTime = zapcore.ISO8601TimeEncoder
-			cfg.Encoder.EncodeCaller = zapcore.ShortCallerEncoder
-			cfg.Encoder.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		cfg.Color = enabled
+		if enabled {
+			cfg.Encoder.EncodeLevel = CustomLevelEncoder
+			cfg.Encoder.EncodeTime = CustomTimeEncoder
+		} else {
+			cfg.Encoder.EncodeLevel = zapcore.CapitalLevelEncoder
+			cfg.Encoder.EncodeTime = plainTimeEncoder
 		}
 	}
 }
 
-func NewWithCore(core ...CoreBuilder) (*zap.SugaredLogger, error) {
+// colorEnabled 输出到终端且未设置 NO_COLOR（https://no-color.org）、TERM 不为 dumb 时启用彩色
+func colorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// NewWithCore 由若干 core 组成新的日志实例，不影响全局日志
+func NewWithCore(core ...CoreBuilder) (*Logger, error) {
 	return new(core...)
 }
 
-func new(builders ...CoreBuilder) (*zap.SugaredLogger, error) {
+// NewWithOptions 与 NewWithCore 相同，opts 设置调用位置、调用栈等 Logger 级别的配置：
+//
+//	// 在 Logger 之上再封装一层辅助函数时，跳过该层以报告真正的调用方
+//	log, err := logger.NewWithOptions([]logger.Option{logger.WithCallerSkip(1)}, logger.WithFileCore())
+func NewWithOptions(opts []Option, core ...CoreBuilder) (*Logger, error) {
+	cfg := DefaultConfig.clone()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return build(cfg, core...)
+}
+
+// WithCaller 是否记录调用位置，默认记录，仅在 New、NewWithOptions 中生效
+func WithCaller(enabled bool) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.DisableCaller = !enabled
+	}
+}
+
+// WithCallerSkip 调用位置额外跳过 n 层，每在 Logger 之上封装一层函数加 1，仅在 New、NewWithOptions 中生效
+func WithCallerSkip(n int) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.CallerSkip = n
+	}
+}
+
+// WithStacktraceLevel 设置记录调用栈的最低级别，生产环境可设为 zap.PanicLevel 以减少日志量，
+// 仅在 New、NewWithOptions 中生效
+func WithStacktraceLevel(level zapcore.Level) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.StacktraceLevel = level
+		cfg.DisableStacktrace = false
+	}
+}
+
+// WithoutStacktrace 任何级别都不记录调用栈，仅在 New、NewWithOptions 中生效
+func WithoutStacktrace() Option {
+	return func(cfg *LoggerConfig) {
+		cfg.DisableStacktrace = true
+	}
+}
+
+func new(builders ...CoreBuilder) (*Logger, error) {
+	return build(DefaultConfig.clone(), builders...)
+}
+
+// build 由 cfg 中 Logger 级别的配置与各 core 构造日志实例
+func build(cfg *LoggerConfig, builders ...CoreBuilder) (*Logger, error) {
+	cores, err := buildCores(builders)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Dynamic {
+		return cfg.newSwapLogger(cores), nil
+	}
+	l := cfg.newLogger(zapcore.NewTee(cores...))
+	l.levels = collectLevels(cores)
+	l.closers = collectClosers(cores)
+	return l, nil
+}
+
+// buildCores 依次执行 CoreBuilder，跳过 nil 与未产生 core 的 builder
+func buildCores(builders []CoreBuilder) ([]zapcore.Core, error) {
 	cores := make([]zapcore.Core, 0, len(builders))
 
 	if len(builders) == 0 {
@@ -186,40 +498,116 @@ func new(builders ...CoreBuilder) (*zap.SugaredLogger, error) {
 	if len(cores) == 0 {
 		return nil, fmt.Errorf("no valid log cores were configured")
 	}
+	return cores, nil
+}
 
+// newLogger 按 Logger 级别的配置在 core 之上创建日志实例
+func (c *LoggerConfig) newLogger(core zapcore.Core) *Logger {
 	opts := []zap.Option{
-		zap.AddCaller(),
-		zap.AddCallerSkip(1),
-		zap.AddStacktrace(zap.ErrorLevel),
+		zap.WithCaller(!c.DisableCaller),
+		zap.AddCallerSkip(c.CallerSkip),
 	}
-
-	logger := zap.New(
-		zapcore.NewTee(cores...),
-		opts...,
-	)
-
-	if Logger != nil {
-		_ = Logger.Sync()
+	if !c.DisableStacktrace {
+		opts = append(opts, zap.AddStacktrace(c.StacktraceLevel))
 	}
+	if len(c.Fields) > 0 {
+		opts = append(opts, zap.Fields(c.Fields...))
+	}
+	opts = append(opts, countEntries())
+	opts = append(opts, c.hookOptions()...)
 
-	Logger = logger.Sugar()
+	l := Wrap(zap.New(core, opts...).Sugar())
+	l.callerSkip = c.CallerSkip
+	return l
+}
 
-	return Logger, nil
+// Close 刷新缓冲并关闭各 core 持有的资源（如网络输出的后台协程），进程退出前调用；
+// Close 之后的日志会被丢弃
+func (l *Logger) Close() error {
+	// 控制台 Sync 在部分平台上返回 EINVAL，这里只关心各资源 Close 的结果
+	_ = l.Sync()
+	return closeAll(l.closers)
 }
 
 func newJSONEncoder(cfg *LoggerConfig) zapcore.Encoder {
-	cfg.Encoder.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-		enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
-	}
+	cfg.Encoder.EncodeTime = plainTimeEncoder
 	return zapcore.NewJSONEncoder(cfg.Encoder)
 }
 
+func plainTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
+}
+
+// expandFilename 替换文件名中的占位符
+func expandFilename(name string) string {
+	if !strings.Contains(name, "{") {
+		return name
+	}
+	host, _ := os.Hostname()
+	return strings.NewReplacer(
+		"{hostname}", host,
+		"{pid}", strconv.Itoa(os.Getpid()),
+		"{app}", filepath.Base(os.Args[0]),
+	).Replace(name)
+}
+
 func newFileWriter(cfg *LoggerConfig) zapcore.WriteSyncer {
-	writer := &cfg.Rotate
-	return zapcore.AddSync(writer)
+	var file io.WriteCloser
+	if cfg.RotatePolicy == BySize {
+		rc := newRotateCounter(&cfg.Rotate, cfg.Perm)
+		if cfg.Archive != nil {
+			// 由 archiver 压缩并按处理后的文件清理
+			rc.archive = &archiver{
+				cfg:        cfg.Archive,
+				perm:       cfg.Perm,
+				retain:     true,
+				maxAge:     cfg.Rotate.MaxAge,
+				maxBackups: cfg.Rotate.MaxBackups,
+				localTime:  cfg.Rotate.LocalTime,
+			}
+			cfg.Rotate.Compress = false
+			cfg.Rotate.MaxAge = 0
+			cfg.Rotate.MaxBackups = 0
+		}
+		file = rc
+	} else {
+		file = newTimeRotator(cfg)
+	}
+	if len(cfg.ReopenSignals) > 0 {
+		return newReopenWriter(file, cfg.ReopenSignals)
+	}
+	return zapcore.AddSync(file)
+}
+
+// New 创建日志，默认同时输出到 logs/zap.log（按 DefaultConfig 轮转）与控制台，Info 级别。
+// opts 同时作用于 Logger 与默认的两个输出，WithCores 替换默认输出；按环境区分的配置见 NewDevelopment、NewProduction：
+//
+//	log, err := logger.New(logger.WithLogLevel(zap.DebugLevel), logger.WithLogFilePath("/var/log/app/app.log"))
+//	log, err := logger.New(logger.WithCores(logger.WithConsoleCore()))
+func New(opts ...Option) (*Logger, error) {
+	cfg := DefaultConfig.clone()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	builders := cfg.Cores
+	if len(builders) == 0 {
+		builders = []CoreBuilder{WithFileCore(opts...), WithConsoleCore(opts...)}
+	}
+	return build(cfg, builders...)
+}
+
+// WithCores 替换 New 默认的文件与控制台输出，仅在 New 中生效
+func WithCores(builders ...CoreBuilder) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Cores = builders
+	}
 }
 
-func New(level zapcore.Level) (*zap.SugaredLogger, error) {
+// NewWithLevel 保留原 New(level) 的行为：创建 logs 目录，写入 logs/zap.log（10MB 轮转、保留 7 天并压缩）与控制台，
+// 并输出一条初始化日志
+//
+// Deprecated: 使用 New(WithLogLevel(level), WithRotateSettings(10, 7, true))
+func NewWithLevel(level zapcore.Level) (*Logger, error) {
 	logDir := "logs"
 	if err := os.MkdirAll(logDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create log directory: %v", err)
@@ -232,7 +620,6 @@ func New(level zapcore.Level) (*zap.SugaredLogger, error) {
 
 	consoleCore := WithConsoleCore(
 		WithLogLevel(level),
-		WithColorOutput(true), // 或 false 禁用颜色
 	)
 
 	logger, err := new(fileCore, consoleCore)
@@ -1,11 +1,41 @@
 package logger
 
 import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
+	"time"
 
+	"github.com/coreos/go-systemd/v22/journal"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/abs2free/go-kit/timeutil"
 )
 
 // 测试日志初始化时 fileCore 和 consoleCore 同时存在的场景
@@ -85,3 +115,1871 @@ func cleanUpLogFiles() {
 		fmt.Printf("Failed to clean up test logs: %v\n", err)
 	}
 }
+
+func TestFromContext(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := zap.New(core).Sugar()
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), sc)
+	ctx = WithContext(ctx, log)
+	ctx = WithFields(ctx, zap.String("job", "sync"))
+
+	var gotID string
+	h := NewHTTPMiddleware(nil, WithoutAccessLog())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = RequestID(r.Context())
+		FromContext(r.Context()).Info("handled")
+	}))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+
+	if gotID == "" || rec.Header().Get(HeaderRequestID) != gotID {
+		t.Fatalf("request id not propagated: %q / %q", gotID, rec.Header().Get(HeaderRequestID))
+	}
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields[KeyTraceID] != sc.TraceID().String() || fields[KeySpanID] != sc.SpanID().String() ||
+		fields[KeyRequestID] != gotID || fields["job"] != "sync" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+
+	if FromContext(context.Background()) == nil {
+		t.Fatalf("expected non-nil fallback logger")
+	}
+}
+
+// 测试多个实例相互独立且不修改全局日志
+func TestIndependentLoggers(t *testing.T) {
+	defer cleanUpLogFiles()
+	app, err := NewWithCore(WithFileCore(WithLogFilePath("test_logs/app.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit, err := NewWithCore(WithFileCore(WithLogFilePath("test_logs/audit.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Default() == app || Default() == audit {
+		t.Fatal("constructors must not replace the global logger")
+	}
+
+	app.Info("app entry")
+	audit.Info("audit entry")
+	_ = app.Sync()
+	_ = audit.Sync()
+
+	data, _ := os.ReadFile("test_logs/audit.log")
+	if !strings.Contains(string(data), "audit entry") || strings.Contains(string(data), "app entry") {
+		t.Fatalf("unexpected audit log %q", data)
+	}
+
+	SetDefault(app)
+	defer SetDefault(nil)
+	if S() != app.SugaredLogger {
+		t.Fatal("SetDefault not applied")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer cleanUpLogFiles()
+	log, err := NewWithCore(
+		WithFileCore(WithLogFilePath("test_logs/level.log"), WithLogLevel(zap.InfoLevel)),
+		WithConsoleCore(WithLogLevel(zap.WarnLevel)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := log.LevelHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("PUT", "/", strings.NewReader(`{"core":"file","level":"debug"}`)))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"file":"debug"`) ||
+		!strings.Contains(rec.Body.String(), `"console":"warn"`) {
+		t.Fatalf("unexpected response %d %s", rec.Code, rec.Body)
+	}
+	log.Debug("debug after change")
+	_ = log.Sync()
+	data, _ := os.ReadFile("test_logs/level.log")
+	if !strings.Contains(string(data), "debug after change") {
+		t.Fatalf("file core level not applied: %q", data)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("PUT", "/", strings.NewReader(`{"core":"kafka","level":"debug"}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+	if err := log.SetLevel("", zap.ErrorLevel); err != nil {
+		t.Fatal(err)
+	}
+	if levels := log.Levels(); levels["file"] != zap.ErrorLevel || levels["console"] != zap.ErrorLevel {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+}
+
+func TestLevelSplitFileCore(t *testing.T) {
+	defer cleanUpLogFiles()
+	log, err := NewWithCore(WithLevelSplitFileCore(
+		[]Option{WithLogFilePath("test_logs/app.log"), WithLogLevel(zap.DebugLevel)},
+		[]Option{WithLogFilePath("test_logs/error.log"), WithRotateSettings(5, 30, false)},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Debug("debug entry")
+	log.Info("info entry")
+	log.Warn("warn entry")
+	log.Error("error entry")
+	_ = log.Sync()
+
+	app, _ := os.ReadFile("test_logs/app.log")
+	errs, _ := os.ReadFile("test_logs/error.log")
+	for _, msg := range []string{"debug entry", "info entry"} {
+		if !strings.Contains(string(app), msg) || strings.Contains(string(errs), msg) {
+			t.Fatalf("%q should only be in app.log", msg)
+		}
+	}
+	for _, msg := range []string{"warn entry", "error entry"} {
+		if !strings.Contains(string(errs), msg) || strings.Contains(string(app), msg) {
+			t.Fatalf("%q should only be in error.log", msg)
+		}
+	}
+	if levels := log.Levels(); levels["app"] != zap.DebugLevel || levels["error"] != zap.InfoLevel {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+}
+
+func TestTimeRotation(t *testing.T) {
+	dir := t.TempDir()
+	cfg := DefaultConfig.clone()
+	WithLogFilePath(filepath.Join(dir, "app.log"))(cfg)
+	WithRotatePolicy(ByTime)(cfg)
+	cfg.Rotate.MaxBackups = 1
+	cfg.Rotate.Compress = true
+
+	r := newTimeRotator(cfg)
+	clock := timeutil.NewFake(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC))
+	r.clock = clock
+	defer r.Close()
+
+	for _, day := range []string{"day1", "day2", "day3"} {
+		if _, err := r.Write([]byte(day + "\n")); err != nil {
+			t.Fatal(err)
+		}
+		clock.Advance(24 * time.Hour)
+	}
+
+	want := []string{"app-2024-05-02.log.gz", "app-2024-05-03.log"}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		entries, _ := os.ReadDir(dir)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		if strings.Join(names, ",") == strings.Join(want, ",") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected files %v, want %v", names, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRotateOptions(t *testing.T) {
+	cfg := DefaultConfig.clone()
+	for _, opt := range []Option{
+		WithLogFilePath("logs/{app}-{pid}.log"),
+		WithMaxBackups(3),
+		WithLocalTime(true),
+	} {
+		opt(cfg)
+	}
+	if cfg.Rotate.MaxBackups != 3 || !cfg.Rotate.LocalTime {
+		t.Fatalf("unexpected rotate config %+v", &cfg.Rotate)
+	}
+	want := fmt.Sprintf("logs/%s-%d.log", filepath.Base(os.Args[0]), os.Getpid())
+	if got := expandFilename(cfg.Rotate.Filename); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	WithRotate(&lumberjack.Logger{MaxSize: 1, MaxAge: 2, MaxBackups: 4})(cfg)
+	if cfg.Rotate.Filename != "logs/{app}-{pid}.log" || cfg.Rotate.MaxSize != 1 || cfg.Rotate.MaxBackups != 4 || cfg.Rotate.LocalTime {
+		t.Fatalf("unexpected rotate config %+v", &cfg.Rotate)
+	}
+	if DefaultConfig.Rotate.MaxBackups != 50 {
+		t.Fatal("options must not modify DefaultConfig")
+	}
+}
+
+func TestModuleLevels(t *testing.T) {
+	defer cleanUpLogFiles()
+	levels, err := ParseModuleLevels("mysql=debug, http=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseModuleLevels("mysql"); err == nil {
+		t.Fatal("expected error for missing level")
+	}
+
+	log, err := NewWithCore(WithFileCore(
+		WithLogFilePath("test_logs/module.log"),
+		WithModuleLevels(levels),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Named("mysql").Named("pool").Debug("mysql debug")
+	log.Named("http").Info("http info")
+	log.Named("http").Warn("http warn")
+	log.Named("cache").Debug("cache debug")
+	log.Info("root info")
+	_ = log.Sync()
+
+	data, _ := os.ReadFile("test_logs/module.log")
+	for _, msg := range []string{"mysql debug", "http warn", "root info"} {
+		if !strings.Contains(string(data), msg) {
+			t.Errorf("expected %q in log", msg)
+		}
+	}
+	for _, msg := range []string{"http info", "cache debug"} {
+		if strings.Contains(string(data), msg) {
+			t.Errorf("unexpected %q in log", msg)
+		}
+	}
+	if !strings.Contains(string(data), `"logger":"mysql.pool"`) {
+		t.Errorf("logger name not encoded: %s", data)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	sl := slog.New(NewSlogHandler(Wrap(zap.New(core).Sugar())))
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	sl.DebugContext(ctx, "dropped")
+	sl.With("service", "api").WithGroup("http").InfoContext(ctx, "request",
+		"status", 200,
+		slog.Group("client", "ip", "10.0.0.1"),
+		"err", errors.New("boom"),
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Message != "request" || e.Level != zap.InfoLevel || !e.Caller.Defined {
+		t.Fatalf("unexpected entry %+v", e.Entry)
+	}
+	fields := e.ContextMap()
+	if fields[KeyRequestID] != "req-1" || fields["service"] != "api" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+	http, _ := fields["http"].(map[string]any)
+	client, _ := http["client"].(map[string]any)
+	if http["status"] != int64(200) || client["ip"] != "10.0.0.1" || http["err"] != "boom" {
+		t.Fatalf("unexpected group fields %v", fields)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	l := Wrap(zap.New(core, zap.AddCaller()).Sugar())
+
+	l.StdLogger(zap.WarnLevel).Printf("tls handshake error from %s", "1.2.3.4")
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Level != zap.WarnLevel ||
+		entries[0].Message != "tls handshake error from 1.2.3.4" {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	if !strings.HasSuffix(entries[0].Caller.File, "logger_test.go") {
+		t.Fatalf("unexpected caller %s", entries[0].Caller.File)
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log := Wrap(zap.New(core).Sugar())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/boom", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal", http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {})
+	h := NewHTTPMiddleware(log, WithSkipPaths("/healthz"))(mux)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/boom?x=1", nil))
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if entries[0].Level != zap.ErrorLevel || fields["status"] != int64(500) || fields["bytes"] != int64(9) ||
+		fields["query"] != "x=1" || fields[KeyRequestID] == "" {
+		t.Fatalf("unexpected access log %v", fields)
+	}
+}
+
+func TestLokiCore(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		attempts int
+		lines    []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "ingester unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/loki/api/v1/push" || r.Header.Get("X-Scope-OrgID") != "team-a" {
+			t.Errorf("unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		var body struct {
+			Streams []lokiStream `json:"streams"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, s := range body.Streams {
+			if s.Stream["service"] != "order" {
+				t.Errorf("unexpected labels %v", s.Stream)
+			}
+			for _, v := range s.Values {
+				lines = append(lines, v[1])
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	log, err := NewWithCore(WithLokiCore(srv.URL, map[string]string{"service": "order"},
+		WithHTTPHeader("X-Scope-OrgID", "team-a"),
+		WithBatch(10, time.Hour),
+		WithRetry(2, time.Millisecond),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Infow("order created", "order_id", 1)
+	log.Warn("stock low")
+	if err := log.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if attempts != 2 || len(lines) != 2 || !strings.Contains(lines[0], `"order_id":1`) {
+		t.Fatalf("unexpected push: attempts=%d lines=%v", attempts, lines)
+	}
+}
+
+func TestBatchWriterDrop(t *testing.T) {
+	block := make(chan struct{})
+	w := newBatchWriter("test", SinkConfig{BufferSize: 1, BatchSize: 1, FlushInterval: time.Hour},
+		func(ctx context.Context, batch []sinkRecord) error {
+			<-block
+			return nil
+		})
+	for i := 0; i < 10; i++ {
+		_, _ = w.Write([]byte("x\n"))
+	}
+	close(block)
+	if err := w.Close(); err == nil || w.Dropped() == 0 {
+		t.Fatalf("expected dropped entries, got %d (%v)", w.Dropped(), err)
+	}
+	if n := testutil.ToFloat64(sinkDropped.WithLabelValues("test")); n != float64(w.Dropped()) {
+		t.Fatalf("metric dropped = %v, want %d", n, w.Dropped())
+	}
+}
+
+// fakeKafka 单节点 broker：应答 Metadata v1 与 Produce v3，首次 Produce 返回 NOT_LEADER
+func fakeKafka(t *testing.T, topic string, got chan<- string) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	host, portStr, _ := net.SplitHostPort(ln.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	var mu sync.Mutex
+	produced := 0
+	serve := func(conn net.Conn) {
+		defer conn.Close()
+		for {
+			var size [4]byte
+			if _, err := io.ReadFull(conn, size[:]); err != nil {
+				return
+			}
+			buf := make([]byte, binary.BigEndian.Uint32(size[:]))
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+			d := kafkaDecoder{buf: buf}
+			api, _, corr := d.int16(), d.int16(), d.int32()
+			d.string() // client id
+
+			var resp kafkaEncoder
+			resp.int32(0)
+			resp.int32(corr)
+			switch api {
+			case kafkaAPIMetadata:
+				resp.int32(1)
+				resp.int32(0)
+				resp.string(host)
+				resp.int32(int32(port))
+				resp.int16(-1)
+				resp.int32(0)
+				resp.int32(1)
+				resp.int16(0)
+				resp.string(topic)
+				resp.buf = append(resp.buf, 0)
+				resp.int32(2)
+				for p := int32(0); p < 2; p++ {
+					resp.int16(0)
+					resp.int32(p)
+					resp.int32(0)
+					resp.int32(1)
+					resp.int32(0)
+					resp.int32(1)
+					resp.int32(0)
+				}
+			case kafkaAPIProduce:
+				d.string() // transactional id
+				d.int16()
+				d.int32()
+				d.int32()
+				d.string()
+				d.int32()
+				partition := d.int32()
+				records := d.next(int(d.int32()))
+				mu.Lock()
+				produced++
+				code := int16(0)
+				if produced == 1 {
+					code = 6 // NOT_LEADER_OR_FOLLOWER
+				}
+				mu.Unlock()
+				if code == 0 {
+					rb := kafkaDecoder{buf: records}
+					rb.next(8 + 4 + 4)
+					if magic := rb.int8(); magic != 2 {
+						t.Errorf("unexpected magic %d", magic)
+					}
+					crc := uint32(rb.int32())
+					if crc != crc32.Checksum(rb.buf, crc32.MakeTable(crc32.Castagnoli)) {
+						t.Errorf("crc mismatch")
+					}
+					rb.next(2 + 4 + 8 + 8 + 8 + 2 + 4)
+					n := rb.int32()
+					for i := int32(0); i < n; i++ {
+						l, k := binary.Varint(rb.buf)
+						rb.next(k)
+						rec := rb.next(int(l))
+						r := rec[1:]
+						for j := 0; j < 3; j++ { // timestamp delta, offset delta, key length
+							_, k := binary.Varint(r)
+							r = r[k:]
+						}
+						vl, k := binary.Varint(r)
+						got <- string(r[k : k+int(vl)])
+					}
+				}
+				resp.int32(1)
+				resp.string(topic)
+				resp.int32(1)
+				resp.int32(partition)
+				resp.int16(code)
+				resp.int64(0)
+				resp.int64(-1)
+				resp.int32(0)
+			}
+			binary.BigEndian.PutUint32(resp.buf, uint32(len(resp.buf)-4))
+			if _, err := conn.Write(resp.buf); err != nil {
+				return
+			}
+		}
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestKafkaCore(t *testing.T) {
+	got := make(chan string, 10)
+	addr := fakeKafka(t, "app-logs", got)
+
+	log, err := NewWithCore(WithKafkaCore([]string{addr}, "app-logs",
+		WithBatch(10, time.Hour),
+		WithRetry(2, time.Millisecond),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Infow("paid", "order_id", 1)
+	log.Warn("slow")
+	if err := log.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	for _, want := range []string{`"order_id":1`, `"msg":"slow"`} {
+		select {
+		case line := <-got:
+			if !strings.Contains(line, want) {
+				t.Fatalf("record %s missing %s", line, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+}
+
+func TestSyslogCore(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	msgs := make(chan string, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			// octet-counting：长度 + 空格 + 消息
+			var n int
+			if _, err := fmt.Fscanf(r, "%d ", &n); err != nil {
+				return
+			}
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			msgs <- string(buf)
+		}
+	}()
+
+	log, err := NewWithCore(WithSyslogCore("tcp", ln.Addr().String(), FacilityLocal0, WithLogLevel(zap.InfoLevel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	log.Debug("hidden")
+	log.Infow("started", "port", 8080)
+	log.Error("failed")
+
+	// local0 = 16：info 为 16*8+6，error 为 16*8+3
+	for _, want := range []string{`<134>1 `, `<131>1 `} {
+		select {
+		case msg := <-msgs:
+			if !strings.HasPrefix(msg, want) || !strings.Contains(msg, fmt.Sprintf(" %d - - {", os.Getpid())) {
+				t.Fatalf("unexpected syslog message %q, want prefix %q", msg, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timeout waiting for %s", want)
+		}
+	}
+}
+
+func TestJournaldCore(t *testing.T) {
+	type sent struct {
+		msg  string
+		pri  journal.Priority
+		vars map[string]string
+	}
+	var got []sent
+	core := &journaldCore{LevelEnabler: zap.InfoLevel, identifier: "order-svc",
+		send: func(msg string, pri journal.Priority, vars map[string]string) error {
+			got = append(got, sent{msg, pri, vars})
+			return nil
+		}}
+	log := zap.New(core, zap.AddCaller()).Named("http").With(zap.String("request-id", "r1"))
+	log.Debug("hidden")
+	log.Warn("slow query", zap.Int("order_id", 42), zap.Duration("cost", time.Second), zap.String("MESSAGE", "x"))
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(got))
+	}
+	e := got[0]
+	if e.msg != "slow query" || e.pri != journal.PriWarning {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+	want := map[string]string{
+		"SYSLOG_IDENTIFIER": "order-svc",
+		"LOGGER":            "http",
+		"REQUEST_ID":        "r1",
+		"ORDER_ID":          "42",
+		"COST":              "1000000000",
+	}
+	for k, v := range want {
+		if e.vars[k] != v {
+			t.Fatalf("%s = %q, want %q (%v)", k, e.vars[k], v, e.vars)
+		}
+	}
+	if _, ok := e.vars["MESSAGE"]; ok || e.vars["CODE_FILE"] == "" || e.vars["CODE_LINE"] == "" {
+		t.Fatalf("unexpected builtin fields %v", e.vars)
+	}
+}
+
+func TestElasticsearchCore(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		n := len(bodies)
+		mu.Unlock()
+		if n == 1 {
+			// 第一条限流可重试，第二条 mapping 错误直接进入死信
+			fmt.Fprint(w, `{"errors":true,"items":[{"create":{"status":429,"error":{"type":"es_rejected_execution_exception"}}},{"create":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad"}}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"errors":false,"items":[{"create":{"status":201}}]}`)
+	}))
+	defer srv.Close()
+
+	deadLetter := filepath.Join(t.TempDir(), "dead.log")
+	log, err := NewWithCore(WithElasticsearchCore(srv.URL, "logs-order-{date}",
+		WithBatch(10, time.Hour),
+		WithRetry(2, time.Millisecond),
+		WithDeadLetter(deadLetter),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Infow("paid", "order_id", 1)
+	log.Infow("bad", "order_id", "x")
+	_ = log.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 2 {
+		t.Fatalf("expected 2 bulk requests, got %d", len(bodies))
+	}
+	index := "logs-order-" + time.Now().UTC().Format("2006.01.02")
+	if !strings.Contains(bodies[0], `{"create":{"_index":"`+index+`"}}`) || !strings.Contains(bodies[0], `"@timestamp":`) {
+		t.Fatalf("unexpected bulk body %s", bodies[0])
+	}
+	if strings.Count(bodies[1], "\n") != 2 || !strings.Contains(bodies[1], `"order_id":1`) {
+		t.Fatalf("expected only the throttled entry to be retried, got %s", bodies[1])
+	}
+	data, err := os.ReadFile(deadLetter)
+	if err != nil || strings.Count(string(data), "\n") != 1 || !strings.Contains(string(data), `"msg":"bad"`) {
+		t.Fatalf("unexpected dead letter %q (%v)", data, err)
+	}
+}
+
+func TestFluentCore(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	type event struct {
+		tag    string
+		time   time.Time
+		record map[string]any
+	}
+	events := make(chan event, 10)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		dec := msgpack.NewDecoder(conn)
+		for {
+			if _, err := dec.DecodeArrayLen(); err != nil {
+				return
+			}
+			tag, _ := dec.DecodeString()
+			n, _ := dec.DecodeArrayLen()
+			for i := 0; i < n; i++ {
+				_, _ = dec.DecodeArrayLen()
+				if id, l, err := dec.DecodeExtHeader(); err != nil || id != 0 || l != 8 {
+					t.Errorf("unexpected event time ext %d/%d: %v", id, l, err)
+					return
+				}
+				ts := make([]byte, 8)
+				_ = dec.ReadFull(ts)
+				record, _ := dec.DecodeMap()
+				events <- event{tag, time.Unix(int64(binary.BigEndian.Uint32(ts)), int64(binary.BigEndian.Uint32(ts[4:]))), record}
+			}
+			option, _ := dec.DecodeMap()
+			if option["chunk"] == nil {
+				t.Errorf("missing chunk in ack mode: %v", option)
+				return
+			}
+			b, _ := msgpack.Marshal(map[string]any{"ack": option["chunk"]})
+			_, _ = conn.Write(b)
+		}
+	}()
+
+	log, err := NewWithCore(WithFluentCore(ln.Addr().String(), "app.order", WithFluentAck(), WithBatch(10, time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Infow("paid", "order_id", 42, "amount", 9.5)
+	if err := log.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	select {
+	case e := <-events:
+		if e.tag != "app.order" || time.Since(e.time) > time.Minute {
+			t.Fatalf("unexpected event %+v", e)
+		}
+		if e.record["msg"] != "paid" || fmt.Sprint(e.record["order_id"]) != "42" || e.record["amount"] != 9.5 {
+			t.Fatalf("unexpected record %#v", e.record)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for event")
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var logs *observer.ObservedLogs
+	log, err := NewWithCore(WithCore("sampled", func(level zapcore.LevelEnabler) zapcore.Core {
+		var core zapcore.Core
+		core, logs = observer.New(level)
+		return core
+	}, WithLogLevel(zap.DebugLevel), WithSampling(2, 3, time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		log.Info("hot path")
+		log.Error("failed")
+	}
+	// info：第 1、2 条及之后每 3 条 1 条（第 5、8 条）；error 不采样
+	if n := logs.FilterMessage("hot path").Len(); n != 4 {
+		t.Fatalf("expected 4 sampled info entries, got %d", n)
+	}
+	if n := logs.FilterMessage("failed").Len(); n != 10 {
+		t.Fatalf("expected all error entries, got %d", n)
+	}
+	if n := testutil.ToFloat64(samplingDropped.WithLabelValues("sampled")); n != 6 {
+		t.Fatalf("dropped metric = %v, want 6", n)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	var logs *observer.ObservedLogs
+	log, err := NewWithCore(WithCore("dedup", func(level zapcore.LevelEnabler) zapcore.Core {
+		var core zapcore.Core
+		core, logs = observer.New(level)
+		return core
+	}, WithDedup(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db := log.With("db", "orders")
+	for i := 0; i < 5; i++ {
+		db.Error("connection refused")
+	}
+	log.Warn("other")
+	_ = log.Close()
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %v", len(entries), entries)
+	}
+	last := entries[2].ContextMap()
+	if entries[2].Message != "connection refused" || last["repeated"] != int64(4) || last["db"] != "orders" {
+		t.Fatalf("unexpected summary %v %v", entries[2].Message, last)
+	}
+}
+
+func TestDedupWindow(t *testing.T) {
+	var logs *observer.ObservedLogs
+	log, err := NewWithCore(WithCore("dedup", func(level zapcore.LevelEnabler) zapcore.Core {
+		var core zapcore.Core
+		core, logs = observer.New(level)
+		return core
+	}, WithDedup(time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	now := time.Now()
+	core := log.Desugar().Core()
+	write := func(at time.Time) {
+		ent := zapcore.Entry{Level: zapcore.InfoLevel, Message: "tick", Time: at}
+		if ce := core.Check(ent, nil); ce != nil {
+			ce.Write()
+		}
+	}
+	write(now)
+	write(now.Add(time.Minute))
+	write(now.Add(2 * time.Minute))
+	// 窗口结束后的下一条先输出上一窗口的汇总
+	write(now.Add(2 * time.Hour))
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 || entries[1].ContextMap()["repeated"] != int64(2) || len(entries[2].Context) != 0 {
+		t.Fatalf("unexpected entries %v", entries)
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	var logs *observer.ObservedLogs
+	log, err := NewWithCore(WithCore("redact", func(level zapcore.LevelEnabler) zapcore.Core {
+		var core zapcore.Core
+		core, logs = observer.New(level)
+		return core
+	}, WithRedaction([]string{"Password", "phone", "api_key"}, []*regexp.Regexp{regexp.MustCompile(`1[3-9]\d{9}`)})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.With("password", "s3cret").Infow("login from 13800138000",
+		"phone", 13800138000,
+		"api_key", "abcdef",
+		"note", "call 13912345678",
+		"user", "alice",
+		zap.Error(errors.New("sms to 13700001111 failed")),
+	)
+
+	e := logs.All()[0]
+	fields := e.ContextMap()
+	if strings.Contains(e.Message, "13800138000") {
+		t.Fatalf("message not redacted: %s", e.Message)
+	}
+	want := map[string]any{
+		"password": "******",
+		"phone":    "138****8000",
+		"api_key":  "******",
+		"user":     "alice",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Fatalf("%s = %v, want %v", k, fields[k], v)
+		}
+	}
+	for _, k := range []string{"note", "error"} {
+		if s := fmt.Sprint(fields[k]); strings.Contains(s, "13912345678") || strings.Contains(s, "13700001111") {
+			t.Fatalf("%s not redacted: %s", k, s)
+		}
+	}
+}
+
+func TestAsyncWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "async.log")
+	log, err := NewWithCore(WithFileCore(WithLogFilePath(path), WithAsyncWrite(1<<20, time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("buffered")
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Fatalf("expected entry to stay in buffer, got %q", data)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"msg":"buffered"`) {
+		t.Fatalf("entry not flushed on close: %q", data)
+	}
+}
+
+func TestCallerOptions(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	observe := func(c *zapcore.Core) { *c = core }
+
+	log, _ := NewWithCore(observe)
+	log.Info("direct")
+	wrapped, _ := NewWithOptions([]Option{WithCallerSkip(1)}, observe)
+	helper := func(msg string) { wrapped.Info(msg) }
+	helper("helper")
+	for _, e := range logs.TakeAll() {
+		if !strings.HasSuffix(e.Caller.Function, ".TestCallerOptions") {
+			t.Fatalf("%s: unexpected caller %s", e.Message, e.Caller.Function)
+		}
+	}
+
+	noCaller, _ := NewWithOptions([]Option{WithCaller(false)}, observe)
+	noCaller.Info("no caller")
+	if e := logs.TakeAll(); len(e) != 1 || e[0].Caller.Defined {
+		t.Fatalf("unexpected entries %+v", e)
+	}
+}
+
+func TestStacktraceOptions(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	observe := func(c *zapcore.Core) { *c = core }
+
+	log, _ := NewWithCore(observe)
+	log.Error("default")
+	panicOnly, _ := NewWithOptions([]Option{WithStacktraceLevel(zap.PanicLevel)}, observe)
+	panicOnly.Error("panic only")
+	none, _ := NewWithOptions([]Option{WithoutStacktrace()}, observe)
+	none.DPanic("none")
+
+	entries := logs.TakeAll()
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+	if entries[0].Stack == "" || entries[1].Stack != "" || entries[2].Stack != "" {
+		t.Fatalf("unexpected stacks %q %q %q", entries[0].Stack, entries[1].Stack, entries[2].Stack)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	dev, err := NewDevelopment()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dev.Desugar().Core().Enabled(zap.DebugLevel) {
+		t.Fatal("development logger should enable debug")
+	}
+	if _, err := os.Stat("logs"); !os.IsNotExist(err) {
+		t.Fatalf("development logger should not write files: %v", err)
+	}
+
+	prod, err := NewProduction("order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod.Desugar().Core().Enabled(zap.DebugLevel) {
+		t.Fatal("production logger should not enable debug")
+	}
+	prod.Info("started")
+	if err := prod.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile("logs/order.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(data, &entry); err != nil || entry["service"] != "order" || entry["msg"] != "started" {
+		t.Fatalf("unexpected entry %s: %v", data, err)
+	}
+}
+
+func TestConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	files := map[string]string{
+		"log.yaml": "level: warn\nstacktrace_level: none\noutputs:\n  - type: file\n    path: " + path +
+			"\n    level: debug\n    max_backups: 3\n    modules: {mysql: error}\n",
+		"log.json": `{"level": "warn", "stacktrace_level": "none", "outputs": [{"type": "file", "path": "` + path +
+			`", "level": "debug", "max_backups": 3, "modules": {"mysql": "error"}}]}`,
+		"log.toml": "level = \"warn\"\nstacktrace_level = \"none\"\n[[outputs]]\ntype = \"file\"\npath = \"" + path +
+			"\"\nlevel = \"debug\"\nmax_backups = 3\nmodules = {mysql = \"error\"}\n",
+	}
+	for name, content := range files {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(file)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		out := cfg.Outputs[0]
+		if cfg.Level != zap.WarnLevel || out.Level == nil || *out.Level != zap.DebugLevel ||
+			out.MaxBackups != 3 || out.Modules["mysql"] != zap.ErrorLevel {
+			t.Fatalf("%s: unexpected config %+v", name, cfg)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	_ = os.WriteFile(bad, []byte("levle: info\n"), 0o644)
+	if _, err := LoadConfig(bad); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+
+	log, err := NewFromFile(filepath.Join(dir, "log.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Debug("debug")
+	log.Named("mysql").Warn("dropped")
+	log.Error("no stack")
+	_ = log.Close()
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"msg":"debug"`) || strings.Contains(string(data), "dropped") ||
+		strings.Contains(string(data), "stacktrace") {
+		t.Fatalf("unexpected output %s", data)
+	}
+
+	// 子包注册的输出类型，未导入时提示需要导入的包
+	if _, _, err := (&Config{Outputs: []OutputConfig{{Type: "otlp"}}}).compile(); err == nil || !strings.Contains(err.Error(), "logger/otellog") {
+		t.Fatalf("expected import hint, got %v", err)
+	}
+	RegisterOutput("custom", func(o *OutputConfig, opts []Option) (CoreBuilder, error) {
+		return WithWriterCore(io.Discard, nil, zap.InfoLevel), nil
+	})
+	if _, builders, err := (&Config{Outputs: []OutputConfig{{Type: "Custom"}}}).compile(); err != nil || len(builders) != 1 {
+		t.Fatalf("unexpected registered output %v %v", builders, err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("APP_LOG_LEVEL", "debug")
+	t.Setenv("APP_LOG_CALLER", "false")
+	t.Setenv("APP_LOG_OUTPUTS", "file, loki")
+	t.Setenv("APP_LOG_FILE_PATH", filepath.Join(t.TempDir(), "env.log"))
+	t.Setenv("APP_LOG_FILE_MODULES", "mysql=warn,http=error")
+	t.Setenv("APP_LOG_LOKI_URL", "http://loki:3100")
+	t.Setenv("APP_LOG_LOKI_LABELS", "service=order")
+
+	cfg, err := ConfigFromEnv("APP_LOG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Level != zap.DebugLevel || cfg.Caller == nil || *cfg.Caller || len(cfg.Outputs) != 2 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	file, loki := cfg.Outputs[0], cfg.Outputs[1]
+	if file.Type != "file" || file.Modules["http"] != zap.ErrorLevel || loki.URL != "http://loki:3100" ||
+		loki.Labels["service"] != "order" {
+		t.Fatalf("unexpected outputs %+v", cfg.Outputs)
+	}
+
+	t.Setenv("APP_LOG_FILE_MAX_SIZE", "big")
+	if _, err := ConfigFromEnv("APP_LOG"); err == nil || !strings.Contains(err.Error(), "APP_LOG_FILE_MAX_SIZE") {
+		t.Fatalf("expected error naming the variable, got %v", err)
+	}
+	if _, err := (&Config{Outputs: []OutputConfig{{Type: "kafka"}}}).Build(); err == nil {
+		t.Fatal("expected error for kafka without brokers")
+	}
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.yaml")
+	logPath := filepath.Join(dir, "app.log")
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("level: info\noutputs:\n  - type: file\n    path: " + logPath + "\n")
+
+	errs := make(chan error, 1)
+	log, err := WatchConfig(path, func(err error) { errs <- err })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	child := log.Named("order").With("id", 1)
+	child.Debug("before")
+
+	write("level: debug\noutputs:\n  - type: file\n    path: " + logPath + "\n")
+	deadline := time.Now().Add(5 * time.Second)
+	for log.Levels()["file"] != zap.DebugLevel {
+		if time.Now().After(deadline) {
+			t.Fatal("config not reloaded")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	child.Debug("after")
+	_ = log.Sync()
+	data, _ := os.ReadFile(logPath)
+	if strings.Contains(string(data), "before") || !strings.Contains(string(data), `"msg":"after","id":1`) {
+		t.Fatalf("unexpected output %s", data)
+	}
+
+	write("level: debug\noutputs: []\n")
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "no outputs") {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("invalid config not reported")
+	}
+	if log.Levels()["file"] != zap.DebugLevel {
+		t.Fatal("invalid config should keep previous outputs")
+	}
+
+	static, _ := NewWithCore(WithConsoleCore())
+	if err := static.Reload(&Config{}); !errors.Is(err, ErrNotReloadable) {
+		t.Fatalf("expected ErrNotReloadable, got %v", err)
+	}
+}
+
+func TestGlobalFields(t *testing.T) {
+	t.Setenv("APP_ENV", "staging")
+	core, logs := observer.New(zap.DebugLevel)
+	log, err := NewWithOptions([]Option{
+		WithServiceInfo("order", ""),
+		WithGlobalFields(zap.String("region", "cn-east")),
+	}, func(c *zapcore.Core) { *c = core })
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Named("db").With("table", "orders").Info("query")
+
+	fields := logs.All()[0].ContextMap()
+	host, _ := os.Hostname()
+	if fields[KeyHost] != host || fields[KeyPID] != int64(os.Getpid()) || fields[KeyService] != "order" ||
+		fields[KeyEnv] != "staging" || fields["region"] != "cn-east" || fields["table"] != "orders" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+	if _, ok := fields["version"]; !ok {
+		t.Fatalf("missing version field: %v", fields)
+	}
+}
+
+func TestDesugarFields(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	log, _ := NewWithCore(func(c *zapcore.Core) { *c = core })
+	SetDefault(log)
+	defer SetDefault(nil)
+
+	L().Info("request", Method("GET"), Path("/orders"), Status(200), Duration(time.Second),
+		ClientIP("10.0.0.1"), UserID("u1"), TraceID("t1"), Component("api"))
+	e := logs.All()[0]
+	if !strings.HasSuffix(e.Caller.Function, ".TestDesugarFields") {
+		t.Fatalf("unexpected caller %s", e.Caller.Function)
+	}
+	want := map[string]any{
+		"method": "GET", "path": "/orders", "status": int64(200), "duration": time.Second,
+		"client_ip": "10.0.0.1", "user_id": "u1", KeyTraceID: "t1", "component": "api",
+	}
+	if fields := e.ContextMap(); fmt.Sprint(fields) != fmt.Sprint(want) {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+}
+
+func TestConsoleColor(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if colorEnabled(f) {
+		t.Fatal("regular file should not be colored")
+	}
+
+	encode := func(enabled bool) string {
+		cfg := DefaultConfig.clone()
+		WithColorOutput(enabled)(cfg)
+		buf, err := zapcore.NewConsoleEncoder(cfg.Encoder).EncodeEntry(zapcore.Entry{Level: zap.WarnLevel, Message: "m"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+	if plain := encode(false); strings.Contains(plain, "\x1b[") || !strings.Contains(plain, "WARN") {
+		t.Fatalf("unexpected plain output %q", plain)
+	}
+	if colored := encode(true); !strings.Contains(colored, "\x1b[33mWARN") {
+		t.Fatalf("unexpected colored output %q", colored)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	if colorEnabled(os.Stdout) {
+		t.Fatal("NO_COLOR should disable colors")
+	}
+}
+
+func TestStderrLevel(t *testing.T) {
+	dir := t.TempDir()
+	stdout, _ := os.Create(filepath.Join(dir, "stdout"))
+	stderr, _ := os.Create(filepath.Join(dir, "stderr"))
+	oldOut, oldErr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	log, err := NewWithCore(WithConsoleCore(WithLogLevel(zap.DebugLevel), WithStderrLevel(zap.WarnLevel)))
+	os.Stdout, os.Stderr = oldOut, oldErr
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.With("k", "v").Debug("debug line")
+	log.Info("info line")
+	log.Warn("warn line")
+	log.Error("error line")
+	_ = log.Close()
+	_ = stdout.Close()
+	_ = stderr.Close()
+
+	out, _ := os.ReadFile(stdout.Name())
+	errOut, _ := os.ReadFile(stderr.Name())
+	if !strings.Contains(string(out), "debug line") || !strings.Contains(string(out), "info line") ||
+		strings.Contains(string(out), "warn line") {
+		t.Fatalf("unexpected stdout %q", out)
+	}
+	if !strings.Contains(string(errOut), "warn line") || !strings.Contains(string(errOut), "error line") ||
+		strings.Contains(string(errOut), "info line") {
+		t.Fatalf("unexpected stderr %q", errOut)
+	}
+}
+
+func TestLogfmtEncoding(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := NewWithCore(WithFileCore(WithLogFilePath(path), WithEncoding(EncodingLogfmt)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Named("api").Infow("request done", "status", 200, "path", "/orders list")
+	_ = log.Close()
+
+	data, _ := os.ReadFile(path)
+	line := strings.TrimSpace(string(data))
+	for _, want := range []string{"level=info", "logger=api", `msg="request done"`, "status=200", `path="/orders list"`} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("missing %s in %q", want, line)
+		}
+	}
+	if _, err := (&Config{Outputs: []OutputConfig{{Type: "file", Encoding: "xml"}}}).Build(); err == nil {
+		t.Fatal("expected error for unknown encoding")
+	}
+}
+
+func TestPrettyEncoder(t *testing.T) {
+	enc := newPrettyEncoder(false)
+	enc.AddString("service", "order")
+	ent := zapcore.Entry{
+		Level:      zap.WarnLevel,
+		Time:       time.Date(2024, 5, 1, 10, 4, 5, 123e6, time.UTC),
+		LoggerName: "api",
+		Message:    "slow request",
+		Stack:      "main.main\n\tmain.go:10",
+	}
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{
+		zap.Duration("elapsed", 1500*time.Millisecond),
+		zap.Any("user", map[string]any{"id": 1}),
+		zap.String("note", "line1\nline2"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "10:04:05.123 WARN   api  slow request\n" +
+		"    elapsed: 1.5s\n" +
+		"    note: line1\n        line2\n" +
+		"    service: order\n" +
+		"    user: {\"id\":1}\n" +
+		"    stacktrace:\n        main.main\n        \tmain.go:10\n"
+	if buf.String() != want {
+		t.Fatalf("unexpected output:\n%s\nwant:\n%s", buf, want)
+	}
+	if len(enc.Fields) != 1 {
+		t.Fatal("entry fields leaked into the encoder")
+	}
+
+	colored, _ := newPrettyEncoder(true).EncodeEntry(ent, nil)
+	if !strings.Contains(colored.String(), "\x1b[33mWARN") {
+		t.Fatalf("expected colored level in %q", colored)
+	}
+}
+
+func TestWriterCore(t *testing.T) {
+	var buf strings.Builder
+	log, err := NewWithCore(
+		WithWriterCore(&buf, nil, zap.DebugLevel, WithName("memory")),
+		WithWriterCore(io.Discard, zapcore.NewConsoleEncoder(DefaultConfig.Encoder), zap.ErrorLevel),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Debugw("concurrent", "i", i)
+		}()
+	}
+	wg.Wait()
+	if err := log.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("expected 10 lines, got %d: %s", len(lines), buf.String())
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("interleaved output %q", line)
+		}
+	}
+	if levels := log.Levels(); levels["memory"] != zap.DebugLevel || levels["writer"] != zap.ErrorLevel {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+
+	stdout, _ := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	defer stdout.Close()
+	old := os.Stdout
+	os.Stdout = stdout
+	console, _ := NewWithCore(WithConsoleCore())
+	os.Stdout = old
+	_ = console.Close()
+	if _, err := stdout.WriteString("still open"); err != nil {
+		t.Fatalf("Close should not close stdout: %v", err)
+	}
+}
+
+func TestReopenSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log, err := NewWithCore(WithFileCore(WithLogFilePath(path), WithReopenSignal()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+
+	log.Info("before")
+	// 模拟 logrotate：改名后发送 SIGHUP
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		log.Info("after")
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("log file was not reopened")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rotated, _ := os.ReadFile(path + ".1")
+	current, _ := os.ReadFile(path)
+	if !strings.Contains(string(rotated), `"before"`) || strings.Contains(string(current), `"before"`) {
+		t.Fatalf("unexpected rotated %q", rotated)
+	}
+	if !strings.Contains(string(current), `"after"`) {
+		t.Fatalf("unexpected current %q", current)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	if os.Getenv("LOGGER_FATAL_CHILD") == "1" {
+		log, _ := NewWithOptions([]Option{
+			WithFatalHook(func(zapcore.Entry) { panic("broken hook") }),
+			WithFatalHook(func(ent zapcore.Entry) { fmt.Println("fatal hook:", ent.Message) }),
+		}, WithWriterCore(io.Discard, nil, zap.InfoLevel))
+		log.Fatal("bye")
+		fmt.Println("not reached")
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestHooks$")
+	cmd.Env = append(os.Environ(), "LOGGER_FATAL_CHILD=1")
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("expected exit code 1, got %v", err)
+	}
+	if !strings.Contains(string(out), "fatal hook: bye") || strings.Contains(string(out), "not reached") {
+		t.Fatalf("unexpected output %q", out)
+	}
+
+	var got []string
+	var buf strings.Builder
+	log, _ := NewWithOptions([]Option{WithPanicHook(func(ent zapcore.Entry) {
+		got = append(got, ent.Message)
+	})}, WithWriterCore(&buf, nil, zap.InfoLevel))
+	log.Named("db").Error("not a panic")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic")
+			}
+		}()
+		log.Named("db").Panic("boom")
+	}()
+	if len(got) != 1 || got[0] != "boom" || !strings.Contains(buf.String(), "boom") {
+		t.Fatalf("unexpected hooks %v, output %q", got, buf.String())
+	}
+}
+
+func TestLoggingMetrics(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.log")
+	log, err := NewWithCore(WithFileCore(WithLogFilePath(path), WithName("metrics"), WithRotateSettings(1, 0, false)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := testutil.ToFloat64(entriesTotal.WithLabelValues("error"))
+	payload := strings.Repeat("x", 1000)
+	for i := 0; i < 3000; i++ {
+		log.Errorw("big", "payload", payload)
+	}
+	log.Debug("disabled")
+	_ = log.Close()
+
+	if n := testutil.ToFloat64(entriesTotal.WithLabelValues("error")) - before; n != 3000 {
+		t.Fatalf("expected 3000 error entries, got %v", n)
+	}
+	var size int64
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		info, _ := e.Info()
+		size += info.Size()
+	}
+	if n := testutil.ToFloat64(sinkBytes.WithLabelValues("metrics")); n != float64(size) {
+		t.Fatalf("expected %d bytes, got %v", size, n)
+	}
+	// 每次轮转产生一个备份文件
+	if n := testutil.ToFloat64(rotations.WithLabelValues(path)); n != float64(len(entries)-1) || n < 2 {
+		t.Fatalf("expected %d rotations, got %v", len(entries)-1, n)
+	}
+	if err := RegisterMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	key := []byte("0123456789abcdef0123456789abcdef")
+	log, err := NewWithCore(WithFileCore(
+		WithLogFilePath(path),
+		WithRotateSettings(1, 0, true),
+		WithMaxBackups(2),
+		WithArchive(CompressionZstd, key),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := strings.Repeat("x", 1000)
+	for i := 0; i < 3500; i++ {
+		log.Infow("big", "payload", payload)
+	}
+	_ = log.Close()
+
+	var archives []string
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		archives, _ = filepath.Glob(filepath.Join(dir, "app-*"))
+		if len(archives) == 2 && strings.HasSuffix(archives[0], ".log.zst.enc") && strings.HasSuffix(archives[1], ".log.zst.enc") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("unexpected archives %v", archives)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	r, err := OpenArchive(archives[1], key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(r)
+	_ = r.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 900 || !json.Valid([]byte(lines[len(lines)-1])) {
+		t.Fatalf("unexpected archive content: %d lines", len(lines))
+	}
+
+	wrong := []byte("fedcba9876543210fedcba9876543210")
+	if r, err := OpenArchive(archives[1], wrong); err == nil {
+		_, err = io.ReadAll(r)
+		_ = r.Close()
+		if !errors.Is(err, ErrArchiveCorrupted) {
+			t.Fatalf("expected ErrArchiveCorrupted, got %v", err)
+		}
+	}
+	if _, _, err := (&Config{Outputs: []OutputConfig{{Type: "file", Compression: "zstd", EncryptionKey: "abcd"}}}).compile(); !errors.Is(err, ErrArchiveKey) {
+		t.Fatalf("expected ErrArchiveKey, got %v", err)
+	}
+
+	// 不压缩也不加密时不处理，备份保持原样
+	cfg := DefaultConfig.clone()
+	WithArchive(CompressionZstd, nil)(cfg)
+	WithArchive("", nil)(cfg)
+	if cfg.Archive != nil {
+		t.Fatalf("expected empty archive to be a no-op, got %+v", cfg.Archive)
+	}
+	backup := filepath.Join(dir, "plain.log")
+	_ = os.WriteFile(backup, []byte("keep"), 0o644)
+	if err := archiveFile(backup, &ArchiveConfig{}, FilePerm{}); err == nil {
+		t.Fatal("expected empty archive config to be rejected")
+	}
+	if data, err := os.ReadFile(backup); err != nil || string(data) != "keep" {
+		t.Fatalf("backup was modified: %q %v", data, err)
+	}
+}
+
+func TestAudit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	key := []byte("audit-secret")
+	if _, err := NewAudit(nil, WithLogFilePath(path)); !errors.Is(err, ErrAuditKey) {
+		t.Fatalf("expected ErrAuditKey, got %v", err)
+	}
+	audit, err := NewAudit(key, WithLogFilePath(path), WithLogLevel(zap.FatalLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range []string{"user.create", "user.update"} {
+		if err := audit.Log(AuditEvent{Actor: "u1", Action: action, Resource: "user/42", Result: "success"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = audit.Close()
+
+	// 崩溃时写了一半的末行被截掉，重启后从最后一条完整记录接续哈希链
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	_, _ = f.WriteString(`{"time":"2024-01-01T00:00:00Z","actor":"u`)
+	_ = f.Close()
+	audit, err = NewAudit(key, WithLogFilePath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = audit.Log(AuditEvent{Actor: "u2", Action: "user.delete", Resource: "user/42", Result: "denied", Details: map[string]any{"reason": "no permission"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := audit.LastHash()
+	_ = audit.Close()
+
+	data, _ := os.ReadFile(path)
+	if got, err := VerifyAudit(strings.NewReader(string(data)), key, ""); err != nil || got != last {
+		t.Fatalf("verify: %v %s\n%s", err, got, data)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &rec); err != nil || rec["actor"] != "u2" || rec["result"] != "denied" {
+		t.Fatalf("unexpected record %s", lines[2])
+	}
+
+	tampered := strings.Replace(string(data), `"result":"denied"`, `"result":"success"`, 1)
+	if _, err := VerifyAudit(strings.NewReader(tampered), key, ""); !errors.Is(err, ErrAuditTampered) || !strings.Contains(err.Error(), "line 3") {
+		t.Fatalf("expected tampering at line 3, got %v", err)
+	}
+	removed := lines[0] + "\n" + lines[2] + "\n"
+	if _, err := VerifyAudit(strings.NewReader(removed), key, ""); !errors.Is(err, ErrAuditTampered) {
+		t.Fatalf("expected removed record to be detected, got %v", err)
+	}
+
+	// 不知道 key 时无法重新计算修改后的哈希
+	if _, err := VerifyAudit(strings.NewReader(string(data)), []byte("other"), ""); !errors.Is(err, ErrAuditTampered) || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("expected wrong key to fail, got %v", err)
+	}
+	if _, err := NewAudit([]byte("other"), WithLogFilePath(path)); !errors.Is(err, ErrAuditTampered) {
+		t.Fatalf("expected wrong key to fail on restart, got %v", err)
+	}
+
+	// 截掉末尾记录时与外部保存的 LastHash 不一致
+	if got, err := VerifyAudit(strings.NewReader(lines[0]+"\n"+lines[1]+"\n"), key, ""); err != nil || got == last {
+		t.Fatalf("expected truncated tail to differ from last hash, got %s %v", got, err)
+	}
+}
+
+func panicky() {
+	panic("boom")
+}
+
+func TestRecover(t *testing.T) {
+	var buf strings.Builder
+	log, _ := NewWithCore(WithWriterCore(&buf, nil, zap.InfoLevel))
+
+	func() {
+		defer Recover(log)
+		panicky()
+	}()
+	var rec map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec["level"] != "error" || rec["panic"] != "boom" || rec["stacktrace"] != nil {
+		t.Fatalf("unexpected record %v", rec)
+	}
+	if stack, _ := rec["stack"].(string); !strings.Contains(stack, "logger.panicky") {
+		t.Fatalf("stack should include the panicking function: %s", stack)
+	}
+
+	done := make(chan any)
+	log.Go(panicky, WithOnPanic(func(p any) { done <- p }))
+	select {
+	case p := <-done:
+		if p != "boom" {
+			t.Fatalf("unexpected panic value %v", p)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("panic was not recovered")
+	}
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("expected re-panic, got %v", p)
+		}
+	}()
+	defer Recover(log, WithRepanic())
+	panicky()
+}
+
+func TestNewOptions(t *testing.T) {
+	t.Chdir(t.TempDir())
+	log, err := New(WithLogFilePath("app/app.log"), WithLogLevel(zap.DebugLevel), WithGlobalFields(zap.String("app", "demo")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Debug("hello")
+	_ = log.Close()
+	data, _ := os.ReadFile("app/app.log")
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 1 || !strings.Contains(lines[0], `"app":"demo"`) {
+		t.Fatalf("unexpected file content %q", data)
+	}
+	if levels := log.Levels(); len(levels) != 2 || levels["console"] != zap.DebugLevel {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+
+	var buf strings.Builder
+	log, err = New(WithCores(WithWriterCore(&buf, nil, zap.InfoLevel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("only writer")
+	if _, err := os.Stat("logs/zap.log"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("default file should not be created: %v", err)
+	}
+	if !strings.Contains(buf.String(), "only writer") {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+func TestFilePerm(t *testing.T) {
+	old := syscall.Umask(0o077)
+	defer syscall.Umask(old)
+
+	dir := filepath.Join(t.TempDir(), "logs", "app")
+	path := filepath.Join(dir, "app.log")
+	log, err := NewWithCore(WithFileCore(
+		WithLogFilePath(path),
+		WithRotateSettings(1, 0, false),
+		WithFileMode(0o640),
+		WithDirMode(0o750),
+		WithFileGroup(os.Getgid()),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := strings.Repeat("x", 1000)
+	for i := 0; i < 1500; i++ {
+		log.Infow("big", "payload", payload)
+	}
+	_ = log.Close()
+
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0o750 {
+		t.Fatalf("unexpected dir mode %v, %v", info.Mode(), err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 2 {
+		t.Fatalf("expected a rotated file, got %v", files)
+	}
+	for _, f := range files {
+		if info, _ := os.Stat(f); info.Mode().Perm() != 0o640 {
+			t.Fatalf("unexpected mode %v of %s", info.Mode(), f)
+		}
+	}
+
+	cfg := &Config{Outputs: []OutputConfig{{Type: "file", FileMode: "0640", DirMode: "750", FileGroup: "0"}}}
+	if _, _, err := cfg.compile(); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Outputs[0].FileMode = "rw-r-----"
+	if _, _, err := cfg.compile(); err == nil {
+		t.Fatal("expected invalid file_mode")
+	}
+}
+
+func TestDynamicCores(t *testing.T) {
+	var app, debug strings.Builder
+	log, err := New(WithDynamicCores(), WithCores(WithWriterCore(&app, nil, zap.InfoLevel)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	derived := log.Named("order").With("k", "v")
+
+	name, err := log.AttachCore(WithWriterCore(&debug, nil, zap.DebugLevel, WithName("debug")))
+	if err != nil || name != "debug" {
+		t.Fatalf("attach: %q, %v", name, err)
+	}
+	if second, _ := log.AttachCore(WithWriterCore(io.Discard, nil, zap.InfoLevel)); second != "writer2" {
+		t.Fatalf("expected writer2, got %q", second)
+	}
+	derived.Debug("incident")
+	if app.Len() != 0 || !strings.Contains(debug.String(), `"k":"v"`) {
+		t.Fatalf("unexpected output app=%q debug=%q", app.String(), debug.String())
+	}
+	if levels := log.Levels(); len(levels) != 3 || levels["debug"] != zap.DebugLevel {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+
+	if err := log.DetachCore("debug"); err != nil {
+		t.Fatal(err)
+	}
+	debug.Reset()
+	derived.Info("resolved")
+	if debug.Len() != 0 || !strings.Contains(app.String(), "resolved") {
+		t.Fatalf("unexpected output app=%q debug=%q", app.String(), debug.String())
+	}
+	if err := log.DetachCore("debug"); !errors.Is(err, ErrUnknownCore) {
+		t.Fatalf("expected ErrUnknownCore, got %v", err)
+	}
+
+	static, _ := New(WithCores(WithWriterCore(io.Discard, nil, zap.InfoLevel)))
+	if _, err := static.AttachCore(WithConsoleCore()); !errors.Is(err, ErrNotReloadable) {
+		t.Fatalf("expected ErrNotReloadable, got %v", err)
+	}
+}
+
+// 与 pkg/errors 相同的调用栈表示
+type testFrame uintptr
+type testStackTrace []testFrame
+
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func newStackError(msg string) *stackError {
+	pcs := make([]uintptr, 32)
+	return &stackError{msg: msg, pcs: pcs[:runtime.Callers(2, pcs)]}
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) StackTrace() testStackTrace {
+	st := make(testStackTrace, len(e.pcs))
+	for i, pc := range e.pcs {
+		st[i] = testFrame(pc)
+	}
+	return st
+}
+
+func TestErrorE(t *testing.T) {
+	var buf strings.Builder
+	log, _ := NewWithCore(WithWriterCore(&buf, nil, zap.InfoLevel))
+
+	root := newStackError("connection refused")
+	err := fmt.Errorf("save order: %w", errors.Join(fmt.Errorf("dial db: %w", root), io.EOF))
+	log.ErrorE(err, "request failed", "order_id", 7)
+	log.Debug("disabled")
+
+	var rec struct {
+		Caller string `json:"caller"`
+		Error  string `json:"error"`
+		Chain  []struct {
+			Type string `json:"type"`
+			Msg  string `json:"msg"`
+		} `json:"error_chain"`
+		Cause   string   `json:"error_cause"`
+		Stack   []string `json:"error_stack"`
+		OrderID int      `json:"order_id"`
+	}
+	if err := json.Unmarshal([]byte(buf.String()), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(rec.Caller, "logger/logger_test.go") || rec.Error != err.Error() || rec.OrderID != 7 {
+		t.Fatalf("unexpected record %s", buf.String())
+	}
+	if len(rec.Chain) != 5 || rec.Chain[3].Type != "*logger.stackError" || rec.Chain[4].Msg != "EOF" {
+		t.Fatalf("unexpected chain %+v", rec.Chain)
+	}
+	if rec.Cause != "connection refused" {
+		t.Fatalf("unexpected cause %q", rec.Cause)
+	}
+	if len(rec.Stack) == 0 || !strings.Contains(rec.Stack[0], "logger.TestErrorE") {
+		t.Fatalf("unexpected stack %v", rec.Stack)
+	}
+
+	if fields := ErrorFields(io.EOF); len(fields) != 1 {
+		t.Fatalf("plain errors should only have the error field, got %d", len(fields))
+	}
+	if ErrorFields(nil) != nil {
+		t.Fatal("expected no fields for nil")
+	}
+}
+
+func TestNetworkCore(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				sc := bufio.NewScanner(conn)
+				for sc.Scan() {
+					lines <- sc.Text()
+					// 每个连接只读一条后断开，验证重连
+					return
+				}
+			}()
+		}
+	}()
+	next := func() map[string]any {
+		t.Helper()
+		select {
+		case line := <-lines:
+			var m map[string]any
+			if err := json.Unmarshal([]byte(line), &m); err != nil {
+				t.Fatalf("invalid line %q: %v", line, err)
+			}
+			return m
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for entry")
+			return nil
+		}
+	}
+
+	log, err := NewWithCore(WithNetworkCore("tcp", ln.Addr().String(), WithRetry(5, 10*time.Millisecond)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer log.Close()
+	log.Infow("first", "n", 1)
+	_ = log.Sync()
+	if m := next(); m["msg"] != "first" {
+		t.Fatalf("unexpected entry %v", m)
+	}
+	// 等待服务端关闭连接
+	time.Sleep(50 * time.Millisecond)
+	log.Infow("second", "n", 2)
+	_ = log.Sync()
+	if m := next(); m["msg"] != "second" {
+		t.Fatalf("unexpected entry %v", m)
+	}
+}
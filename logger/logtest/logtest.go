@@ -0,0 +1,95 @@
+// Package logtest 提供写入内存的 Logger，用于在测试中断言日志输出；
+// 不修改全局日志，需要替换 logger.Default() 并在失败时打印日志的场景见 testkit.Logger
+package logtest
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+// Logs 捕获的日志，并发安全
+type Logs struct {
+	obs *observer.ObservedLogs
+}
+
+// New 创建记录 Debug 及以上级别日志的 Logger，opts 同 logger.NewWithOptions，
+// 调用位置、调用栈、全局字段等行为与正式日志一致：
+//
+//	log, logs := logtest.New()
+//	svc := order.NewService(log)
+//	svc.Create(ctx, req)
+//	if !logs.ContainsMessage("order created") || logs.CountAtLevel(zap.ErrorLevel) != 0 {
+//		t.Fatalf("unexpected logs: %v", logs.Messages())
+//	}
+func New(opts ...logger.Option) (*logger.Logger, *Logs) {
+	return NewAt(zapcore.DebugLevel, opts...)
+}
+
+// NewAt 与 New 相同，只记录 level 及以上级别的日志
+func NewAt(level zapcore.Level, opts ...logger.Option) (*logger.Logger, *Logs) {
+	core, obs := observer.New(level)
+	l, err := logger.NewWithOptions(opts, func(c *zapcore.Core) { *c = core })
+	if err != nil {
+		// core 固定存在，不会出错
+		panic(err)
+	}
+	return l, &Logs{obs: obs}
+}
+
+// Entries 返回全部日志
+func (l *Logs) Entries() []observer.LoggedEntry {
+	return l.obs.All()
+}
+
+// Len 返回日志条数
+func (l *Logs) Len() int {
+	return l.obs.Len()
+}
+
+// Messages 按顺序返回全部消息
+func (l *Logs) Messages() []string {
+	entries := l.obs.All()
+	msgs := make([]string, len(entries))
+	for i, e := range entries {
+		msgs[i] = e.Message
+	}
+	return msgs
+}
+
+// ContainsMessage 是否存在消息包含 substr 的日志
+func (l *Logs) ContainsMessage(substr string) bool {
+	return l.obs.FilterMessageSnippet(substr).Len() > 0
+}
+
+// CountAtLevel 返回指定级别的日志条数
+func (l *Logs) CountAtLevel(level zapcore.Level) int {
+	return l.obs.FilterLevelExact(level).Len()
+}
+
+// WithField 返回字段 key 等于 value 的日志；按字符串形式比较，int 与 int64 等数值类型可以直接比较
+func (l *Logs) WithField(key string, value any) []observer.LoggedEntry {
+	want := fmt.Sprint(value)
+	return l.obs.Filter(func(e observer.LoggedEntry) bool {
+		v, ok := e.ContextMap()[key]
+		return ok && fmt.Sprint(v) == want
+	}).All()
+}
+
+// Reset 清空已捕获的日志
+func (l *Logs) Reset() {
+	l.obs.TakeAll()
+}
+
+// String 逐行列出日志，便于断言失败时输出
+func (l *Logs) String() string {
+	var b strings.Builder
+	for _, e := range l.obs.All() {
+		fmt.Fprintf(&b, "[%s] %s %v\n", e.Level, e.Message, e.ContextMap())
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package logtest
+
+import (
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+func TestLogs(t *testing.T) {
+	log, logs := New(logger.WithGlobalFields(zap.String("service", "order")))
+	log.Debugw("loading", "id", 1)
+	log.Named("db").Errorw("query failed", "id", 2)
+	log.Warn("slow")
+
+	if logs.Len() != 3 || !logs.ContainsMessage("query") || logs.ContainsMessage("missing") {
+		t.Fatalf("unexpected logs:\n%s", logs)
+	}
+	if logs.CountAtLevel(zap.ErrorLevel) != 1 || logs.CountAtLevel(zap.InfoLevel) != 0 {
+		t.Fatalf("unexpected level counts:\n%s", logs)
+	}
+	if got := logs.WithField("id", 2); len(got) != 1 || got[0].LoggerName != "db" {
+		t.Fatalf("unexpected field match %+v", got)
+	}
+	if got := logs.WithField("service", "order"); len(got) != 3 {
+		t.Fatalf("global fields missing:\n%s", logs)
+	}
+	if e := logs.Entries()[0]; !strings.HasSuffix(e.Caller.Function, ".TestLogs") {
+		t.Fatalf("unexpected caller %s", e.Caller.Function)
+	}
+	if strings.Join(logs.Messages(), ",") != "loading,query failed,slow" {
+		t.Fatalf("unexpected messages %v", logs.Messages())
+	}
+	logs.Reset()
+	if logs.Len() != 0 {
+		t.Fatal("reset did not clear logs")
+	}
+
+	warnLog, warnLogs := NewAt(zap.WarnLevel)
+	warnLog.Info("ignored")
+	if warnLogs.Len() != 0 {
+		t.Fatalf("unexpected logs:\n%s", warnLogs)
+	}
+}
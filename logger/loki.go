@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLokiCore 将 JSON 格式的日志批量推送到 Grafana Loki（/loki/api/v1/push），
+// labels 为固定的流标签，应保持低基数（service、env 等），级别等字段可用 LogQL 的 | json 过滤。
+// 批大小、重试、背压通过 WithBatch、WithRetry、WithBuffer 配置，多租户用 WithHTTPHeader("X-Scope-OrgID", ...)：
+//
+//	logger.WithLokiCore("http://loki:3100", map[string]string{"service": "order"},
+//		logger.WithLogLevel(zap.InfoLevel), logger.WithBatch(1000, 2*time.Second))
+func WithLokiCore(url string, labels map[string]string, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		push := strings.TrimRight(url, "/")
+		if !strings.HasSuffix(push, "/loki/api/v1/push") {
+			push += "/loki/api/v1/push"
+		}
+		client := &http.Client{}
+		headers := cfg.Sink.Headers
+		w := newBatchWriter("loki", cfg.Sink, func(ctx context.Context, batch []sinkRecord) error {
+			body, err := lokiPayload(labels, batch)
+			if err != nil {
+				return errPermanent{err}
+			}
+			return postHTTP(ctx, client, push, "application/json", body, headers)
+		})
+		*core = cfg.newCore("loki", newJSONEncoder(cfg), w)
+	}
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func lokiPayload(labels map[string]string, batch []sinkRecord) ([]byte, error) {
+	values := make([][2]string, len(batch))
+	for i, rec := range batch {
+		values[i] = [2]string{strconv.FormatInt(rec.time.UnixNano(), 10), string(rec.line)}
+	}
+	return json.Marshal(map[string][]lokiStream{
+		"streams": {{Stream: labels, Values: values}},
+	})
+}
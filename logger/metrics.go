@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"io"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志自身的指标，进程内各 Logger 共用，通过 RegisterMetrics 注册
+var (
+	sinkDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_sink_dropped_total",
+		Help: "Log entries dropped by asynchronous sinks because the buffer was full or the sink was closed.",
+	}, []string{"sink"})
+	sinkFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_sink_failed_total",
+		Help: "Log entries that could not be delivered by asynchronous sinks after retries.",
+	}, []string{"sink"})
+	samplingDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_sampling_dropped_total",
+		Help: "Log entries dropped by sampling.",
+	}, []string{"core"})
+	entriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_entries_total",
+		Help: "Log entries written, by level.",
+	}, []string{"level"})
+	sinkBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_sink_bytes_total",
+		Help: "Bytes of encoded log entries written, by sink.",
+	}, []string{"sink"})
+	rotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "logger_rotations_total",
+		Help: "Log file rotations, by configured file path.",
+	}, []string{"file"})
+)
+
+// RegisterMetrics 将日志指标注册到 reg，通常为 monitor.Registry。包括按级别的日志条数（logger_entries_total，
+// 可用于 error 日志速率告警）、各输出写入的字节数、网络输出丢弃与发送失败的条数、采样丢弃的条数以及文件轮转次数：
+//
+//	logger.RegisterMetrics(monitor.Registry)
+func RegisterMetrics(reg prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{sinkDropped, sinkFailed, samplingDropped, entriesTotal, sinkBytes, rotations} {
+		if err := reg.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countEntries 每条写出的日志按级别计数一次，多个输出时不重复计数
+func countEntries() zap.Option {
+	var counters [zapcore.FatalLevel - zapcore.DebugLevel + 1]prometheus.Counter
+	for l := zapcore.DebugLevel; l <= zapcore.FatalLevel; l++ {
+		counters[l-zapcore.DebugLevel] = entriesTotal.WithLabelValues(l.String())
+	}
+	return zap.Hooks(func(ent zapcore.Entry) error {
+		if ent.Level >= zapcore.DebugLevel && ent.Level <= zapcore.FatalLevel {
+			counters[ent.Level-zapcore.DebugLevel].Inc()
+		}
+		return nil
+	})
+}
+
+// countingWriter 统计写入输出的字节数
+type countingWriter struct {
+	zapcore.WriteSyncer
+	bytes prometheus.Counter
+}
+
+func newCountingWriter(sink string, ws zapcore.WriteSyncer) *countingWriter {
+	return &countingWriter{WriteSyncer: ws, bytes: sinkBytes.WithLabelValues(sink)}
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.WriteSyncer.Write(p)
+	w.bytes.Add(float64(n))
+	return n, err
+}
+
+// Close 关闭底层输出
+func (w *countingWriter) Close() error {
+	if closer, ok := w.WriteSyncer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithNetworkCore 通过 TCP、UDP 或 unix socket 将编码后的日志（默认 JSON，每行一条）发往远端收集器，
+// 如 Logstash 的 tcp / udp 输入、Vector 的 socket 源。日志先进入内存队列（WithBuffer），
+// 连接断开时按 WithRetry 指数退避重连并重发整批，因此可能重复但不会静默丢失；
+// 队列满或重试耗尽时分别计入 logger_sink_dropped_total、logger_sink_failed_total（sink="network"）。
+// UDP 每条日志一个数据报，超过 MTU 的日志可能被截断：
+//
+//	logger.WithNetworkCore("tcp", "collector:5000", logger.WithBuffer(50000, false), logger.WithRetry(10, time.Second))
+func WithNetworkCore(network, addr string, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		s := &netStreamer{network: network, addr: addr}
+		w := newBatchWriter("network", cfg.Sink, s.send)
+		w.onClose = s.Close
+		*core = cfg.newCore("network", cfg.newEncoder(EncodingJSON), w)
+	}
+}
+
+// netStreamer 维护到收集器的连接，发送失败时关闭连接，下次发送时重连
+type netStreamer struct {
+	network string
+	addr    string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (s *netStreamer) send(ctx context.Context, batch []sinkRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn != nil && !s.datagram() && !connAlive(s.conn) {
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	if s.conn == nil {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, s.network, s.addr)
+		if err != nil {
+			return fmt.Errorf("network: dial %s: %w", s.addr, err)
+		}
+		s.conn = conn
+	}
+	deadline, _ := ctx.Deadline()
+	_ = s.conn.SetWriteDeadline(deadline)
+	if err := s.write(batch); err != nil {
+		_ = s.conn.Close()
+		s.conn = nil
+		return fmt.Errorf("network: write %s: %w", s.addr, err)
+	}
+	return nil
+}
+
+func (s *netStreamer) write(batch []sinkRecord) error {
+	if s.datagram() {
+		for _, rec := range batch {
+			if _, err := s.conn.Write(rec.line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		buf.Write(rec.line)
+		buf.WriteByte('\n')
+	}
+	_, err := s.conn.Write(buf.Bytes())
+	return err
+}
+
+func (s *netStreamer) datagram() bool {
+	switch s.network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return true
+	}
+	return false
+}
+
+// connAlive 检查对端是否已关闭连接。收集器不会发送数据，以很快超时的读取探测，返回 EOF、连接重置等非超时错误说明连接已断开；
+// 否则对已关闭连接的第一次写入仍会成功，这批日志将丢失
+func connAlive(conn net.Conn) bool {
+	// 截止时间已过时不会真正读取，留出 1ms
+	_ = conn.SetReadDeadline(time.Now().Add(time.Millisecond))
+	var b [1]byte
+	_, err := conn.Read(b[:])
+	_ = conn.SetReadDeadline(time.Time{})
+	var ne net.Error
+	return err == nil || errors.As(err, &ne) && ne.Timeout()
+}
+
+// Close 关闭连接
+func (s *netStreamer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
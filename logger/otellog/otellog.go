@@ -0,0 +1,287 @@
+// Package otellog 将 go-kit logger 的日志转换为 OpenTelemetry 日志记录，通过 OTLP 导出或交给已有的 LoggerProvider。
+// 导入本包时注册配置文件中的 otlp 输出类型
+package otellog
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	olog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+// OTLP 传输协议
+const (
+	GRPC = "grpc"
+	HTTP = "http"
+)
+
+// scope 日志记录的 instrumentation scope
+const scope = "github.com/abs2free/go-kit/logger"
+
+func init() {
+	logger.RegisterOutput("otlp", func(o *logger.OutputConfig, opts []logger.Option) (logger.CoreBuilder, error) {
+		switch o.Protocol {
+		case "", GRPC, HTTP:
+		default:
+			return nil, fmt.Errorf("unknown protocol %q", o.Protocol)
+		}
+		return NewOTLP(o.Protocol, o.URL, opts...), nil
+	})
+}
+
+// NewOTLP 将日志转换为 OpenTelemetry 日志记录，经批量处理后通过 OTLP（protocol 为 grpc 或 http）导出。
+// endpoint 为 host:port 或带协议的 URL（http:// 表示不加密），为空时读取 OTEL_EXPORTER_OTLP_ENDPOINT 等环境变量。
+// 带 trace_id、span_id 字段的日志（如 logger.FromContext 得到的日志）会设置记录的链路上下文，便于在观测平台中与链路关联；
+// 创建导出器失败时输出到 stderr 并忽略该 core，Logger.Close 时导出剩余记录：
+//
+//	log, err := logger.NewWithCore(logger.WithFileCore(), otellog.NewOTLP(otellog.GRPC, "http://otel-collector:4317", logger.WithLogLevel(zap.InfoLevel)))
+func NewOTLP(protocol, endpoint string, options ...logger.Option) logger.CoreBuilder {
+	return func(core *zapcore.Core) {
+		exporter, err := newExporter(protocol, endpoint)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logger: otlp: %v\n", err)
+			return
+		}
+		provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+		newCore(provider, provider, options)(core)
+	}
+}
+
+// New 与 NewOTLP 相同，使用已有的 LoggerProvider（如与链路追踪共用资源属性、自定义导出器），
+// provider 由调用方关闭，Logger.Close 时只导出剩余记录
+func New(provider olog.LoggerProvider, options ...logger.Option) logger.CoreBuilder {
+	return newCore(provider, nil, options)
+}
+
+func newCore(provider olog.LoggerProvider, shutdown *sdklog.LoggerProvider, options []logger.Option) logger.CoreBuilder {
+	return logger.WithCore("otel", func(level zapcore.LevelEnabler) zapcore.Core {
+		return &otelCore{LevelEnabler: level, logger: provider.Logger(scope), provider: provider, shutdown: shutdown}
+	}, options...)
+}
+
+func newExporter(protocol, endpoint string) (sdklog.Exporter, error) {
+	ctx := context.Background()
+	switch protocol {
+	case GRPC, "":
+		var opts []otlploggrpc.Option
+		if strings.Contains(endpoint, "://") {
+			opts = append(opts, otlploggrpc.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			opts = append(opts, otlploggrpc.WithEndpoint(endpoint))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	case HTTP:
+		var opts []otlploghttp.Option
+		if strings.Contains(endpoint, "://") {
+			// 与 OTEL_EXPORTER_OTLP_ENDPOINT 一致，未指定路径时使用 /v1/logs
+			if u, err := url.Parse(endpoint); err == nil && strings.Trim(u.Path, "/") == "" {
+				u.Path = "/v1/logs"
+				endpoint = u.String()
+			}
+			opts = append(opts, otlploghttp.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			opts = append(opts, otlploghttp.WithEndpoint(endpoint))
+		}
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unknown protocol %q", protocol)
+	}
+}
+
+// otelCore 将条目转为 OTel 日志记录
+type otelCore struct {
+	zapcore.LevelEnabler
+	logger   olog.Logger
+	provider olog.LoggerProvider
+	// shutdown NewOTLP 创建的 provider 由本包关闭
+	shutdown *sdklog.LoggerProvider
+	fields   []zapcore.Field
+}
+
+func (c *otelCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *otelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *otelCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(enc)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+
+	var rec olog.Record
+	rec.SetTimestamp(ent.Time)
+	rec.SetBody(olog.StringValue(ent.Message))
+	rec.SetSeverity(otelSeverity(ent.Level))
+	rec.SetSeverityText(ent.Level.String())
+
+	// 链路上下文由 Emit 的 ctx 携带，不再作为属性重复记录
+	ctx := context.Background()
+	if sc := spanContextFromFields(enc.Fields); sc.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, sc)
+		delete(enc.Fields, logger.KeyTraceID)
+		delete(enc.Fields, logger.KeySpanID)
+	}
+
+	attrs := make([]olog.KeyValue, 0, len(enc.Fields)+5)
+	if ent.LoggerName != "" {
+		attrs = append(attrs, olog.String("logger", ent.LoggerName))
+	}
+	if ent.Caller.Defined {
+		attrs = append(attrs,
+			olog.String("code.filepath", ent.Caller.File),
+			olog.Int("code.lineno", ent.Caller.Line),
+			olog.String("code.function", ent.Caller.Function),
+		)
+	}
+	if ent.Stack != "" {
+		attrs = append(attrs, olog.String("stacktrace", ent.Stack))
+	}
+	for k, v := range enc.Fields {
+		attrs = append(attrs, olog.KeyValue{Key: k, Value: otelValue(v)})
+	}
+	rec.AddAttributes(attrs...)
+
+	c.logger.Emit(ctx, rec)
+	return nil
+}
+
+// otelFlusher sdklog.LoggerProvider 等支持主动导出的 provider
+type otelFlusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// Sync 导出已缓冲的记录（Fatal、Panic 前 zap 会调用）
+func (c *otelCore) Sync() error {
+	return c.flush(2 * time.Second)
+}
+
+// Close 导出剩余记录，NewOTLP 创建的 provider 同时关闭
+func (c *otelCore) Close() error {
+	if c.shutdown != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return c.shutdown.Shutdown(ctx)
+	}
+	return c.flush(5 * time.Second)
+}
+
+func (c *otelCore) flush(timeout time.Duration) error {
+	f, ok := c.provider.(otelFlusher)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return f.ForceFlush(ctx)
+}
+
+// spanContextFromFields 由 FromContext 附加的 trace_id、span_id 字段还原链路上下文
+func spanContextFromFields(fields map[string]any) trace.SpanContext {
+	tid, _ := fields[logger.KeyTraceID].(string)
+	sid, _ := fields[logger.KeySpanID].(string)
+	traceID, err := trace.TraceIDFromHex(tid)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	spanID, err := trace.SpanIDFromHex(sid)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
+// otelSeverity 与 OTel 官方 zap 桥接的映射一致
+func otelSeverity(l zapcore.Level) olog.Severity {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return olog.SeverityDebug
+	case l == zapcore.InfoLevel:
+		return olog.SeverityInfo
+	case l == zapcore.WarnLevel:
+		return olog.SeverityWarn
+	case l == zapcore.ErrorLevel:
+		return olog.SeverityError
+	case l == zapcore.DPanicLevel:
+		return olog.SeverityFatal1
+	case l == zapcore.PanicLevel:
+		return olog.SeverityFatal2
+	default:
+		return olog.SeverityFatal3
+	}
+}
+
+// otelValue 将 MapObjectEncoder 产生的值转为 OTel 属性值，嵌套对象与数组保留结构
+func otelValue(v any) olog.Value {
+	switch v := v.(type) {
+	case string:
+		return olog.StringValue(v)
+	case bool:
+		return olog.BoolValue(v)
+	case int:
+		return olog.IntValue(v)
+	case int8:
+		return olog.Int64Value(int64(v))
+	case int16:
+		return olog.Int64Value(int64(v))
+	case int32:
+		return olog.Int64Value(int64(v))
+	case int64:
+		return olog.Int64Value(v)
+	case uint8:
+		return olog.Int64Value(int64(v))
+	case uint16:
+		return olog.Int64Value(int64(v))
+	case uint32:
+		return olog.Int64Value(int64(v))
+	case float32:
+		return olog.Float64Value(float64(v))
+	case float64:
+		return olog.Float64Value(v)
+	case []byte:
+		return olog.BytesValue(v)
+	case time.Time:
+		return olog.StringValue(v.Format(time.RFC3339Nano))
+	case map[string]any:
+		kvs := make([]olog.KeyValue, 0, len(v))
+		for k, e := range v {
+			kvs = append(kvs, olog.KeyValue{Key: k, Value: otelValue(e)})
+		}
+		return olog.MapValue(kvs...)
+	case []any:
+		vals := make([]olog.Value, len(v))
+		for i, e := range v {
+			vals[i] = otelValue(e)
+		}
+		return olog.SliceValue(vals...)
+	default:
+		return olog.StringValue(fmt.Sprint(v))
+	}
+}
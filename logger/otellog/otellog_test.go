@@ -0,0 +1,107 @@
+package otellog
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	olog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryLogExporter) Export(_ context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(context.Context) error   { return nil }
+func (e *memoryLogExporter) ForceFlush(context.Context) error { return nil }
+
+func TestOTelCore(t *testing.T) {
+	exp := &memoryLogExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exp)))
+	log, err := logger.NewWithCore(New(provider))
+	if err != nil {
+		t.Fatal(err)
+	}
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = logger.WithContext(ctx, log.SugaredLogger)
+	logger.FromContext(ctx).Named("order").Warnw("slow query", "table", "orders", "rows", 42)
+	log.Debug("disabled")
+	_ = log.Close()
+
+	if len(exp.records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(exp.records))
+	}
+	rec := exp.records[0]
+	if rec.Body().AsString() != "slow query" || rec.Severity() != olog.SeverityWarn || rec.TraceID() != traceID || rec.SpanID() != spanID {
+		t.Fatalf("unexpected record %v", rec)
+	}
+	attrs := map[string]olog.Value{}
+	rec.WalkAttributes(func(kv olog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	if attrs["table"].AsString() != "orders" || attrs["rows"].AsInt64() != 42 || attrs["logger"].AsString() != "order" {
+		t.Fatalf("unexpected attributes %v", attrs)
+	}
+	if _, ok := attrs[logger.KeyTraceID]; ok {
+		t.Fatal("trace_id should not be duplicated as an attribute")
+	}
+
+	received := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/v1/logs" {
+			received <- body
+		}
+	}))
+	defer srv.Close()
+	log, err = logger.NewWithCore(NewOTLP(HTTP, srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Info("exported over otlp")
+	_ = log.Close()
+	select {
+	case body := <-received:
+		if !strings.Contains(string(body), "exported over otlp") {
+			t.Fatalf("unexpected payload %q", body)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no otlp request received")
+	}
+}
+
+func TestConfigOutput(t *testing.T) {
+	cfg := &logger.Config{Outputs: []logger.OutputConfig{{Type: "otlp", Protocol: "http", URL: "http://127.0.0.1:1"}}}
+	log, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = log.Close()
+
+	cfg.Outputs[0].Protocol = "udp"
+	if _, err := cfg.Build(); err == nil || !strings.Contains(err.Error(), "unknown protocol") {
+		t.Fatalf("expected protocol error, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// FilePerm 日志目录与文件的权限
+type FilePerm struct {
+	// FileMode 日志文件的权限，为 0 时沿用 lumberjack 的行为（新建为 0600，轮转后继承原文件）
+	FileMode os.FileMode
+	// DirMode 日志目录不存在时创建的权限，为 0 时为 0755
+	DirMode os.FileMode
+	// Group 日志文件的属组 ID，为空时不修改
+	Group *int
+}
+
+// WithFileMode 日志文件的权限，包括轮转后新建的文件与归档文件。创建后显式 chmod，不受进程 umask 影响，
+// 已存在的文件也会被修正：
+//
+//	logger.WithFileCore(logger.WithFileMode(0o640), logger.WithDirMode(0o750), logger.WithFileGroup(admGID))
+func WithFileMode(mode os.FileMode) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Perm.FileMode = mode
+	}
+}
+
+// WithDirMode 日志目录不存在时以该权限创建，已存在的目录不修改
+func WithDirMode(mode os.FileMode) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Perm.DirMode = mode
+	}
+}
+
+// WithFileGroup 日志文件的属组，进程需要是文件所有者且属于该组，或具有 CAP_CHOWN
+func WithFileGroup(gid int) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Perm.Group = &gid
+	}
+}
+
+func (p *FilePerm) isZero() bool {
+	return p.FileMode == 0 && p.DirMode == 0 && p.Group == nil
+}
+
+// prepare 按权限创建目录与文件，文件已存在时修正权限与属组；未配置权限时不做处理，由 lumberjack 创建
+func (p *FilePerm) prepare(path string) error {
+	if p.isZero() {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		mode := p.DirMode
+		if mode == 0 {
+			mode = 0o755
+		}
+		if err := os.MkdirAll(dir, mode); err != nil {
+			return fmt.Errorf("logger: create log directory: %w", err)
+		}
+		if err := os.Chmod(dir, mode); err != nil {
+			return fmt.Errorf("logger: chmod log directory: %w", err)
+		}
+	}
+	if p.FileMode == 0 && p.Group == nil {
+		return nil
+	}
+	mode := p.FileMode
+	if mode == 0 {
+		mode = 0o600
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, mode)
+	if err != nil {
+		return fmt.Errorf("logger: create log file: %w", err)
+	}
+	_ = f.Close()
+	return p.apply(path)
+}
+
+// apply 设置已存在文件的权限与属组
+func (p *FilePerm) apply(path string) error {
+	if p.FileMode != 0 {
+		if err := os.Chmod(path, p.FileMode); err != nil {
+			return fmt.Errorf("logger: chmod log file: %w", err)
+		}
+	}
+	if p.Group != nil {
+		if err := os.Chown(path, -1, *p.Group); err != nil {
+			return fmt.Errorf("logger: chown log file: %w", err)
+		}
+	}
+	return nil
+}
+
+// copyPerm 压缩、归档后的文件使用与原文件相同的权限，并按配置设置属组
+func copyPerm(dst string, src *os.File, perm FilePerm) error {
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return perm.apply(dst)
+}
+
+// lookupGroup 组名或数字 GID
+func lookupGroup(name string) (int, error) {
+	if gid, err := strconv.Atoi(name); err == nil {
+		return gid, nil
+	}
+	g, err := user.LookupGroup(name)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// NewDevelopment 创建本地开发用的日志：控制台以 pretty 格式（终端中彩色）输出，字段逐行列在消息下方，
+// Debug 级别，记录调用位置，Warn 及以上附带调用栈，不写文件
+func NewDevelopment() (*Logger, error) {
+	return NewWithOptions(
+		[]Option{WithStacktraceLevel(zap.WarnLevel)},
+		WithConsoleCore(WithLogLevel(zap.DebugLevel), WithEncoding(EncodingPretty)),
+	)
+}
+
+// NewProduction 创建生产环境用的日志：JSON 写入 logs/<serviceName>.log 并按大小轮转压缩，
+// Info 级别，Error 以下的日志每秒同一消息前 100 条之后每 100 条保留 1 条，每条日志带 WithServiceInfo 的字段。
+// serviceName 为空时使用可执行文件名：
+//
+//	log, err := logger.NewProduction("order")
+func NewProduction(serviceName string) (*Logger, error) {
+	name := serviceName
+	if name == "" {
+		name = "{app}"
+	}
+	return NewWithOptions(
+		[]Option{WithServiceInfo(serviceName, "")},
+		WithFileCore(
+			WithLogFilePath("logs/"+name+".log"),
+			WithLogLevel(zap.InfoLevel),
+			WithRotateSettings(DefaultConfig.Rotate.MaxSize, DefaultConfig.Rotate.MaxAge, true),
+			WithSampling(100, 100, time.Second),
+		),
+	)
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// EncodingPretty 本地开发用的多行格式：消息一行，字段按名称排序逐行缩进列在下方
+const EncodingPretty = "pretty"
+
+var prettyPool = buffer.NewPool()
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// prettyEncoder 输出形如：
+//
+//	10:04:05.123 INFO  order  service/order.go:42  order created
+//	    amount: 99.5
+//	    user_id: u1
+type prettyEncoder struct {
+	// fields With 附加的字段
+	*zapcore.MapObjectEncoder
+	color bool
+}
+
+func newPrettyEncoder(color bool) *prettyEncoder {
+	return &prettyEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder(), color: color}
+}
+
+func (e *prettyEncoder) Clone() zapcore.Encoder {
+	clone := newPrettyEncoder(e.color)
+	for k, v := range e.Fields {
+		clone.Fields[k] = v
+	}
+	return clone
+}
+
+func (e *prettyEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	all := e.Clone().(*prettyEncoder)
+	for _, f := range fields {
+		f.AddTo(all)
+	}
+
+	buf := prettyPool.Get()
+	e.paint(buf, ansiDim, ent.Time.Format("15:04:05.000"))
+	buf.AppendByte(' ')
+	e.paintLevel(buf, ent.Level)
+	if ent.LoggerName != "" {
+		buf.AppendString("  ")
+		e.paint(buf, ansiBold, ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		buf.AppendString("  ")
+		e.paint(buf, ansiDim, ent.Caller.TrimmedPath())
+	}
+	buf.AppendString("  ")
+	e.paint(buf, ansiBold, ent.Message)
+	buf.AppendByte('\n')
+
+	keys := make([]string, 0, len(all.Fields))
+	for k := range all.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf.AppendString("    ")
+		e.paint(buf, ansiCyan, k)
+		buf.AppendString(": ")
+		appendIndented(buf, prettyValue(all.Fields[k]))
+		buf.AppendByte('\n')
+	}
+	if ent.Stack != "" {
+		buf.AppendString("    ")
+		e.paint(buf, ansiCyan, "stacktrace")
+		buf.AppendString(":\n        ")
+		appendIndented(buf, ent.Stack)
+		buf.AppendByte('\n')
+	}
+	return buf, nil
+}
+
+func (e *prettyEncoder) paint(buf *buffer.Buffer, color, s string) {
+	if !e.color {
+		buf.AppendString(s)
+		return
+	}
+	buf.AppendString(color)
+	buf.AppendString(s)
+	buf.AppendString(ansiReset)
+}
+
+func (e *prettyEncoder) paintLevel(buf *buffer.Buffer, l zapcore.Level) {
+	s := fmt.Sprintf("%-5s", l.CapitalString())
+	if !e.color {
+		buf.AppendString(s)
+		return
+	}
+	c := levelColors[l]
+	if c == "" {
+		c = levelColors[zapcore.FatalLevel]
+	}
+	buf.AppendString(c)
+	buf.AppendString(s)
+	buf.AppendString(ansiReset)
+}
+
+// levelColors 与 CustomLevelEncoder 的配色一致
+var levelColors = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "\x1b[37m",
+	zapcore.InfoLevel:   "\x1b[32m",
+	zapcore.WarnLevel:   "\x1b[33m",
+	zapcore.ErrorLevel:  "\x1b[31m",
+	zapcore.DPanicLevel: "\x1b[35m",
+	zapcore.PanicLevel:  "\x1b[35m",
+	zapcore.FatalLevel:  "\x1b[35m",
+}
+
+// prettyValue 字符串原样输出，嵌套对象与数组输出为 JSON
+func prettyValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration, fmt.Stringer, error:
+		return fmt.Sprint(v)
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// appendIndented 多行值（如 errorVerbose、调用栈）的后续行与首行对齐
+func appendIndented(buf *buffer.Buffer, s string) {
+	buf.AppendString(strings.ReplaceAll(strings.TrimRight(s, "\n"), "\n", "\n        "))
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+)
+
+// RecoverOption Recover、Go 的选项
+type RecoverOption func(*recoverOptions)
+
+type recoverOptions struct {
+	repanic bool
+	onPanic func(p any)
+}
+
+// WithRepanic 记录日志后重新 panic，用于不能带着错误状态继续运行的场景
+func WithRepanic() RecoverOption {
+	return func(o *recoverOptions) {
+		o.repanic = true
+	}
+}
+
+// WithOnPanic 记录日志后调用 fn，如上报告警、标记任务失败
+func WithOnPanic(fn func(p any)) RecoverOption {
+	return func(o *recoverOptions) {
+		o.onPanic = fn
+	}
+}
+
+// Recover 捕获 panic 并以 Error 级别记录 panic 值与完整堆栈，log 为空时使用 Default，必须直接用于 defer：
+//
+//	defer logger.Recover(log)
+func Recover(log *Logger, opts ...RecoverOption) {
+	if p := recover(); p != nil {
+		handlePanic(log, p, opts)
+	}
+}
+
+// Go 在新的 goroutine 中运行 fn，捕获其中的 panic 并记录到 Default，避免未记录的 panic 使进程直接退出：
+//
+//	logger.Go(func() { consume(ch) })
+func Go(fn func(), opts ...RecoverOption) {
+	Default().Go(fn, opts...)
+}
+
+// Go 与包级 Go 相同，记录到 l
+func (l *Logger) Go(fn func(), opts ...RecoverOption) {
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				handlePanic(l, p, opts)
+			}
+		}()
+		fn()
+	}()
+}
+
+func handlePanic(log *Logger, p any, opts []RecoverOption) {
+	var o recoverOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if log == nil {
+		log = Default()
+	}
+	z := log.Desugar().WithOptions(zap.WithCaller(false), zap.AddStacktrace(zap.FatalLevel+1))
+	// 跳过 handlePanic 与调用它的 defer，堆栈从 runtime.gopanic 开始
+	z.Error("panic recovered", zap.Any("panic", p), zap.StackSkip("stack", 2))
+	if o.onPanic != nil {
+		o.onPanic(p)
+	}
+	if o.repanic {
+		_ = log.Sync()
+		panic(p)
+	}
+}
@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/abs2free/go-kit/masking"
+)
+
+// RedactionConfig 脱敏配置
+type RedactionConfig struct {
+	// Keys 需要脱敏的字段名（小写）
+	Keys map[string]struct{}
+	// Patterns 在消息与字符串字段中查找并脱敏的正则
+	Patterns []*regexp.Regexp
+}
+
+// WithRedaction 在编码前脱敏：字段名命中 keys（不区分大小写）时按 masking.KeyRules 的规则脱敏，
+// 无对应规则的完全隐藏；消息、字符串字段与 error 中匹配 patterns 的部分按 masking.Default 脱敏。
+// 只处理顶层字段，zap.Any 等嵌套对象内部的值不处理：
+//
+//	logger.WithFileCore(logger.WithRedaction(
+//		[]string{"password", "token", "id_card", "phone"},
+//		[]*regexp.Regexp{regexp.MustCompile(`1[3-9]\d{9}`)},
+//	))
+func WithRedaction(keys []string, patterns []*regexp.Regexp) Option {
+	return func(cfg *LoggerConfig) {
+		r := &RedactionConfig{Keys: make(map[string]struct{}, len(keys)), Patterns: patterns}
+		for _, k := range keys {
+			r.Keys[strings.ToLower(k)] = struct{}{}
+		}
+		cfg.Redaction = r
+	}
+}
+
+// redactCore 包裹最内层的 core，With 与 Write 时脱敏
+type redactCore struct {
+	zapcore.Core
+	cfg *RedactionConfig
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.cfg.fields(fields)), cfg: c.cfg}
+}
+
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent.Message = c.cfg.text(ent.Message)
+	return c.Core.Write(ent, c.cfg.fields(fields))
+}
+
+func (r *RedactionConfig) text(s string) string {
+	for _, re := range r.Patterns {
+		s = re.ReplaceAllStringFunc(s, masking.Default)
+	}
+	return s
+}
+
+// fields 返回脱敏后的字段，未改动时复用原切片
+func (r *RedactionConfig) fields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		nf, changed := r.field(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, nf)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func (r *RedactionConfig) field(f zapcore.Field) (zapcore.Field, bool) {
+	key := strings.ToLower(f.Key)
+	if _, ok := r.Keys[key]; ok {
+		value := f.String
+		if f.Type != zapcore.StringType {
+			value = fieldString(f)
+		}
+		if masked, ok := masking.ByKey(key, value); ok {
+			return zap.String(f.Key, masked), true
+		}
+		return zap.String(f.Key, masking.Secret(value)), true
+	}
+	if len(r.Patterns) == 0 {
+		return f, false
+	}
+	switch f.Type {
+	case zapcore.StringType:
+		if s := r.text(f.String); s != f.String {
+			return zap.String(f.Key, s), true
+		}
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			if msg := err.Error(); r.text(msg) != msg {
+				return zap.String(f.Key, r.text(msg)), true
+			}
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			if v := s.String(); r.text(v) != v {
+				return zap.String(f.Key, r.text(v)), true
+			}
+		}
+	}
+	return f, false
+}
+
+// fieldString 将任意类型的字段值转为字符串
+func fieldString(f zapcore.Field) string {
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return fmt.Sprint(enc.Fields[f.Key])
+}
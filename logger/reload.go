@@ -0,0 +1,363 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap/zapcore"
+)
+
+// ErrNotReloadable 日志不是由 Config 或 WithDynamicCores 创建的，不能重新加载或增减输出
+var ErrNotReloadable = errors.New("logger: logger is not reloadable")
+
+// reloadDebounce 配置文件变化后等待的时间，编辑器保存时常触发多个事件
+const reloadDebounce = 100 * time.Millisecond
+
+// swapState 一组输出及其级别与需要关闭的资源
+type swapState struct {
+	core    zapcore.Core
+	levels  []namedLevel
+	closers []io.Closer
+	entries []swapEntry
+}
+
+// swapEntry 一个 CoreBuilder 产生的输出，级别名称在加入时确定，之后增减其他输出不会改变
+type swapEntry struct {
+	core    zapcore.Core
+	levels  []namedLevel
+	closers []io.Closer
+}
+
+// swapRoot 保存当前输出，替换时串行
+type swapRoot struct {
+	mu  sync.Mutex
+	cur atomic.Pointer[swapState]
+}
+
+func newSwapRoot(cores []zapcore.Core) *swapRoot {
+	r := &swapRoot{}
+	r.cur.Store(newSwapState(cores))
+	return r
+}
+
+func newSwapState(cores []zapcore.Core) *swapState {
+	entries := make([]swapEntry, 0, len(cores))
+	for _, core := range cores {
+		entries = append(entries, newSwapEntry(entries, core))
+	}
+	return stateOf(entries)
+}
+
+// newSwapEntry 与 collectLevels 相同，名称与已有输出重复时追加序号
+func newSwapEntry(existing []swapEntry, core zapcore.Core) swapEntry {
+	seen := make(map[string]bool)
+	for _, e := range existing {
+		for _, nl := range e.levels {
+			seen[nl.name] = true
+		}
+	}
+	e := swapEntry{core: core, closers: collectClosers([]zapcore.Core{core})}
+	for _, nl := range collectLevels([]zapcore.Core{core}) {
+		name := nl.name
+		for n := 2; seen[nl.name]; n++ {
+			nl.name = name + strconv.Itoa(n)
+		}
+		seen[nl.name] = true
+		e.levels = append(e.levels, nl)
+	}
+	return e
+}
+
+func stateOf(entries []swapEntry) *swapState {
+	st := &swapState{entries: entries}
+	cores := make([]zapcore.Core, len(entries))
+	for i, e := range entries {
+		cores[i] = e.core
+		st.levels = append(st.levels, e.levels...)
+		st.closers = append(st.closers, e.closers...)
+	}
+	st.core = zapcore.NewTee(cores...)
+	return st
+}
+
+func (r *swapRoot) load() *swapState {
+	return r.cur.Load()
+}
+
+// swap 替换为 cores 并关闭原输出的资源
+func (r *swapRoot) swap(cores []zapcore.Core) error {
+	r.mu.Lock()
+	old := r.cur.Swap(newSwapState(cores))
+	r.mu.Unlock()
+	_ = old.core.Sync()
+	return closeAll(old.closers)
+}
+
+// attach 加入 core，返回其级别名称
+func (r *swapRoot) attach(core zapcore.Core) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	old := r.load()
+	entry := newSwapEntry(old.entries, core)
+	r.cur.Store(stateOf(append(old.entries[:len(old.entries):len(old.entries)], entry)))
+	names := make([]string, len(entry.levels))
+	for i, nl := range entry.levels {
+		names[i] = nl.name
+	}
+	return names
+}
+
+// detach 移除包含名为 name 的级别的 core 并关闭其资源
+func (r *swapRoot) detach(name string) error {
+	r.mu.Lock()
+	old := r.load()
+	var removed *swapEntry
+	entries := make([]swapEntry, 0, len(old.entries))
+	for i, e := range old.entries {
+		if removed == nil && e.hasLevel(name) {
+			removed = &old.entries[i]
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if removed == nil {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %q", ErrUnknownCore, name)
+	}
+	r.cur.Store(stateOf(entries))
+	r.mu.Unlock()
+	_ = removed.core.Sync()
+	return closeAll(removed.closers)
+}
+
+func (e *swapEntry) hasLevel(name string) bool {
+	for _, nl := range e.levels {
+		if nl.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Close 关闭当前输出的资源
+func (r *swapRoot) Close() error {
+	return closeAll(r.load().closers)
+}
+
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		errs = append(errs, c.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// swapCore 总是写入 swapRoot 的当前输出；With 派生的副本记录字段，
+// 输出替换后在新的 core 上重新附加
+type swapCore struct {
+	root   *swapRoot
+	fields []zapcore.Field
+	cached atomic.Pointer[swapCached]
+}
+
+type swapCached struct {
+	state *swapState
+	core  zapcore.Core
+}
+
+func (c *swapCore) current() zapcore.Core {
+	st := c.root.load()
+	if len(c.fields) == 0 {
+		return st.core
+	}
+	if p := c.cached.Load(); p != nil && p.state == st {
+		return p.core
+	}
+	core := st.core.With(c.fields)
+	c.cached.Store(&swapCached{state: st, core: core})
+	return core
+}
+
+func (c *swapCore) Enabled(level zapcore.Level) bool {
+	return c.current().Enabled(level)
+}
+
+func (c *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(c.fields)+len(fields))
+	return &swapCore{root: c.root, fields: append(append(all, c.fields...), fields...)}
+}
+
+func (c *swapCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.current().Check(ent, ce)
+}
+
+func (c *swapCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.current().Write(ent, fields)
+}
+
+func (c *swapCore) Sync() error {
+	return c.current().Sync()
+}
+
+// Build 按配置创建日志，创建的日志可通过 Reload 替换配置
+func (c *Config) Build() (*Logger, error) {
+	opts, builders, err := c.compile()
+	if err != nil {
+		return nil, err
+	}
+	cores, err := buildCores(builders)
+	if err != nil {
+		return nil, err
+	}
+	cfg := DefaultConfig.clone()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg.newSwapLogger(cores), nil
+}
+
+// newSwapLogger 创建输出可替换、增减的日志
+func (c *LoggerConfig) newSwapLogger(cores []zapcore.Core) *Logger {
+	root := newSwapRoot(cores)
+	l := c.newLogger(&swapCore{root: root})
+	l.swap = root
+	l.closers = []io.Closer{root}
+	return l
+}
+
+// Reload 按新配置原子替换输出与级别，已派生的日志（Named、With）同样生效，原输出随后关闭；
+// 调用位置、调用栈等 Logger 级别的配置在创建时确定，不随之改变。
+// 新配置无效时返回错误并保留原配置
+func (l *Logger) Reload(cfg *Config) error {
+	if l.swap == nil {
+		return ErrNotReloadable
+	}
+	_, builders, err := cfg.compile()
+	if err != nil {
+		return err
+	}
+	cores, err := buildCores(builders)
+	if err != nil {
+		return err
+	}
+	return l.swap.swap(cores)
+}
+
+// WatchConfig 按配置文件创建日志并监听文件变化，变化后重新加载（见 Reload），
+// 用于不重启服务打开 debug 或增加输出。监听所在目录，因此编辑器先写临时文件再改名、
+// Kubernetes ConfigMap 替换符号链接等方式均可触发。加载失败时保留原配置并调用 onError，
+// onError 为 nil 时输出到 stderr。Logger.Close 时停止监听：
+//
+//	log, err := logger.WatchConfig("/etc/app/log.yaml", nil)
+func WatchConfig(path string, onError func(error)) (*Logger, error) {
+	l, err := NewFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if onError == nil {
+		onError = func(err error) {
+			fmt.Fprintf(os.Stderr, "logger: reload %s: %v\n", path, err)
+		}
+	}
+	w, err := newConfigWatcher(path, l, onError)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	l.closers = append([]io.Closer{w}, l.closers...)
+	return l, nil
+}
+
+// configWatcher 监听配置文件，内容变化时重新加载
+type configWatcher struct {
+	path    string
+	log     *Logger
+	onError func(error)
+	watcher *fsnotify.Watcher
+	last    []byte
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newConfigWatcher(path string, l *Logger, onError func(error)) (*configWatcher, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("logger: read config: %w", err)
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("logger: watch config: %w", err)
+	}
+	if err := fw.Add(filepath.Dir(path)); err != nil {
+		_ = fw.Close()
+		return nil, fmt.Errorf("logger: watch config: %w", err)
+	}
+	w := &configWatcher{path: path, log: l, onError: onError, watcher: fw, last: data, done: make(chan struct{})}
+	go w.run()
+	return w, nil
+}
+
+func (w *configWatcher) run() {
+	defer close(w.done)
+	var timer <-chan time.Time
+	for {
+		select {
+		case _, ok := <-w.watcher.Events:
+			if !ok {
+				return
+			}
+			// 只在目录内有变化时比较文件内容，合并短时间内的多个事件
+			timer = time.After(reloadDebounce)
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return
+			}
+			w.onError(err)
+		case <-timer:
+			timer = nil
+			w.reload()
+		}
+	}
+}
+
+func (w *configWatcher) reload() {
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		// 文件被替换的间隙可能暂时不存在，等待下一次事件
+		if !errors.Is(err, os.ErrNotExist) {
+			w.onError(err)
+		}
+		return
+	}
+	if bytes.Equal(data, w.last) {
+		return
+	}
+	// 无效的内容也只报告一次，等待下次修改
+	w.last = data
+	cfg, err := LoadConfig(w.path)
+	if err == nil {
+		err = w.log.Reload(cfg)
+	}
+	if err != nil {
+		w.onError(err)
+	}
+}
+
+// Close 停止监听
+func (w *configWatcher) Close() error {
+	var err error
+	w.once.Do(func() {
+		err = w.watcher.Close()
+		<-w.done
+	})
+	return err
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// WithReopenSignal 收到信号时关闭日志文件，下一条日志按原路径重新打开，用于配合外部 logrotate：
+// logrotate 改名后发送信号，之后的日志写入新文件，无需 copytruncate。未指定信号时为 SIGHUP，
+// 仅对文件输出生效，Logger.Close 时停止监听：
+//
+//	logger.WithFileCore(logger.WithReopenSignal())
+//
+// 对应的 logrotate 配置：
+//
+//	postrotate
+//	    kill -HUP $(cat /var/run/app.pid)
+//	endscript
+func WithReopenSignal(sigs ...os.Signal) Option {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGHUP}
+	}
+	return func(cfg *LoggerConfig) {
+		cfg.ReopenSignals = sigs
+	}
+}
+
+// reopenWriter 收到信号时关闭底层文件，由 lumberjack 或 timeRotator 在下次写入时重新打开
+type reopenWriter struct {
+	zapcore.WriteSyncer
+	file io.Closer
+	sigs chan os.Signal
+	done chan struct{}
+	once sync.Once
+}
+
+func newReopenWriter(file io.WriteCloser, sigs []os.Signal) *reopenWriter {
+	w := &reopenWriter{
+		WriteSyncer: zapcore.AddSync(file),
+		file:        file,
+		sigs:        make(chan os.Signal, 1),
+		done:        make(chan struct{}),
+	}
+	signal.Notify(w.sigs, sigs...)
+	go w.run()
+	return w
+}
+
+func (w *reopenWriter) run() {
+	for {
+		select {
+		case <-w.sigs:
+			_ = w.file.Close()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Close 停止监听信号并关闭文件
+func (w *reopenWriter) Close() error {
+	w.once.Do(func() {
+		signal.Stop(w.sigs)
+		close(w.done)
+	})
+	return w.file.Close()
+}
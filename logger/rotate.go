@@ -0,0 +1,315 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/abs2free/go-kit/timeutil"
+)
+
+// RotatePolicy 文件轮转策略
+type RotatePolicy int
+
+const (
+	// BySize 按大小轮转（lumberjack 默认行为）
+	BySize RotatePolicy = iota
+	// ByTime 按时间轮转，文件名带日期，如 app-2024-05-01.log
+	ByTime
+	// Both 按时间切分文件，同一周期内超过 MaxSize 时再按大小轮转
+	Both
+)
+
+// RotateInterval 按时间轮转的周期
+type RotateInterval int
+
+const (
+	Daily RotateInterval = iota
+	Hourly
+)
+
+func (i RotateInterval) layout() string {
+	if i == Hourly {
+		return "2006-01-02-15"
+	}
+	return "2006-01-02"
+}
+
+// WithRotatePolicy 设置轮转策略，默认 BySize
+func WithRotatePolicy(policy RotatePolicy) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.RotatePolicy = policy
+	}
+}
+
+// WithRotateInterval 设置按时间轮转的周期，默认 Daily
+func WithRotateInterval(interval RotateInterval) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.RotateInterval = interval
+	}
+}
+
+// timeRotator 按周期写入带日期的文件，周期切换时压缩上一周期文件并按 MaxAge、MaxBackups 清理。
+// 日期按 Rotate.LocalTime 决定使用本地时间或 UTC，与 lumberjack 备份文件名保持一致
+type timeRotator struct {
+	tmpl     *lumberjack.Logger
+	base     string
+	ext      string
+	interval RotateInterval
+	bySize   bool
+	clock    timeutil.Clock
+	rotated  prometheus.Counter
+	archive  *ArchiveConfig
+	perm     FilePerm
+
+	mu     sync.Mutex
+	period string
+	cur    *rotateCounter
+
+	millMu sync.Mutex
+}
+
+func newTimeRotator(cfg *LoggerConfig) *timeRotator {
+	name := cfg.Rotate.Filename
+	ext := filepath.Ext(name)
+	return &timeRotator{
+		tmpl:     cloneRotate(&cfg.Rotate),
+		base:     strings.TrimSuffix(name, ext),
+		ext:      ext,
+		interval: cfg.RotateInterval,
+		bySize:   cfg.RotatePolicy == Both,
+		clock:    timeutil.Real,
+		rotated:  rotations.WithLabelValues(name),
+		archive:  cfg.Archive,
+		perm:     cfg.Perm,
+	}
+}
+
+func (r *timeRotator) stamp(t time.Time) string {
+	if !r.tmpl.LocalTime {
+		t = t.UTC()
+	}
+	return t.Format(r.interval.layout())
+}
+
+func (r *timeRotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp := r.stamp(r.clock.Now())
+	if r.cur == nil || stamp != r.period {
+		var prev string
+		if r.cur != nil {
+			prev = r.cur.Filename
+			_ = r.cur.Close()
+		}
+		r.period = stamp
+		r.cur = r.open(stamp)
+		if prev != "" {
+			r.rotated.Inc()
+			go r.mill(prev)
+		}
+	}
+	return r.cur.Write(p)
+}
+
+// open 创建当前周期的写入器；仅按时间轮转时放大 MaxSize，避免周期内被 lumberjack 切分
+func (r *timeRotator) open(stamp string) *rotateCounter {
+	l := cloneRotate(r.tmpl)
+	l.Filename = r.base + "-" + stamp + r.ext
+	if !r.bySize {
+		l.MaxSize = 1 << 20
+	}
+	rc := &rotateCounter{Logger: l, rotated: r.rotated, perm: r.perm}
+	if r.archive != nil {
+		// 周期内按大小轮转出的文件同样处理，由 mill 统一清理
+		l.Compress = false
+		rc.archive = &archiver{cfg: r.archive, perm: r.perm, localTime: l.LocalTime}
+	}
+	return rc
+}
+
+func (r *timeRotator) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cur == nil {
+		return nil
+	}
+	err := r.cur.Close()
+	r.cur = nil
+	return err
+}
+
+// rotateCounter 统计 lumberjack 按大小轮转的次数。lumberjack 没有轮转回调，
+// 这里按与其相同的规则跟踪文件大小：打开已有文件时加上本次写入达到上限、或写入后将超过上限时轮转
+type rotateCounter struct {
+	*lumberjack.Logger
+	rotated prometheus.Counter
+	// archive 不为空时在轮转后处理备份文件
+	archive *archiver
+	// perm 打开文件前按权限创建，轮转后修正 lumberjack 新建文件的权限
+	perm FilePerm
+
+	mu     sync.Mutex
+	opened bool
+	size   int64
+}
+
+func newRotateCounter(l *lumberjack.Logger, perm FilePerm) *rotateCounter {
+	return &rotateCounter{Logger: l, rotated: rotations.WithLabelValues(l.Filename), perm: perm}
+}
+
+func (w *rotateCounter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n, max := int64(len(p)), w.max()
+	var rotated bool
+	if n <= max {
+		if !w.opened {
+			w.size = 0
+			if info, err := os.Stat(w.Filename); err == nil {
+				w.size = info.Size()
+				rotated = w.size+n >= max
+			}
+		} else {
+			rotated = w.size+n > max
+		}
+	}
+	if rotated {
+		w.rotated.Inc()
+		w.size = 0
+	}
+	if !w.opened {
+		if err := w.perm.prepare(w.Filename); err != nil {
+			return 0, err
+		}
+	}
+	written, err := w.Logger.Write(p)
+	w.size += int64(written)
+	if err != nil {
+		return written, err
+	}
+	w.opened = true
+	if rotated {
+		// lumberjack 新建文件的权限受 umask 影响
+		if !w.perm.isZero() {
+			err = w.perm.apply(w.Filename)
+		}
+		if w.archive != nil {
+			go w.archive.sweep(w.Filename)
+		}
+	}
+	return written, err
+}
+
+// max 与 lumberjack 一致，MaxSize 为 0 时为 100MB
+func (w *rotateCounter) max() int64 {
+	if w.MaxSize == 0 {
+		return 100 << 20
+	}
+	return int64(w.MaxSize) << 20
+}
+
+// Close 关闭文件，下次写入时重新打开并读取文件大小
+func (w *rotateCounter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.opened = false
+	return w.Logger.Close()
+}
+
+// mill 压缩上一周期文件并清理过期文件，在后台执行避免阻塞写入
+func (r *timeRotator) mill(prev string) {
+	r.millMu.Lock()
+	defer r.millMu.Unlock()
+
+	if r.archive != nil {
+		if err := archiveFile(prev, r.archive, r.perm); err != nil {
+			fmt.Fprintf(os.Stderr, "logger: archive %s: %v\n", prev, err)
+		}
+	} else if r.tmpl.Compress {
+		_ = gzipFile(prev, r.perm)
+	}
+
+	matches, err := filepath.Glob(r.base + "-*")
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	current := r.cur
+	r.mu.Unlock()
+
+	type dated struct {
+		path string
+		t    time.Time
+	}
+	var files []dated
+	layout := r.interval.layout()
+	prefix := filepath.Base(r.base) + "-"
+	for _, m := range matches {
+		if current != nil && m == current.Filename {
+			continue
+		}
+		name := strings.TrimPrefix(filepath.Base(m), prefix)
+		if len(name) < len(layout) {
+			continue
+		}
+		t, err := time.Parse(layout, name[:len(layout)])
+		if err != nil {
+			continue
+		}
+		files = append(files, dated{path: m, t: t})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].t.After(files[j].t) })
+
+	cutoff := r.clock.Now().Add(-time.Duration(r.tmpl.MaxAge) * 24 * time.Hour)
+	for i, f := range files {
+		expired := r.tmpl.MaxAge > 0 && f.t.Before(cutoff)
+		overflow := r.tmpl.MaxBackups > 0 && i >= r.tmpl.MaxBackups
+		if expired || overflow {
+			_ = os.Remove(f.path)
+		}
+	}
+}
+
+func gzipFile(path string, perm FilePerm) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := copyPerm(dst.Name(), src, perm); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SamplingConfig 采样配置：每个 tick 内相同级别、相同消息的日志先输出 Initial 条，之后每 Thereafter 条输出 1 条
+type SamplingConfig struct {
+	Initial    int
+	Thereafter int
+	Tick       time.Duration
+}
+
+// WithSampling 对 Error 以下级别的日志采样，流量突增时避免 debug/info 日志占满磁盘，Error 及以上始终输出。
+// 被丢弃的条数计入 logger_sampling_dropped_total{core}：
+//
+//	logger.WithFileCore(logger.WithSampling(100, 10, time.Second))
+func WithSampling(initial, thereafter int, tick time.Duration) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Sampling = &SamplingConfig{Initial: initial, Thereafter: thereafter, Tick: tick}
+	}
+}
+
+// samplingCore Error 以下级别经过 sampled，其余直接交给 Core
+type samplingCore struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+func newSamplingCore(core zapcore.Core, name string, cfg *SamplingConfig) zapcore.Core {
+	dropped := samplingDropped.WithLabelValues(name)
+	sampled := zapcore.NewSamplerWithOptions(core, cfg.Tick, cfg.Initial, cfg.Thereafter,
+		zapcore.SamplerHook(func(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+			if dec&zapcore.LogDropped != 0 {
+				dropped.Inc()
+			}
+		}))
+	return &samplingCore{Core: core, sampled: sampled}
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{Core: c.Core.With(fields), sampled: c.sampled.With(fields)}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level >= zapcore.ErrorLevel {
+		return c.Core.Check(ent, ce)
+	}
+	return c.sampled.Check(ent, ce)
+}
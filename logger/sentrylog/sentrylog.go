@@ -0,0 +1,198 @@
+// Package sentrylog 将 go-kit logger 中 Error 及以上级别的日志上报到 Sentry
+package sentrylog
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+type options struct {
+	level       zapcore.Level
+	environment string
+	release     string
+	sampleRate  float64
+	tags        map[string]string
+	transport   sentry.Transport
+}
+
+// Option 上报选项
+type Option func(*options)
+
+// WithLevel 设置上报的最低级别，默认 Error
+func WithLevel(level zapcore.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithEnvironment 设置环境（production、staging 等），默认读取 SENTRY_ENVIRONMENT
+func WithEnvironment(env string) Option {
+	return func(o *options) {
+		o.environment = env
+	}
+}
+
+// WithRelease 设置版本，通常为 version.Get().Version
+func WithRelease(release string) Option {
+	return func(o *options) {
+		o.release = release
+	}
+}
+
+// WithSampleRate 设置采样率 (0, 1]，默认 1 即全部上报
+func WithSampleRate(rate float64) Option {
+	return func(o *options) {
+		o.sampleRate = rate
+	}
+}
+
+// WithTags 附加固定标签，如 service、region
+func WithTags(tags map[string]string) Option {
+	return func(o *options) {
+		o.tags = tags
+	}
+}
+
+// New 将 Error 及以上级别的日志（消息、字段、调用栈）上报到 Sentry，
+// 与其他 core 并列使用，不影响原有输出。字段作为 extra 上报，zap.Error 的错误作为异常类型与描述；
+// DSN 无效时输出到 stderr 并忽略该 core。事件在后台发送，Panic、Fatal 与 Logger.Close 时等待未发送的事件：
+//
+//	log, _ := logger.NewWithCore(
+//		logger.WithFileCore(),
+//		sentrylog.New(os.Getenv("SENTRY_DSN"), sentrylog.WithEnvironment("production"), sentrylog.WithSampleRate(0.5)),
+//	)
+func New(dsn string, opts ...Option) logger.CoreBuilder {
+	return func(core *zapcore.Core) {
+		o := &options{level: zapcore.ErrorLevel, sampleRate: 1}
+		for _, opt := range opts {
+			opt(o)
+		}
+		client, err := sentry.NewClient(sentry.ClientOptions{
+			Dsn:         dsn,
+			Environment: o.environment,
+			Release:     o.release,
+			SampleRate:  o.sampleRate,
+			Transport:   o.transport,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "logger: sentry: %v\n", err)
+			return
+		}
+		logger.WithCore("sentry", func(level zapcore.LevelEnabler) zapcore.Core {
+			return &sentryCore{LevelEnabler: level, client: client, tags: o.tags}
+		}, logger.WithLogLevel(o.level))(core)
+	}
+}
+
+// sentryCore 将条目转为 Sentry 事件
+type sentryCore struct {
+	zapcore.LevelEnabler
+	client *sentry.Client
+	tags   map[string]string
+	fields []zapcore.Field
+}
+
+func (c *sentryCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *sentryCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sentryCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	enc := zapcore.NewMapObjectEncoder()
+	var cause error
+	for _, fs := range [][]zapcore.Field{c.fields, fields} {
+		for _, f := range fs {
+			if err, ok := f.Interface.(error); ok && f.Type == zapcore.ErrorType {
+				cause = err
+			}
+			f.AddTo(enc)
+		}
+	}
+
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(ent.Level)
+	event.Message = ent.Message
+	event.Timestamp = ent.Time
+	event.Logger = ent.LoggerName
+	event.Extra = enc.Fields
+	for k, v := range c.tags {
+		event.Tags[k] = v
+	}
+	if ent.Caller.Defined {
+		event.Tags["caller"] = ent.Caller.TrimmedPath()
+	}
+
+	exc := sentry.Exception{Type: ent.Message, Stacktrace: sentryStacktrace()}
+	if cause != nil {
+		exc.Type = reflect.TypeOf(cause).String()
+		exc.Value = cause.Error()
+	}
+	event.Exception = []sentry.Exception{exc}
+
+	c.client.CaptureEvent(event, nil, nil)
+	if ent.Level > zapcore.ErrorLevel {
+		// Panic、Fatal 之后进程可能退出，等待事件发送完毕
+		c.client.Flush(2 * time.Second)
+	}
+	return nil
+}
+
+// Sync 不等待发送，避免每次 Sync 阻塞调用方；剩余事件在 Close 时发送
+func (c *sentryCore) Sync() error {
+	return nil
+}
+
+// Close 发送剩余事件
+func (c *sentryCore) Close() error {
+	c.client.Flush(5 * time.Second)
+	return nil
+}
+
+func sentryLevel(l zapcore.Level) sentry.Level {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return sentry.LevelDebug
+	case l == zapcore.InfoLevel:
+		return sentry.LevelInfo
+	case l == zapcore.WarnLevel:
+		return sentry.LevelWarning
+	case l == zapcore.ErrorLevel:
+		return sentry.LevelError
+	default:
+		return sentry.LevelFatal
+	}
+}
+
+// sentryStacktrace 采集调用栈并去掉 zap、logger 与本包的帧
+func sentryStacktrace() *sentry.Stacktrace {
+	st := sentry.NewStacktrace()
+	if st == nil {
+		return nil
+	}
+	frames := st.Frames[:0]
+	for _, f := range st.Frames {
+		if strings.HasPrefix(f.Module, "go.uber.org/zap") || f.Module == "github.com/abs2free/go-kit/logger" ||
+			f.Module == "github.com/abs2free/go-kit/logger/sentrylog" {
+			continue
+		}
+		frames = append(frames, f)
+	}
+	st.Frames = frames
+	return st
+}
@@ -0,0 +1,90 @@
+package sentrylog
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+type fakeSentryTransport struct {
+	mu      sync.Mutex
+	events  []*sentry.Event
+	flushes int
+}
+
+func (t *fakeSentryTransport) Flush(time.Duration) bool {
+	t.mu.Lock()
+	t.flushes++
+	t.mu.Unlock()
+	return true
+}
+func (t *fakeSentryTransport) FlushWithContext(context.Context) bool { return true }
+func (t *fakeSentryTransport) Configure(sentry.ClientOptions)        {}
+func (t *fakeSentryTransport) Close()                                {}
+func (t *fakeSentryTransport) SendEvent(e *sentry.Event) {
+	t.mu.Lock()
+	t.events = append(t.events, e)
+	t.mu.Unlock()
+}
+
+func TestNew(t *testing.T) {
+	tr := &fakeSentryTransport{}
+	obs, logs := observer.New(zap.InfoLevel)
+	log, err := logger.NewWithCore(
+		func(core *zapcore.Core) { *core = obs },
+		New("", WithEnvironment("staging"), WithTags(map[string]string{"service": "order"}),
+			func(o *options) { o.transport = tr }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Warn("slow")
+	log.Errorw("charge failed", "order_id", 7, zap.Error(os.ErrDeadlineExceeded))
+	_ = log.Sync()
+	tr.mu.Lock()
+	if tr.flushes != 0 {
+		t.Fatalf("Sync should not flush, got %d flushes", tr.flushes)
+	}
+	tr.mu.Unlock()
+	func() {
+		defer func() { _ = recover() }()
+		log.Panic("boom")
+	}()
+	tr.mu.Lock()
+	if tr.flushes != 1 {
+		t.Fatalf("expected Panic to flush, got %d flushes", tr.flushes)
+	}
+	tr.mu.Unlock()
+	_ = log.Close()
+
+	if logs.Len() != 3 {
+		t.Fatalf("expected normal output to continue, got %d entries", logs.Len())
+	}
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if len(tr.events) != 2 || tr.flushes != 2 {
+		t.Fatalf("expected 2 sentry events and a flush on Close, got %d %d", len(tr.events), tr.flushes)
+	}
+	e := tr.events[0]
+	if e.Message != "charge failed" || e.Level != sentry.LevelError || e.Environment != "staging" || e.Tags["service"] != "order" {
+		t.Fatalf("unexpected event %+v", e)
+	}
+	if e.Extra["order_id"] != int64(7) || len(e.Exception) != 1 || e.Exception[0].Value != os.ErrDeadlineExceeded.Error() {
+		t.Fatalf("unexpected extra/exception %v %+v", e.Extra, e.Exception)
+	}
+	for _, f := range e.Exception[0].Stacktrace.Frames {
+		if strings.HasPrefix(f.Module, "go.uber.org/zap") || strings.HasPrefix(f.Module, "github.com/abs2free/go-kit/logger") {
+			t.Fatalf("zap frame in stacktrace: %+v", f)
+		}
+	}
+}
@@ -0,0 +1,388 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SinkConfig 网络输出（Loki、Kafka、Elasticsearch 等）的缓冲与重试配置
+type SinkConfig struct {
+	// BufferSize 内存队列长度，默认 10000
+	BufferSize int
+	// BatchSize 单批最大条数，默认 500
+	BatchSize int
+	// FlushInterval 未攒满一批时的发送间隔，默认 1s
+	FlushInterval time.Duration
+	// MaxRetries 单批最大重试次数，默认 3
+	MaxRetries int
+	// RetryBackoff 首次重试等待时间，之后指数增长，默认 500ms
+	RetryBackoff time.Duration
+	// Timeout 单次发送超时，默认 10s
+	Timeout time.Duration
+	// BlockOnFull 队列满时阻塞写入（背压）而不是丢弃，默认丢弃以免拖慢业务
+	BlockOnFull bool
+	// Headers 附加的 HTTP 请求头（认证、租户等）
+	Headers map[string]string
+	// DeadLetterPath 重试后仍发送失败的日志追加写入该文件（每行一条），为空时丢弃
+	DeadLetterPath string
+	// RequireAck 等待服务端确认后才视为发送成功（Fluentd ack 模式）
+	RequireAck bool
+}
+
+// WithBatch 设置网络输出的批大小与发送间隔
+func WithBatch(size int, interval time.Duration) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Sink.BatchSize = size
+		cfg.Sink.FlushInterval = interval
+	}
+}
+
+// WithBuffer 设置网络输出的队列长度，blockOnFull 为 true 时队列满会阻塞写日志的协程
+func WithBuffer(size int, blockOnFull bool) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Sink.BufferSize = size
+		cfg.Sink.BlockOnFull = blockOnFull
+	}
+}
+
+// WithRetry 设置网络输出的重试次数与首次退避时间
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Sink.MaxRetries = maxRetries
+		cfg.Sink.RetryBackoff = backoff
+	}
+}
+
+// WithHTTPHeader 为基于 HTTP 的输出附加请求头，如 Authorization、X-Scope-OrgID
+func WithHTTPHeader(key, value string) Option {
+	return func(cfg *LoggerConfig) {
+		headers := make(map[string]string, len(cfg.Sink.Headers)+1)
+		for k, v := range cfg.Sink.Headers {
+			headers[k] = v
+		}
+		headers[key] = value
+		cfg.Sink.Headers = headers
+	}
+}
+
+// WithDeadLetter 设置死信文件，网络输出重试后仍失败的日志写入该文件，便于恢复后重放
+func WithDeadLetter(path string) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.Sink.DeadLetterPath = path
+	}
+}
+
+func (c SinkConfig) withDefaults() SinkConfig {
+	if c.BufferSize <= 0 {
+		c.BufferSize = 10000
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 500
+	}
+	if c.FlushInterval <= 0 {
+		c.FlushInterval = time.Second
+	}
+	if c.MaxRetries < 0 {
+		c.MaxRetries = 0
+	} else if c.MaxRetries == 0 {
+		c.MaxRetries = 3
+	}
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = 500 * time.Millisecond
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10 * time.Second
+	}
+	return c
+}
+
+// errPermanent 标记不应重试的发送错误（如 400）
+type errPermanent struct{ error }
+
+func (e errPermanent) Unwrap() error { return e.error }
+
+// errPartial 批内部分失败：retry 重试，rejected 不再重试
+type errPartial struct {
+	error
+	retry    []sinkRecord
+	rejected []sinkRecord
+}
+
+func (e errPartial) Unwrap() error { return e.error }
+
+// sinkRecord 已编码的一条日志
+type sinkRecord struct {
+	time time.Time
+	line []byte
+}
+
+// batchWriter 异步批量发送的 WriteSyncer：Write 将编码后的日志放入有界队列，
+// 后台协程按条数或间隔批量调用 send，失败时指数退避重试
+type batchWriter struct {
+	name string
+	cfg  SinkConfig
+	send func(ctx context.Context, batch []sinkRecord) error
+
+	queue   chan sinkRecord
+	flushCh chan chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	closed  atomic.Bool
+	// closeOnce 保证 onClose 只调用一次
+	closeOnce sync.Once
+
+	dropped atomic.Int64
+	failed  atomic.Int64
+	// errOut 发送失败的提示输出，默认 stderr
+	errOut io.Writer
+	// onClose 后台协程退出后调用，用于释放连接等资源
+	onClose func() error
+}
+
+func newBatchWriter(name string, cfg SinkConfig, send func(context.Context, []sinkRecord) error) *batchWriter {
+	cfg = cfg.withDefaults()
+	w := &batchWriter{
+		name:    name,
+		cfg:     cfg,
+		send:    send,
+		queue:   make(chan sinkRecord, cfg.BufferSize),
+		flushCh: make(chan chan struct{}),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		errOut:  os.Stderr,
+	}
+	go w.run()
+	return w
+}
+
+// Write 复制数据后入队；队列满时按配置阻塞或丢弃
+func (w *batchWriter) Write(p []byte) (int, error) {
+	if w.closed.Load() {
+		w.drop()
+		return len(p), nil
+	}
+	rec := sinkRecord{time: time.Now(), line: bytes.TrimRight(append([]byte(nil), p...), "\n")}
+	if w.cfg.BlockOnFull {
+		select {
+		case w.queue <- rec:
+		case <-w.done:
+			w.drop()
+		}
+		return len(p), nil
+	}
+	select {
+	case w.queue <- rec:
+	default:
+		w.drop()
+	}
+	return len(p), nil
+}
+
+// Sync 等待队列中已有的日志发送完毕
+func (w *batchWriter) Sync() error {
+	if w.closed.Load() {
+		return nil
+	}
+	ack := make(chan struct{})
+	select {
+	case w.flushCh <- ack:
+		<-ack
+	case <-w.stopped:
+	}
+	return nil
+}
+
+// Close 发送剩余日志并停止后台协程
+func (w *batchWriter) Close() error {
+	w.once.Do(func() {
+		w.closed.Store(true)
+		close(w.done)
+	})
+	<-w.stopped
+	if w.onClose != nil {
+		w.closeOnce.Do(func() { _ = w.onClose() })
+	}
+	if n := w.dropped.Load(); n > 0 {
+		return fmt.Errorf("logger: %s sink dropped %d entries", w.name, n)
+	}
+	return nil
+}
+
+func (w *batchWriter) drop() {
+	w.dropped.Add(1)
+	sinkDropped.WithLabelValues(w.name).Inc()
+}
+
+// Dropped 返回因队列满或关闭后写入而丢弃的条数
+func (w *batchWriter) Dropped() int64 {
+	return w.dropped.Load()
+}
+
+// Failed 返回重试后仍发送失败的条数
+func (w *batchWriter) Failed() int64 {
+	return w.failed.Load()
+}
+
+func (w *batchWriter) run() {
+	defer close(w.stopped)
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]sinkRecord, 0, w.cfg.BatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			w.deliver(batch)
+			batch = make([]sinkRecord, 0, w.cfg.BatchSize)
+		}
+	}
+	// drain 取出队列中已有的全部日志
+	drain := func() {
+		for {
+			select {
+			case rec := <-w.queue:
+				batch = append(batch, rec)
+				if len(batch) >= w.cfg.BatchSize {
+					flush()
+				}
+			default:
+				flush()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case rec := <-w.queue:
+			batch = append(batch, rec)
+			if len(batch) >= w.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case ack := <-w.flushCh:
+			drain()
+			close(ack)
+		case <-w.done:
+			drain()
+			return
+		}
+	}
+}
+
+// deliver 发送一批日志，可重试错误按指数退避重试；关闭期间不再等待退避
+func (w *batchWriter) deliver(batch []sinkRecord) {
+	backoff := w.cfg.RetryBackoff
+	var err error
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(backoff):
+			case <-w.done:
+			}
+			backoff *= 2
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+		err = w.send(ctx, batch)
+		cancel()
+		var partial errPartial
+		if errors.As(err, &partial) {
+			w.fail(partial.rejected, err)
+			if batch = partial.retry; len(batch) == 0 {
+				return
+			}
+			continue
+		}
+		var perm errPermanent
+		if err == nil || errors.As(err, &perm) {
+			break
+		}
+	}
+	if err != nil {
+		w.fail(batch, err)
+	}
+}
+
+// fail 记录发送失败的日志，配置了死信文件时写入其中
+func (w *batchWriter) fail(batch []sinkRecord, err error) {
+	if len(batch) == 0 {
+		return
+	}
+	w.failed.Add(int64(len(batch)))
+	sinkFailed.WithLabelValues(w.name).Add(float64(len(batch)))
+	if w.cfg.DeadLetterPath != "" {
+		derr := appendDeadLetter(w.cfg.DeadLetterPath, batch)
+		if derr == nil {
+			fmt.Fprintf(w.errOut, "%s logger: %s sink: %d entries written to %s: %v\n", time.Now().Format(time.RFC3339), w.name, len(batch), w.cfg.DeadLetterPath, err)
+			return
+		}
+		err = errors.Join(err, derr)
+	}
+	fmt.Fprintf(w.errOut, "%s logger: %s sink: drop %d entries: %v\n", time.Now().Format(time.RFC3339), w.name, len(batch), err)
+}
+
+func appendDeadLetter(path string, batch []sinkRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		buf.Write(rec.line)
+		buf.WriteByte('\n')
+	}
+	_, err = f.Write(buf.Bytes())
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// postHTTP 发送请求，429 与 5xx 视为可重试，其余非 2xx 为永久错误
+func postHTTP(ctx context.Context, client *http.Client, url, contentType string, body []byte, headers map[string]string) error {
+	_, err := postHTTPBody(ctx, client, url, contentType, body, headers, 0)
+	return err
+}
+
+// postHTTPBody 同 postHTTP，成功时返回最多 limit 字节的响应体
+func postHTTPBody(ctx context.Context, client *http.Client, url, contentType string, body []byte, headers map[string]string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, errPermanent{err}
+	}
+	req.Header.Set("Content-Type", contentType)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		if limit <= 0 {
+			return nil, nil
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+		return data, err
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	err = fmt.Errorf("status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return nil, err
+	}
+	return nil, errPermanent{err}
+}
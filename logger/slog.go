@@ -0,0 +1,171 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandler 将 log/slog 的记录写入 Logger 的 core，与应用日志共享输出、轮转与格式；
+// 记录的 context 中的 trace_id、request_id 等字段（见 FromContext）一并写入
+type SlogHandler struct {
+	core   zapcore.Core
+	name   string
+	groups []string
+	// opened 已通过 zap.Namespace 打开的分组数
+	opened int
+	attrs  []zap.Field
+}
+
+// NewSlogHandler 基于 log 创建 slog.Handler，log 为 nil 时使用全局日志：
+//
+//	slog.SetDefault(slog.New(logger.NewSlogHandler(log)))
+func NewSlogHandler(log *Logger) *SlogHandler {
+	if log == nil {
+		log = Default()
+	}
+	z := log.Desugar()
+	return &SlogHandler{core: z.Core(), name: z.Name()}
+}
+
+// Enabled 实现 slog.Handler
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(slogLevel(level))
+}
+
+// Handle 实现 slog.Handler
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	ent := zapcore.Entry{
+		Level:      slogLevel(record.Level),
+		Time:       record.Time,
+		Message:    record.Message,
+		LoggerName: h.name,
+	}
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, frame.PC != 0)
+		ce.Caller.Function = frame.Function
+	}
+
+	// context 字段写在最外层，不受分组影响
+	fields := ContextFieldsOf(ctx)
+	fields = append(fields, h.attrs...)
+	groups := h.groups[h.opened:]
+	record.Attrs(func(a slog.Attr) bool {
+		if f, ok := slogField(a); ok {
+			// 只有存在字段时才打开分组，避免输出空对象
+			for _, g := range groups {
+				fields = append(fields, zap.Namespace(g))
+			}
+			groups = nil
+			fields = append(fields, f)
+		}
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := h.clone()
+	for _, a := range attrs {
+		if f, ok := slogField(a); ok {
+			for _, g := range c.groups[c.opened:] {
+				c.attrs = append(c.attrs, zap.Namespace(g))
+			}
+			c.opened = len(c.groups)
+			c.attrs = append(c.attrs, f)
+		}
+	}
+	return c
+}
+
+// WithGroup 实现 slog.Handler
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := h.clone()
+	c.groups = append(c.groups, name)
+	return c
+}
+
+func (h *SlogHandler) clone() *SlogHandler {
+	c := *h
+	c.groups = append([]string(nil), h.groups...)
+	c.attrs = append([]zap.Field(nil), h.attrs...)
+	return &c
+}
+
+// slogLevel 将 slog 级别映射到最接近且不高于它的 zap 级别
+func slogLevel(l slog.Level) zapcore.Level {
+	switch {
+	case l >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case l >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case l >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	default:
+		return zapcore.DebugLevel
+	}
+}
+
+// slogField 转换属性，空属性按 slog 约定忽略
+func slogField(a slog.Attr) (zap.Field, bool) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return zap.Skip(), false
+	}
+	v := a.Value
+	switch v.Kind() {
+	case slog.KindString:
+		return zap.String(a.Key, v.String()), true
+	case slog.KindInt64:
+		return zap.Int64(a.Key, v.Int64()), true
+	case slog.KindUint64:
+		return zap.Uint64(a.Key, v.Uint64()), true
+	case slog.KindFloat64:
+		return zap.Float64(a.Key, v.Float64()), true
+	case slog.KindBool:
+		return zap.Bool(a.Key, v.Bool()), true
+	case slog.KindDuration:
+		return zap.Duration(a.Key, v.Duration()), true
+	case slog.KindTime:
+		return zap.Time(a.Key, v.Time()), true
+	case slog.KindGroup:
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return zap.Skip(), false
+		}
+		if a.Key == "" {
+			// 无名分组按 slog 约定内联，这里作为对象的字段展开
+			return zap.Inline(slogGroup(attrs)), true
+		}
+		return zap.Object(a.Key, slogGroup(attrs)), true
+	default:
+		if err, ok := v.Any().(error); ok {
+			return zap.NamedError(a.Key, err), true
+		}
+		return zap.Any(a.Key, v.Any()), true
+	}
+}
+
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		if f, ok := slogField(a); ok {
+			f.AddTo(enc)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"errors"
+	"os"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 分级文件的默认路径
+const (
+	DefaultAppLogPath   = "logs/app.log"
+	DefaultErrorLogPath = "logs/error.log"
+)
+
+// WithLevelSplitFileCore 按级别拆分文件输出：Debug/Info 写入 app.log，Warn 及以上写入 error.log。
+// 两个文件分别使用 appOptions、errorOptions 配置路径、轮转与级别，运行时级别名为 app / error：
+//
+//	logger.WithLevelSplitFileCore(
+//		[]logger.Option{logger.WithLogFilePath("logs/app.log"), logger.WithRotateSettings(100, 7, true)},
+//		[]logger.Option{logger.WithLogFilePath("logs/error.log"), logger.WithRotateSettings(50, 30, true)},
+//	)
+func WithLevelSplitFileCore(appOptions, errorOptions []Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		app := newSplitFileCore("app", DefaultAppLogPath, appOptions, func(l zapcore.Level) bool {
+			return l < zapcore.WarnLevel
+		})
+		errs := newSplitFileCore("error", DefaultErrorLogPath, errorOptions, func(l zapcore.Level) bool {
+			return l >= zapcore.WarnLevel
+		})
+		*core = newLeveledTee(app, errs)
+	}
+}
+
+func newSplitFileCore(name, path string, options []Option, accept func(zapcore.Level) bool) *leveledCore {
+	cfg := DefaultConfig.clone()
+	WithLogFilePath(path)(cfg)
+	for _, opt := range options {
+		opt(cfg)
+	}
+	cfg.Rotate.Filename = expandFilename(cfg.Rotate.Filename)
+
+	core := cfg.newCore(name, cfg.newEncoder(EncodingJSON), newFileWriter(cfg))
+	core.Core = &filterCore{Core: core.Core, accept: accept}
+	return core
+}
+
+// filterCore 只接受 accept 返回 true 的级别，模块级别覆盖也不能越过该限制
+type filterCore struct {
+	zapcore.Core
+	accept func(zapcore.Level) bool
+}
+
+func (c *filterCore) Enabled(l zapcore.Level) bool {
+	return c.accept(l) && c.Core.Enabled(l)
+}
+
+func (c *filterCore) With(fields []zapcore.Field) zapcore.Core {
+	return &filterCore{Core: c.Core.With(fields), accept: c.accept}
+}
+
+func (c *filterCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.accept(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// WithStderrLevel 控制台输出中 level 及以上的日志写入标准错误，其余写入标准输出，
+// 便于按流区分日志的容器平台与 CI 系统识别告警与错误：
+//
+//	logger.WithConsoleCore(logger.WithStderrLevel(zap.WarnLevel))
+func WithStderrLevel(level zapcore.Level) Option {
+	return func(cfg *LoggerConfig) {
+		cfg.StderrLevel = &level
+	}
+}
+
+// newStreamSplitCore 按级别写入标准输出或标准错误，两者共用级别与模块级别等配置
+func (c *LoggerConfig) newStreamSplitCore(name string, enc zapcore.Encoder, at zapcore.Level) *leveledCore {
+	stdout, closers := c.writeSyncer(name, zapcore.Lock(os.Stdout))
+	stderr, errClosers := c.writeSyncer(name, zapcore.Lock(os.Stderr))
+	return c.wrapCore(name, func(level zapcore.LevelEnabler) zapcore.Core {
+		return &streamSplitCore{
+			low:  zapcore.NewCore(enc, stdout, level),
+			high: zapcore.NewCore(enc.Clone(), stderr, level),
+			at:   at,
+		}
+	}, append(closers, errClosers...)...)
+}
+
+// streamSplitCore 低于 at 的条目写入 low，其余写入 high
+type streamSplitCore struct {
+	low, high zapcore.Core
+	at        zapcore.Level
+}
+
+func (c *streamSplitCore) Enabled(l zapcore.Level) bool {
+	return c.low.Enabled(l)
+}
+
+func (c *streamSplitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &streamSplitCore{low: c.low.With(fields), high: c.high.With(fields), at: c.at}
+}
+
+func (c *streamSplitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *streamSplitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if ent.Level >= c.at {
+		return c.high.Write(ent, fields)
+	}
+	return c.low.Write(ent, fields)
+}
+
+func (c *streamSplitCore) Sync() error {
+	return errors.Join(c.low.Sync(), c.high.Sync())
+}
@@ -0,0 +1,32 @@
+package logger
+
+import (
+	"log"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// StdLogger 返回以 level 写入当前日志的 *log.Logger，用于 http.Server.ErrorLog、sarama 等
+// 只接受标准库日志的依赖：
+//
+//	srv := &http.Server{ErrorLog: log.StdLogger(zap.WarnLevel)}
+func (l *Logger) StdLogger(level zapcore.Level) *log.Logger {
+	z := l.Desugar().WithOptions(zap.AddCallerSkip(-l.callerSkip))
+	std, err := zap.NewStdLogAt(z, level)
+	if err != nil {
+		// 仅在 level 非法时出错，退回 Info
+		std = zap.NewStdLog(z)
+	}
+	return std
+}
+
+// StdLogger 基于全局日志创建 *log.Logger
+func StdLogger(level zapcore.Level) *log.Logger {
+	return Default().StdLogger(level)
+}
+
+// RedirectStdLog 将标准库 log 包的全局输出重定向到 l（Info 级别），返回恢复函数
+func (l *Logger) RedirectStdLog() func() {
+	return zap.RedirectStdLog(l.Desugar().WithOptions(zap.AddCallerSkip(-l.callerSkip)))
+}
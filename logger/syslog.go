@@ -0,0 +1,226 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogFacility syslog 设施（RFC 5424 6.2.1）
+type SyslogFacility int
+
+const (
+	FacilityKern SyslogFacility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthpriv
+	FacilityFtp
+)
+
+const (
+	FacilityLocal0 SyslogFacility = iota + 16
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+// WithSyslogCore 以 RFC 5424 格式将日志发往 syslog，消息体为 JSON。
+// network 为 udp、tcp、unix 或 unixgram，tcp 使用 octet-counting 分帧（RFC 6587），unix 按行分隔；
+// network 与 addr 均为空时连接本机 /dev/log。级别映射为：
+// Debug→debug(7)、Info→info(6)、Warn→warning(4)、Error→err(3)、DPanic→crit(2)、Panic→alert(1)、Fatal→emerg(0)：
+//
+//	logger.WithSyslogCore("udp", "rsyslog:514", logger.FacilityLocal0, logger.WithLogLevel(zap.InfoLevel))
+func WithSyslogCore(network, addr string, facility SyslogFacility, options ...Option) CoreBuilder {
+	return func(core *zapcore.Core) {
+		cfg := DefaultConfig.clone()
+		for _, opt := range options {
+			opt(cfg)
+		}
+		w := newSyslogWriter(network, addr, facility)
+		enc := newJSONEncoder(cfg)
+		*core = cfg.wrapCore("syslog", func(level zapcore.LevelEnabler) zapcore.Core {
+			return &syslogCore{LevelEnabler: level, enc: enc, w: w}
+		}, w)
+	}
+}
+
+// syslogSeverity 将 zap 级别映射为 syslog severity
+func syslogSeverity(l zapcore.Level) int {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return 7
+	case l == zapcore.InfoLevel:
+		return 6
+	case l == zapcore.WarnLevel:
+		return 4
+	case l == zapcore.ErrorLevel:
+		return 3
+	case l == zapcore.DPanicLevel:
+		return 2
+	case l == zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// syslogCore 按条目级别写入 syslog，级别过滤由外层 leveledCore / moduleCore 负责
+type syslogCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	w   *syslogWriter
+}
+
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, enc: enc, w: c.w}
+}
+
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	return c.w.write(ent.Level, ent.Time, bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func (c *syslogCore) Sync() error {
+	return nil
+}
+
+// syslogWriter 维护到 syslog 的连接，写失败时重连一次
+type syslogWriter struct {
+	network  string
+	addr     string
+	facility SyslogFacility
+	hostname string
+	app      string
+	pid      string
+
+	mu   sync.Mutex
+	conn net.Conn
+	// octet tcp 使用 octet-counting 分帧，newline unix 流按行分隔
+	octet   bool
+	newline bool
+	closed  bool
+}
+
+func newSyslogWriter(network, addr string, facility SyslogFacility) *syslogWriter {
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	return &syslogWriter{
+		network:  network,
+		addr:     addr,
+		facility: facility,
+		hostname: hostname,
+		app:      filepath.Base(os.Args[0]),
+		pid:      strconv.Itoa(os.Getpid()),
+	}
+}
+
+func (w *syslogWriter) write(level zapcore.Level, t time.Time, msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return errors.New("logger: syslog writer closed")
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if err = w.dial(); err != nil {
+				return err
+			}
+		}
+		if _, err = w.conn.Write(w.format(level, t, msg)); err == nil {
+			return nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+	return fmt.Errorf("logger: syslog write: %w", err)
+}
+
+// format 生成 RFC 5424 消息：<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG
+func (w *syslogWriter) format(level zapcore.Level, t time.Time, msg []byte) []byte {
+	pri := int(w.facility)*8 + syslogSeverity(level)
+	line := fmt.Appendf(nil, "<%d>1 %s %s %s %s - - ", pri, t.Format(time.RFC3339Nano), w.hostname, w.app, w.pid)
+	line = append(line, msg...)
+	switch {
+	case w.octet:
+		return append(fmt.Appendf(nil, "%d ", len(line)), line...)
+	case w.newline:
+		return append(line, '\n')
+	}
+	return line
+}
+
+func (w *syslogWriter) dial() error {
+	if w.network != "" || w.addr != "" {
+		conn, err := net.DialTimeout(w.network, w.addr, 5*time.Second)
+		if err != nil {
+			return fmt.Errorf("logger: syslog dial %s %s: %w", w.network, w.addr, err)
+		}
+		w.conn = conn
+		w.octet = strings.HasPrefix(w.network, "tcp")
+		w.newline = w.network == "unix"
+		return nil
+	}
+	// 本机 syslog，不同系统的 socket 路径与类型不同
+	for _, path := range []string{"/dev/log", "/var/run/syslog", "/var/run/log"} {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				w.conn = conn
+				w.newline = network == "unix"
+				return nil
+			}
+		}
+	}
+	return errors.New("logger: syslog: local syslog socket not found")
+}
+
+// Close 关闭连接，之后的写入返回错误
+func (w *syslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn != nil {
+		err := w.conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
@@ -10,12 +10,13 @@ import (
 func main() {
 
 	// 初始化日志
-	log, err := logger.New(zap.DebugLevel)
+	log, err := logger.New(logger.WithLogLevel(zap.DebugLevel))
 	if err != nil {
 		fmt.Printf("Failed to initialize logger: %v\n", err)
 		return
 	}
 	defer log.Sync()
+	logger.SetDefault(log)
 	log.Info("this is a test")
 	log.Errorf("this is a error message")
 }
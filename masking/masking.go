@@ -0,0 +1,144 @@
+package masking
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 内置脱敏规则名，用于 struct tag `mask:"phone"` 与 ByKey
+const (
+	KindPhone    = "phone"
+	KindEmail    = "email"
+	KindIDCard   = "idcard"
+	KindBankCard = "bankcard"
+	KindName     = "name"
+	KindSecret   = "secret"
+	KindDefault  = "default"
+)
+
+// Func 脱敏函数
+type Func func(string) string
+
+var (
+	mu    sync.RWMutex
+	funcs = map[string]Func{
+		KindPhone:    Phone,
+		KindEmail:    Email,
+		KindIDCard:   IDCard,
+		KindBankCard: BankCard,
+		KindName:     Name,
+		KindSecret:   Secret,
+		KindDefault:  Default,
+	}
+)
+
+// Register 注册自定义脱敏规则，同名规则会被覆盖
+func Register(kind string, fn Func) {
+	mu.Lock()
+	defer mu.Unlock()
+	funcs[kind] = fn
+}
+
+// Mask 按规则名脱敏，未知规则使用 Default
+func Mask(kind, s string) string {
+	mu.RLock()
+	fn, ok := funcs[kind]
+	mu.RUnlock()
+	if !ok {
+		fn = Default
+	}
+	return fn(s)
+}
+
+// keep 保留前 head 个与后 tail 个字符，其余替换为 *；长度不足时全部替换
+func keep(s string, head, tail int) string {
+	runes := []rune(s)
+	n := len(runes)
+	if n == 0 {
+		return s
+	}
+	if n <= head+tail {
+		return strings.Repeat("*", n)
+	}
+	return string(runes[:head]) + strings.Repeat("*", n-head-tail) + string(runes[n-tail:])
+}
+
+// Phone 手机号保留前 3 后 4 位：138****8000
+func Phone(s string) string {
+	if strings.HasPrefix(s, "+") && len(s) > 11 {
+		// 国际格式保留国家码
+		return s[:len(s)-11] + keep(s[len(s)-11:], 3, 4)
+	}
+	return keep(s, 3, 4)
+}
+
+// Email 邮箱用户名保留首字符：z***@example.com
+func Email(s string) string {
+	at := strings.LastIndexByte(s, '@')
+	if at <= 0 {
+		return Default(s)
+	}
+	name := s[:at]
+	_, size := utf8.DecodeRuneInString(name)
+	return name[:size] + "***" + s[at:]
+}
+
+// IDCard 证件号保留前 6 后 4 位：110105********002X
+func IDCard(s string) string {
+	return keep(s, 6, 4)
+}
+
+// BankCard 银行卡号保留前 6 后 4 位，忽略空格分隔
+func BankCard(s string) string {
+	return keep(strings.ReplaceAll(s, " ", ""), 6, 4)
+}
+
+// Name 姓名保留首字：张**
+func Name(s string) string {
+	n := utf8.RuneCountInString(s)
+	if n <= 1 {
+		return s
+	}
+	return keep(s, 1, 0)
+}
+
+// Secret 密码、token 等完全隐藏
+func Secret(s string) string {
+	if s == "" {
+		return s
+	}
+	return "******"
+}
+
+// Default 保留首尾各 1/4
+func Default(s string) string {
+	n := utf8.RuneCountInString(s)
+	return keep(s, n/4, n/4)
+}
+
+// KeyRules 字段名到规则的映射，ByKey 与 Paths 按字段名匹配时使用（不区分大小写）
+var KeyRules = map[string]string{
+	"phone":         KindPhone,
+	"mobile":        KindPhone,
+	"email":         KindEmail,
+	"id_card":       KindIDCard,
+	"idcard":        KindIDCard,
+	"id_no":         KindIDCard,
+	"bank_card":     KindBankCard,
+	"card_no":       KindBankCard,
+	"password":      KindSecret,
+	"token":         KindSecret,
+	"secret":        KindSecret,
+	"access_token":  KindSecret,
+	"refresh_token": KindSecret,
+}
+
+// ByKey 根据字段名判断是否需要脱敏，供日志脱敏等按 key 处理的场景使用
+func ByKey(key, value string) (string, bool) {
+	kind, ok := KeyRules[strings.ToLower(key)]
+	if !ok {
+		return value, false
+	}
+	return Mask(kind, value), true
+}
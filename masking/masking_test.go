@@ -0,0 +1,101 @@
+package masking
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	cases := []struct {
+		kind, in, want string
+	}{
+		{KindPhone, "13800138000", "138****8000"},
+		{KindPhone, "+8613800138000", "+86138****8000"},
+		{KindEmail, "zhangsan@example.com", "z***@example.com"},
+		{KindIDCard, "11010519491231002X", "110105********002X"},
+		{KindBankCard, "6222 0212 3456 7890", "622202******7890"},
+		{KindName, "张三丰", "张**"},
+		{KindSecret, "p@ss", "******"},
+		{"unknown", "abcdefgh", "ab****gh"},
+	}
+	for _, c := range cases {
+		if got := Mask(c.kind, c.in); got != c.want {
+			t.Errorf("Mask(%s, %q) = %q, want %q", c.kind, c.in, got, c.want)
+		}
+	}
+
+	if v, ok := ByKey("Mobile", "13800138000"); !ok || v != "138****8000" {
+		t.Fatalf("unexpected ByKey result %q %v", v, ok)
+	}
+	if _, ok := ByKey("nickname", "x"); ok {
+		t.Fatalf("nickname should not be masked")
+	}
+}
+
+type contact struct {
+	Phone *string `mask:"phone"`
+}
+
+type user struct {
+	Name     string   `mask:"name"`
+	Emails   []string `mask:"email"`
+	Contacts []contact
+	Extra    map[string]contact
+	internal string
+}
+
+func TestStruct(t *testing.T) {
+	phone := "13800138000"
+	u := user{
+		Name:     "李四",
+		Emails:   []string{"lisi@example.com"},
+		Contacts: []contact{{Phone: &phone}},
+		Extra:    map[string]contact{"home": {Phone: &phone}},
+		internal: "keep",
+	}
+
+	m := Struct(u)
+	if m.Name != "李*" || m.Emails[0] != "l***@example.com" || *m.Contacts[0].Phone != "138****8000" ||
+		*m.Extra["home"].Phone != "138****8000" || m.internal != "keep" {
+		t.Fatalf("unexpected masked value %+v", m)
+	}
+	if u.Name != "李四" || phone != "13800138000" || u.Emails[0] != "lisi@example.com" {
+		t.Fatalf("original value modified")
+	}
+}
+
+func TestPaths(t *testing.T) {
+	var data any
+	_ = json.Unmarshal([]byte(`{"user": {"phone": "13800138000"}, "items": [{"card_no": "6222021234567890"}]}`), &data)
+
+	Paths(data, map[string]string{"user.phone": KindPhone, "items.card_no": KindBankCard})
+
+	out, _ := json.Marshal(data)
+	want := `{"items":[{"card_no":"622202******7890"}],"user":{"phone":"138****8000"}}`
+	if string(out) != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+}
+
+func TestStructCycle(t *testing.T) {
+	type node struct {
+		Phone string `mask:"phone"`
+		Next  *node
+		Meta  map[string]any
+	}
+	a := &node{Phone: "13812345678", Meta: map[string]any{}}
+	b := &node{Phone: "13987654321", Next: a}
+	a.Next = b
+	a.Meta["self"] = a.Meta
+
+	got := Struct(a)
+	if got.Phone != "138****5678" || got.Next.Phone != "139****4321" {
+		t.Fatalf("unexpected masked values %q %q", got.Phone, got.Next.Phone)
+	}
+	if got.Next.Next != got || got == a {
+		t.Fatal("expected the cycle to be preserved in the copy")
+	}
+	if a.Phone != "13812345678" {
+		t.Fatal("source modified")
+	}
+}
@@ -0,0 +1,158 @@
+package masking
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Struct 返回按 `mask` tag 脱敏后的深拷贝，原值不变；tag 可用于 string、*string、[]string 字段：
+//
+//	type User struct {
+//		Phone string `json:"phone" mask:"phone"`
+//		Email string `json:"email" mask:"email"`
+//	}
+//	json.NewEncoder(w).Encode(masking.Struct(user))
+//
+// 同一指针、map、slice 被多处引用时拷贝中仍共享同一副本，循环引用不会导致死循环
+func Struct[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	c := copier{seen: make(map[refKey]reflect.Value)}
+	c.copyMasked(dst, src, "")
+	return dst.Interface().(T)
+}
+
+// refKey 标识一个已拷贝的引用；同一对象以不同规则脱敏时结果不同，需分别拷贝
+type refKey struct {
+	ptr  uintptr
+	len  int
+	typ  reflect.Type
+	kind string
+}
+
+type copier struct {
+	seen map[refKey]reflect.Value
+}
+
+func newRefKey(v reflect.Value, kind string) refKey {
+	k := refKey{ptr: v.Pointer(), typ: v.Type(), kind: kind}
+	if v.Kind() == reflect.Slice {
+		k.len = v.Len()
+	}
+	return k
+}
+
+// copied 返回已拷贝过的副本，用于处理共享与循环引用
+func (c *copier) copied(dst, src reflect.Value, kind string) bool {
+	if n, ok := c.seen[newRefKey(src, kind)]; ok {
+		dst.Set(n)
+		return true
+	}
+	return false
+}
+
+func (c *copier) copyMasked(dst, src reflect.Value, kind string) {
+	switch src.Kind() {
+	case reflect.String:
+		if kind != "" {
+			dst.SetString(Mask(kind, src.String()))
+			return
+		}
+		dst.Set(src)
+	case reflect.Pointer:
+		if src.IsNil() || c.copied(dst, src, kind) {
+			return
+		}
+		n := reflect.New(src.Type().Elem())
+		c.seen[newRefKey(src, kind)] = n
+		c.copyMasked(n.Elem(), src.Elem(), kind)
+		dst.Set(n)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		inner := src.Elem()
+		n := reflect.New(inner.Type()).Elem()
+		c.copyMasked(n, inner, kind)
+		dst.Set(n)
+	case reflect.Struct:
+		// 先整体复制（包含未导出字段），再处理可设置的字段
+		dst.Set(src)
+		t := src.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := dst.Field(i)
+			if !f.CanSet() {
+				continue
+			}
+			c.copyMasked(f, src.Field(i), t.Field(i).Tag.Get("mask"))
+		}
+	case reflect.Slice:
+		if src.IsNil() || c.copied(dst, src, kind) {
+			return
+		}
+		n := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		c.seen[newRefKey(src, kind)] = n
+		for i := 0; i < src.Len(); i++ {
+			c.copyMasked(n.Index(i), src.Index(i), kind)
+		}
+		dst.Set(n)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copyMasked(dst.Index(i), src.Index(i), kind)
+		}
+	case reflect.Map:
+		if src.IsNil() || c.copied(dst, src, kind) {
+			return
+		}
+		n := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.seen[newRefKey(src, kind)] = n
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			c.copyMasked(v, iter.Value(), kind)
+			n.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(n)
+	default:
+		dst.Set(src)
+	}
+}
+
+// Paths 按字段路径脱敏 JSON 解码得到的通用结构（map[string]any / []any），原地修改。
+// 路径以 . 分隔，数组会对每个元素生效，如 {"user.phone": "phone", "items.card_no": "bankcard"}；
+// 用于导出、转发等没有结构体定义的场景
+func Paths(data any, rules map[string]string) {
+	for path, kind := range rules {
+		applyPath(data, strings.Split(path, "."), kind)
+	}
+}
+
+func applyPath(node any, path []string, kind string) {
+	switch n := node.(type) {
+	case []any:
+		for i, item := range n {
+			if len(path) == 0 {
+				if s, ok := item.(string); ok {
+					n[i] = Mask(kind, s)
+				}
+				continue
+			}
+			applyPath(item, path, kind)
+		}
+	case map[string]any:
+		if len(path) == 0 {
+			return
+		}
+		v, ok := n[path[0]]
+		if !ok {
+			return
+		}
+		if len(path) == 1 {
+			if s, ok := v.(string); ok {
+				n[path[0]] = Mask(kind, s)
+				return
+			}
+		}
+		applyPath(v, path[1:], kind)
+	}
+}
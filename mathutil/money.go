@@ -0,0 +1,320 @@
+package mathutil
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("mathutil: currency mismatch")
+	ErrOverflow         = errors.New("mathutil: amount overflow")
+	ErrInvalidAmount    = errors.New("mathutil: invalid amount")
+)
+
+// Currency ISO 4217 货币代码
+type Currency string
+
+const (
+	CNY Currency = "CNY"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	HKD Currency = "HKD"
+	JPY Currency = "JPY"
+	KRW Currency = "KRW"
+)
+
+// DefaultCurrency 未指定货币时使用（如从数据库 Scan 到零值 Money）
+var DefaultCurrency = CNY
+
+var (
+	currencyMu sync.RWMutex
+	minorUnits = map[Currency]int{
+		CNY: 2, USD: 2, EUR: 2, HKD: 2, JPY: 0, KRW: 0,
+	}
+)
+
+// RegisterCurrency 注册货币及其小数位数，未注册的货币默认 2 位。可并发调用
+func RegisterCurrency(c Currency, digits int) {
+	currencyMu.Lock()
+	defer currencyMu.Unlock()
+	minorUnits[c] = digits
+}
+
+// Digits 返回货币的小数位数
+func (c Currency) Digits() int {
+	currencyMu.RLock()
+	d, ok := minorUnits[c]
+	currencyMu.RUnlock()
+	if ok {
+		return d
+	}
+	return 2
+}
+
+// Money 金额，以最小货币单位（如分）的整数保存，避免浮点误差
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New 以最小货币单位创建金额，New(1234, CNY) 表示 12.34 元
+func New(minor int64, c Currency) Money {
+	return Money{amount: minor, currency: c}
+}
+
+// Parse 解析十进制字符串，小数位超过货币精度时按 mode 舍入
+func Parse(s string, c Currency, mode RoundingMode) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return fromRat(r, c, mode)
+}
+
+// parseExact 解析金额，小数位超过货币精度时返回错误而不是静默舍入
+func parseExact(s string, c Currency) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || strings.ContainsAny(s, "/eE") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(c.Digits())))
+	if !scaled.IsInt() {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, s, c.Digits())
+	}
+	return fromRat(r, c, Down)
+}
+
+// MustParse 同 Parse，舍入方式为 HalfUp，出错时 panic，用于常量
+func MustParse(s string, c Currency) Money {
+	m, err := Parse(s, c, HalfUp)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func fromRat(r *big.Rat, c Currency, mode RoundingMode) (Money, error) {
+	n := Round(r, c.Digits(), mode)
+	if !n.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: n.Int64(), currency: c}, nil
+}
+
+// Minor 返回最小货币单位的整数金额
+func (m Money) Minor() int64 {
+	return m.amount
+}
+
+// Currency 返回货币
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// IsZero 是否为 0
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// Sign 返回 -1、0、1
+func (m Money) Sign() int {
+	switch {
+	case m.amount < 0:
+		return -1
+	case m.amount > 0:
+		return 1
+	}
+	return 0
+}
+
+func (m Money) check(o Money) error {
+	if m.currency != o.currency {
+		return fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+	return nil
+}
+
+// Add 相加，货币不同返回 ErrCurrencyMismatch，溢出返回 ErrOverflow
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.amount + o.amount
+	if (o.amount > 0 && sum < m.amount) || (o.amount < 0 && sum > m.amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: sum, currency: m.currency}, nil
+}
+
+// Sub 相减
+func (m Money) Sub(o Money) (Money, error) {
+	if o.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(o.Neg())
+}
+
+// Neg 取反
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Cmp 比较大小，货币不同返回 ErrCurrencyMismatch
+func (m Money) Cmp(o Money) (int, error) {
+	if err := m.check(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < o.amount:
+		return -1, nil
+	case m.amount > o.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Mul 乘以整数
+func (m Money) Mul(n int64) (Money, error) {
+	if m.amount == 0 || n == 0 {
+		return Money{currency: m.currency}, nil
+	}
+	p := m.amount * n
+	if p/n != m.amount || (m.amount == -1 && n == math.MinInt64) || (n == -1 && m.amount == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: p, currency: m.currency}, nil
+}
+
+// MulRate 乘以十进制字符串表示的比率（如费率 "0.006"、折扣 "0.85"），结果按 mode 舍入
+func (m Money) MulRate(rate string, mode RoundingMode) (Money, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: rate %q", ErrInvalidAmount, rate)
+	}
+	r.Mul(r, m.rat())
+	return fromRat(r, m.currency, mode)
+}
+
+// Allocate 按比例拆分金额，舍入产生的余数从前往后逐个分配 1 个最小单位，保证各份之和等于原金额
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, fmt.Errorf("%w: negative ratio %d", ErrInvalidAmount, r)
+		}
+		total += r
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("%w: ratios sum to zero", ErrInvalidAmount)
+	}
+
+	parts := make([]Money, len(ratios))
+	amount := big.NewInt(m.amount)
+	remainder := m.amount
+	for i, r := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(r))
+		share.Quo(share, big.NewInt(total))
+		parts[i] = Money{amount: share.Int64(), currency: m.currency}
+		remainder -= share.Int64()
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].amount += step
+		remainder -= step
+	}
+	return parts, nil
+}
+
+func (m Money) rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(m.amount), pow10(m.currency.Digits()))
+}
+
+// String 返回十进制金额，如 "12.34"、"-0.50"
+func (m Money) String() string {
+	return m.rat().FloatString(m.currency.Digits())
+}
+
+// Format 返回带货币代码的金额，如 "CNY 12.34"
+func (m Money) Format() string {
+	return string(m.currency) + " " + m.String()
+}
+
+type moneyJSON struct {
+	Amount   string   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+// MarshalJSON 输出 {"amount":"12.34","currency":"CNY"}，金额使用字符串避免前端精度丢失
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.currency})
+}
+
+// UnmarshalJSON 解析 MarshalJSON 的输出，多余小数位视为错误
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("mathutil: unmarshal money: %w", err)
+	}
+	if v.Currency == "" {
+		v.Currency = DefaultCurrency
+	}
+	r, err := parseExact(v.Amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = r
+	return nil
+}
+
+// Value 以十进制字符串写入 DECIMAL 列，货币需单独存储
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+// Scan 读取 DECIMAL 列；保留接收者原有货币，零值时使用 DefaultCurrency
+func (m *Money) Scan(src any) error {
+	c := m.currency
+	if c == "" {
+		c = DefaultCurrency
+	}
+
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		r, err := fromRat(new(big.Rat).SetInt64(v), c, Down)
+		if err != nil {
+			return err
+		}
+		*m = r
+		return nil
+	case nil:
+		*m = Money{currency: c}
+		return nil
+	default:
+		return fmt.Errorf("mathutil: cannot scan %T into Money", src)
+	}
+
+	r, err := parseExact(s, c)
+	if err != nil {
+		return err
+	}
+	*m = r
+	return nil
+}
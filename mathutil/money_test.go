@@ -0,0 +1,125 @@
+package mathutil
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestRound(t *testing.T) {
+	cases := []struct {
+		in   string
+		mode RoundingMode
+		want int64
+	}{
+		{"2.345", HalfUp, 235},
+		{"-2.345", HalfUp, -235},
+		{"2.345", HalfEven, 234},
+		{"2.355", HalfEven, 236},
+		{"2.345", HalfDown, 234},
+		{"2.341", Up, 235},
+		{"-2.349", Down, -234},
+		{"-2.341", Floor, -235},
+		{"-2.349", Ceiling, -234},
+	}
+	for _, c := range cases {
+		r, _ := new(big.Rat).SetString(c.in)
+		if got := Round(r, 2, c.mode).Int64(); got != c.want {
+			t.Errorf("Round(%s, %d) = %d, want %d", c.in, c.mode, got, c.want)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a := MustParse("0.1", CNY)
+	b := MustParse("0.2", CNY)
+	sum, err := a.Add(b)
+	if err != nil || sum.String() != "0.30" {
+		t.Fatalf("0.1 + 0.2 = %s, %v", sum, err)
+	}
+
+	if _, err := a.Add(New(1, USD)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("expected ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := New(math.MaxInt64, CNY).Add(New(1, CNY)); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+	if _, err := New(math.MaxInt64, CNY).Mul(2); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+
+	fee, err := MustParse("99.99", CNY).MulRate("0.006", HalfUp)
+	if err != nil || fee.Minor() != 60 {
+		t.Fatalf("fee = %s, %v", fee, err)
+	}
+
+	parts, err := MustParse("100", CNY).Allocate(1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts[0].Minor() != 3334 || parts[1].Minor() != 3333 || parts[2].Minor() != 3333 {
+		t.Fatalf("unexpected allocation %v", parts)
+	}
+
+	if got := MustParse("1234.5", JPY).String(); got != "1235" {
+		t.Fatalf("JPY rounding: %s", got)
+	}
+	if _, err := Parse("1e3", CNY, HalfUp); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+}
+
+func TestMoneyEncoding(t *testing.T) {
+	m := MustParse("-12.5", USD)
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"amount":"-12.50","currency":"USD"}` {
+		t.Fatalf("unexpected json %s", data)
+	}
+
+	var got Money
+	if err := json.Unmarshal(data, &got); err != nil || got != m {
+		t.Fatalf("round trip: %v, %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"1.001","currency":"CNY"}`), &got); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("expected ErrInvalidAmount, got %v", err)
+	}
+
+	v, _ := m.Value()
+	if v != "-12.50" {
+		t.Fatalf("unexpected sql value %v", v)
+	}
+	scanned := New(0, USD)
+	if err := scanned.Scan([]byte("-12.50")); err != nil || scanned != m {
+		t.Fatalf("scan: %v, %v", scanned, err)
+	}
+	var def Money
+	if err := def.Scan(int64(3)); err != nil || def.Format() != "CNY 3.00" {
+		t.Fatalf("scan int: %v, %v", def.Format(), err)
+	}
+}
+
+func TestRegisterCurrencyConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			RegisterCurrency(Currency(fmt.Sprintf("X%02d", i)), 3)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = New(100, CNY).String()
+		}()
+	}
+	wg.Wait()
+	if d := Currency("X07").Digits(); d != 3 {
+		t.Fatalf("expected 3 digits, got %d", d)
+	}
+}
@@ -0,0 +1,71 @@
+package mathutil
+
+import "math/big"
+
+// RoundingMode 舍入方式
+type RoundingMode int
+
+const (
+	// HalfUp 四舍五入（远离零）
+	HalfUp RoundingMode = iota
+	// HalfEven 银行家舍入，五后为偶数时舍去
+	HalfEven
+	// HalfDown 五舍六入
+	HalfDown
+	// Down 向零截断
+	Down
+	// Up 远离零进位
+	Up
+	// Floor 向负无穷
+	Floor
+	// Ceiling 向正无穷
+	Ceiling
+)
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// Round 将 r 舍入到 scale 位小数，返回放大 10^scale 后的整数，Round(12.345, 2, HalfUp) = 1235
+func Round(r *big.Rat, scale int, mode RoundingMode) *big.Int {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+	num, den := scaled.Num(), scaled.Denom()
+
+	// 截断的商与余数，余数符号与 num 相同
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+
+	neg := num.Sign() < 0
+	// 比较 |rem|*2 与 den 判断是否过半
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(den)
+
+	away := false
+	switch mode {
+	case HalfUp:
+		away = half >= 0
+	case HalfDown:
+		away = half > 0
+	case HalfEven:
+		away = half > 0 || (half == 0 && q.Bit(0) == 1)
+	case Down:
+	case Up:
+		away = true
+	case Floor:
+		away = neg
+	case Ceiling:
+		away = !neg
+	}
+
+	if away {
+		if neg {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
@@ -0,0 +1,14 @@
+//go:build linux || darwin
+
+package monitor
+
+import "syscall"
+
+// diskFree 返回非特权用户可用的空间
+func diskFree(path string) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return st.Bavail * uint64(st.Bsize), nil
+}
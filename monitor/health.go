@@ -0,0 +1,303 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCheckTimeout 未通过 WithCheckTimeout 指定时单个检查的超时
+const DefaultCheckTimeout = 5 * time.Second
+
+// 检查状态
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// ErrNotReady SetReady(false) 后就绪检查返回的错误
+var ErrNotReady = errors.New("monitor: not ready")
+
+// CheckFunc 健康检查，返回 nil 表示正常，应在 ctx 结束时尽快返回
+type CheckFunc func(ctx context.Context) error
+
+// CheckOption 检查的选项
+type CheckOption func(*check)
+
+// WithCheckTimeout 设置检查的超时，默认 DefaultCheckTimeout
+func WithCheckTimeout(d time.Duration) CheckOption {
+	return func(c *check) {
+		c.timeout = d
+	}
+}
+
+// WithLiveness 同时作为存活检查。默认只参与就绪检查：依赖（数据库、Redis）不可用时应摘除流量而不是重启进程，
+// 只有重启能恢复的问题（如死锁、磁盘写满）才应影响存活检查
+func WithLiveness() CheckOption {
+	return func(c *check) {
+		c.liveness = true
+	}
+}
+
+type check struct {
+	name     string
+	fn       CheckFunc
+	timeout  time.Duration
+	liveness bool
+}
+
+// CheckResult 单个检查的结果
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report 检查报告，所有检查正常时 Status 为 up
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Health 健康检查，注册命名的检查并提供 Kubernetes 的存活（/healthz）与就绪（/readyz）端点，
+// 响应为 JSON，全部检查通过时返回 200，否则返回 503。探针需要从 Pod IP 访问，应挂载到业务端口或单独的探针端口，
+// 不要为此让 Monitor 监听 Pod IP，否则 pprof 也会一并暴露：
+//
+//	health := monitor.NewHealth()
+//	health.Register("db", monitor.PingCheck(db), monitor.WithCheckTimeout(2*time.Second))
+//	health.Register("redis", monitor.RedisCheck("redis:6379", ""))
+//	health.Register("disk", monitor.DiskCheck("/data", 1<<30), monitor.WithLiveness())
+//	mux.Handle("GET /healthz", health.LiveHandler())
+//	mux.Handle("GET /readyz", health.ReadyHandler())
+//	h, err := monitor.Monitor(ctx, "127.0.0.1:6060", log) // pprof 只监听本机
+type Health struct {
+	mu       sync.RWMutex
+	checks   map[string]*check
+	notReady atomic.Bool
+}
+
+// NewHealth 创建健康检查，初始为就绪状态
+func NewHealth() *Health {
+	return &Health{checks: make(map[string]*check)}
+}
+
+// Register 注册检查，同名的检查会被替换
+func (h *Health) Register(name string, fn CheckFunc, opts ...CheckOption) {
+	c := &check{name: name, fn: fn, timeout: DefaultCheckTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = c
+}
+
+// Unregister 移除检查
+func (h *Health) Unregister(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.checks, name)
+}
+
+// Names 返回已注册的检查名称
+func (h *Health) Names() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetReady 设置就绪状态，为 false 时就绪检查失败，如启动预热完成前、优雅停机开始后摘除流量
+func (h *Health) SetReady(ready bool) {
+	h.notReady.Store(!ready)
+}
+
+// Live 执行存活检查（WithLiveness 的检查）
+func (h *Health) Live(ctx context.Context) Report {
+	return h.run(ctx, true)
+}
+
+// Ready 执行就绪检查（全部检查）
+func (h *Health) Ready(ctx context.Context) Report {
+	report := h.run(ctx, false)
+	if h.notReady.Load() {
+		report.Status = StatusDown
+		report.Checks["ready"] = CheckResult{Status: StatusDown, Error: ErrNotReady.Error(), Duration: "0s"}
+	}
+	return report
+}
+
+// run 并发执行检查，不响应 ctx 的检查在超时后不再等待
+func (h *Health) run(ctx context.Context, liveness bool) Report {
+	h.mu.RLock()
+	checks := make([]*check, 0, len(h.checks))
+	for _, c := range h.checks {
+		if !liveness || c.liveness {
+			checks = append(checks, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(checks))}
+	for i, c := range checks {
+		if results[i].Status != StatusUp {
+			report.Status = StatusDown
+		}
+		report.Checks[c.name] = results[i]
+	}
+	return report
+}
+
+func (c *check) run(ctx context.Context) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- c.fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	res := CheckResult{Status: StatusUp, Duration: time.Since(start).Round(time.Microsecond).String()}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// LiveHandler 存活检查端点
+func (h *Health) LiveHandler() http.Handler {
+	return h.handler(h.Live)
+}
+
+// ReadyHandler 就绪检查端点
+func (h *Health) ReadyHandler() http.Handler {
+	return h.handler(h.Ready)
+}
+
+func (h *Health) handler(run func(context.Context) Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := run(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if report.Status != StatusUp {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+// WithHealth 在 Monitor 的服务上提供 /healthz 与 /readyz，适用于 Monitor 只监听 127.0.0.1 的本机检查（如 sidecar）；
+// Kubernetes 探针应将 LiveHandler、ReadyHandler 挂载到业务端口或探针端口
+func WithHealth(h *Health) Option {
+	return func(o *options) {
+		o.handlers = append(o.handlers,
+			route{pattern: "GET /healthz", handler: h.LiveHandler()},
+			route{pattern: "GET /readyz", handler: h.ReadyHandler()},
+		)
+	}
+}
+
+// Pinger *sql.DB 等支持 PingContext 的连接
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PingCheck 以 PingContext 检查连接，如 *sql.DB
+func PingCheck(p Pinger) CheckFunc {
+	return p.PingContext
+}
+
+// RedisCheck 连接 addr 发送 PING（password 非空时先 AUTH），回复 PONG 为正常。
+// 不依赖 Redis 客户端，已有客户端时可用 Register 注册其 Ping
+func RedisCheck(addr, password string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("redis: %w", err)
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			_ = conn.SetDeadline(deadline)
+		}
+
+		r := bufio.NewReader(conn)
+		if password != "" {
+			if err := redisCommand(conn, r, "+OK", "AUTH", password); err != nil {
+				return err
+			}
+		}
+		return redisCommand(conn, r, "+PONG", "PING")
+	}
+}
+
+// redisCommand 以 RESP 发送命令并检查单行回复
+func redisCommand(conn net.Conn, r *bufio.Reader, want string, args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("redis: %w", err)
+	}
+	if line = strings.TrimRight(line, "\r\n"); line != want {
+		return fmt.Errorf("redis: %s: unexpected reply %q", args[0], line)
+	}
+	return nil
+}
+
+// DiskCheck 检查 path 所在文件系统的可用空间不少于 minFree 字节
+func DiskCheck(path string, minFree uint64) CheckFunc {
+	return func(ctx context.Context) error {
+		free, err := diskFree(path)
+		if err != nil {
+			return fmt.Errorf("disk: %w", err)
+		}
+		if free < minFree {
+			return fmt.Errorf("disk: %s has %d bytes free, want at least %d", path, free, minFree)
+		}
+		return nil
+	}
+}
@@ -2,12 +2,14 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
-	_ "net/http/pprof"
 	"os"
 	"os/signal"
 	"runtime"
+	"sync"
 	"syscall"
 	"time"
 
@@ -15,9 +17,19 @@ import (
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/timeutil"
+	"github.com/abs2free/go-kit/version"
 )
 
-func MonitorByPromethues(addr string, log *zap.SugaredLogger) {
+// Clock 监测协程使用的时钟，测试时可替换为 timeutil.Fake
+var Clock = timeutil.Real
+
+// Registry 进程内共享的指标注册表，其他组件（tracing、notify 等）的指标也注册到这里，
+// 由 MonitorByPromethues 统一暴露
+var Registry = newRegistry()
+
+func newRegistry() *prometheus.Registry {
 	// Create non-global registry.
 	reg := prometheus.NewRegistry()
 
@@ -26,20 +38,96 @@ func MonitorByPromethues(addr string, log *zap.SugaredLogger) {
 		collectors.NewGoCollector(),
 		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
 	)
+	return reg
+}
+
+// MonitorByPromethues 在 addr 上提供 /metrics 与 /version，使用独立的 mux，不注册到 http.DefaultServeMux
+func MonitorByPromethues(addr string, log *zap.SugaredLogger) {
+	reg := Registry
 
 	// Expose /metrics HTTP endpoint using the created custom registry.
-	http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
-	log.Fatal(http.ListenAndServe(addr, nil))
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	mux.Handle("GET /version", version.Handler())
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+// Handle Monitor 启动的监测协程与 pprof 服务，传入的 ctx 结束或调用 Close 时停止
+type Handle struct {
+	ln     net.Listener
+	srv    *http.Server
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	err    error
+}
+
+// Option Monitor 的选项
+type Option func(*options)
+
+type options struct {
+	pprof    []string
+	handlers []route
+}
+
+type route struct {
+	pattern string
+	handler http.Handler
 }
 
-func Monitor(cancel context.CancelFunc, addr string, log *zap.SugaredLogger) {
+// WithPprof 只暴露 names 所列的 pprof 端点（见 PprofHandler），默认全部暴露
+func WithPprof(names ...string) Option {
+	return func(o *options) {
+		o.pprof = names
+	}
+}
+
+// WithHandler 在 Monitor 的服务上注册其他处理器，pattern 与 http.ServeMux 相同
+func WithHandler(pattern string, h http.Handler) Option {
+	return func(o *options) {
+		o.handlers = append(o.handlers, route{pattern: pattern, handler: h})
+	}
+}
+
+// Monitor 启动监测协程（每 10 秒输出协程数与内存）与 pprof 服务，监听失败时返回错误。
+// 服务使用独立的 mux，只包含 pprof、/version 与 WithHandler 注册的处理器，不会经由 http.DefaultServeMux
+// 暴露到其他服务上。ctx 结束或调用 Close 时停止计时器并关闭服务，不会遗留协程：
+//
+//	h, err := monitor.Monitor(ctx, "127.0.0.1:6060", log, monitor.WithPprof("heap", "goroutine", monitor.PprofProfile))
+//	if err != nil { ... }
+//	defer h.Close()
+func Monitor(ctx context.Context, addr string, log *zap.SugaredLogger, opts ...Option) (*Handle, error) {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/debug/pprof/", PprofHandler(o.pprof...))
+	mux.Handle("GET /version", version.Handler())
+	for _, r := range o.handlers {
+		mux.Handle(r.pattern, r.handler)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("monitor: listen %s: %w", addr, err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	h := &Handle{ln: ln, srv: &http.Server{Handler: mux}, cancel: cancel}
+	h.wg.Add(3)
+
 	// 监测
 	go func() {
-		ticker := time.NewTicker(time.Second * 10)
+		defer h.wg.Done()
+		ticker := Clock.NewTicker(time.Second * 10)
+		defer ticker.Stop()
 		var mem runtime.MemStats
 
 		for {
-			<-ticker.C
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C():
+			}
 			log.Infof("goroutine 数量: %d \n", runtime.NumGoroutine())
 			runtime.ReadMemStats(&mem)
 			log.Infof("Alloc = %v kB\n", mem.Alloc/1024/8)
@@ -48,16 +136,39 @@ func Monitor(cancel context.CancelFunc, addr string, log *zap.SugaredLogger) {
 
 	// 性能分析
 	go func() {
-		log.Infoln("pprof start:", addr)
-		err := http.ListenAndServe(addr, nil)
-		if err != nil {
+		defer h.wg.Done()
+		log.Infoln("pprof start:", ln.Addr())
+		if err := h.srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Infof("listen has a err:%v", err)
 			cancel()
 		}
-		log.Infoln("visit :%s ", "http://127.0.0.1:6060/debug/pprof/")
 	}()
+
+	go func() {
+		defer h.wg.Done()
+		<-ctx.Done()
+		shutdownCtx, done := context.WithTimeout(context.Background(), 5*time.Second)
+		defer done()
+		h.err = h.srv.Shutdown(shutdownCtx)
+	}()
+	return h, nil
+}
+
+// Addr 返回 pprof 服务实际监听的地址，addr 端口为 0 时可由此得到分配的端口
+func (h *Handle) Addr() net.Addr {
+	return h.ln.Addr()
+}
+
+// Close 停止监测协程并关闭 pprof 服务，等待协程退出，可重复调用
+func (h *Handle) Close() error {
+	h.cancel()
+	h.wg.Wait()
+	return h.err
 }
 
+// SignalCheck 收到 SIGINT / SIGTERM 时调用 cancel
+//
+// Deprecated: 使用 NewShutdown，可注册按顺序执行的停机回调并限制总时长
 func SignalCheck(cancel context.CancelFunc) {
 	// 信号量监控
 	sg := make(chan os.Signal, 1)
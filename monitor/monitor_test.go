@@ -0,0 +1,294 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/testkit"
+)
+
+func TestMonitorStop(t *testing.T) {
+	clock := testkit.Clock(t)
+	old := Clock
+	Clock = clock
+	defer func() { Clock = old }()
+	logs := testkit.Logger(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h, err := Monitor(ctx, "127.0.0.1:0", logs.Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + h.Addr().String() + "/debug/pprof/"
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+
+	clock.WaitForWaiters(1)
+	clock.Advance(10 * time.Second)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(logs.All()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	logs.AssertLogged(zap.InfoLevel, "goroutine 数量")
+
+	// ctx 结束后计时器停止、服务关闭
+	cancel()
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := clock.Waiters(); n != 0 {
+		t.Fatalf("ticker not stopped, %d waiters", n)
+	}
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("expected pprof server to be closed")
+	}
+	if err := h.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Monitor(context.Background(), "127.0.0.1:-1", logs.Sugar()); err == nil {
+		t.Fatal("expected listen error")
+	}
+}
+
+func TestPprofHandler(t *testing.T) {
+	get := func(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	all := PprofHandler()
+	if rec := get(all, "GET", "/debug/pprof/", ""); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `href="goroutine?debug=1"`) {
+		t.Fatalf("unexpected index %d %s", rec.Code, rec.Body)
+	}
+	if rec := get(all, "GET", "/debug/pprof/heap?debug=1", ""); !strings.Contains(rec.Body.String(), "heap profile") {
+		t.Fatalf("unexpected heap profile %s", rec.Body)
+	}
+	if rec := get(all, "GET", "/debug/pprof/cmdline", ""); !strings.HasPrefix(rec.Body.String(), os.Args[0]) {
+		t.Fatalf("unexpected cmdline %q", rec.Body)
+	}
+	pc := reflect.ValueOf(TestPprofHandler).Pointer()
+	if rec := get(all, "POST", "/debug/pprof/symbol", fmt.Sprintf("%#x", pc)); !strings.Contains(rec.Body.String(), "monitor.TestPprofHandler") {
+		t.Fatalf("unexpected symbol %s", rec.Body)
+	}
+	if rec := get(all, "GET", "/debug/pprof/profile?seconds=0.05", ""); rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+		t.Fatalf("unexpected cpu profile %d", rec.Code)
+	}
+
+	only := PprofHandler("heap")
+	if rec := get(only, "GET", "/debug/pprof/heap", ""); rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", rec.Code)
+	}
+	for _, path := range []string{"/debug/pprof/goroutine", "/debug/pprof/cmdline", "/debug/pprof/profile"} {
+		if rec := get(only, "GET", path, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("%s should not be exposed, got %d", path, rec.Code)
+		}
+	}
+
+	// 不注册到默认 mux
+	for _, path := range []string{"/debug/pprof/heap", "/version"} {
+		if _, pattern := http.DefaultServeMux.Handler(httptest.NewRequest("GET", path, nil)); pattern != "" {
+			t.Fatalf("%s registered on DefaultServeMux: %q", path, pattern)
+		}
+	}
+
+	h, err := Monitor(context.Background(), "127.0.0.1:0", zap.NewNop().Sugar(), WithPprof("heap"),
+		WithHandler("GET /ping", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("pong")) })))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	base := "http://" + h.Addr().String()
+	for path, want := range map[string]int{"/ping": 200, "/version": 200, "/debug/pprof/heap": 200, "/debug/pprof/goroutine": 404} {
+		resp, err := http.Get(base + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s: got %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	// 只应答 PING 的 Redis
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 64)
+				n, _ := conn.Read(buf)
+				if strings.Contains(string(buf[:n]), "PING") {
+					_, _ = conn.Write([]byte("+PONG\r\n"))
+				}
+			}()
+		}
+	}()
+
+	health := NewHealth()
+	health.Register("redis", RedisCheck(ln.Addr().String(), ""))
+	health.Register("disk", DiskCheck(t.TempDir(), 1), WithLiveness())
+	health.Register("ok", func(ctx context.Context) error { return nil }, WithLiveness())
+
+	serve := func(h http.Handler) (int, Report) {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+		var report Report
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, report
+	}
+
+	code, report := serve(health.ReadyHandler())
+	if code != http.StatusOK || report.Status != StatusUp || len(report.Checks) != 3 {
+		t.Fatalf("unexpected ready report %d %+v", code, report)
+	}
+
+	// 不响应 ctx 的检查按超时计为失败，panic 不影响其他检查
+	block := make(chan struct{})
+	defer close(block)
+	health.Register("slow", func(ctx context.Context) error { <-block; return nil }, WithCheckTimeout(20*time.Millisecond))
+	health.Register("panic", func(ctx context.Context) error { panic("boom") })
+	health.Register("full", DiskCheck(t.TempDir(), math.MaxUint64))
+	code, report = serve(health.ReadyHandler())
+	if code != http.StatusServiceUnavailable || report.Status != StatusDown {
+		t.Fatalf("unexpected ready report %d %+v", code, report)
+	}
+	if r := report.Checks["slow"]; r.Status != StatusDown || !strings.Contains(r.Error, "deadline") {
+		t.Fatalf("unexpected slow result %+v", r)
+	}
+	if r := report.Checks["panic"]; r.Error != "panic: boom" {
+		t.Fatalf("unexpected panic result %+v", r)
+	}
+	if r := report.Checks["redis"]; r.Status != StatusUp || report.Checks["full"].Status != StatusDown {
+		t.Fatalf("unexpected results %+v", report.Checks)
+	}
+
+	// 存活检查只包含 WithLiveness 的检查
+	code, report = serve(health.LiveHandler())
+	if code != http.StatusOK || len(report.Checks) != 2 {
+		t.Fatalf("unexpected live report %d %+v", code, report)
+	}
+
+	for _, name := range []string{"slow", "panic", "full"} {
+		health.Unregister(name)
+	}
+	health.SetReady(false)
+	if code, report = serve(health.ReadyHandler()); code != http.StatusServiceUnavailable || report.Checks["ready"].Error != ErrNotReady.Error() {
+		t.Fatalf("unexpected report after SetReady(false) %d %+v", code, report)
+	}
+	health.SetReady(true)
+
+	if err := RedisCheck(ln.Addr().String(), "secret")(context.Background()); err == nil {
+		t.Fatal("expected AUTH to fail")
+	}
+
+	h, err := Monitor(context.Background(), "127.0.0.1:0", zap.NewNop().Sugar(), WithHealth(health))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	for _, path := range []string{"/healthz", "/readyz"} {
+		resp, err := http.Get("http://" + h.Addr().String() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" {
+			t.Fatalf("%s: unexpected response %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	health := NewHealth()
+	sd := NewShutdown(WithShutdownTimeout(100*time.Millisecond), WithShutdownHealth(health))
+	var mu sync.Mutex
+	var order []string
+	add := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+	block := make(chan struct{})
+	defer close(block)
+	sd.Register("http", func(ctx context.Context) error {
+		if health.Ready(ctx).Status != StatusDown {
+			t.Error("expected not ready during shutdown")
+		}
+		add("http")
+		return nil
+	})
+	sd.Register("db", func(ctx context.Context) error {
+		add("db")
+		return errors.New("close failed")
+	})
+	sd.Register("queue", func(ctx context.Context) error {
+		add("queue")
+		<-block // 忽略 ctx，超时后不再等待
+		return nil
+	})
+	sd.Register("logger", func(ctx context.Context) error {
+		add("logger")
+		return nil
+	})
+	sd.Notify(syscall.SIGUSR1)
+
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-sd.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown not started")
+	}
+	err := sd.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.Join(order, ","); got != "http,db,queue" {
+		t.Fatalf("unexpected order %s", got)
+	}
+	if err == nil || !strings.Contains(err.Error(), "db: close failed") ||
+		!strings.Contains(err.Error(), "queue: context deadline exceeded") || !strings.Contains(err.Error(), "logger: skipped") {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("expected deadline error")
+	}
+	if again := sd.Run(); again != err {
+		t.Fatalf("Run should return the first result, got %v", again)
+	}
+}
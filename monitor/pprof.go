@@ -0,0 +1,193 @@
+package monitor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pprof 端点名称，用于 PprofHandler、WithPprof 选择暴露的端点；
+// 此外还可使用 runtime/pprof 的 profile 名，如 heap、goroutine、allocs、block、mutex、threadcreate
+const (
+	PprofCmdline = "cmdline"
+	PprofProfile = "profile"
+	PprofSymbol  = "symbol"
+	PprofTrace   = "trace"
+)
+
+// pprofAll 未指定时暴露的端点，与 net/http/pprof 相同
+var pprofAll = []string{"allocs", "block", PprofCmdline, "goroutine", "heap", "mutex", PprofProfile, PprofSymbol, "threadcreate", PprofTrace}
+
+// PprofHandler 返回只包含 names 所列端点的 pprof 处理器，路径与 net/http/pprof 相同（/debug/pprof/...），
+// names 为空时暴露全部端点。不导入 net/http/pprof，因此不会注册到 http.DefaultServeMux，
+// 可挂载到内部端口的服务上：
+//
+//	mux.Handle("/debug/pprof/", monitor.PprofHandler("heap", "goroutine", monitor.PprofProfile))
+func PprofHandler(names ...string) http.Handler {
+	if len(names) == 0 {
+		names = pprofAll
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/{$}", pprofIndex(names))
+	for _, name := range names {
+		path := "/debug/pprof/" + name
+		switch name {
+		case PprofCmdline:
+			mux.HandleFunc("GET "+path, pprofCmdline)
+		case PprofProfile:
+			mux.HandleFunc("GET "+path, pprofCPU)
+		case PprofSymbol:
+			mux.HandleFunc(path, pprofSymbol)
+		case PprofTrace:
+			mux.HandleFunc("GET "+path, pprofTrace)
+		default:
+			mux.HandleFunc("GET "+path, pprofLookup(name))
+		}
+	}
+	return mux
+}
+
+// pprofIndex 列出已暴露的端点
+func pprofIndex(names []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		buf.WriteString("<html><head><title>/debug/pprof/</title></head><body>\n/debug/pprof/<br><br>\n")
+		for _, name := range names {
+			link := html.EscapeString(name)
+			switch name {
+			case PprofCmdline, PprofProfile, PprofSymbol, PprofTrace:
+				fmt.Fprintf(&buf, "<a href=\"%s\">%s</a><br>\n", link, link)
+			default:
+				count := 0
+				if p := pprof.Lookup(name); p != nil {
+					count = p.Count()
+				}
+				fmt.Fprintf(&buf, "%d <a href=\"%s?debug=1\">%s</a><br>\n", count, link, link)
+			}
+		}
+		buf.WriteString("</body></html>\n")
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(buf.Bytes())
+	}
+}
+
+func pprofCmdline(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprint(w, strings.Join(os.Args, "\x00"))
+}
+
+// pprofCPU 采集 seconds 秒（默认 30）的 CPU profile
+func pprofCPU(w http.ResponseWriter, r *http.Request) {
+	d := pprofSeconds(r, 30)
+	setAttachment(w, "profile")
+	if err := pprof.StartCPUProfile(w); err != nil {
+		pprofError(w, http.StatusInternalServerError, "could not enable CPU profiling: "+err.Error())
+		return
+	}
+	sleepRequest(r, d)
+	pprof.StopCPUProfile()
+}
+
+// pprofTrace 采集 seconds 秒（默认 1）的执行追踪
+func pprofTrace(w http.ResponseWriter, r *http.Request) {
+	d := pprofSeconds(r, 1)
+	setAttachment(w, "trace")
+	if err := trace.Start(w); err != nil {
+		pprofError(w, http.StatusInternalServerError, "could not enable tracing: "+err.Error())
+		return
+	}
+	sleepRequest(r, d)
+	trace.Stop()
+}
+
+// pprofSymbol 将以 + 分隔的程序计数器解析为函数名，供 go tool pprof 符号化
+func pprofSymbol(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	var buf bytes.Buffer
+	buf.WriteString("num_symbols: 1\n")
+	var b *bufio.Reader
+	if r.Method == http.MethodPost {
+		b = bufio.NewReader(r.Body)
+	} else {
+		b = bufio.NewReader(strings.NewReader(r.URL.RawQuery))
+	}
+	for {
+		word, err := b.ReadSlice('+')
+		if err == nil {
+			word = word[:len(word)-1]
+		}
+		if pc, _ := strconv.ParseUint(string(word), 0, 64); pc != 0 {
+			if f := runtime.FuncForPC(uintptr(pc)); f != nil {
+				fmt.Fprintf(&buf, "%#x %s\n", pc, f.Name())
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	_, _ = w.Write(buf.Bytes())
+}
+
+// pprofLookup 输出 runtime/pprof 中名为 name 的 profile，debug 非 0 时为文本格式，heap 带 gc 参数时先执行 GC
+func pprofLookup(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p := pprof.Lookup(name)
+		if p == nil {
+			pprofError(w, http.StatusNotFound, "unknown profile")
+			return
+		}
+		if name == "heap" && r.FormValue("gc") != "" {
+			runtime.GC()
+		}
+		debug, _ := strconv.Atoi(r.FormValue("debug"))
+		if debug != 0 {
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			setAttachment(w, name)
+		}
+		_ = p.WriteTo(w, debug)
+	}
+}
+
+func pprofSeconds(r *http.Request, def float64) time.Duration {
+	sec, err := strconv.ParseFloat(r.FormValue("seconds"), 64)
+	if err != nil || sec <= 0 {
+		sec = def
+	}
+	return time.Duration(sec * float64(time.Second))
+}
+
+// sleepRequest 等待 d，客户端断开时提前返回
+func sleepRequest(r *http.Request, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-r.Context().Done():
+	}
+}
+
+func setAttachment(w http.ResponseWriter, name string) {
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+}
+
+// pprofError 输出错误，去掉已设置的下载头
+func pprofError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Del("Content-Disposition")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,194 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultShutdownTimeout 未通过 WithShutdownTimeout 指定时停机的总时限
+const DefaultShutdownTimeout = 30 * time.Second
+
+// ShutdownOption Shutdown 的选项
+type ShutdownOption func(*Shutdown)
+
+// WithShutdownTimeout 设置全部停机回调的总时限，默认 DefaultShutdownTimeout。
+// Kubernetes 中应小于 terminationGracePeriodSeconds
+func WithShutdownTimeout(d time.Duration) ShutdownOption {
+	return func(s *Shutdown) {
+		s.timeout = d
+	}
+}
+
+// WithShutdownLogger 设置日志，默认不输出
+func WithShutdownLogger(log *zap.SugaredLogger) ShutdownOption {
+	return func(s *Shutdown) {
+		s.log = log
+	}
+}
+
+// WithShutdownHealth 停机开始时先将 h 置为未就绪，负载均衡不再分配新请求
+func WithShutdownHealth(h *Health) ShutdownOption {
+	return func(s *Shutdown) {
+		s.health = h
+	}
+}
+
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Shutdown 优雅停机，组件按顺序注册停机回调，收到 SIGINT / SIGTERM 或调用 Run 时依次执行，
+// 所有回调共用一个总时限；停机期间再次收到信号时立即退出：
+//
+//	sd := monitor.NewShutdown(monitor.WithShutdownTimeout(20*time.Second), monitor.WithShutdownLogger(log))
+//	sd.Register("http", srv.Shutdown)
+//	sd.Register("db", func(ctx context.Context) error { return db.Close() })
+//	sd.Register("logger", func(ctx context.Context) error { return log.Sync() })
+//	sd.Notify()
+//	<-sd.Context().Done() // 停机开始，停止接收新任务
+//	err := sd.Wait()
+type Shutdown struct {
+	timeout time.Duration
+	log     *zap.SugaredLogger
+	health  *Health
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu    sync.Mutex
+	hooks []shutdownHook
+
+	once sync.Once
+	done chan struct{}
+	err  error
+}
+
+// NewShutdown 创建停机管理
+func NewShutdown(opts ...ShutdownOption) *Shutdown {
+	s := &Shutdown{timeout: DefaultShutdownTimeout, log: zap.NewNop().Sugar(), done: make(chan struct{})}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// Register 注册停机回调，按注册顺序执行。ctx 在总时限到期时结束，回调应尽快返回
+func (s *Shutdown) Register(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, shutdownHook{name: name, fn: fn})
+}
+
+// Notify 监听 sigs（默认 SIGINT、SIGTERM），收到信号时执行 Run；停机期间再次收到信号时以状态码 1 退出
+func (s *Shutdown) Notify(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+	go func() {
+		select {
+		case sig := <-ch:
+			s.log.Infof("%s received, shutting down", sig)
+		case <-s.ctx.Done():
+			signal.Stop(ch)
+			return
+		}
+		go func() {
+			select {
+			case sig := <-ch:
+				s.log.Errorf("%s received again, exiting", sig)
+				os.Exit(1)
+			case <-s.done:
+				signal.Stop(ch)
+			}
+		}()
+		_ = s.Run()
+	}()
+}
+
+// Context 返回停机开始时取消的 ctx，可作为服务主循环的 ctx
+func (s *Shutdown) Context() context.Context {
+	return s.ctx
+}
+
+// Done 返回全部回调执行完毕（或超时）后关闭的 channel
+func (s *Shutdown) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait 等待停机完成，返回回调的错误
+func (s *Shutdown) Wait() error {
+	<-s.done
+	return s.err
+}
+
+// Run 开始停机：取消 Context，依次执行回调，返回各回调的错误。总时限到期时不再等待当前回调，
+// 剩余回调不再执行。只执行一次，重复调用等待第一次完成并返回相同的错误
+func (s *Shutdown) Run() error {
+	s.once.Do(func() {
+		defer close(s.done)
+		s.cancel()
+		if s.health != nil {
+			s.health.SetReady(false)
+		}
+
+		s.mu.Lock()
+		hooks := append([]shutdownHook(nil), s.hooks...)
+		s.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+		var errs []error
+		for i, h := range hooks {
+			start := time.Now()
+			err := runHook(ctx, h)
+			if err == nil {
+				s.log.Infof("shutdown: %s done in %s", h.name, time.Since(start).Round(time.Millisecond))
+				continue
+			}
+			s.log.Errorf("shutdown: %s: %v", h.name, err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			if ctx.Err() != nil {
+				for _, skipped := range hooks[i+1:] {
+					errs = append(errs, fmt.Errorf("%s: skipped: %w", skipped.name, ctx.Err()))
+				}
+				break
+			}
+		}
+		if len(errs) > 0 {
+			s.err = fmt.Errorf("monitor: shutdown: %w", errors.Join(errs...))
+		}
+	})
+	<-s.done
+	return s.err
+}
+
+// runHook 执行回调，ctx 结束时不再等待
+func runHook(ctx context.Context, h shutdownHook) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- h.fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,35 @@
+package netserver
+
+import (
+	"net"
+	"time"
+)
+
+// Conn 带读写超时的连接，每次 Read/Write 前刷新 deadline
+type Conn struct {
+	net.Conn
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+}
+
+func newConn(c net.Conn, opts *options) *Conn {
+	return &Conn{Conn: c, readTimeout: opts.readTimeout, writeTimeout: opts.writeTimeout}
+}
+
+func (c *Conn) Read(p []byte) (int, error) {
+	if c.readTimeout > 0 {
+		if err := c.Conn.SetReadDeadline(time.Now().Add(c.readTimeout)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Read(p)
+}
+
+func (c *Conn) Write(p []byte) (int, error) {
+	if c.writeTimeout > 0 {
+		if err := c.Conn.SetWriteDeadline(time.Now().Add(c.writeTimeout)); err != nil {
+			return 0, err
+		}
+	}
+	return c.Conn.Write(p)
+}
@@ -0,0 +1,273 @@
+package netserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrServerClosed = errors.New("netserver: server closed")
+
+// Handler 处理单个连接，ServeConn 返回后连接被关闭。
+// ctx 在 Shutdown 时取消，处理完当前请求后应尽快返回
+type Handler interface {
+	ServeConn(ctx context.Context, conn *Conn)
+}
+
+// HandlerFunc 函数适配器
+type HandlerFunc func(ctx context.Context, conn *Conn)
+
+func (f HandlerFunc) ServeConn(ctx context.Context, conn *Conn) {
+	f(ctx, conn)
+}
+
+type options struct {
+	maxConns     int
+	readTimeout  time.Duration
+	writeTimeout time.Duration
+	onConnect    func(*Conn) error
+	onClose      func(*Conn)
+	log          *zap.SugaredLogger
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithMaxConns 限制并发连接数，超出时新连接被立即关闭；默认不限制
+func WithMaxConns(n int) Option {
+	return func(o *options) {
+		o.maxConns = n
+	}
+}
+
+// WithTimeouts 设置每次读、写操作的超时，0 表示不限制
+func WithTimeouts(read, write time.Duration) Option {
+	return func(o *options) {
+		o.readTimeout = read
+		o.writeTimeout = write
+	}
+}
+
+// WithOnConnect 连接建立后、交给 Handler 前回调，返回错误时拒绝连接（如 IP 黑名单）
+func WithOnConnect(fn func(*Conn) error) Option {
+	return func(o *options) {
+		o.onConnect = fn
+	}
+}
+
+// WithOnClose 连接关闭后回调
+func WithOnClose(fn func(*Conn)) Option {
+	return func(o *options) {
+		o.onClose = fn
+	}
+}
+
+// WithLogger 设置日志，默认不输出
+func WithLogger(log *zap.SugaredLogger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+// Server 通用 TCP 服务，用于不适合 HTTP 的自定义协议
+//
+//	srv := netserver.New(":9000", netserver.HandlerFunc(func(ctx context.Context, c *netserver.Conn) {
+//		scanner := bufio.NewScanner(c)
+//		for scanner.Scan() { ... }
+//	}), netserver.WithMaxConns(1000), netserver.WithTimeouts(time.Minute, 10*time.Second))
+//	go srv.ListenAndServe()
+//	...
+//	srv.Shutdown(ctx)
+type Server struct {
+	addr    string
+	handler Handler
+	opts    *options
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New 创建服务
+func New(addr string, handler Handler, opts ...Option) *Server {
+	o := &options{log: zap.NewNop().Sugar()}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Server{
+		addr:      addr,
+		handler:   handler,
+		opts:      o,
+		ctx:       ctx,
+		cancel:    cancel,
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[*Conn]struct{}),
+	}
+}
+
+// ListenAndServe 监听 TCP 地址并处理连接，Shutdown 后返回 ErrServerClosed
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("netserver: listen %s: %w", s.addr, err)
+	}
+	return s.Serve(ln)
+}
+
+// Serve 在给定 listener 上处理连接，可用于 TLS 或 unix socket
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		_ = ln.Close()
+		return ErrServerClosed
+	}
+	s.listeners[ln] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, ln)
+		s.mu.Unlock()
+		_ = ln.Close()
+	}()
+
+	s.opts.log.Infof("netserver: listening on %s", ln.Addr())
+
+	var backoff time.Duration
+	for {
+		raw, err := ln.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return fmt.Errorf("netserver: accept: %w", err)
+			}
+			// 与 net/http 相同的退避策略，文件句柄耗尽（EMFILE / ENFILE）、连接被对端中止（ECONNABORTED）
+			// 等错误均可恢复，只有 listener 被关闭时才返回
+			if backoff == 0 {
+				backoff = 5 * time.Millisecond
+			} else if backoff *= 2; backoff > time.Second {
+				backoff = time.Second
+			}
+			s.opts.log.Warnf("netserver: accept error: %v; retrying in %v", err, backoff)
+			select {
+			case <-time.After(backoff):
+			case <-s.ctx.Done():
+				return ErrServerClosed
+			}
+			continue
+		}
+		backoff = 0
+
+		c := newConn(raw, s.opts)
+		if !s.track(c) {
+			s.opts.log.Warnf("netserver: connection limit %d reached, rejecting %s", s.opts.maxConns, raw.RemoteAddr())
+			_ = raw.Close()
+			continue
+		}
+		go s.serveConn(c)
+	}
+}
+
+// track 登记连接，超过连接数限制或服务关闭时返回 false
+func (s *Server) track(c *Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || (s.opts.maxConns > 0 && len(s.conns) >= s.opts.maxConns) {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) serveConn(c *Conn) {
+	log := s.opts.log.With("remote", c.RemoteAddr().String())
+	start := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorw("netserver: handler panic", "panic", r)
+		}
+		_ = c.Close()
+
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+
+		if s.opts.onClose != nil {
+			s.opts.onClose(c)
+		}
+		log.Debugw("netserver: connection closed", "duration", time.Since(start))
+		s.wg.Done()
+	}()
+
+	if s.opts.onConnect != nil {
+		if err := s.opts.onConnect(c); err != nil {
+			log.Infow("netserver: connection rejected", "error", err)
+			return
+		}
+	}
+	log.Debug("netserver: connection accepted")
+	s.handler.ServeConn(s.ctx, c)
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// ConnCount 返回当前连接数
+func (s *Server) ConnCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.conns)
+}
+
+// Shutdown 停止监听并取消 Handler 的 ctx，等待连接处理完毕；
+// ctx 到期时强制关闭剩余连接并返回 ctx 错误
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	for ln := range s.listeners {
+		_ = ln.Close()
+	}
+	s.mu.Unlock()
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		n := len(s.conns)
+		for c := range s.conns {
+			_ = c.Close()
+		}
+		s.mu.Unlock()
+		s.opts.log.Warnf("netserver: shutdown timeout, %d connections closed forcibly", n)
+		<-done
+		return ctx.Err()
+	}
+}
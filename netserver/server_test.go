@@ -0,0 +1,161 @@
+package netserver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, h Handler, opts ...Option) (*Server, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(ln.Addr().String(), h, opts...)
+	go func() { _ = srv.Serve(ln) }()
+	return srv, ln.Addr().String()
+}
+
+// echo 逐行回显，收到关闭信号后结束
+var echo = HandlerFunc(func(ctx context.Context, c *Conn) {
+	r := bufio.NewReader(c)
+	for ctx.Err() == nil {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		_, _ = io.WriteString(c, line)
+	}
+})
+
+func TestEchoAndMaxConns(t *testing.T) {
+	srv, addr := startServer(t, echo, WithMaxConns(1))
+	defer srv.Shutdown(context.Background())
+
+	c1, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1.Close()
+	_, _ = io.WriteString(c1, "hello\n")
+	line, err := bufio.NewReader(c1).ReadString('\n')
+	if err != nil || line != "hello\n" {
+		t.Fatalf("unexpected echo %q: %v", line, err)
+	}
+
+	c2, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+	_ = c2.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := c2.Read(make([]byte, 1)); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected second connection to be rejected, got %v", err)
+	}
+}
+
+func TestReadTimeoutAndShutdown(t *testing.T) {
+	closed := make(chan struct{}, 2)
+	srv, addr := startServer(t, echo,
+		WithTimeouts(50*time.Millisecond, time.Second),
+		WithOnClose(func(*Conn) { closed <- struct{}{} }),
+	)
+
+	idle, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idle.Close()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("idle connection not closed by read timeout")
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Fatalf("expected listener to be closed")
+	}
+}
+
+func TestShutdownForceClose(t *testing.T) {
+	block := HandlerFunc(func(_ context.Context, c *Conn) {
+		_, _ = c.Read(make([]byte, 1))
+	})
+	srv, addr := startServer(t, block)
+
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for srv.ConnCount() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := srv.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if srv.ConnCount() != 0 {
+		t.Fatalf("connections not closed")
+	}
+}
+
+// flakyListener 前 n 次 Accept 返回文件句柄耗尽
+type flakyListener struct {
+	net.Listener
+	n int
+}
+
+func (l *flakyListener) Accept() (net.Conn, error) {
+	if l.n > 0 {
+		l.n--
+		return nil, &net.OpError{Op: "accept", Net: "tcp", Err: os.NewSyscallError("accept4", syscall.EMFILE)}
+	}
+	return l.Listener.Accept()
+}
+
+func TestAcceptBackoff(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := New(ln.Addr().String(), echo)
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(&flakyListener{Listener: ln, n: 3}) }()
+
+	c, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	_ = c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, _ = io.WriteString(c, "hello\n")
+	if line, err := bufio.NewReader(c).ReadString('\n'); err != nil || line != "hello\n" {
+		t.Fatalf("expected server to survive EMFILE, got %q: %v", line, err)
+	}
+
+	// listener 被外部关闭时返回
+	_ = c.Close()
+	_ = ln.Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after listener was closed")
+	}
+	_ = srv.Shutdown(context.Background())
+}
@@ -0,0 +1,105 @@
+package netutil
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Allowlist IP 白名单，支持 CIDR 与单个地址
+//
+//	al, _ := netutil.ParseAllowlist("10.0.0.0/8", "192.168.1.10")
+//	al.TrustedProxies, _ = netutil.ParseAllowlist("10.0.0.0/8")
+//	mux.Handle("/admin/", al.Middleware(adminHandler))
+type Allowlist struct {
+	prefixes []netip.Prefix
+
+	// TrustedProxies 可信的反向代理，来自这些地址的请求才会读取 X-Forwarded-For；为空时只使用 RemoteAddr
+	TrustedProxies *Allowlist
+}
+
+// ParseAllowlist 解析 CIDR 或 IP 列表
+func ParseAllowlist(entries ...string) (*Allowlist, error) {
+	a := &Allowlist{}
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("netutil: parse cidr %q: %w", e, err)
+			}
+			a.prefixes = append(a.prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(e)
+		if err != nil {
+			return nil, fmt.Errorf("netutil: parse ip %q: %w", e, err)
+		}
+		addr = addr.Unmap()
+		a.prefixes = append(a.prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return a, nil
+}
+
+// Contains 判断地址是否在白名单内，无法解析的地址返回 false
+func (a *Allowlist) Contains(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	return a.ContainsAddr(addr)
+}
+
+// ContainsAddr 同 Contains
+func (a *Allowlist) ContainsAddr(addr netip.Addr) bool {
+	if a == nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range a.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP 返回请求的客户端地址：RemoteAddr 属于 trusted 时，从右向左跳过
+// X-Forwarded-For 中的可信代理，取第一个不可信的地址，避免客户端伪造请求头
+func ClientIP(r *http.Request, trusted *Allowlist) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	if !trusted.Contains(remote) {
+		return remote
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(hops[i])
+		if !trusted.Contains(ip) {
+			return ip
+		}
+	}
+	return remote
+}
+
+// Middleware 客户端地址不在白名单内时返回 403
+func (a *Allowlist) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.Contains(ClientIP(r, a.TrustedProxies)) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
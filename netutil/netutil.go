@@ -0,0 +1,87 @@
+package netutil
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+)
+
+var ErrNoAddress = errors.New("netutil: no usable address found")
+
+// AdvertiseEnv 按顺序检查的环境变量，容器环境中通常通过 Downward API 注入 POD_IP
+var AdvertiseEnv = []string{"ADVERTISE_IP", "POD_IP", "HOST_IP"}
+
+// OutboundIP 返回访问外网时使用的本机地址。
+// 通过 UDP "连接" 让内核选路，不会真正发送数据包，也不要求目标可达
+func OutboundIP() (netip.Addr, error) {
+	conn, err := net.Dial("udp", "8.8.8.8:53")
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("netutil: detect outbound ip: %w", err)
+	}
+	defer conn.Close()
+
+	addr, ok := netip.AddrFromSlice(conn.LocalAddr().(*net.UDPAddr).IP)
+	if !ok {
+		return netip.Addr{}, ErrNoAddress
+	}
+	return addr.Unmap(), nil
+}
+
+// PrivateIPv4 返回第一个处于 up 状态的网卡上的私有 IPv4 地址
+func PrivateIPv4() (netip.Addr, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("netutil: list interfaces: %w", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			addr, ok := netip.AddrFromSlice(ipnet.IP)
+			if ok && addr.Unmap().Is4() && addr.IsPrivate() {
+				return addr.Unmap(), nil
+			}
+		}
+	}
+	return netip.Addr{}, ErrNoAddress
+}
+
+// AdvertiseIP 返回注册到服务发现时对外公布的地址：
+// 优先读取 AdvertiseEnv 中的环境变量，其次为出口地址，最后退回网卡上的私有地址
+func AdvertiseIP() (netip.Addr, error) {
+	for _, key := range AdvertiseEnv {
+		if v := os.Getenv(key); v != "" {
+			addr, err := netip.ParseAddr(v)
+			if err != nil {
+				return netip.Addr{}, fmt.Errorf("netutil: parse %s: %w", key, err)
+			}
+			return addr, nil
+		}
+	}
+	if addr, err := OutboundIP(); err == nil && !addr.IsLoopback() {
+		return addr, nil
+	}
+	return PrivateIPv4()
+}
+
+// FreePort 返回本机当前空闲的 TCP 端口，用于测试中启动服务；
+// 端口在返回后才被使用，并发场景下存在被其他进程占用的可能
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("netutil: find free port: %w", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,84 @@
+package netutil
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestFreePort(t *testing.T) {
+	port, err := FreePort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		t.Fatalf("port %d not usable: %v", port, err)
+	}
+	_ = l.Close()
+}
+
+func TestAdvertiseIPFromEnv(t *testing.T) {
+	t.Setenv("POD_IP", "10.1.2.3")
+	addr, err := AdvertiseIP()
+	if err != nil || addr.String() != "10.1.2.3" {
+		t.Fatalf("got %v, %v", addr, err)
+	}
+
+	t.Setenv("POD_IP", "not-an-ip")
+	if _, err := AdvertiseIP(); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestAllowlist(t *testing.T) {
+	al, err := ParseAllowlist("10.0.0.0/8", "192.168.1.10", "2001:db8::/32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ip, want := range map[string]bool{
+		"10.20.30.40":     true,
+		"192.168.1.10":    true,
+		"192.168.1.11":    false,
+		"::ffff:10.0.0.1": true,
+		"2001:db8::1":     true,
+		"garbage":         false,
+	} {
+		if got := al.Contains(ip); got != want {
+			t.Errorf("Contains(%s) = %v, want %v", ip, got, want)
+		}
+	}
+	if _, err := ParseAllowlist("10.0.0.0/33"); err == nil {
+		t.Fatal("expected error for invalid cidr")
+	}
+}
+
+func TestAllowlistMiddleware(t *testing.T) {
+	al, _ := ParseAllowlist("203.0.113.0/24")
+	al.TrustedProxies, _ = ParseAllowlist("10.0.0.0/8")
+	h := al.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	cases := []struct {
+		remote, xff string
+		want        int
+	}{
+		{"203.0.113.5:1234", "", http.StatusOK},
+		{"198.51.100.1:1234", "203.0.113.5", http.StatusForbidden},
+		{"10.0.0.2:1234", "203.0.113.5, 10.0.0.9", http.StatusOK},
+		{"10.0.0.2:1234", "203.0.113.5, 198.51.100.1", http.StatusForbidden},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = c.remote
+		if c.xff != "" {
+			req.Header.Set("X-Forwarded-For", c.xff)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Errorf("remote=%s xff=%q: got %d, want %d", c.remote, c.xff, rec.Code, c.want)
+		}
+	}
+}
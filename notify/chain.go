@@ -0,0 +1,214 @@
+package notify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/abs2free/go-kit/timeutil"
+)
+
+var (
+	ErrThrottled  = errors.New("notify: throttled")
+	ErrDuplicated = errors.New("notify: duplicated message suppressed")
+)
+
+// ChainOption 组合通知配置选项
+type ChainOption func(*Chain)
+
+// WithThrottle 全局限流，每分钟最多发送 perMinute 条，超出的消息直接丢弃并返回 ErrThrottled
+func WithThrottle(perMinute int) ChainOption {
+	return func(c *Chain) {
+		if perMinute > 0 {
+			c.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+		}
+	}
+}
+
+// WithDedup 在 window 时间内相同标题和内容的消息只发送一次，发送中的相同消息同样视为重复；
+// 被限流或全部渠道失败的消息不计入，重试时仍会发送
+func WithDedup(window time.Duration) ChainOption {
+	return func(c *Chain) {
+		c.dedupWindow = window
+	}
+}
+
+// WithMetrics 注册投递指标
+func WithMetrics(reg prometheus.Registerer) ChainOption {
+	return func(c *Chain) {
+		c.metrics = newChainMetrics(reg)
+	}
+}
+
+// WithClock 替换时钟，去重窗口与限流均按该时钟计时，测试中可注入 timeutil.Fake
+func WithClock(clock timeutil.Clock) ChainOption {
+	return func(c *Chain) {
+		c.clock = clock
+	}
+}
+
+// Chain 按顺序尝试各渠道直到成功，例如 飞书 → 短信 → 邮件
+type Chain struct {
+	notifiers []Notifier
+
+	limiter     *rate.Limiter
+	dedupWindow time.Duration
+	metrics     *chainMetrics
+	clock       timeutil.Clock
+
+	mu   sync.Mutex
+	seen map[string]dedupEntry
+}
+
+// dedupEntry 去重记录，pending 为发送中的预留，sent 为成功发送的时间
+type dedupEntry struct {
+	pending bool
+	sent    time.Time
+}
+
+// NewChain 创建组合通知
+func NewChain(notifiers []Notifier, opts ...ChainOption) *Chain {
+	c := &Chain{
+		notifiers: notifiers,
+		clock:     timeutil.Real,
+		seen:      make(map[string]dedupEntry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Chain) Name() string {
+	return "chain"
+}
+
+// Send 依次尝试各渠道，全部失败时返回合并后的错误
+func (c *Chain) Send(ctx context.Context, msg Message) error {
+	key, ok := c.reserve(msg)
+	if !ok {
+		c.metrics.suppress("duplicate")
+		return ErrDuplicated
+	}
+	sent := false
+	defer func() { c.release(key, sent) }()
+
+	if c.limiter != nil && !c.limiter.AllowN(c.clock.Now(), 1) {
+		c.metrics.suppress("throttled")
+		return ErrThrottled
+	}
+
+	var errs []error
+	for _, n := range c.notifiers {
+		start := c.clock.Now()
+		err := n.Send(ctx, msg)
+		c.metrics.observe(n.Name(), err, c.clock.Since(start))
+		if err == nil {
+			sent = true
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", n.Name(), err))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(errs) == 0 {
+		return errors.New("notify: no notifier configured")
+	}
+	return errors.Join(errs...)
+}
+
+// reserve 检查并预留消息的去重键，检查与预留在同一临界区内，并发的相同消息只有一条能发送；
+// 窗口内已成功发送或正在发送时返回 false。未开启去重时键为空
+func (c *Chain) reserve(msg Message) (string, bool) {
+	if c.dedupWindow <= 0 {
+		return "", true
+	}
+
+	sum := sha256.Sum256([]byte(msg.Title + "\x00" + msg.Content))
+	key := hex.EncodeToString(sum[:])
+	now := c.clock.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.seen {
+		if !e.pending && now.Sub(e.sent) > c.dedupWindow {
+			delete(c.seen, k)
+		}
+	}
+
+	if _, ok := c.seen[key]; ok {
+		return key, false
+	}
+	c.seen[key] = dedupEntry{pending: true}
+	return key, true
+}
+
+// release 结束预留：发送成功时记录发送时间，否则删除，重试时仍可发送
+func (c *Chain) release(key string, sent bool) {
+	if key == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sent {
+		c.seen[key] = dedupEntry{sent: c.clock.Now()}
+	} else {
+		delete(c.seen, key)
+	}
+}
+
+type chainMetrics struct {
+	deliveries *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	suppressed *prometheus.CounterVec
+}
+
+func newChainMetrics(reg prometheus.Registerer) *chainMetrics {
+	m := &chainMetrics{
+		deliveries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notify_deliveries_total",
+			Help: "Notification delivery attempts by channel and result.",
+		}, []string{"channel", "result"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "notify_delivery_duration_seconds",
+			Help:    "Notification delivery latency by channel.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"channel"}),
+		suppressed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "notify_suppressed_total",
+			Help: "Notifications dropped before delivery by reason.",
+		}, []string{"reason"}),
+	}
+	reg.MustRegister(m.deliveries, m.duration, m.suppressed)
+	return m
+}
+
+func (m *chainMetrics) observe(channel string, err error, d time.Duration) {
+	if m == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.deliveries.WithLabelValues(channel, result).Inc()
+	m.duration.WithLabelValues(channel).Observe(d.Seconds())
+}
+
+func (m *chainMetrics) suppress(reason string) {
+	if m == nil {
+		return
+	}
+	m.suppressed.WithLabelValues(reason).Inc()
+}
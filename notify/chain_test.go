@@ -0,0 +1,157 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/abs2free/go-kit/testkit"
+)
+
+type stubNotifier struct {
+	name string
+	err  error
+	sent int
+}
+
+func (s *stubNotifier) Name() string { return s.name }
+
+func (s *stubNotifier) Send(context.Context, Message) error {
+	s.sent++
+	return s.err
+}
+
+func TestChainFallbackAndDedup(t *testing.T) {
+	feishu := &stubNotifier{name: "feishu", err: errors.New("down")}
+	sms := &stubNotifier{name: "sms"}
+	email := &stubNotifier{name: "email"}
+
+	reg := prometheus.NewRegistry()
+	clock := testkit.Clock(t)
+	chain := NewChain([]Notifier{feishu, sms, email}, WithDedup(time.Minute), WithMetrics(reg), WithClock(clock))
+
+	msg := Message{Title: "db down", Content: "primary unreachable"}
+	if err := chain.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if feishu.sent != 1 || sms.sent != 1 || email.sent != 0 {
+		t.Fatalf("unexpected fallback: feishu=%d sms=%d email=%d", feishu.sent, sms.sent, email.sent)
+	}
+
+	if err := chain.Send(context.Background(), msg); !errors.Is(err, ErrDuplicated) {
+		t.Fatalf("expected ErrDuplicated, got %v", err)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if err := chain.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send after window: %v", err)
+	}
+
+	if got := testutil.ToFloat64(chain.metrics.deliveries.WithLabelValues("feishu", "failure")); got != 2 {
+		t.Fatalf("expected 2 feishu failures, got %v", got)
+	}
+	if got := testutil.ToFloat64(chain.metrics.suppressed.WithLabelValues("duplicate")); got != 1 {
+		t.Fatalf("expected 1 suppressed duplicate, got %v", got)
+	}
+}
+
+func TestChainThrottleAndAllFailed(t *testing.T) {
+	failing := &stubNotifier{name: "a", err: errors.New("boom")}
+	clock := testkit.Clock(t)
+	chain := NewChain([]Notifier{failing}, WithThrottle(1), WithClock(clock))
+
+	if err := chain.Send(context.Background(), Message{Content: "1"}); err == nil {
+		t.Fatalf("expected error when all channels fail")
+	}
+	if err := chain.Send(context.Background(), Message{Content: "2"}); !errors.Is(err, ErrThrottled) {
+		t.Fatalf("expected ErrThrottled, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if err := chain.Send(context.Background(), Message{Content: "3"}); errors.Is(err, ErrThrottled) {
+		t.Fatalf("expected throttle to reset after a minute")
+	}
+}
+
+func TestChainDedupAfterFailure(t *testing.T) {
+	flaky := &stubNotifier{name: "feishu", err: errors.New("down")}
+	clock := testkit.Clock(t)
+	chain := NewChain([]Notifier{flaky}, WithDedup(5*time.Minute), WithThrottle(1), WithClock(clock))
+
+	msg := Message{Title: "db down", Content: "primary unreachable"}
+	if err := chain.Send(context.Background(), msg); err == nil {
+		t.Fatal("expected error when all channels fail")
+	}
+
+	// 被限流的消息同样不计入去重
+	flaky.err = nil
+	if err := chain.Send(context.Background(), msg); !errors.Is(err, ErrThrottled) {
+		t.Fatalf("expected ErrThrottled, got %v", err)
+	}
+
+	// 窗口内重试仍会发送
+	clock.Advance(time.Minute)
+	if err := chain.Send(context.Background(), msg); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if flaky.sent != 2 {
+		t.Fatalf("expected 2 attempts, got %d", flaky.sent)
+	}
+	if err := chain.Send(context.Background(), msg); !errors.Is(err, ErrDuplicated) {
+		t.Fatalf("expected ErrDuplicated after success, got %v", err)
+	}
+}
+
+// blockingNotifier 收到消息后阻塞到 release 关闭
+type blockingNotifier struct {
+	sent    atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingNotifier) Name() string { return "blocking" }
+
+func (b *blockingNotifier) Send(context.Context, Message) error {
+	if b.sent.Add(1) == 1 {
+		close(b.started)
+	}
+	<-b.release
+	return nil
+}
+
+func TestChainDedupConcurrent(t *testing.T) {
+	n := &blockingNotifier{started: make(chan struct{}), release: make(chan struct{})}
+	chain := NewChain([]Notifier{n}, WithDedup(time.Minute))
+	msg := Message{Title: "db down", Content: "primary unreachable"}
+
+	first := make(chan error, 1)
+	go func() { first <- chain.Send(context.Background(), msg) }()
+	<-n.started
+
+	// 发送中的相同消息视为重复
+	var wg sync.WaitGroup
+	var dup atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if errors.Is(chain.Send(context.Background(), msg), ErrDuplicated) {
+				dup.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	close(n.release)
+	if err := <-first; err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if n.sent.Load() != 1 || dup.Load() != 10 {
+		t.Fatalf("expected 1 delivery and 10 duplicates, got %d %d", n.sent.Load(), dup.Load())
+	}
+}
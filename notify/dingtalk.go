@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// DingTalk 钉钉群机器人
+type DingTalk struct {
+	*robot
+}
+
+// NewDingTalk 创建钉钉机器人，webhook 为包含 access_token 的完整地址
+func NewDingTalk(webhook string, opts ...RobotOption) *DingTalk {
+	return &DingTalk{robot: newRobot(webhook, opts)}
+}
+
+func (d *DingTalk) Name() string {
+	return "dingtalk"
+}
+
+func (d *DingTalk) Send(ctx context.Context, msg Message) error {
+	target := d.webhook
+	if d.secret != "" {
+		signed, err := dingTalkSignURL(target, d.secret, time.Now())
+		if err != nil {
+			return err
+		}
+		target = signed
+	}
+
+	at := map[string]any{"atMobiles": msg.AtMobiles, "isAtAll": msg.AtAll}
+
+	var payload map[string]any
+	switch msg.Format {
+	case FormatMarkdown:
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"title": msg.Title, "text": msg.Content},
+			"at":       at,
+		}
+	case FormatCard:
+		card := map[string]any{"title": msg.Title, "text": msg.Content}
+		if msg.URL != "" {
+			card["singleTitle"] = "查看详情"
+			card["singleURL"] = msg.URL
+		}
+		payload = map[string]any{"msgtype": "actionCard", "actionCard": card}
+	default:
+		payload = map[string]any{
+			"msgtype": "text",
+			"text":    map[string]any{"content": joinTitle(msg)},
+			"at":      at,
+		}
+	}
+
+	var resp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := d.post(ctx, target, payload, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("notify: dingtalk error %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
+
+// dingTalkSignURL 钉钉加签：HmacSHA256(timestamp + "\n" + secret)，附加到 URL 参数
+func dingTalkSignURL(webhook, secret string, now time.Time) (string, error) {
+	u, err := url.Parse(webhook)
+	if err != nil {
+		return "", fmt.Errorf("notify: invalid webhook: %w", err)
+	}
+
+	ts := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts + "\n" + secret))
+
+	q := u.Query()
+	q.Set("timestamp", ts)
+	q.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func joinTitle(msg Message) string {
+	if msg.Title == "" {
+		return msg.Content
+	}
+	return msg.Title + "\n" + msg.Content
+}
@@ -0,0 +1,377 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TLSMode SMTP 连接加密方式
+type TLSMode int
+
+const (
+	// TLSStartTLS 明文连接后升级（587 端口）
+	TLSStartTLS TLSMode = iota
+	// TLSImplicit 直接建立 TLS 连接（465 端口）
+	TLSImplicit
+	// TLSNone 不加密，仅用于内网中继
+	TLSNone
+)
+
+// SMTPConfig SMTP 配置
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	TLS      TLSMode
+	// TLSConfig 自定义 TLS 配置，为空时使用 ServerName=Host
+	TLSConfig *tls.Config
+	Timeout   time.Duration
+	// Retries 失败后的重试次数，Backoff 为首次重试间隔，之后指数增长
+	Retries int
+	Backoff time.Duration
+}
+
+// Email 邮件内容，Text 与 HTML 同时存在时以 multipart/alternative 发送
+type Email struct {
+	To          []string
+	Cc          []string
+	Bcc         []string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+// Mailer SMTP 邮件发送器，同时实现 Notifier
+type Mailer struct {
+	cfg SMTPConfig
+	to  []string
+}
+
+// NewMailer 创建邮件发送器，to 为作为 Notifier 使用时的默认收件人
+func NewMailer(cfg SMTPConfig, to ...string) *Mailer {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 15 * time.Second
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = time.Second
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+		if cfg.TLS == TLSImplicit {
+			cfg.Port = 465
+		}
+	}
+	return &Mailer{cfg: cfg, to: to}
+}
+
+func (m *Mailer) Name() string {
+	return "email"
+}
+
+// Send 将通用消息转换为邮件发送给默认收件人
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	e := Email{
+		To:          m.to,
+		Subject:     msg.Title,
+		Text:        msg.Content,
+		Attachments: msg.Attachments,
+	}
+	if msg.Format != FormatText {
+		var html strings.Builder
+		if msg.Title != "" {
+			html.WriteString("<h3>" + htmltemplate.HTMLEscapeString(msg.Title) + "</h3>")
+		}
+		html.WriteString("<pre>" + htmltemplate.HTMLEscapeString(msg.Content) + "</pre>")
+		if msg.URL != "" {
+			html.WriteString(`<p><a href="` + htmltemplate.HTMLEscapeString(msg.URL) + `">查看详情</a></p>`)
+		}
+		e.HTML = html.String()
+	}
+	return m.SendEmail(ctx, e)
+}
+
+// SendEmail 发送邮件，失败时按退避策略重试
+func (m *Mailer) SendEmail(ctx context.Context, e Email) error {
+	rcpts := append(append(append([]string{}, e.To...), e.Cc...), e.Bcc...)
+	if len(rcpts) == 0 {
+		return fmt.Errorf("notify: email has no recipients")
+	}
+
+	data, err := buildEmail(m.cfg.From, e)
+	if err != nil {
+		return err
+	}
+
+	backoff := m.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		err = m.deliver(ctx, rcpts, data)
+		if err == nil || attempt >= m.cfg.Retries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	if err != nil {
+		return fmt.Errorf("notify: send email: %w", err)
+	}
+	return nil
+}
+
+func (m *Mailer) deliver(ctx context.Context, rcpts []string, data []byte) error {
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	tlsCfg := m.cfg.TLSConfig
+	if tlsCfg == nil {
+		tlsCfg = &tls.Config{ServerName: m.cfg.Host}
+	}
+
+	dialer := &net.Dialer{Timeout: m.cfg.Timeout}
+	var conn net.Conn
+	var err error
+	if m.cfg.TLS == TLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsCfg}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(m.cfg.Timeout))
+
+	c, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if m.cfg.TLS == TLSStartTLS {
+		if err := c.StartTLS(tlsCfg); err != nil {
+			return err
+		}
+	}
+
+	if m.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	from, err := mail.ParseAddress(m.cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+	for _, r := range rcpts {
+		if err := c.Rcpt(r); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildEmail 构造 MIME 邮件：multipart/mixed 包含 multipart/alternative 正文和附件
+func buildEmail(from string, e Email) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from)
+	header.Set("To", strings.Join(e.To, ", "))
+	if len(e.Cc) > 0 {
+		header.Set("Cc", strings.Join(e.Cc, ", "))
+	}
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", e.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", messageID(from))
+	header.Set("MIME-Version", "1.0")
+
+	mixed := multipart.NewWriter(&buf)
+	header.Set("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+	for _, k := range []string{"From", "To", "Cc", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type"} {
+		if v := header.Get(k); v != "" {
+			fmt.Fprintf(&buf, "%s: %s\r\n", k, v)
+		}
+	}
+	buf.WriteString("\r\n")
+
+	var body bytes.Buffer
+	alt := multipart.NewWriter(&body)
+	if e.Text != "" || e.HTML == "" {
+		if err := writeQuotedPart(alt, "text/plain; charset=utf-8", e.Text); err != nil {
+			return nil, err
+		}
+	}
+	if e.HTML != "" {
+		if err := writeQuotedPart(alt, "text/html; charset=utf-8", e.HTML); err != nil {
+			return nil, err
+		}
+	}
+	if err := alt.Close(); err != nil {
+		return nil, err
+	}
+
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alt.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, a := range e.Attachments {
+		contentType := a.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Name})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, a.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPart(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64Lines 按 RFC 2045 每 76 字符换行
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := w.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if _, d, ok := strings.Cut(addr.Address, "@"); ok {
+			domain = d
+		}
+	}
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
+
+// EmailTemplate 邮件模板：主题与纯文本使用 text/template，HTML 使用 html/template 自动转义
+type EmailTemplate struct {
+	subject *template.Template
+	text    *template.Template
+	html    *htmltemplate.Template
+}
+
+// NewEmailTemplate 解析邮件模板，text 或 html 可为空
+func NewEmailTemplate(subject, text, html string) (*EmailTemplate, error) {
+	t := &EmailTemplate{}
+	var err error
+	if t.subject, err = template.New("subject").Parse(subject); err != nil {
+		return nil, fmt.Errorf("notify: parse subject template: %w", err)
+	}
+	if text != "" {
+		if t.text, err = template.New("text").Parse(text); err != nil {
+			return nil, fmt.Errorf("notify: parse text template: %w", err)
+		}
+	}
+	if html != "" {
+		if t.html, err = htmltemplate.New("html").Parse(html); err != nil {
+			return nil, fmt.Errorf("notify: parse html template: %w", err)
+		}
+	}
+	return t, nil
+}
+
+// Render 渲染邮件，收件人与附件由调用方补充
+func (t *EmailTemplate) Render(data any) (Email, error) {
+	var e Email
+	var buf bytes.Buffer
+
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return e, fmt.Errorf("notify: render subject: %w", err)
+	}
+	e.Subject = buf.String()
+
+	if t.text != nil {
+		buf.Reset()
+		if err := t.text.Execute(&buf, data); err != nil {
+			return e, fmt.Errorf("notify: render text: %w", err)
+		}
+		e.Text = buf.String()
+	}
+	if t.html != nil {
+		buf.Reset()
+		if err := t.html.Execute(&buf, data); err != nil {
+			return e, fmt.Errorf("notify: render html: %w", err)
+		}
+		e.HTML = buf.String()
+	}
+	return e, nil
+}
@@ -0,0 +1,61 @@
+package notify
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBuildEmailStructure(t *testing.T) {
+	tpl, err := NewEmailTemplate("订单 {{.ID}} 已发货", "Hi {{.Name}}", "<p>Hi {{.Name}}</p>")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	e, err := tpl.Render(map[string]string{"ID": "A1", "Name": "<Bob>"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(e.HTML, "&lt;Bob&gt;") {
+		t.Fatalf("expected html escaping, got %s", e.HTML)
+	}
+
+	e.To = []string{"bob@example.com"}
+	e.Attachments = []Attachment{{Name: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF")}}
+
+	raw, err := buildEmail("Shop <noreply@example.com>", e)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if subject != "订单 A1 已发货" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+
+	_, params, _ := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	mr := multipart.NewReader(m.Body, params["boundary"])
+
+	var types []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		mt, _, _ := mime.ParseMediaType(p.Header.Get("Content-Type"))
+		types = append(types, mt)
+	}
+	if len(types) != 2 || types[0] != "multipart/alternative" || types[1] != "application/pdf" {
+		t.Fatalf("unexpected parts %v", types)
+	}
+}
@@ -0,0 +1,95 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Feishu 飞书群机器人
+type Feishu struct {
+	*robot
+}
+
+// NewFeishu 创建飞书机器人
+func NewFeishu(webhook string, opts ...RobotOption) *Feishu {
+	return &Feishu{robot: newRobot(webhook, opts)}
+}
+
+func (f *Feishu) Name() string {
+	return "feishu"
+}
+
+func (f *Feishu) Send(ctx context.Context, msg Message) error {
+	var payload map[string]any
+	switch msg.Format {
+	case FormatMarkdown, FormatCard:
+		payload = map[string]any{"msg_type": "interactive", "card": feishuCard(msg)}
+	default:
+		content := joinTitle(msg)
+		if msg.AtAll {
+			content += `<at user_id="all">所有人</at>`
+		}
+		payload = map[string]any{
+			"msg_type": "text",
+			"content":  map[string]any{"text": content},
+		}
+	}
+
+	if f.secret != "" {
+		ts := time.Now().Unix()
+		payload["timestamp"] = strconv.FormatInt(ts, 10)
+		payload["sign"] = feishuSign(f.secret, ts)
+	}
+
+	var resp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := f.post(ctx, f.webhook, payload, &resp); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("notify: feishu error %d: %s", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
+func feishuCard(msg Message) map[string]any {
+	content := msg.Content
+	if msg.AtAll {
+		content += "\n<at id=all></at>"
+	}
+
+	elements := []any{
+		map[string]any{"tag": "markdown", "content": content},
+	}
+	if msg.URL != "" {
+		elements = append(elements, map[string]any{
+			"tag": "action",
+			"actions": []any{map[string]any{
+				"tag":  "button",
+				"text": map[string]any{"tag": "plain_text", "content": "查看详情"},
+				"url":  msg.URL,
+				"type": "primary",
+			}},
+		})
+	}
+
+	return map[string]any{
+		"header": map[string]any{
+			"title": map[string]any{"tag": "plain_text", "content": msg.Title},
+		},
+		"elements": elements,
+	}
+}
+
+// feishuSign 飞书加签：以 timestamp + "\n" + secret 为密钥对空串做 HmacSHA256
+func feishuSign(secret string, ts int64) string {
+	mac := hmac.New(sha256.New, []byte(strconv.FormatInt(ts, 10)+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
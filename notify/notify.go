@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Format 消息格式
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatMarkdown
+	FormatCard
+)
+
+// Message 通用消息，各渠道按自身能力渲染
+type Message struct {
+	Title   string
+	Content string
+	Format  Format
+	// URL 卡片消息的跳转链接
+	URL string
+	// AtMobiles / AtAll 群机器人 @ 提醒，不支持的渠道忽略
+	AtMobiles []string
+	AtAll     bool
+	// Attachments 附件，仅支持文件上传的渠道（Slack Bot、Telegram）发送
+	Attachments []Attachment
+}
+
+// Attachment 消息附件
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Notifier 通知渠道
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// RobotOption 机器人配置选项
+type RobotOption func(*robot)
+
+// WithSecret 设置加签密钥
+func WithSecret(secret string) RobotOption {
+	return func(r *robot) {
+		r.secret = secret
+	}
+}
+
+// WithHTTPClient 设置自定义 HTTP 客户端
+func WithHTTPClient(client *http.Client) RobotOption {
+	return func(r *robot) {
+		r.client = client
+	}
+}
+
+// WithEndpoint 覆盖 API 地址，用于代理或测试
+func WithEndpoint(endpoint string) RobotOption {
+	return func(r *robot) {
+		r.webhook = endpoint
+	}
+}
+
+// WithRateLimit 设置每分钟最大发送条数，超出时阻塞等待，<=0 表示不限制
+func WithRateLimit(perMinute int) RobotOption {
+	return func(r *robot) {
+		if perMinute <= 0 {
+			r.limiter = nil
+			return
+		}
+		r.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+	}
+}
+
+// robot 群机器人公共实现
+type robot struct {
+	webhook string
+	secret  string
+	client  *http.Client
+	limiter *rate.Limiter
+}
+
+func newRobot(webhook string, opts []RobotOption) *robot {
+	r := &robot{
+		webhook: webhook,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		// 钉钉/飞书/企业微信官方限制均为每分钟 20 条左右
+		limiter: rate.NewLimiter(rate.Every(3*time.Second), 20),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// post 发送 JSON 请求并解析响应
+func (r *robot) post(ctx context.Context, url string, payload any, result any) error {
+	if err := r.wait(ctx); err != nil {
+		return err
+	}
+	return postJSON(ctx, r.client, url, nil, payload, result)
+}
+
+func (r *robot) wait(ctx context.Context) error {
+	if r.limiter == nil {
+		return nil
+	}
+	return r.limiter.Wait(ctx)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, header http.Header, payload any, result any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("notify: marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return doRequest(client, req, result)
+}
+
+// doRequest 执行请求，检查状态码并按需解析 JSON 响应
+func doRequest(client *http.Client, req *http.Request, result any) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notify: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notify: unexpected status %d: %s", resp.StatusCode, data)
+	}
+	if result != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("notify: decode response: %w", err)
+		}
+	}
+	return nil
+}
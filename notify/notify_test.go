@@ -0,0 +1,108 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRobotServer(t *testing.T, resp string, check func(r *http.Request, body map[string]any)) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		check(r, body)
+		_, _ = w.Write([]byte(resp))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDingTalkSignedMarkdown(t *testing.T) {
+	srv := newRobotServer(t, `{"errcode":0,"errmsg":"ok"}`, func(r *http.Request, body map[string]any) {
+		if r.URL.Query().Get("sign") == "" || r.URL.Query().Get("timestamp") == "" {
+			t.Errorf("expected signed url, got %s", r.URL)
+		}
+		if body["msgtype"] != "markdown" {
+			t.Errorf("expected markdown msgtype, got %v", body["msgtype"])
+		}
+	})
+
+	d := NewDingTalk(srv.URL+"?access_token=abc", WithSecret("SEC123"))
+	err := d.Send(context.Background(), Message{Title: "告警", Content: "**disk full**", Format: FormatMarkdown})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
+
+func TestRobotErrorCodes(t *testing.T) {
+	srv := newRobotServer(t, `{"code":19021,"msg":"sign match fail"}`, func(r *http.Request, body map[string]any) {
+		if body["sign"] == nil {
+			t.Errorf("expected sign field in feishu body")
+		}
+	})
+
+	f := NewFeishu(srv.URL, WithSecret("s"))
+	if err := f.Send(context.Background(), Message{Content: "hi"}); err == nil {
+		t.Fatalf("expected feishu error code to be surfaced")
+	}
+
+	srv = newRobotServer(t, `{"errcode":93000,"errmsg":"invalid webhook"}`, func(*http.Request, map[string]any) {})
+	w := NewWeCom(srv.URL, WithRateLimit(0))
+	if err := w.Send(context.Background(), Message{Content: "hi", Format: FormatCard}); err == nil {
+		t.Fatalf("expected wecom error code to be surfaced")
+	}
+}
+
+func TestWeComAtAllKeepsCallerSlice(t *testing.T) {
+	var mentioned []any
+	srv := newRobotServer(t, `{"errcode":0,"errmsg":"ok"}`, func(_ *http.Request, body map[string]any) {
+		mentioned, _ = body["text"].(map[string]any)["mentioned_mobile_list"].([]any)
+	})
+
+	mobiles := make([]string, 1, 2)
+	mobiles[0] = "13800000000"
+	w := NewWeCom(srv.URL, WithRateLimit(0))
+	if err := w.Send(context.Background(), Message{Content: "hi", AtMobiles: mobiles, AtAll: true}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(mentioned) != 2 || mentioned[1] != "@all" {
+		t.Fatalf("unexpected mentioned list %v", mentioned)
+	}
+	if spare := mobiles[:2]; spare[1] != "" {
+		t.Fatalf("caller slice backing array modified: %v", spare)
+	}
+}
+
+func TestTelegramTemplateAndAttachment(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	tpl := MustTemplate("[{{.Env}}] {{.Service}}", "error rate {{.Rate}}%", FormatMarkdown)
+	msg, err := tpl.Render(map[string]any{"Env": "prod", "Service": "order", "Rate": 12})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if msg.Title != "[prod] order" || msg.Content != "error rate 12%" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+
+	msg.Attachments = []Attachment{{Name: "dump.txt", Data: []byte("stack")}}
+	tg := NewTelegram("TOKEN", "42", WithEndpoint(srv.URL))
+	if err := tg.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	want := []string{"/botTOKEN/sendMessage", "/botTOKEN/sendDocument"}
+	if len(paths) != 2 || paths[0] != want[0] || paths[1] != want[1] {
+		t.Fatalf("unexpected calls %v", paths)
+	}
+}
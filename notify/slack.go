@@ -0,0 +1,181 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const slackAPI = "https://slack.com/api"
+
+// SlackWebhook Slack Incoming Webhook，不支持附件
+type SlackWebhook struct {
+	*robot
+}
+
+// NewSlackWebhook 创建 Slack Incoming Webhook 通知
+func NewSlackWebhook(webhook string, opts ...RobotOption) *SlackWebhook {
+	opts = append([]RobotOption{slackRateLimit()}, opts...)
+	return &SlackWebhook{robot: newRobot(webhook, opts)}
+}
+
+func (s *SlackWebhook) Name() string {
+	return "slack"
+}
+
+func (s *SlackWebhook) Send(ctx context.Context, msg Message) error {
+	// Incoming Webhook 成功时返回纯文本 ok
+	return s.post(ctx, s.webhook, slackPayload(msg), nil)
+}
+
+// SlackBot 通过 Bot Token 调用 Web API 发送消息，支持文件附件
+type SlackBot struct {
+	*robot
+	token   string
+	channel string
+}
+
+// NewSlackBot 创建 Slack Bot 通知，channel 为频道 ID
+func NewSlackBot(token, channel string, opts ...RobotOption) *SlackBot {
+	opts = append([]RobotOption{WithEndpoint(slackAPI), slackRateLimit()}, opts...)
+	return &SlackBot{robot: newRobot("", opts), token: token, channel: channel}
+}
+
+func (s *SlackBot) Name() string {
+	return "slack"
+}
+
+func (s *SlackBot) Send(ctx context.Context, msg Message) error {
+	payload := slackPayload(msg)
+	payload["channel"] = s.channel
+
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+
+	var resp slackResponse
+	header := http.Header{"Authorization": {"Bearer " + s.token}}
+	if err := postJSON(ctx, s.client, s.webhook+"/chat.postMessage", header, payload, &resp); err != nil {
+		return err
+	}
+	if err := resp.err("chat.postMessage"); err != nil {
+		return err
+	}
+
+	for _, a := range msg.Attachments {
+		if err := s.upload(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upload 使用 files.getUploadURLExternal + files.completeUploadExternal 上传附件
+func (s *SlackBot) upload(ctx context.Context, a Attachment) error {
+	form := url.Values{}
+	form.Set("filename", a.Name)
+	form.Set("length", strconv.Itoa(len(a.Data)))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhook+"/files.getUploadURLExternal", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var ticket struct {
+		slackResponse
+		UploadURL string `json:"upload_url"`
+		FileID    string `json:"file_id"`
+	}
+	if err := doRequest(s.client, req, &ticket); err != nil {
+		return err
+	}
+	if err := ticket.err("files.getUploadURLExternal"); err != nil {
+		return err
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, ticket.UploadURL, bytes.NewReader(a.Data))
+	if err != nil {
+		return err
+	}
+	if a.ContentType != "" {
+		req.Header.Set("Content-Type", a.ContentType)
+	}
+	if err := doRequest(s.client, req, nil); err != nil {
+		return err
+	}
+
+	var resp slackResponse
+	header := http.Header{"Authorization": {"Bearer " + s.token}}
+	complete := map[string]any{
+		"files":      []any{map[string]any{"id": ticket.FileID, "title": a.Name}},
+		"channel_id": s.channel,
+	}
+	if err := postJSON(ctx, s.client, s.webhook+"/files.completeUploadExternal", header, complete, &resp); err != nil {
+		return err
+	}
+	return resp.err("files.completeUploadExternal")
+}
+
+type slackResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error"`
+}
+
+func (r slackResponse) err(method string) error {
+	if r.OK {
+		return nil
+	}
+	return fmt.Errorf("notify: slack %s failed: %s", method, r.Error)
+}
+
+// slackRateLimit Slack 对单个频道的限制约为每秒 1 条
+func slackRateLimit() RobotOption {
+	return func(r *robot) {
+		r.limiter = rate.NewLimiter(rate.Every(time.Second), 1)
+	}
+}
+
+func slackPayload(msg Message) map[string]any {
+	text := joinTitle(msg)
+	if msg.AtAll {
+		text = "<!channel> " + text
+	}
+
+	if msg.Format == FormatText {
+		return map[string]any{"text": text}
+	}
+
+	blocks := []any{}
+	if msg.Title != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "header",
+			"text": map[string]any{"type": "plain_text", "text": msg.Title},
+		})
+	}
+	blocks = append(blocks, map[string]any{
+		"type": "section",
+		"text": map[string]any{"type": "mrkdwn", "text": msg.Content},
+	})
+	if msg.Format == FormatCard && msg.URL != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "actions",
+			"elements": []any{map[string]any{
+				"type": "button",
+				"text": map[string]any{"type": "plain_text", "text": "View"},
+				"url":  msg.URL,
+			}},
+		})
+	}
+
+	// text 作为通知栏的降级显示
+	return map[string]any{"text": text, "blocks": blocks}
+}
@@ -0,0 +1,135 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSlackWebhookPayload(t *testing.T) {
+	var body map[string]any
+	srv := newRobotServer(t, "ok", func(r *http.Request, b map[string]any) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected content type %q", r.Header.Get("Content-Type"))
+		}
+		body = b
+	})
+
+	s := NewSlackWebhook(srv.URL, WithRateLimit(0))
+	msg := Message{Title: "Deploy", Content: "*order* v2 released", Format: FormatCard, URL: "https://ci/1", AtAll: true}
+	if err := s.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	if body["text"] != "<!channel> Deploy\n*order* v2 released" {
+		t.Fatalf("unexpected fallback text %q", body["text"])
+	}
+	blocks, _ := body["blocks"].([]any)
+	if len(blocks) != 3 {
+		t.Fatalf("expected header, section and actions blocks, got %v", body["blocks"])
+	}
+	var types []string
+	for _, b := range blocks {
+		types = append(types, b.(map[string]any)["type"].(string))
+	}
+	if strings.Join(types, ",") != "header,section,actions" {
+		t.Fatalf("unexpected block types %v", types)
+	}
+	button := blocks[2].(map[string]any)["elements"].([]any)[0].(map[string]any)
+	if button["url"] != "https://ci/1" {
+		t.Fatalf("unexpected button %v", button)
+	}
+
+	// 纯文本消息不带 blocks
+	if err := s.Send(context.Background(), Message{Content: "hi"}); err != nil {
+		t.Fatalf("send text: %v", err)
+	}
+	if body["text"] != "hi" || body["blocks"] != nil {
+		t.Fatalf("unexpected text payload %v", body)
+	}
+}
+
+func TestSlackWebhookError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_payload", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	err := NewSlackWebhook(srv.URL, WithRateLimit(0)).Send(context.Background(), Message{Content: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "invalid_payload") {
+		t.Fatalf("expected invalid_payload error, got %v", err)
+	}
+}
+
+func TestSlackBotMessageAndUpload(t *testing.T) {
+	var calls []string
+	var uploaded string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.URL.Path)
+		if r.URL.Path != "/upload" && r.Header.Get("Authorization") != "Bearer xoxb-1" {
+			t.Errorf("%s: missing bearer token", r.URL.Path)
+		}
+
+		switch r.URL.Path {
+		case "/chat.postMessage":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["channel"] != "C1" || body["text"] != "hi" {
+				t.Errorf("unexpected message body %v", body)
+			}
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "/files.getUploadURLExternal":
+			_ = r.ParseForm()
+			if r.PostForm.Get("filename") != "dump.txt" || r.PostForm.Get("length") != "5" {
+				t.Errorf("unexpected upload form %v", r.PostForm)
+			}
+			_, _ = w.Write([]byte(`{"ok":true,"upload_url":"` + srv.URL + `/upload","file_id":"F1"}`))
+		case "/upload":
+			data, _ := io.ReadAll(r.Body)
+			uploaded = string(data)
+		case "/files.completeUploadExternal":
+			var body struct {
+				Files     []map[string]string `json:"files"`
+				ChannelID string              `json:"channel_id"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body.ChannelID != "C1" || len(body.Files) != 1 || body.Files[0]["id"] != "F1" {
+				t.Errorf("unexpected complete body %+v", body)
+			}
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	bot := NewSlackBot("xoxb-1", "C1", WithEndpoint(srv.URL), WithRateLimit(0))
+	msg := Message{Content: "hi", Format: FormatText, Attachments: []Attachment{{Name: "dump.txt", Data: []byte("stack")}}}
+	if err := bot.Send(context.Background(), msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	want := "/chat.postMessage,/files.getUploadURLExternal,/upload,/files.completeUploadExternal"
+	if strings.Join(calls, ",") != want || uploaded != "stack" {
+		t.Fatalf("unexpected calls %v, uploaded %q", calls, uploaded)
+	}
+}
+
+func TestSlackBotAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Web API 出错时仍返回 200，错误在 ok/error 字段中
+		_, _ = w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+	}))
+	defer srv.Close()
+
+	bot := NewSlackBot("xoxb-1", "C404", WithEndpoint(srv.URL), WithRateLimit(0))
+	err := bot.Send(context.Background(), Message{Content: "hi"})
+	if err == nil || !strings.Contains(err.Error(), "chat.postMessage failed: channel_not_found") {
+		t.Fatalf("expected channel_not_found error, got %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/abs2free/go-kit/timeutil"
+)
+
+var (
+	ErrSMSRateLimited = errors.New("notify: sms rate limited for this number")
+	ErrInvalidPhone   = errors.New("notify: phone number must be in E.164 format")
+)
+
+// SMSProvider 短信服务商
+type SMSProvider interface {
+	Name() string
+	// SendSMS 使用服务商模板发送短信，params 为模板变量
+	SendSMS(ctx context.Context, phone, template string, params map[string]string) error
+}
+
+// SMSOption 短信配置选项
+type SMSOption func(*SMS)
+
+// WithNumberLimit 单个号码的发送频率限制，例如每分钟 1 条、突发 1 条
+func WithNumberLimit(every time.Duration, burst int) SMSOption {
+	return func(s *SMS) {
+		s.every = every
+		s.burst = burst
+	}
+}
+
+// WithSMSClock 替换号码限流使用的时钟，测试中可注入 timeutil.Fake
+func WithSMSClock(clock timeutil.Clock) SMSOption {
+	return func(s *SMS) {
+		s.clock = clock
+	}
+}
+
+// WithAlertTemplate 作为 Notifier 使用时的模板与默认接收号码，
+// 消息标题和正文分别以 title、content 变量传入模板
+func WithAlertTemplate(template string, phones ...string) SMSOption {
+	return func(s *SMS) {
+		s.alertTemplate = template
+		s.phones = phones
+	}
+}
+
+// SMS 在服务商之上增加按号码限流，并可作为告警 Notifier 使用
+type SMS struct {
+	provider SMSProvider
+	every    time.Duration
+	burst    int
+	clock    timeutil.Clock
+
+	alertTemplate string
+	phones        []string
+
+	mu       sync.Mutex
+	limiters map[string]*numberLimiter
+}
+
+type numberLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+	// pending 正在发送、尚未扣除额度的条数
+	pending int
+}
+
+// NewSMS 创建短信发送器，默认每个号码每分钟 1 条
+func NewSMS(provider SMSProvider, opts ...SMSOption) *SMS {
+	s := &SMS{
+		provider: provider,
+		every:    time.Minute,
+		burst:    1,
+		clock:    timeutil.Real,
+		limiters: make(map[string]*numberLimiter),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *SMS) Name() string {
+	return "sms:" + s.provider.Name()
+}
+
+// SendSMS 检查号码频率后交给服务商发送，超出频率返回 ErrSMSRateLimited；发送失败时归还额度
+func (s *SMS) SendSMS(ctx context.Context, phone, template string, params map[string]string) error {
+	l, ok := s.reserve(phone)
+	if !ok {
+		return ErrSMSRateLimited
+	}
+	err := s.provider.SendSMS(ctx, phone, template, params)
+	s.settle(l, err == nil)
+	return err
+}
+
+// Send 实现 Notifier，向 WithAlertTemplate 配置的号码发送告警
+func (s *SMS) Send(ctx context.Context, msg Message) error {
+	if s.alertTemplate == "" || len(s.phones) == 0 {
+		return errors.New("notify: sms alert template or phones not configured")
+	}
+
+	params := map[string]string{"title": msg.Title, "content": msg.Content}
+	var errs []error
+	for _, phone := range s.phones {
+		if err := s.SendSMS(ctx, phone, s.alertTemplate, params); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	// 只要有一个号码发送成功即视为送达
+	if len(errs) == len(s.phones) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// reserve 为号码预留一次发送额度，额度不足（含发送中的预留）时返回 false；未限流时返回 nil
+func (s *SMS) reserve(phone string) (*numberLimiter, bool) {
+	if s.every <= 0 {
+		return nil, true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	l, ok := s.limiters[phone]
+	if !ok {
+		l = &numberLimiter{limiter: rate.NewLimiter(rate.Every(s.every), max(s.burst, 1))}
+		s.limiters[phone] = l
+		s.prune(now)
+	}
+	l.lastSeen = now
+	if l.limiter.TokensAt(now) < float64(l.pending+1) {
+		return nil, false
+	}
+	l.pending++
+	return l, true
+}
+
+// settle 释放预留，发送成功时才扣除额度
+func (s *SMS) settle(l *numberLimiter, sent bool) {
+	if l == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l.pending--
+	if sent {
+		l.limiter.AllowN(s.clock.Now(), 1)
+	}
+}
+
+// prune 清理长时间未使用的号码，避免 map 无限增长
+func (s *SMS) prune(now time.Time) {
+	if len(s.limiters) < 10000 {
+		return
+	}
+	idle := s.every * time.Duration(max(s.burst, 1))
+	for phone, l := range s.limiters {
+		if now.Sub(l.lastSeen) > idle {
+			delete(s.limiters, phone)
+		}
+	}
+}
+
+func defaultSMSClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
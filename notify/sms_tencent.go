@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TencentSMS 腾讯云短信服务
+type TencentSMS struct {
+	SecretID  string
+	SecretKey string
+	AppID     string
+	SignName  string
+	Region    string
+	// CountryCode 号码不以 + 开头时补全的国家码，如 "+86"；为空时要求号码为 E.164 格式
+	CountryCode string
+	Endpoint    string
+	Client      *http.Client
+}
+
+// NewTencentSMS 创建腾讯云短信服务商
+func NewTencentSMS(secretID, secretKey, appID, signName string) *TencentSMS {
+	return &TencentSMS{
+		SecretID:  secretID,
+		SecretKey: secretKey,
+		AppID:     appID,
+		SignName:  signName,
+		Region:    "ap-guangzhou",
+		Endpoint:  "https://sms.tencentcloudapi.com",
+		Client:    defaultSMSClient(),
+	}
+}
+
+func (t *TencentSMS) Name() string {
+	return "tencent"
+}
+
+// SendSMS 腾讯云模板变量为有序数组，params 的 key 应为 "1"、"2"... 对应模板中的 {1}、{2}。
+// 号码应为 E.164 格式（如 +8613800000000），未设置 CountryCode 时其他格式返回 ErrInvalidPhone
+func (t *TencentSMS) SendSMS(ctx context.Context, phone, template string, params map[string]string) error {
+	if !strings.HasPrefix(phone, "+") {
+		if t.CountryCode == "" {
+			return fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+		}
+		phone = "+" + strings.TrimPrefix(t.CountryCode, "+") + phone
+	}
+
+	payload, err := json.Marshal(map[string]any{
+		"PhoneNumberSet":   []string{phone},
+		"SmsSdkAppId":      t.AppID,
+		"SignName":         t.SignName,
+		"TemplateId":       template,
+		"TemplateParamSet": orderedParams(params),
+	})
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(t.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("X-TC-Action", "SendSms")
+	req.Header.Set("X-TC-Version", "2021-01-11")
+	req.Header.Set("X-TC-Region", t.Region)
+	req.Header.Set("X-TC-Timestamp", strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set("Authorization", tc3Authorization(t.SecretID, t.SecretKey, "sms", u.Host, payload, now))
+
+	var resp struct {
+		Response struct {
+			Error *struct {
+				Code    string `json:"Code"`
+				Message string `json:"Message"`
+			} `json:"Error"`
+			SendStatusSet []struct {
+				Code    string `json:"Code"`
+				Message string `json:"Message"`
+			} `json:"SendStatusSet"`
+		} `json:"Response"`
+	}
+	if err := doRequest(t.Client, req, &resp); err != nil {
+		return err
+	}
+	if e := resp.Response.Error; e != nil {
+		return fmt.Errorf("notify: tencent sms %s: %s", e.Code, e.Message)
+	}
+	for _, s := range resp.Response.SendStatusSet {
+		if s.Code != "Ok" {
+			return fmt.Errorf("notify: tencent sms %s: %s", s.Code, s.Message)
+		}
+	}
+	return nil
+}
+
+func orderedParams(params map[string]string) []string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, errA := strconv.Atoi(keys[i])
+		b, errB := strconv.Atoi(keys[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+		return keys[i] < keys[j]
+	})
+
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, params[k])
+	}
+	return values
+}
+
+// tc3Authorization 腾讯云 API 3.0 TC3-HMAC-SHA256 签名
+func tc3Authorization(secretID, secretKey, service, host string, payload []byte, now time.Time) string {
+	date := now.Format("2006-01-02")
+	payloadHash := sha256.Sum256(payload)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodPost,
+		"/",
+		"",
+		"content-type:application/json; charset=utf-8\nhost:" + host + "\n",
+		"content-type;host",
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + service + "/tc3_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "TC3-HMAC-SHA256\n" + strconv.FormatInt(now.Unix(), 10) + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	secretDate := hmacSHA256([]byte("TC3"+secretKey), date)
+	secretService := hmacSHA256(secretDate, service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return "TC3-HMAC-SHA256 Credential=" + secretID + "/" + scope +
+		", SignedHeaders=content-type;host, Signature=" + signature
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/abs2free/go-kit/testkit"
+)
+
+func TestSMSPerNumberLimitWithTwilio(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "AC1" {
+			t.Errorf("expected basic auth user AC1, got %q", user)
+		}
+		_ = r.ParseForm()
+		bodies = append(bodies, r.PostForm.Get("Body"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"SM1","error_code":null}`))
+	}))
+	defer srv.Close()
+
+	twilio := NewTwilioSMS("AC1", "token", "+15550000")
+	twilio.Endpoint = srv.URL
+	if err := twilio.RegisterTemplate("verify", "Your code is {{.code}}"); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+
+	clock := testkit.Clock(t)
+	sms := NewSMS(twilio, WithNumberLimit(time.Minute, 1), WithSMSClock(clock))
+	ctx := context.Background()
+
+	if err := sms.SendSMS(ctx, "+15551111", "verify", map[string]string{"code": "123456"}); err != nil {
+		t.Fatalf("first send: %v", err)
+	}
+	if err := sms.SendSMS(ctx, "+15551111", "verify", map[string]string{"code": "654321"}); !errors.Is(err, ErrSMSRateLimited) {
+		t.Fatalf("expected ErrSMSRateLimited, got %v", err)
+	}
+	if err := sms.SendSMS(ctx, "+15552222", "verify", map[string]string{"code": "000000"}); err != nil {
+		t.Fatalf("other number should not be limited: %v", err)
+	}
+
+	// 推进时钟后额度恢复，无需真实等待
+	clock.Advance(time.Minute)
+	if err := sms.SendSMS(ctx, "+15551111", "verify", map[string]string{"code": "111111"}); err != nil {
+		t.Fatalf("send after window: %v", err)
+	}
+
+	if len(bodies) != 3 || bodies[0] != "Your code is 123456" {
+		t.Fatalf("unexpected bodies %v", bodies)
+	}
+}
+
+func TestSMSQuotaRefundedOnFailure(t *testing.T) {
+	fail := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"SM1","error_code":null}`))
+	}))
+	defer srv.Close()
+
+	twilio := NewTwilioSMS("AC1", "token", "+15550000")
+	twilio.Endpoint = srv.URL
+	_ = twilio.RegisterTemplate("verify", "{{.code}}")
+	sms := NewSMS(twilio, WithNumberLimit(time.Minute, 1))
+	ctx := context.Background()
+	code := map[string]string{"code": "1"}
+
+	if err := sms.SendSMS(ctx, "+15551111", "verify", code); err == nil || errors.Is(err, ErrSMSRateLimited) {
+		t.Fatalf("expected provider error, got %v", err)
+	}
+	// 失败的发送不占用额度
+	fail = false
+	if err := sms.SendSMS(ctx, "+15551111", "verify", code); err != nil {
+		t.Fatalf("retry after failure: %v", err)
+	}
+	if err := sms.SendSMS(ctx, "+15551111", "verify", code); !errors.Is(err, ErrSMSRateLimited) {
+		t.Fatalf("expected ErrSMSRateLimited, got %v", err)
+	}
+}
+
+// 官方文档的签名示例：阿里云短信 API 签名机制、腾讯云 API 3.0 签名方法 v3
+func TestSMSSignatureVectors(t *testing.T) {
+	q := url.Values{}
+	for k, v := range map[string]string{
+		"AccessKeyId":      "testId",
+		"Action":           "SendSms",
+		"Format":           "XML",
+		"OutId":            "123",
+		"PhoneNumbers":     "15300000001",
+		"RegionId":         "cn-hangzhou",
+		"SignName":         "阿里云短信测试专用",
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureNonce":   "45e25e9b-0a6f-4070-8c85-2956eda1b466",
+		"SignatureVersion": "1.0",
+		"TemplateCode":     "SMS_71390007",
+		"TemplateParam":    `{"customer":"test"}`,
+		"Timestamp":        "2017-07-12T02:42:19Z",
+		"Version":          "2017-05-25",
+	} {
+		q.Set(k, v)
+	}
+	if got := aliyunSignature(http.MethodGet, q, "testSecret"); got != "zJDF+Lrzhj/ThnlvIToysFRq6t4=" {
+		t.Fatalf("aliyun signature = %s", got)
+	}
+
+	payload := []byte(`{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`)
+	got := tc3Authorization("AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE", "Gu5t9xGARNpq86cd98joQYCN3EXAMPLE",
+		"cvm", "cvm.tencentcloudapi.com", payload, time.Unix(1551113065, 0).UTC())
+	want := "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE/2019-02-25/cvm/tc3_request, " +
+		"SignedHeaders=content-type;host, Signature=72e494ea809ad7a8c8f7a4507b9bddcbaa8e581f516e8da2f66e2c5a96525168"
+	if got != want {
+		t.Fatalf("tencent authorization = %s", got)
+	}
+}
+
+func TestTencentSMSRequiresE164(t *testing.T) {
+	var phones []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ PhoneNumberSet []string }
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		phones = append(phones, body.PhoneNumberSet...)
+		_, _ = w.Write([]byte(`{"Response":{"SendStatusSet":[{"Code":"Ok"}]}}`))
+	}))
+	defer srv.Close()
+
+	tencent := NewTencentSMS("id", "key", "app", "sign")
+	tencent.Endpoint = srv.URL
+	ctx := context.Background()
+
+	if err := tencent.SendSMS(ctx, "13800000000", "1", nil); !errors.Is(err, ErrInvalidPhone) {
+		t.Fatalf("expected ErrInvalidPhone, got %v", err)
+	}
+	if err := tencent.SendSMS(ctx, "+8613800000000", "1", nil); err != nil {
+		t.Fatalf("send e164: %v", err)
+	}
+	tencent.CountryCode = "+86"
+	if err := tencent.SendSMS(ctx, "13900000000", "1", nil); err != nil {
+		t.Fatalf("send with country code: %v", err)
+	}
+	if len(phones) != 2 || phones[0] != "+8613800000000" || phones[1] != "+8613900000000" {
+		t.Fatalf("unexpected phones %v", phones)
+	}
+}
+
+func TestOrderedParams(t *testing.T) {
+	got := orderedParams(map[string]string{"10": "c", "2": "b", "1": "a"})
+	if len(got) != 3 || got[0] != "a" || got[1] != "b" || got[2] != "c" {
+		t.Fatalf("unexpected order %v", got)
+	}
+}
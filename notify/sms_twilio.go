@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// TwilioSMS Twilio 短信服务。Twilio 没有服务端模板，
+// 模板在本地以 text/template 渲染，通过 RegisterTemplate 注册
+type TwilioSMS struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	Endpoint   string
+	Client     *http.Client
+
+	mu        sync.RWMutex
+	templates map[string]*template.Template
+}
+
+// NewTwilioSMS 创建 Twilio 短信服务商
+func NewTwilioSMS(accountSID, authToken, from string) *TwilioSMS {
+	return &TwilioSMS{
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		From:       from,
+		Endpoint:   "https://api.twilio.com",
+		Client:     defaultSMSClient(),
+		templates:  make(map[string]*template.Template),
+	}
+}
+
+func (t *TwilioSMS) Name() string {
+	return "twilio"
+}
+
+// RegisterTemplate 注册本地模板，例如 "Your code is {{.code}}"
+func (t *TwilioSMS) RegisterTemplate(name, text string) error {
+	tpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("notify: parse twilio template %s: %w", name, err)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.templates[name] = tpl
+	return nil
+}
+
+func (t *TwilioSMS) SendSMS(ctx context.Context, phone, name string, params map[string]string) error {
+	t.mu.RLock()
+	tpl := t.templates[name]
+	t.mu.RUnlock()
+	if tpl == nil {
+		return fmt.Errorf("notify: twilio template %s not registered", name)
+	}
+
+	var body bytes.Buffer
+	if err := tpl.Execute(&body, params); err != nil {
+		return fmt.Errorf("notify: render twilio template %s: %w", name, err)
+	}
+
+	form := url.Values{}
+	form.Set("To", phone)
+	form.Set("From", t.From)
+	form.Set("Body", body.String())
+
+	endpoint := t.Endpoint + "/2010-04-01/Accounts/" + t.AccountSID + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.AccountSID, t.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp struct {
+		ErrorCode    *int   `json:"error_code"`
+		ErrorMessage string `json:"error_message"`
+	}
+	if err := doRequest(t.Client, req, &resp); err != nil {
+		return err
+	}
+	if resp.ErrorCode != nil {
+		return fmt.Errorf("notify: twilio error %d: %s", *resp.ErrorCode, resp.ErrorMessage)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// Telegram Telegram Bot 通知
+type Telegram struct {
+	*robot
+	token  string
+	chatID string
+}
+
+// NewTelegram 创建 Telegram Bot 通知，chatID 可以是用户、群组或频道 ID
+func NewTelegram(token, chatID string, opts ...RobotOption) *Telegram {
+	opts = append([]RobotOption{WithEndpoint(telegramAPI), func(r *robot) {
+		// 同一群组每分钟最多 20 条
+		r.limiter = rate.NewLimiter(rate.Every(3*time.Second), 20)
+	}}, opts...)
+	return &Telegram{robot: newRobot("", opts), token: token, chatID: chatID}
+}
+
+func (t *Telegram) Name() string {
+	return "telegram"
+}
+
+func (t *Telegram) Send(ctx context.Context, msg Message) error {
+	payload := map[string]any{
+		"chat_id": t.chatID,
+		"text":    joinTitle(msg),
+	}
+
+	switch msg.Format {
+	case FormatMarkdown, FormatCard:
+		text := msg.Content
+		if msg.Title != "" {
+			text = "*" + msg.Title + "*\n" + text
+		}
+		payload["text"] = text
+		payload["parse_mode"] = "Markdown"
+		if msg.Format == FormatCard && msg.URL != "" {
+			payload["reply_markup"] = map[string]any{
+				"inline_keyboard": [][]any{{map[string]any{"text": "View", "url": msg.URL}}},
+			}
+		}
+	}
+
+	var resp telegramResponse
+	if err := t.post(ctx, t.method("sendMessage"), payload, &resp); err != nil {
+		return err
+	}
+	if err := resp.err("sendMessage"); err != nil {
+		return err
+	}
+
+	for _, a := range msg.Attachments {
+		if err := t.sendDocument(ctx, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Telegram) sendDocument(ctx context.Context, a Attachment) error {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	_ = mw.WriteField("chat_id", t.chatID)
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="document"; filename=%q`, a.Name))
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	header.Set("Content-Type", contentType)
+
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(a.Data); err != nil {
+		return err
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	if err := t.wait(ctx); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.method("sendDocument"), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var resp telegramResponse
+	if err := doRequest(t.client, req, &resp); err != nil {
+		return err
+	}
+	return resp.err("sendDocument")
+}
+
+func (t *Telegram) method(name string) string {
+	return t.webhook + "/bot" + t.token + "/" + name
+}
+
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	Description string `json:"description"`
+}
+
+func (r telegramResponse) err(method string) error {
+	if r.OK {
+		return nil
+	}
+	return fmt.Errorf("notify: telegram %s failed: %s", method, r.Description)
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Template 消息模板，标题与正文均使用 text/template 语法
+type Template struct {
+	title   *template.Template
+	content *template.Template
+	format  Format
+}
+
+// NewTemplate 解析消息模板
+func NewTemplate(title, content string, format Format) (*Template, error) {
+	t, err := template.New("title").Parse(title)
+	if err != nil {
+		return nil, fmt.Errorf("notify: parse title template: %w", err)
+	}
+	c, err := template.New("content").Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("notify: parse content template: %w", err)
+	}
+	return &Template{title: t, content: c, format: format}, nil
+}
+
+// MustTemplate 与 NewTemplate 相同，解析失败时 panic，适用于包级变量
+func MustTemplate(title, content string, format Format) *Template {
+	t, err := NewTemplate(title, content, format)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Render 使用数据渲染出消息
+func (t *Template) Render(data any) (Message, error) {
+	var title, content bytes.Buffer
+	if err := t.title.Execute(&title, data); err != nil {
+		return Message{}, fmt.Errorf("notify: render title: %w", err)
+	}
+	if err := t.content.Execute(&content, data); err != nil {
+		return Message{}, fmt.Errorf("notify: render content: %w", err)
+	}
+	return Message{Title: title.String(), Content: content.String(), Format: t.format}, nil
+}
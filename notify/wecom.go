@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// WeCom 企业微信群机器人，webhook 地址中的 key 即为凭证，不支持加签
+type WeCom struct {
+	*robot
+}
+
+// NewWeCom 创建企业微信机器人
+func NewWeCom(webhook string, opts ...RobotOption) *WeCom {
+	return &WeCom{robot: newRobot(webhook, opts)}
+}
+
+func (w *WeCom) Name() string {
+	return "wecom"
+}
+
+func (w *WeCom) Send(ctx context.Context, msg Message) error {
+	var payload map[string]any
+	switch msg.Format {
+	case FormatMarkdown:
+		content := msg.Content
+		if msg.Title != "" {
+			content = "## " + msg.Title + "\n" + content
+		}
+		payload = map[string]any{
+			"msgtype":  "markdown",
+			"markdown": map[string]any{"content": content},
+		}
+	case FormatCard:
+		payload = map[string]any{
+			"msgtype": "news",
+			"news": map[string]any{
+				"articles": []any{map[string]any{
+					"title":       msg.Title,
+					"description": msg.Content,
+					"url":         msg.URL,
+				}},
+			},
+		}
+	default:
+		mobiles := msg.AtMobiles
+		if msg.AtAll {
+			// 复制后再追加，避免写入调用方切片的底层数组
+			mobiles = append(slices.Clone(mobiles), "@all")
+		}
+		payload = map[string]any{
+			"msgtype": "text",
+			"text": map[string]any{
+				"content":               joinTitle(msg),
+				"mentioned_mobile_list": mobiles,
+			},
+		}
+	}
+
+	var resp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := w.post(ctx, w.webhook, payload, &resp); err != nil {
+		return err
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("notify: wecom error %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/abs2free/go-kit/i18n"
+	"github.com/abs2free/go-kit/mathutil"
+	"github.com/abs2free/go-kit/strutil"
+	"github.com/abs2free/go-kit/timeutil"
+)
+
+// Funcs 返回默认模板函数：
+//
+//	date      {{date .CreatedAt "2006-01-02"}}，layout 省略时为 2006-01-02 15:04:05
+//	duration  {{duration .Elapsed}}       -> 1h 5m
+//	money     {{money .Amount}}           -> CNY 12.34
+//	truncate  {{truncate .Title 20}}      按字符截断并追加 …
+//	t         {{t .L "welcome" "name" .Name}}，.L 为 *i18n.Localizer
+//	tn        {{tn .L "cart.items" .Count}}
+//	upper/lower/join/default
+func Funcs() map[string]any {
+	return map[string]any{
+		"date": func(t time.Time, layout ...string) string {
+			if t.IsZero() {
+				return ""
+			}
+			l := "2006-01-02 15:04:05"
+			if len(layout) > 0 {
+				l = layout[0]
+			}
+			return t.Format(l)
+		},
+		"duration": timeutil.Humanize,
+		"money": func(m mathutil.Money) string {
+			return m.Format()
+		},
+		"truncate": func(s string, n int) string {
+			return strutil.Truncate(s, n, "…")
+		},
+		"t": func(l *i18n.Localizer, id string, args ...any) string {
+			if l == nil {
+				return id
+			}
+			return l.T(id, args...)
+		},
+		"tn": func(l *i18n.Localizer, id string, count int, args ...any) string {
+			if l == nil {
+				return id
+			}
+			return l.N(id, count, args...)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+		"default": func(def, v any) any {
+			if v == nil || fmt.Sprint(v) == "" {
+				return def
+			}
+			return v
+		},
+	}
+}
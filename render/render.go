@@ -0,0 +1,196 @@
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"sync"
+	texttemplate "text/template"
+)
+
+var ErrNotFound = errors.New("render: template not found")
+
+type options struct {
+	layout   string
+	partials []string
+	funcs    map[string]any
+	dev      bool
+	text     bool
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithLayout 设置布局模板，页面通过 {{define "content"}} 等块填充布局；
+// 布局文件中以 {{template "content" .}} 引用
+func WithLayout(name string) Option {
+	return func(o *options) {
+		o.layout = name
+	}
+}
+
+// WithPartials 设置公共片段的 glob 模式（如 "partials/*.html"），与每个页面一起解析
+func WithPartials(patterns ...string) Option {
+	return func(o *options) {
+		o.partials = append(o.partials, patterns...)
+	}
+}
+
+// WithFuncs 追加模板函数，同名时覆盖 Funcs 中的默认函数
+func WithFuncs(funcs map[string]any) Option {
+	return func(o *options) {
+		for k, v := range funcs {
+			o.funcs[k] = v
+		}
+	}
+}
+
+// WithDevMode 开发模式：每次渲染都重新解析，修改模板文件无需重启（需配合 os.DirFS 使用）
+func WithDevMode(dev bool) Option {
+	return func(o *options) {
+		o.dev = dev
+	}
+}
+
+// WithText 使用 text/template，不做 HTML 转义，用于纯文本邮件、短信等
+func WithText() Option {
+	return func(o *options) {
+		o.text = true
+	}
+}
+
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+// Renderer 模板渲染器，解析结果按页面缓存
+//
+//	//go:embed templates
+//	var templates embed.FS
+//
+//	sub, _ := fs.Sub(templates, "templates")
+//	r := render.New(sub, render.WithLayout("layouts/base.html"), render.WithPartials("partials/*.html"))
+//	r.HTML(w, http.StatusOK, "pages/order.html", data)
+type Renderer struct {
+	fsys fs.FS
+	opts *options
+
+	mu    sync.RWMutex
+	cache map[string]executor
+}
+
+// New 创建渲染器
+func New(fsys fs.FS, opts ...Option) *Renderer {
+	o := &options{funcs: Funcs()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Renderer{fsys: fsys, opts: o, cache: make(map[string]executor)}
+}
+
+// Render 渲染页面到 w；设置了布局时执行布局模板，否则执行页面本身
+func (r *Renderer) Render(w io.Writer, name string, data any) error {
+	t, err := r.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	entry := path.Base(name)
+	if r.opts.layout != "" {
+		entry = path.Base(r.opts.layout)
+	}
+	// 先渲染到缓冲区，避免模板执行出错时输出半个页面
+	var buf bytes.Buffer
+	if err := t.ExecuteTemplate(&buf, entry, data); err != nil {
+		return fmt.Errorf("render: execute %s: %w", name, err)
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// RenderString 渲染为字符串，例如生成邮件正文
+func (r *Renderer) RenderString(name string, data any) (string, error) {
+	var b bytes.Buffer
+	if err := r.Render(&b, name, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// HTML 渲染并写入 HTTP 响应；渲染失败时返回 500 且不输出部分内容
+func (r *Renderer) HTML(w http.ResponseWriter, status int, name string, data any) error {
+	var b bytes.Buffer
+	if err := r.Render(&b, name, data); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	contentType := "text/html; charset=utf-8"
+	if r.opts.text {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, err := b.WriteTo(w)
+	return err
+}
+
+func (r *Renderer) lookup(name string) (executor, error) {
+	if !r.opts.dev {
+		r.mu.RLock()
+		t, ok := r.cache[name]
+		r.mu.RUnlock()
+		if ok {
+			return t, nil
+		}
+	}
+
+	t, err := r.parse(name)
+	if err != nil {
+		return nil, err
+	}
+	if !r.opts.dev {
+		r.mu.Lock()
+		r.cache[name] = t
+		r.mu.Unlock()
+	}
+	return t, nil
+}
+
+// parse 按 布局、片段、页面 的顺序解析，页面中的 define 覆盖布局中的默认块
+func (r *Renderer) parse(name string) (executor, error) {
+	if _, err := fs.Stat(r.fsys, name); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	var files []string
+	if r.opts.layout != "" {
+		files = append(files, r.opts.layout)
+	}
+	for _, pattern := range r.opts.partials {
+		matches, err := fs.Glob(r.fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("render: glob %s: %w", pattern, err)
+		}
+		files = append(files, matches...)
+	}
+	files = append(files, name)
+
+	var (
+		t   executor
+		err error
+	)
+	if r.opts.text {
+		t, err = texttemplate.New(path.Base(files[0])).Funcs(r.opts.funcs).ParseFS(r.fsys, files...)
+	} else {
+		t, err = htmltemplate.New(path.Base(files[0])).Funcs(r.opts.funcs).ParseFS(r.fsys, files...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("render: parse %s: %w", name, err)
+	}
+	return t, nil
+}
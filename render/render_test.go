@@ -0,0 +1,92 @@
+package render
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/abs2free/go-kit/i18n"
+	"github.com/abs2free/go-kit/mathutil"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html":    {Data: []byte(`<html><title>{{block "title" .}}默认标题{{end}}</title><body>{{template "content" .}}{{template "footer" .}}</body></html>`)},
+		"partials/footer.html": {Data: []byte(`{{define "footer"}}<footer>{{date .Now "2006"}}</footer>{{end}}`)},
+		"pages/order.html":     {Data: []byte(`{{define "title"}}订单 {{.ID}}{{end}}{{define "content"}}<p>{{.Note}}</p><p>{{money .Amount}}</p><p>{{t .L "welcome" "name" .Name}}</p>{{end}}`)},
+		"pages/broken.html":    {Data: []byte(`{{define "content"}}{{.Now.Missing}}{{end}}`)},
+		"mail/alert.txt":       {Data: []byte(`{{.Title}} <{{truncate .Body 5}}> 耗时 {{duration .Elapsed}}`)},
+	}
+}
+
+func TestRenderWithLayout(t *testing.T) {
+	bundle := i18n.NewBundle("zh-CN")
+	_ = bundle.Add("zh-CN", map[string]string{"welcome": "你好，{name}"})
+
+	r := New(testFS(), WithLayout("layouts/base.html"), WithPartials("partials/*.html"))
+	data := map[string]any{
+		"ID":     42,
+		"Note":   "<script>",
+		"Amount": mathutil.MustParse("9.9", mathutil.CNY),
+		"L":      bundle.Localizer("zh-CN"),
+		"Name":   "小明",
+		"Now":    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	rec := httptest.NewRecorder()
+	if err := r.HTML(rec, http.StatusOK, "pages/order.html", data); err != nil {
+		t.Fatal(err)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{"<title>订单 42</title>", "&lt;script&gt;", "CNY 9.90", "你好，小明", "<footer>2024</footer>"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in %s", want, body)
+		}
+	}
+	if rec.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", rec.Header().Get("Content-Type"))
+	}
+
+	rec = httptest.NewRecorder()
+	if err := r.HTML(rec, http.StatusOK, "pages/broken.html", data); err == nil {
+		t.Fatal("expected execute error")
+	}
+	if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "<html>") {
+		t.Fatalf("partial output written on error: %d %q", rec.Code, rec.Body.String())
+	}
+
+	if _, err := r.RenderString("pages/missing.html", nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRenderTextAndDevMode(t *testing.T) {
+	fsys := testFS()
+	r := New(fsys, WithText(), WithDevMode(true))
+
+	out, err := r.RenderString("mail/alert.txt", map[string]any{
+		"Title": "<告警>", "Body": "磁盘使用率超过阈值", "Elapsed": 90 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "<告警> <磁盘使用…> 耗时 1m 30s" {
+		t.Fatalf("unexpected text output %q", out)
+	}
+
+	fsys["mail/alert.txt"] = &fstest.MapFile{Data: []byte(`v2 {{.Title}}`)}
+	if out, _ := r.RenderString("mail/alert.txt", map[string]any{"Title": "x"}); out != "v2 x" {
+		t.Fatalf("dev mode did not reload: %q", out)
+	}
+
+	cached := New(fsys, WithText())
+	_, _ = cached.RenderString("mail/alert.txt", map[string]any{"Title": "x"})
+	fsys["mail/alert.txt"] = &fstest.MapFile{Data: []byte(`v3`)}
+	if out, _ := cached.RenderString("mail/alert.txt", map[string]any{"Title": "y"}); out != "v2 y" {
+		t.Fatalf("expected cached template, got %q", out)
+	}
+}
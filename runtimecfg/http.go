@@ -0,0 +1,104 @@
+package runtimecfg
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/abs2free/go-kit/ctxmeta"
+	"github.com/abs2free/go-kit/strutil"
+)
+
+// BearerToken 校验 Authorization: Bearer <token> 的中间件，token 为空时拒绝所有请求
+func BearerToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if token == "" || !ok || !strutil.SecureEqual(got, token) {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// BearerTokens 与 BearerToken 相同，每个操作人使用各自的 token（token -> 操作人），
+// 校验通过后将操作人写入 ctxmeta.UserID，Handler 以此记录审计日志
+func BearerTokens(tokens map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			operator := ""
+			for token, op := range tokens {
+				// 逐个比较，不因提前命中而泄露时间差
+				if token != "" && strutil.SecureEqual(got, token) {
+					operator = op
+				}
+			}
+			if !ok || operator == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctxmeta.WithUserID(r.Context(), operator)))
+		})
+	}
+}
+
+type setRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Handler 管理接口：GET 返回全部参数，POST {"name":"api.qps","value":"200"} 修改参数。
+// 必须提供认证中间件（如 BearerTokens、BearerToken 或 auth.Verifier.Middleware）。操作人取自认证中间件写入的身份
+// （ctxmeta.UserID），不信任客户端提供的请求头；没有身份时为客户端地址
+func (reg *Registry) Handler(authn func(http.Handler) http.Handler) http.Handler {
+	if authn == nil {
+		panic("runtimecfg: Handler requires an authentication middleware")
+	}
+	return authn(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, reg.List())
+		case http.MethodPost, http.MethodPut:
+			var req setRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+			if err := reg.Set(req.Name, req.Value, "http", operator(r)); err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, ErrUnknown) {
+					status = http.StatusNotFound
+				}
+				http.Error(w, err.Error(), status)
+				return
+			}
+			value, _ := reg.Get(req.Name)
+			writeJSON(w, http.StatusOK, setRequest{Name: req.Name, Value: value})
+		default:
+			w.Header().Set("Allow", "GET, POST, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func operator(r *http.Request) string {
+	if id := ctxmeta.UserID(r.Context()); id != "" {
+		return id
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
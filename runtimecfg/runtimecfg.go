@@ -0,0 +1,307 @@
+package runtimecfg
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+var (
+	ErrUnknown   = errors.New("runtimecfg: unknown tunable")
+	ErrDuplicate = errors.New("runtimecfg: tunable already registered")
+)
+
+// Var 可在运行时修改的值
+type Var interface {
+	// String 返回当前值的文本形式
+	String() string
+	// Set 解析并设置新值，解析或校验失败时返回错误且不修改当前值
+	Set(s string) error
+}
+
+type entry struct {
+	name  string
+	usage string
+	def   string
+	v     Var
+}
+
+type options struct {
+	log *zap.SugaredLogger
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithLogger 设置审计日志，默认使用全局日志 logger.S()
+func WithLogger(log *zap.SugaredLogger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+// Registry 运行时可调参数注册表，所有修改通过日志审计
+//
+//	cfg := runtimecfg.New()
+//	qps := cfg.Int("api.qps", 100, "每秒请求上限")
+//	cfg.Text("log.level", "日志级别", &atomicLevel)
+//	mux.Handle("/debug/tunables", cfg.Handler(runtimecfg.BearerToken(os.Getenv("ADMIN_TOKEN"))))
+//	...
+//	limiter.SetLimit(rate.Limit(qps.Get()))
+type Registry struct {
+	opts *options
+
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+// New 创建注册表
+func New(opts ...Option) *Registry {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Registry{opts: o, entries: make(map[string]*entry)}
+}
+
+// Default 进程级默认注册表
+var Default = New()
+
+func (r *Registry) log() *zap.SugaredLogger {
+	if r.opts.log != nil {
+		return r.opts.log
+	}
+	return logger.S()
+}
+
+// Var 注册自定义值，重名时 panic（属于编程错误，应在启动时暴露）
+func (r *Registry) Var(name, usage string, v Var) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.entries[name]; ok {
+		panic(fmt.Errorf("%w: %s", ErrDuplicate, name))
+	}
+	r.entries[name] = &entry{name: name, usage: usage, def: v.String(), v: v}
+}
+
+// Set 修改参数值，source 记录修改来源（如 "http"、"remote"），operator 记录操作人
+func (r *Registry) Set(name, value, source, operator string) error {
+	r.mu.RLock()
+	e, ok := r.entries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknown, name)
+	}
+
+	old := e.v.String()
+	if err := e.v.Set(value); err != nil {
+		r.log().Warnw("runtimecfg: rejected change", "name", name, "value", value,
+			"source", source, "operator", operator, "error", err)
+		return fmt.Errorf("runtimecfg: set %s: %w", name, err)
+	}
+	if cur := e.v.String(); cur != old {
+		r.log().Infow("runtimecfg: value changed", "name", name, "old", old, "new", cur,
+			"source", source, "operator", operator)
+	}
+	return nil
+}
+
+// Get 返回参数当前值的文本形式
+func (r *Registry) Get(name string) (string, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.entries[name]
+	if !ok {
+		return "", false
+	}
+	return e.v.String(), true
+}
+
+// Info 参数描述
+type Info struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Default string `json:"default"`
+	Usage   string `json:"usage,omitempty"`
+}
+
+// List 按名称排序返回全部参数
+func (r *Registry) List() []Info {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]Info, 0, len(r.entries))
+	for _, e := range r.entries {
+		list = append(list, Info{Name: e.name, Value: e.v.String(), Default: e.def, Usage: e.usage})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Watch 绑定远程配置：updates 中每条内容为 {"name": "value"} 形式的 JSON，
+// 可直接使用 discovery.Nacos.WatchConfig 的返回值；未知参数与非法值通过 onError 报告，其余照常生效
+func (r *Registry) Watch(ctx context.Context, updates <-chan string, onError func(error)) {
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case content, ok := <-updates:
+				if !ok {
+					return
+				}
+				var values map[string]any
+				if err := json.Unmarshal([]byte(content), &values); err != nil {
+					report(fmt.Errorf("runtimecfg: parse remote config: %w", err))
+					continue
+				}
+				for name, v := range values {
+					s, ok := v.(string)
+					if !ok {
+						b, _ := json.Marshal(v)
+						s = string(b)
+					}
+					if err := r.Set(name, s, "remote", ""); err != nil {
+						report(err)
+					}
+				}
+			}
+		}
+	}()
+}
+
+// Value 并发安全的类型化参数
+type Value[T any] struct {
+	mu       sync.RWMutex
+	v        T
+	parse    func(string) (T, error)
+	format   func(T) string
+	validate func(T) error
+	onChange []func(old, new T)
+}
+
+// Get 返回当前值
+func (v *Value[T]) Get() T {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.v
+}
+
+func (v *Value[T]) String() string {
+	return v.format(v.Get())
+}
+
+func (v *Value[T]) Set(s string) error {
+	n, err := v.parse(s)
+	if err != nil {
+		return err
+	}
+	if v.validate != nil {
+		if err := v.validate(n); err != nil {
+			return err
+		}
+	}
+
+	v.mu.Lock()
+	old := v.v
+	v.v = n
+	callbacks := v.onChange
+	v.mu.Unlock()
+
+	for _, fn := range callbacks {
+		fn(old, n)
+	}
+	return nil
+}
+
+// Validate 设置校验函数，返回 v 便于链式调用
+func (v *Value[T]) Validate(fn func(T) error) *Value[T] {
+	v.mu.Lock()
+	v.validate = fn
+	v.mu.Unlock()
+	return v
+}
+
+// OnChange 注册变更回调，例如修改限流器速率；回调在 Set 的调用方协程中同步执行
+func (v *Value[T]) OnChange(fn func(old, new T)) *Value[T] {
+	v.mu.Lock()
+	v.onChange = append(v.onChange, fn)
+	v.mu.Unlock()
+	return v
+}
+
+func register[T any](r *Registry, name, usage string, def T, parse func(string) (T, error), format func(T) string) *Value[T] {
+	v := &Value[T]{v: def, parse: parse, format: format}
+	r.Var(name, usage, v)
+	return v
+}
+
+// Int 注册整数参数
+func (r *Registry) Int(name string, def int64, usage string) *Value[int64] {
+	return register(r, name, usage, def,
+		func(s string) (int64, error) { return strconv.ParseInt(s, 10, 64) },
+		func(n int64) string { return strconv.FormatInt(n, 10) })
+}
+
+// Float 注册浮点参数
+func (r *Registry) Float(name string, def float64, usage string) *Value[float64] {
+	return register(r, name, usage, def,
+		func(s string) (float64, error) { return strconv.ParseFloat(s, 64) },
+		func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) })
+}
+
+// Bool 注册布尔参数，常用于功能开关
+func (r *Registry) Bool(name string, def bool, usage string) *Value[bool] {
+	return register(r, name, usage, def, strconv.ParseBool, strconv.FormatBool)
+}
+
+// String 注册字符串参数
+func (r *Registry) String(name, def, usage string) *Value[string] {
+	return register(r, name, usage, def,
+		func(s string) (string, error) { return s, nil },
+		func(s string) string { return s })
+}
+
+// Duration 注册时长参数，格式同 time.ParseDuration
+func (r *Registry) Duration(name string, def time.Duration, usage string) *Value[time.Duration] {
+	return register(r, name, usage, def, time.ParseDuration, time.Duration.String)
+}
+
+// textVar 适配实现了 TextMarshaler/TextUnmarshaler 的类型，如 *zap.AtomicLevel
+type textVar struct {
+	v interface {
+		encoding.TextMarshaler
+		encoding.TextUnmarshaler
+	}
+}
+
+func (t textVar) String() string {
+	b, _ := t.v.MarshalText()
+	return string(b)
+}
+
+func (t textVar) Set(s string) error {
+	return t.v.UnmarshalText([]byte(s))
+}
+
+// Text 注册实现了文本编解码的值，例如 Text("log.level", "日志级别", &atomicLevel)
+func (r *Registry) Text(name, usage string, v interface {
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}) {
+	r.Var(name, usage, textVar{v: v})
+}
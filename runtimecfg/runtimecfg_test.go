@@ -0,0 +1,128 @@
+package runtimecfg
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/testkit"
+)
+
+func TestRegistrySetAndAudit(t *testing.T) {
+	logs := testkit.Logger(t)
+	r := New()
+
+	qps := r.Int("api.qps", 100, "每秒请求上限").Validate(func(n int64) error {
+		if n <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	})
+	var changed [2]int64
+	qps.OnChange(func(old, new int64) { changed = [2]int64{old, new} })
+	level := zap.NewAtomicLevel()
+	r.Text("log.level", "日志级别", &level)
+
+	if err := r.Set("api.qps", "200", "http", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if qps.Get() != 200 || changed != [2]int64{100, 200} {
+		t.Fatalf("unexpected value %d, callback %v", qps.Get(), changed)
+	}
+	logs.AssertField("value changed", "operator", "alice")
+
+	if err := r.Set("api.qps", "-1", "http", "bob"); err == nil || qps.Get() != 200 {
+		t.Fatalf("expected validation error, got %v (value %d)", err, qps.Get())
+	}
+	logs.AssertLogged(zap.WarnLevel, "rejected change")
+
+	if err := r.Set("log.level", "debug", "http", ""); err != nil || level.Level() != zap.DebugLevel {
+		t.Fatalf("log level not applied: %v", err)
+	}
+	if err := r.Set("missing", "1", "http", ""); !errors.Is(err, ErrUnknown) {
+		t.Fatalf("expected ErrUnknown, got %v", err)
+	}
+
+	list := r.List()
+	if len(list) != 2 || list[0].Name != "api.qps" || list[0].Default != "100" || list[0].Value != "200" {
+		t.Fatalf("unexpected list %+v", list)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	testkit.Logger(t)
+	r := New()
+	enabled := r.Bool("checkout.v2", false, "")
+	h := r.Handler(BearerToken("secret"))
+
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodGet, "/").Build()).
+		AssertStatus(http.StatusUnauthorized)
+
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodPost, "/").
+		Header("Authorization", "Bearer secret").
+		JSON(map[string]string{"name": "checkout.v2", "value": "true"}).
+		Build()).
+		AssertStatus(http.StatusOK).
+		AssertJSON(`{"name":"checkout.v2","value":"true"}`)
+	if !enabled.Get() {
+		t.Fatal("toggle not applied")
+	}
+
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodPost, "/").
+		Header("Authorization", "Bearer secret").
+		JSON(map[string]string{"name": "nope", "value": "1"}).
+		Build()).
+		AssertStatus(http.StatusNotFound)
+
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodGet, "/").Header("Authorization", "Bearer secret").Build()).
+		AssertStatus(http.StatusOK).
+		AssertJSON(`[{"name":"checkout.v2","value":"true","default":"false"}]`)
+}
+
+func TestHandlerOperator(t *testing.T) {
+	logs := testkit.Logger(t)
+	r := New()
+	r.Bool("checkout.v2", false, "")
+	h := r.Handler(BearerTokens(map[string]string{"alice-token": "alice", "bob-token": "bob"}))
+
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodPost, "/").
+		Header("Authorization", "Bearer nope").
+		JSON(map[string]string{"name": "checkout.v2", "value": "true"}).
+		Build()).
+		AssertStatus(http.StatusUnauthorized)
+
+	// X-Operator 由客户端提供，不能冒充他人
+	testkit.Serve(t, h, testkit.NewRequest(http.MethodPost, "/").
+		Header("Authorization", "Bearer bob-token").
+		Header("X-Operator", "alice").
+		JSON(map[string]string{"name": "checkout.v2", "value": "true"}).
+		Build()).
+		AssertStatus(http.StatusOK)
+	logs.AssertField("value changed", "operator", "bob")
+}
+
+func TestWatch(t *testing.T) {
+	testkit.Logger(t)
+	r := New()
+	timeout := r.Duration("http.timeout", time.Second, "")
+
+	updates := make(chan string)
+	errs := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.Watch(ctx, updates, func(err error) { errs <- err })
+
+	updates <- `{"http.timeout": "3s", "unknown": 1}`
+	if err := <-errs; !errors.Is(err, ErrUnknown) {
+		t.Fatalf("expected ErrUnknown, got %v", err)
+	}
+	// 无缓冲通道，第二次发送成功说明第一条已处理完
+	updates <- `{}`
+	if timeout.Get() != 3*time.Second {
+		t.Fatalf("remote value not applied: %v", timeout.Get())
+	}
+}
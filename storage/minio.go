@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MinIO MinIO 驱动
+type MinIO struct {
+	core   *minio.Core
+	bucket string
+}
+
+var _ Storage = (*MinIO)(nil)
+
+// NewMinIO 创建 MinIO 驱动，Endpoint 为 host:port，不带 scheme
+func NewMinIO(cfg Config) (*MinIO, error) {
+	core, err := minio.NewCore(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: minio client: %w", err)
+	}
+	return &MinIO{core: core, bucket: cfg.Bucket}, nil
+}
+
+// Client 返回底层 SDK 客户端，用于通用接口未覆盖的操作
+func (s *MinIO) Client() *minio.Client {
+	return s.core.Client
+}
+
+func (s *MinIO) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error {
+	o := newPutOptions(opts)
+	_, err := s.core.Client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{ContentType: o.contentType})
+	if err != nil {
+		return fmt.Errorf("storage: minio put %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *MinIO) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.core.Client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, minioError("get", key, err)
+	}
+	// GetObject 延迟到首次读取才发请求，先 Stat 以便及时返回 ErrNotFound
+	if _, err := obj.Stat(); err != nil {
+		_ = obj.Close()
+		return nil, minioError("get", key, err)
+	}
+	return obj, nil
+}
+
+func (s *MinIO) Delete(ctx context.Context, key string) error {
+	if err := s.core.Client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		return minioError("delete", key, err)
+	}
+	return nil
+}
+
+func (s *MinIO) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	for obj := range s.core.Client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, fmt.Errorf("storage: minio list %s: %w", prefix, obj.Err)
+		}
+		objects = append(objects, ObjectInfo{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			ETag:         obj.ETag,
+			LastModified: obj.LastModified,
+		})
+	}
+	return objects, nil
+}
+
+func (s *MinIO) PresignURL(ctx context.Context, method, key string, expires time.Duration) (string, error) {
+	expires, err := checkPresign(method, expires)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := s.core.Client.Presign(ctx, method, s.bucket, key, expires, nil)
+	if err != nil {
+		return "", fmt.Errorf("storage: minio presign %s: %w", key, err)
+	}
+	return u.String(), nil
+}
+
+func (s *MinIO) CreateMultipart(ctx context.Context, key string, opts ...PutOption) (string, error) {
+	o := newPutOptions(opts)
+	uploadID, err := s.core.NewMultipartUpload(ctx, s.bucket, key, minio.PutObjectOptions{ContentType: o.contentType})
+	if err != nil {
+		return "", fmt.Errorf("storage: minio create multipart %s: %w", key, err)
+	}
+	return uploadID, nil
+}
+
+func (s *MinIO) UploadPart(ctx context.Context, key, uploadID string, number int, r io.Reader, size int64) (Part, error) {
+	part, err := s.core.PutObjectPart(ctx, s.bucket, key, uploadID, number, r, size, minio.PutObjectPartOptions{})
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: part.PartNumber, ETag: part.ETag}, nil
+}
+
+func (s *MinIO) CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error {
+	completed := make([]minio.CompletePart, 0, len(parts))
+	for _, p := range parts {
+		completed = append(completed, minio.CompletePart{PartNumber: p.Number, ETag: p.ETag})
+	}
+	if _, err := s.core.CompleteMultipartUpload(ctx, s.bucket, key, uploadID, completed, minio.PutObjectOptions{}); err != nil {
+		return fmt.Errorf("storage: minio complete multipart %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *MinIO) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	if err := s.core.AbortMultipartUpload(ctx, s.bucket, key, uploadID); err != nil {
+		return fmt.Errorf("storage: minio abort multipart %s: %w", key, err)
+	}
+	return nil
+}
+
+func minioError(op, key string, err error) error {
+	resp := minio.ToErrorResponse(err)
+	if resp.Code == "NoSuchKey" || resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return fmt.Errorf("storage: minio %s %s: %w", op, key, err)
+}
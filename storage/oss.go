@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aliyun/aliyun-oss-go-sdk/oss"
+)
+
+// OSS 阿里云对象存储驱动
+type OSS struct {
+	bucket *oss.Bucket
+}
+
+var _ Storage = (*OSS)(nil)
+
+// NewOSS 创建 OSS 驱动，Endpoint 例如 https://oss-cn-hangzhou.aliyuncs.com
+func NewOSS(cfg Config) (*OSS, error) {
+	client, err := oss.New(cfg.Endpoint, cfg.AccessKey, cfg.SecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("storage: oss client: %w", err)
+	}
+	bucket, err := client.Bucket(cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("storage: oss bucket %s: %w", cfg.Bucket, err)
+	}
+	return &OSS{bucket: bucket}, nil
+}
+
+// Bucket 返回底层 SDK Bucket，用于通用接口未覆盖的操作
+func (s *OSS) Bucket() *oss.Bucket {
+	return s.bucket
+}
+
+func (s *OSS) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error {
+	o := newPutOptions(opts)
+	options := []oss.Option{oss.WithContext(ctx), oss.ContentType(o.contentType)}
+	if size >= 0 {
+		options = append(options, oss.ContentLength(size))
+	}
+	if err := s.bucket.PutObject(key, r, options...); err != nil {
+		return fmt.Errorf("storage: oss put %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *OSS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	body, err := s.bucket.GetObject(key, oss.WithContext(ctx))
+	if err != nil {
+		return nil, ossError("get", key, err)
+	}
+	return body, nil
+}
+
+func (s *OSS) Delete(ctx context.Context, key string) error {
+	if err := s.bucket.DeleteObject(key, oss.WithContext(ctx)); err != nil {
+		return ossError("delete", key, err)
+	}
+	return nil
+}
+
+func (s *OSS) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var (
+		objects []ObjectInfo
+		token   string
+	)
+	for {
+		res, err := s.bucket.ListObjectsV2(oss.WithContext(ctx), oss.Prefix(prefix), oss.ContinuationToken(token))
+		if err != nil {
+			return nil, fmt.Errorf("storage: oss list %s: %w", prefix, err)
+		}
+		for _, obj := range res.Objects {
+			objects = append(objects, ObjectInfo{
+				Key:          obj.Key,
+				Size:         obj.Size,
+				ETag:         obj.ETag,
+				LastModified: obj.LastModified,
+			})
+		}
+		if !res.IsTruncated {
+			return objects, nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+func (s *OSS) PresignURL(_ context.Context, method, key string, expires time.Duration) (string, error) {
+	expires, err := checkPresign(method, expires)
+	if err != nil {
+		return "", err
+	}
+	url, err := s.bucket.SignURL(key, oss.HTTPMethod(method), int64(expires/time.Second))
+	if err != nil {
+		return "", fmt.Errorf("storage: oss presign %s: %w", key, err)
+	}
+	return url, nil
+}
+
+func (s *OSS) CreateMultipart(ctx context.Context, key string, opts ...PutOption) (string, error) {
+	o := newPutOptions(opts)
+	res, err := s.bucket.InitiateMultipartUpload(key, oss.WithContext(ctx), oss.ContentType(o.contentType))
+	if err != nil {
+		return "", fmt.Errorf("storage: oss create multipart %s: %w", key, err)
+	}
+	return res.UploadID, nil
+}
+
+func (s *OSS) imur(key, uploadID string) oss.InitiateMultipartUploadResult {
+	return oss.InitiateMultipartUploadResult{Bucket: s.bucket.BucketName, Key: key, UploadID: uploadID}
+}
+
+func (s *OSS) UploadPart(ctx context.Context, key, uploadID string, number int, r io.Reader, size int64) (Part, error) {
+	part, err := s.bucket.UploadPart(s.imur(key, uploadID), r, size, number, oss.WithContext(ctx))
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: part.PartNumber, ETag: part.ETag}, nil
+}
+
+func (s *OSS) CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error {
+	uploaded := make([]oss.UploadPart, 0, len(parts))
+	for _, p := range parts {
+		uploaded = append(uploaded, oss.UploadPart{PartNumber: p.Number, ETag: p.ETag})
+	}
+	if _, err := s.bucket.CompleteMultipartUpload(s.imur(key, uploadID), uploaded, oss.WithContext(ctx)); err != nil {
+		return fmt.Errorf("storage: oss complete multipart %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *OSS) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	if err := s.bucket.AbortMultipartUpload(s.imur(key, uploadID), oss.WithContext(ctx)); err != nil {
+		return fmt.Errorf("storage: oss abort multipart %s: %w", key, err)
+	}
+	return nil
+}
+
+func ossError(op, key string, err error) error {
+	var svcErr oss.ServiceError
+	if errors.As(err, &svcErr) && (svcErr.Code == "NoSuchKey" || svcErr.StatusCode == http.StatusNotFound) {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return fmt.Errorf("storage: oss %s %s: %w", op, key, err)
+}
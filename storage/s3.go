@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// S3 AWS S3 及 S3 兼容服务驱动
+type S3 struct {
+	client  *s3.Client
+	presign *s3.PresignClient
+	bucket  string
+}
+
+var _ Storage = (*S3)(nil)
+
+// NewS3 创建 S3 驱动，Endpoint 为空时使用 AWS 官方地址
+func NewS3(cfg Config) (*S3, error) {
+	opts := s3.Options{
+		Region:       cfg.Region,
+		UsePathStyle: cfg.PathStyle,
+	}
+	if cfg.AccessKey != "" {
+		opts.Credentials = credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, "")
+	} else {
+		opts.Credentials = aws.AnonymousCredentials{}
+	}
+	if cfg.Endpoint != "" {
+		opts.BaseEndpoint = aws.String(cfg.Endpoint)
+	}
+
+	client := s3.New(opts)
+	return &S3{client: client, presign: s3.NewPresignClient(client), bucket: cfg.Bucket}, nil
+}
+
+// Client 返回底层 SDK 客户端，用于通用接口未覆盖的操作
+func (s *S3) Client() *s3.Client {
+	return s.client
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error {
+	o := newPutOptions(opts)
+	in := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        r,
+		ContentType: aws.String(o.contentType),
+	}
+	if size >= 0 {
+		in.ContentLength = aws.Int64(size)
+	}
+	if _, err := s.client.PutObject(ctx, in); err != nil {
+		return fmt.Errorf("storage: s3 put %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	if err != nil {
+		return nil, s3Error("get", key, err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)})
+	if err != nil {
+		return s3Error("delete", key, err)
+	}
+	return nil
+}
+
+func (s *S3) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	p := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	})
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("storage: s3 list %s: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         aws.ToString(obj.ETag),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+func (s *S3) PresignURL(ctx context.Context, method, key string, expires time.Duration) (string, error) {
+	expires, err := checkPresign(method, expires)
+	if err != nil {
+		return "", err
+	}
+
+	var req *v4.PresignedHTTPRequest
+	if method == http.MethodGet {
+		req, err = s.presign.PresignGetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)},
+			s3.WithPresignExpires(expires))
+	} else {
+		req, err = s.presign.PresignPutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(s.bucket), Key: aws.String(key)},
+			s3.WithPresignExpires(expires))
+	}
+	if err != nil {
+		return "", fmt.Errorf("storage: s3 presign %s: %w", key, err)
+	}
+	return req.URL, nil
+}
+
+func (s *S3) CreateMultipart(ctx context.Context, key string, opts ...PutOption) (string, error) {
+	o := newPutOptions(opts)
+	out, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(o.contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("storage: s3 create multipart %s: %w", key, err)
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+func (s *S3) UploadPart(ctx context.Context, key, uploadID string, number int, r io.Reader, size int64) (Part, error) {
+	out, err := s.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(int32(number)),
+		Body:          r,
+		ContentLength: aws.Int64(size),
+	})
+	if err != nil {
+		return Part{}, err
+	}
+	return Part{Number: number, ETag: aws.ToString(out.ETag)}, nil
+}
+
+func (s *S3) CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, p := range parts {
+		completed = append(completed, types.CompletedPart{PartNumber: aws.Int32(int32(p.Number)), ETag: aws.String(p.ETag)})
+	}
+	_, err := s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return fmt.Errorf("storage: s3 complete multipart %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *S3) AbortMultipart(ctx context.Context, key, uploadID string) error {
+	_, err := s.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	if err != nil {
+		return fmt.Errorf("storage: s3 abort multipart %s: %w", key, err)
+	}
+	return nil
+}
+
+func s3Error(op, key string, err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NotFound":
+			return fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+	}
+	return fmt.Errorf("storage: s3 %s %s: %w", op, key, err)
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrNotFound          = errors.New("storage: object not found")
+	ErrUnsupportedDriver = errors.New("storage: unsupported driver")
+)
+
+// ObjectInfo 对象元信息
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// Part 已上传的分片
+type Part struct {
+	Number int
+	ETag   string
+}
+
+// Storage 对象存储通用接口，S3、OSS、MinIO 驱动均实现该接口
+type Storage interface {
+	// Put 上传对象，size 未知时传 -1
+	Put(ctx context.Context, key string, r io.Reader, size int64, opts ...PutOption) error
+	// Get 下载对象，对象不存在时返回 ErrNotFound
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// List 列出前缀下的全部对象
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// PresignURL 生成预签名地址，method 为 http.MethodGet 或 http.MethodPut；
+	// 签名有效期以秒为单位，expires 不足 1 秒的部分向上取整
+	PresignURL(ctx context.Context, method, key string, expires time.Duration) (string, error)
+
+	// 分片上传，通常通过 Upload 使用
+	CreateMultipart(ctx context.Context, key string, opts ...PutOption) (uploadID string, err error)
+	UploadPart(ctx context.Context, key, uploadID string, number int, r io.Reader, size int64) (Part, error)
+	CompleteMultipart(ctx context.Context, key, uploadID string, parts []Part) error
+	AbortMultipart(ctx context.Context, key, uploadID string) error
+}
+
+type putOptions struct {
+	contentType string
+}
+
+// PutOption 上传选项
+type PutOption func(*putOptions)
+
+// WithContentType 设置对象 Content-Type
+func WithContentType(contentType string) PutOption {
+	return func(o *putOptions) {
+		o.contentType = contentType
+	}
+}
+
+func newPutOptions(opts []PutOption) *putOptions {
+	o := &putOptions{contentType: "application/octet-stream"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// MinPartSize S3 协议要求除最后一个分片外每片至少 5MB
+const MinPartSize = 5 << 20
+
+// Upload 分片上传大文件，partSize 小于 MinPartSize 时按 MinPartSize 处理；
+// 任一分片失败时中止上传，避免服务端残留未完成的分片。r 为空时不能以零个分片完成上传，改为 Put 空对象
+func Upload(ctx context.Context, s Storage, key string, r io.Reader, partSize int64, opts ...PutOption) error {
+	if partSize < MinPartSize {
+		partSize = MinPartSize
+	}
+
+	br := bufio.NewReader(r)
+	if _, err := br.Peek(1); err == io.EOF {
+		return s.Put(ctx, key, bytes.NewReader(nil), 0, opts...)
+	} else if err != nil {
+		return fmt.Errorf("storage: read %s: %w", key, err)
+	}
+	r = br
+
+	uploadID, err := s.CreateMultipart(ctx, key, opts...)
+	if err != nil {
+		return err
+	}
+
+	parts, err := uploadParts(ctx, s, key, uploadID, r, partSize)
+	if err == nil {
+		err = s.CompleteMultipart(ctx, key, uploadID, parts)
+	}
+	if err != nil {
+		if abortErr := s.AbortMultipart(context.WithoutCancel(ctx), key, uploadID); abortErr != nil {
+			return errors.Join(err, abortErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func uploadParts(ctx context.Context, s Storage, key, uploadID string, r io.Reader, partSize int64) ([]Part, error) {
+	var parts []Part
+	buf := make([]byte, partSize)
+	for number := 1; ; number++ {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			part, uerr := s.UploadPart(ctx, key, uploadID, number, bytes.NewReader(buf[:n]), int64(n))
+			if uerr != nil {
+				return nil, fmt.Errorf("storage: upload part %d of %s: %w", number, key, uerr)
+			}
+			parts = append(parts, part)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("storage: read part %d of %s: %w", number, key, err)
+		}
+	}
+}
+
+// checkPresign 校验预签名参数，返回向上取整到秒的有效期
+func checkPresign(method string, expires time.Duration) (time.Duration, error) {
+	if method != http.MethodGet && method != http.MethodPut {
+		return 0, fmt.Errorf("storage: unsupported presign method %q", method)
+	}
+	if expires <= 0 {
+		return 0, fmt.Errorf("storage: presign expires must be positive, got %s", expires)
+	}
+	return (expires + time.Second - 1).Truncate(time.Second), nil
+}
+
+// Config 对象存储配置
+type Config struct {
+	// Driver 驱动：s3、oss、minio
+	Driver    string `json:"driver" yaml:"driver"`
+	Endpoint  string `json:"endpoint" yaml:"endpoint"`
+	Region    string `json:"region" yaml:"region"`
+	Bucket    string `json:"bucket" yaml:"bucket"`
+	AccessKey string `json:"access_key" yaml:"access_key"`
+	SecretKey string `json:"secret_key" yaml:"secret_key"`
+	// UseSSL 仅 MinIO 使用
+	UseSSL bool `json:"use_ssl" yaml:"use_ssl"`
+	// PathStyle S3 兼容服务（如自建 Ceph）通常需要开启
+	PathStyle bool `json:"path_style" yaml:"path_style"`
+}
+
+// New 根据配置创建对应驱动
+func New(cfg Config) (Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("storage: bucket is required")
+	}
+
+	switch cfg.Driver {
+	case "s3":
+		return NewS3(cfg)
+	case "oss":
+		return NewOSS(cfg)
+	case "minio":
+		return NewMinIO(cfg)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDriver, cfg.Driver)
+	}
+}
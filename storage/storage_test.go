@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	neturl "net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memStorage 记录分片上传过程的内存实现
+type memStorage struct {
+	Storage
+	parts     map[int][]byte
+	completed []Part
+	aborted   bool
+	failPart  int
+	put       map[string][]byte
+}
+
+func (m *memStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ ...PutOption) error {
+	data, _ := io.ReadAll(r)
+	if m.put == nil {
+		m.put = map[string][]byte{}
+	}
+	m.put[key] = data
+	return nil
+}
+
+func (m *memStorage) CreateMultipart(context.Context, string, ...PutOption) (string, error) {
+	m.parts = map[int][]byte{}
+	return "upload-1", nil
+}
+
+func (m *memStorage) UploadPart(_ context.Context, _, _ string, number int, r io.Reader, _ int64) (Part, error) {
+	if number == m.failPart {
+		return Part{}, errors.New("boom")
+	}
+	data, _ := io.ReadAll(r)
+	m.parts[number] = data
+	return Part{Number: number, ETag: "etag-" + strconv.Itoa(number)}, nil
+}
+
+func (m *memStorage) CompleteMultipart(_ context.Context, _, _ string, parts []Part) error {
+	m.completed = parts
+	return nil
+}
+
+func (m *memStorage) AbortMultipart(context.Context, string, string) error {
+	m.aborted = true
+	return nil
+}
+
+func TestUpload(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), MinPartSize*2+10)
+
+	m := &memStorage{}
+	if err := Upload(context.Background(), m, "big.bin", bytes.NewReader(data), 1); err != nil {
+		t.Fatalf("upload: %v", err)
+	}
+	if len(m.completed) != 3 || len(m.parts[3]) != 10 || m.aborted {
+		t.Fatalf("unexpected parts: %d completed, last part %d bytes", len(m.completed), len(m.parts[3]))
+	}
+
+	m = &memStorage{failPart: 2}
+	if err := Upload(context.Background(), m, "big.bin", bytes.NewReader(data), MinPartSize); err == nil {
+		t.Fatalf("expected error")
+	}
+	if !m.aborted || m.completed != nil {
+		t.Fatalf("failed upload should be aborted")
+	}
+
+	// 空对象不走分片上传
+	m = &memStorage{}
+	if err := Upload(context.Background(), m, "empty.txt", strings.NewReader(""), MinPartSize); err != nil {
+		t.Fatalf("upload empty: %v", err)
+	}
+	if data, ok := m.put["empty.txt"]; !ok || len(data) != 0 || m.parts != nil {
+		t.Fatalf("expected empty Put without multipart, got put=%v parts=%v", m.put, m.parts)
+	}
+}
+
+func TestNewUnsupportedDriver(t *testing.T) {
+	if _, err := New(Config{Driver: "ftp", Bucket: "b"}); !errors.Is(err, ErrUnsupportedDriver) {
+		t.Fatalf("expected ErrUnsupportedDriver, got %v", err)
+	}
+}
+
+// fakeS3 path-style 的最小 S3 服务，OSS 与 MinIO 的对象接口与其兼容
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/bucket" || r.URL.Path == "/bucket/" {
+		f.list(w, r.URL.Query().Get("prefix"))
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			data = decodeChunked(data)
+		}
+		f.objects[key] = data
+		w.Header().Set("ETag", `"etag"`)
+	case http.MethodGet, http.MethodHead:
+		data, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method == http.MethodGet {
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`))
+			}
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// decodeChunked 解出 aws-chunked 编码（MinIO 在 HTTP 下使用流式签名）的数据，
+// 每块格式为 "<十六进制长度>;chunk-signature=...\r\n<数据>\r\n"
+func decodeChunked(body []byte) []byte {
+	var data []byte
+	for len(body) > 0 {
+		header, rest, _ := bytes.Cut(body, []byte("\r\n"))
+		size, _ := strconv.ParseInt(string(bytes.SplitN(header, []byte(";"), 2)[0]), 16, 64)
+		if size == 0 || int64(len(rest)) < size {
+			break
+		}
+		data = append(data, rest[:size]...)
+		body = bytes.TrimPrefix(rest[size:], []byte("\r\n"))
+	}
+	return data
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, prefix string) {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>bucket</Name><IsTruncated>false</IsTruncated>`)
+	keys := make([]string, 0, len(f.objects))
+	for k := range f.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, `<Contents><Key>%s</Key><Size>%d</Size><ETag>"etag"</ETag><LastModified>2024-01-01T00:00:00.000Z</LastModified></Contents>`,
+			k, len(f.objects[k]))
+	}
+	fmt.Fprintf(&b, `<KeyCount>%d</KeyCount></ListBucketResult>`, len(keys))
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+func TestDrivers(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	defer srv.Close()
+
+	cases := []struct {
+		cfg Config
+		// expiresParam 预签名地址中的有效期参数
+		expiresParam string
+	}{
+		{Config{Driver: "s3", Endpoint: srv.URL, PathStyle: true}, "X-Amz-Expires="},
+		// OSS 对 IP 形式的 Endpoint 使用 path-style
+		{Config{Driver: "oss", Endpoint: srv.URL}, "Expires="},
+		{Config{Driver: "minio", Endpoint: strings.TrimPrefix(srv.URL, "http://")}, "X-Amz-Expires="},
+	}
+	for _, c := range cases {
+		t.Run(c.cfg.Driver, func(t *testing.T) {
+			c.cfg.Region, c.cfg.Bucket, c.cfg.AccessKey, c.cfg.SecretKey = "us-east-1", "bucket", "ak", "sk"
+			s, err := New(c.cfg)
+			if err != nil {
+				t.Fatalf("new: %v", err)
+			}
+			testDriver(t, s, c.expiresParam)
+		})
+	}
+}
+
+func testDriver(t *testing.T, s Storage, expiresParam string) {
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "a/b.txt", strings.NewReader("hello"), 5, WithContentType("text/plain")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	body, err := s.Get(ctx, "a/b.txt")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	_ = body.Close()
+	if string(data) != "hello" {
+		t.Fatalf("unexpected body %q", data)
+	}
+
+	objects, err := s.List(ctx, "a/")
+	if err != nil || len(objects) != 1 || objects[0].Key != "a/b.txt" || objects[0].Size != 5 {
+		t.Fatalf("unexpected list %+v: %v", objects, err)
+	}
+
+	if err := s.Delete(ctx, "a/b.txt"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := s.Get(ctx, "a/b.txt"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	url, err := s.PresignURL(ctx, http.MethodGet, "a/b.txt", time.Minute)
+	if err != nil || !strings.Contains(url, "Signature=") || !strings.Contains(url, expiresParam) {
+		t.Fatalf("unexpected presign url %q: %v", url, err)
+	}
+	if _, err := s.PresignURL(ctx, http.MethodDelete, "a/b.txt", time.Minute); err == nil {
+		t.Fatal("expected unsupported method error")
+	}
+	if _, err := s.PresignURL(ctx, http.MethodGet, "a/b.txt", 0); err == nil {
+		t.Fatal("expected error for non-positive expires")
+	}
+}
+
+func TestPresignExpiresRoundsUp(t *testing.T) {
+	s, err := NewOSS(Config{Endpoint: "http://127.0.0.1:9000", Bucket: "bucket", AccessKey: "ak", SecretKey: "sk"})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	before := time.Now().Unix()
+	url, err := s.PresignURL(context.Background(), http.MethodGet, "a.txt", 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("presign: %v", err)
+	}
+	// 不足 1 秒的有效期不能被截断为 0 导致地址立即过期
+	u, _ := neturl.Parse(url)
+	expires, _ := strconv.ParseInt(u.Query().Get("Expires"), 10, 64)
+	if expires <= before {
+		t.Fatalf("expected expiry after %d, got %q", before, url)
+	}
+
+	m, err := NewMinIO(Config{Endpoint: "127.0.0.1:9000", Region: "us-east-1", Bucket: "bucket", AccessKey: "ak", SecretKey: "sk"})
+	if err != nil {
+		t.Fatalf("new minio: %v", err)
+	}
+	url, err = m.PresignURL(context.Background(), http.MethodGet, "a.txt", 1500*time.Millisecond)
+	if err != nil || !strings.Contains(url, "X-Amz-Expires=2&") {
+		t.Fatalf("expected 2s expiry, got %q: %v", url, err)
+	}
+}
@@ -0,0 +1,80 @@
+package strutil
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// 常用字母表
+const (
+	Digits       = "0123456789"
+	LowerLetters = "abcdefghijklmnopqrstuvwxyz"
+	UpperLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Alphanumeric = Digits + LowerLetters + UpperLetters
+	// Readable 去掉易混淆字符 0/O、1/l/I 的字母表，适合邀请码、短信验证码等需要人工输入的场景
+	Readable = "23456789abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
+)
+
+var (
+	// ErrInvalidAlphabet 字母表少于 2 个字符或含重复字符（重复字符会使分布不均匀）
+	ErrInvalidAlphabet = errors.New("strutil: alphabet must contain at least 2 distinct characters")
+	// ErrInvalidLength 长度为负数
+	ErrInvalidLength = errors.New("strutil: length must not be negative")
+)
+
+// Random 使用 crypto/rand 从 alphabet 中均匀选取 n 个字符（按 rune 计），alphabet 不能含重复字符
+func Random(n int, alphabet string) (string, error) {
+	if n < 0 {
+		return "", ErrInvalidLength
+	}
+	chars := []rune(alphabet)
+	if len(chars) < 2 {
+		return "", ErrInvalidAlphabet
+	}
+	seen := make(map[rune]struct{}, len(chars))
+	for _, c := range chars {
+		if _, ok := seen[c]; ok {
+			return "", ErrInvalidAlphabet
+		}
+		seen[c] = struct{}{}
+	}
+
+	max := big.NewInt(int64(len(chars)))
+	out := make([]rune, n)
+	for i := range out {
+		// rand.Int 内部使用拒绝采样，不存在取模偏差
+		idx, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("strutil: read random: %w", err)
+		}
+		out[i] = chars[idx.Int64()]
+	}
+	return string(out), nil
+}
+
+// MustRandom 同 Random，出错时 panic
+func MustRandom(n int, alphabet string) string {
+	s, err := Random(n, alphabet)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Token 生成 nBytes 字节随机数的 URL 安全 base64 编码（无填充），可用作会话 ID、重置密码令牌等
+func Token(nBytes int) (string, error) {
+	b := make([]byte, nBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("strutil: read random: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// SecureEqual 常量时间比较两个字符串，用于校验令牌、签名等秘密值，避免时序攻击
+func SecureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
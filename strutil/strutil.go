@@ -0,0 +1,104 @@
+package strutil
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Truncate 按字符（rune）截断到最多 max 个字符，超出时以 ellipsis 结尾且总长度不超过 max，
+// 不会截断半个汉字或 emoji
+func Truncate(s string, max int, ellipsis string) string {
+	if max <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+
+	keep := max - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		// 省略号本身已超过长度限制，直接截断
+		return string([]rune(s)[:max])
+	}
+	return string([]rune(s)[:keep]) + ellipsis
+}
+
+// Reverse 按字符反转
+func Reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+// words 将标识符拆分为单词：支持 snake_case、kebab-case、空格分隔以及 camelCase，
+// 连续大写视为缩写，HTTPServer -> [HTTP Server]
+func words(s string) []string {
+	var (
+		out []string
+		cur []rune
+	)
+	flush := func() {
+		if len(cur) > 0 {
+			out = append(out, string(cur))
+			cur = cur[:0]
+		}
+	}
+
+	runes := []rune(s)
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == '.' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(cur) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return out
+}
+
+// SnakeCase 转为 snake_case，如 UserID -> user_id
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "_"))
+}
+
+// KebabCase 转为 kebab-case
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(words(s), "-"))
+}
+
+// CamelCase 转为 lowerCamelCase，如 user_id -> userId
+func CamelCase(s string) string {
+	ws := words(s)
+	for i, w := range ws {
+		if i == 0 {
+			ws[i] = strings.ToLower(w)
+			continue
+		}
+		ws[i] = capitalize(w)
+	}
+	return strings.Join(ws, "")
+}
+
+// PascalCase 转为 UpperCamelCase，如 user_id -> UserId
+func PascalCase(s string) string {
+	ws := words(s)
+	for i, w := range ws {
+		ws[i] = capitalize(w)
+	}
+	return strings.Join(ws, "")
+}
+
+func capitalize(w string) string {
+	r, size := utf8.DecodeRuneInString(w)
+	return string(unicode.ToUpper(r)) + strings.ToLower(w[size:])
+}
@@ -0,0 +1,86 @@
+package strutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandom(t *testing.T) {
+	s, err := Random(32, Readable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 32 || strings.ContainsAny(s, "0O1lI") {
+		t.Fatalf("unexpected random string %q", s)
+	}
+	if zh := MustRandom(4, "甲乙丙丁"); len([]rune(zh)) != 4 {
+		t.Fatalf("unexpected rune count in %q", zh)
+	}
+	for _, alphabet := range []string{"a", "aab", "甲乙甲"} {
+		if _, err := Random(8, alphabet); err != ErrInvalidAlphabet {
+			t.Fatalf("%q: expected ErrInvalidAlphabet, got %v", alphabet, err)
+		}
+	}
+	if _, err := Random(-1, Readable); err != ErrInvalidLength {
+		t.Fatalf("expected ErrInvalidLength, got %v", err)
+	}
+	if s, err := Random(0, Readable); err != nil || s != "" {
+		t.Fatalf("unexpected empty result %q %v", s, err)
+	}
+
+	tok, _ := Token(32)
+	if len(tok) != 43 || strings.ContainsAny(tok, "+/=") {
+		t.Fatalf("unexpected token %q", tok)
+	}
+	if !SecureEqual(tok, tok) || SecureEqual(tok, tok[:10]) {
+		t.Fatal("SecureEqual mismatch")
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		in       string
+		max      int
+		ellipsis string
+		want     string
+	}{
+		{"你好世界欢迎你", 5, "...", "你好..."},
+		{"hello", 5, "…", "hello"},
+		{"hello world", 6, "…", "hello…"},
+		{"👍👍👍", 2, "", "👍👍"},
+		{"abcdef", 2, "...", "ab"},
+	}
+	for _, c := range cases {
+		if got := Truncate(c.in, c.max, c.ellipsis); got != c.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", c.in, c.max, got, c.want)
+		}
+	}
+	if Reverse("ab中文") != "文中ba" {
+		t.Fatal("reverse")
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	cases := []struct {
+		in, snake, kebab, camel, pascal string
+	}{
+		{"UserID", "user_id", "user-id", "userId", "UserId"},
+		{"HTTPServerError", "http_server_error", "http-server-error", "httpServerError", "HttpServerError"},
+		{"order_item-count", "order_item_count", "order-item-count", "orderItemCount", "OrderItemCount"},
+		{"getV2Api", "get_v2_api", "get-v2-api", "getV2Api", "GetV2Api"},
+	}
+	for _, c := range cases {
+		if got := SnakeCase(c.in); got != c.snake {
+			t.Errorf("SnakeCase(%q) = %q", c.in, got)
+		}
+		if got := KebabCase(c.in); got != c.kebab {
+			t.Errorf("KebabCase(%q) = %q", c.in, got)
+		}
+		if got := CamelCase(c.in); got != c.camel {
+			t.Errorf("CamelCase(%q) = %q", c.in, got)
+		}
+		if got := PascalCase(c.in); got != c.pascal {
+			t.Errorf("PascalCase(%q) = %q", c.in, got)
+		}
+	}
+}
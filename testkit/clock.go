@@ -0,0 +1,41 @@
+package testkit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/abs2free/go-kit/timeutil"
+)
+
+// ClockStart Clock 的初始时间，固定值保证测试输出可复现
+var ClockStart = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// FakeClock 测试用可控时钟，基于 timeutil.Fake，可传给接受 timeutil.Clock 的组件：
+// notify.WithClock（去重窗口与限流）、notify.WithSMSClock（号码限流）与 monitor.Clock。
+// 本仓库没有定时调度与缓存包，其他组件需自行依赖 timeutil.Clock 后才能接入
+//
+//	clock := testkit.Clock(t)
+//	chain := notify.NewChain(ns, notify.WithDedup(time.Minute), notify.WithClock(clock))
+//	clock.Advance(2 * time.Minute)
+type FakeClock struct {
+	*timeutil.Fake
+	t testing.TB
+}
+
+// Clock 创建从 ClockStart 开始的可控时钟
+func Clock(t testing.TB) *FakeClock {
+	return &FakeClock{Fake: timeutil.NewFake(ClockStart), t: t}
+}
+
+// WaitForWaiters 等待至少 n 个定时器处于等待状态，用于确认被测协程已阻塞在 After/Ticker 上再推进时间；
+// 5 秒内未满足则测试失败
+func (c *FakeClock) WaitForWaiters(n int) {
+	c.t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Waiters() < n {
+		if time.Now().After(deadline) {
+			c.t.Fatalf("testkit: timed out waiting for %d clock waiters, have %d", n, c.Waiters())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
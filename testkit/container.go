@@ -0,0 +1,182 @@
+package testkit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os/exec"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Container 测试用的一次性 Docker 容器，测试结束时自动删除。
+// 通过 docker 命令行管理，没有使用 testcontainers-go：为避免给所有依赖本模块的项目引入
+// Docker SDK 及其依赖树，只要求测试机器安装 docker；本机没有 docker 时跳过测试。
+// 本仓库没有 Redis、Kafka 客户端包，StartRedis、StartKafka 只返回连接地址，由调用方自行创建客户端；
+// MySQL 可通过 MySQL.DB 得到 db 包包装的 *sql.DB
+type Container struct {
+	t  testing.TB
+	ID string
+}
+
+type containerOptions struct {
+	image   string
+	env     map[string]string
+	timeout time.Duration
+}
+
+// ContainerOption 容器配置选项
+type ContainerOption func(*containerOptions)
+
+// WithImage 替换默认镜像
+func WithImage(image string) ContainerOption {
+	return func(o *containerOptions) {
+		o.image = image
+	}
+}
+
+// WithEnv 设置容器环境变量
+func WithEnv(key, value string) ContainerOption {
+	return func(o *containerOptions) {
+		o.env[key] = value
+	}
+}
+
+// WithStartupTimeout 设置等待容器就绪的超时时间，默认 2 分钟
+func WithStartupTimeout(d time.Duration) ContainerOption {
+	return func(o *containerOptions) {
+		o.timeout = d
+	}
+}
+
+func newContainerOptions(image string, env map[string]string, opts []ContainerOption) *containerOptions {
+	o := &containerOptions{image: image, env: env, timeout: 2 * time.Minute}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// dockerRunArgs 生成 docker run 参数，ports 为 "hostPort:containerPort" 或仅 containerPort（随机映射）
+func dockerRunArgs(o *containerOptions, ports []string, cmd ...string) []string {
+	args := []string{"run", "-d", "--rm", "--label", "org.abs2free.testkit=true"}
+	for _, p := range ports {
+		if !strings.Contains(p, ":") {
+			p = "127.0.0.1::" + p
+		} else {
+			p = "127.0.0.1:" + p
+		}
+		args = append(args, "-p", p)
+	}
+	keys := make([]string, 0, len(o.env))
+	for k := range o.env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		args = append(args, "-e", k+"="+o.env[k])
+	}
+	args = append(args, o.image)
+	return append(args, cmd...)
+}
+
+func docker(ctx context.Context, stdin []byte, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("docker %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// startContainer 启动容器并注册清理，ready 返回 nil 前持续重试直到超时
+func startContainer(t testing.TB, o *containerOptions, ports []string, ready func(ctx context.Context, c *Container) error, cmd ...string) *Container {
+	t.Helper()
+	if _, err := exec.LookPath("docker"); err != nil {
+		t.Skip("testkit: docker not available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	defer cancel()
+
+	out, err := docker(ctx, nil, dockerRunArgs(o, ports, cmd...)...)
+	if err != nil {
+		t.Fatalf("testkit: start %s: %v", o.image, err)
+	}
+	c := &Container{t: t, ID: strings.TrimSpace(out)}
+	t.Cleanup(func() {
+		_, _ = docker(context.Background(), nil, "rm", "-f", "-v", c.ID)
+	})
+
+	var lastErr error
+	for {
+		if lastErr = ready(ctx, c); lastErr == nil {
+			return c
+		}
+		select {
+		case <-ctx.Done():
+			logs, _ := docker(context.Background(), nil, "logs", "--tail", "50", c.ID)
+			t.Fatalf("testkit: %s not ready after %s: %v\n%s", o.image, o.timeout, lastErr, logs)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// HostPort 返回容器端口映射到宿主机的地址，如 "127.0.0.1:49153"
+func (c *Container) HostPort(port string) string {
+	c.t.Helper()
+	out, err := docker(context.Background(), nil, "port", c.ID, port)
+	if err != nil {
+		c.t.Fatalf("testkit: resolve port %s: %v", port, err)
+	}
+	// 可能输出多行（IPv4/IPv6），取第一行
+	return parsePortOutput(out)
+}
+
+func parsePortOutput(out string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	host, port, err := net.SplitHostPort(strings.TrimSpace(line))
+	if err != nil {
+		return strings.TrimSpace(line)
+	}
+	if host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// Exec 在容器内执行命令，失败时终止测试
+func (c *Container) Exec(stdin []byte, cmd ...string) string {
+	c.t.Helper()
+	out, err := c.exec(context.Background(), stdin, cmd...)
+	if err != nil {
+		c.t.Fatalf("testkit: exec %v: %v", cmd, err)
+	}
+	return out
+}
+
+func (c *Container) exec(ctx context.Context, stdin []byte, cmd ...string) (string, error) {
+	args := []string{"exec"}
+	if stdin != nil {
+		args = append(args, "-i")
+	}
+	args = append(append(args, c.ID), cmd...)
+	return docker(ctx, stdin, args...)
+}
+
+// dialReady 检查端口可连接
+func dialReady(ctx context.Context, addr string) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
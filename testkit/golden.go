@@ -0,0 +1,34 @@
+package testkit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Golden 将 got 与 testdata/<name>.golden 比较。
+// 设置环境变量 UPDATE_GOLDEN=1 运行 go test 时写入 got 作为新的期望值；
+// 不注册 -update 命令行参数，避免与调用方或其他库的同名 flag 冲突
+func Golden(t testing.TB, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+
+	if os.Getenv("UPDATE_GOLDEN") == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("testkit: create testdata: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("testkit: write golden: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("testkit: read golden %s: %v (run with UPDATE_GOLDEN=1 to create it)", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("golden %s mismatch\n got: %s\nwant: %s", path, got, want)
+	}
+}
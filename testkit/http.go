@@ -0,0 +1,164 @@
+package testkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// RequestBuilder 构造测试请求
+//
+//	req := testkit.NewRequest(http.MethodPost, "/users").Query("dry_run", "1").JSON(body).Build()
+//	resp := testkit.Serve(t, handler, req)
+//	resp.AssertStatus(http.StatusCreated).AssertJSON(`{"id":1}`)
+type RequestBuilder struct {
+	method string
+	target string
+	query  url.Values
+	header http.Header
+	body   []byte
+	err    error
+}
+
+// NewRequest 创建请求构造器，target 可带查询参数
+func NewRequest(method, target string) *RequestBuilder {
+	return &RequestBuilder{method: method, target: target, query: url.Values{}, header: http.Header{}}
+}
+
+// Header 设置请求头
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.header.Set(key, value)
+	return b
+}
+
+// Query 追加查询参数
+func (b *RequestBuilder) Query(key, value string) *RequestBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// Body 设置原始请求体
+func (b *RequestBuilder) Body(body string) *RequestBuilder {
+	b.body = []byte(body)
+	return b
+}
+
+// JSON 将 v 编码为 JSON 请求体并设置 Content-Type
+func (b *RequestBuilder) JSON(v any) *RequestBuilder {
+	b.body, b.err = json.Marshal(v)
+	b.header.Set("Content-Type", "application/json")
+	return b
+}
+
+// Build 生成 *http.Request；JSON 编码失败时 panic（测试数据问题）
+func (b *RequestBuilder) Build() *http.Request {
+	if b.err != nil {
+		panic("testkit: encode request body: " + b.err.Error())
+	}
+	target := b.target
+	if len(b.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + b.query.Encode()
+	}
+	req := httptest.NewRequest(b.method, target, bytes.NewReader(b.body))
+	for k, v := range b.header {
+		req.Header[k] = v
+	}
+	return req
+}
+
+// Response 包装 httptest.ResponseRecorder，断言方法失败时记录错误并返回自身以便链式调用
+type Response struct {
+	t testing.TB
+	*httptest.ResponseRecorder
+}
+
+// Serve 用 handler 处理请求并返回响应
+func Serve(t testing.TB, h http.Handler, req *http.Request) *Response {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return &Response{t: t, ResponseRecorder: rec}
+}
+
+// AssertStatus 断言状态码
+func (r *Response) AssertStatus(code int) *Response {
+	r.t.Helper()
+	if r.Code != code {
+		r.t.Errorf("status = %d, want %d; body: %s", r.Code, code, r.Body.String())
+	}
+	return r
+}
+
+// AssertHeader 断言响应头
+func (r *Response) AssertHeader(key, value string) *Response {
+	r.t.Helper()
+	if got := r.Header().Get(key); got != value {
+		r.t.Errorf("header %s = %q, want %q", key, got, value)
+	}
+	return r
+}
+
+// AssertJSON 按语义比较 JSON 响应体，忽略字段顺序与空白；want 可以是 JSON 字符串、[]byte 或任意可编码的值
+func (r *Response) AssertJSON(want any) *Response {
+	r.t.Helper()
+	var wantData []byte
+	switch v := want.(type) {
+	case string:
+		wantData = []byte(v)
+	case []byte:
+		wantData = v
+	default:
+		var err error
+		if wantData, err = json.Marshal(v); err != nil {
+			r.t.Fatalf("testkit: encode expected json: %v", err)
+		}
+	}
+
+	var got, exp any
+	if err := json.Unmarshal(r.Body.Bytes(), &got); err != nil {
+		r.t.Errorf("response is not valid json: %v; body: %s", err, r.Body.String())
+		return r
+	}
+	if err := json.Unmarshal(wantData, &exp); err != nil {
+		r.t.Fatalf("testkit: expected value is not valid json: %v", err)
+	}
+	if !reflect.DeepEqual(got, exp) {
+		r.t.Errorf("json body mismatch\n got: %s\nwant: %s", r.Body.String(), wantData)
+	}
+	return r
+}
+
+// DecodeJSON 将响应体解码到 v
+func (r *Response) DecodeJSON(v any) {
+	r.t.Helper()
+	if err := json.NewDecoder(bytes.NewReader(r.Body.Bytes())).Decode(v); err != nil && err != io.EOF {
+		r.t.Fatalf("testkit: decode response: %v; body: %s", err, r.Body.String())
+	}
+}
+
+// AssertGolden 将响应体与 testdata/<name>.golden 比较，JSON 响应先格式化；设置 UPDATE_GOLDEN=1 更新黄金文件
+func (r *Response) AssertGolden(name string) *Response {
+	r.t.Helper()
+	Golden(r.t, name, normalizeJSON(r.Body.Bytes()))
+	return r
+}
+
+// normalizeJSON 格式化 JSON，使黄金文件便于阅读与 diff；非 JSON 原样返回
+func normalizeJSON(data []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, bytes.TrimSpace(data), "", "  "); err != nil {
+		return data
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
@@ -0,0 +1,57 @@
+package testkit
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/abs2free/go-kit/netutil"
+)
+
+// Kafka 一次性单节点 Kafka（KRaft 模式）
+type Kafka struct {
+	*Container
+	Brokers []string
+}
+
+// StartKafka 启动 apache/kafka 容器，broker 可连接后返回。
+// advertised.listeners 需要写入宿主机端口，因此先选取空闲端口再做固定映射
+func StartKafka(t testing.TB, opts ...ContainerOption) *Kafka {
+	t.Helper()
+	port, err := netutil.FreePort()
+	if err != nil {
+		t.Fatalf("testkit: %v", err)
+	}
+	addr := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+
+	o := newContainerOptions("apache/kafka:3.8.0", map[string]string{
+		"KAFKA_NODE_ID":                                  "1",
+		"KAFKA_PROCESS_ROLES":                            "broker,controller",
+		"KAFKA_LISTENERS":                                "PLAINTEXT://:9092,CONTROLLER://:9093",
+		"KAFKA_ADVERTISED_LISTENERS":                     "PLAINTEXT://" + addr,
+		"KAFKA_CONTROLLER_LISTENER_NAMES":                "CONTROLLER",
+		"KAFKA_LISTENER_SECURITY_PROTOCOL_MAP":           "CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT",
+		"KAFKA_CONTROLLER_QUORUM_VOTERS":                 "1@localhost:9093",
+		"KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR":         "1",
+		"KAFKA_TRANSACTION_STATE_LOG_MIN_ISR":            "1",
+		"KAFKA_TRANSACTION_STATE_LOG_REPLICATION_FACTOR": "1",
+		"KAFKA_AUTO_CREATE_TOPICS_ENABLE":                "true",
+	}, opts)
+
+	c := startContainer(t, o, []string{strconv.Itoa(port) + ":9092"}, func(ctx context.Context, c *Container) error {
+		if err := dialReady(ctx, addr); err != nil {
+			return err
+		}
+		_, err := c.exec(ctx, nil, "/opt/kafka/bin/kafka-topics.sh", "--bootstrap-server", "localhost:9092", "--list")
+		return err
+	})
+	return &Kafka{Container: c, Brokers: []string{addr}}
+}
+
+// CreateTopic 创建主题
+func (k *Kafka) CreateTopic(topic string, partitions int) {
+	k.t.Helper()
+	k.Exec(nil, "/opt/kafka/bin/kafka-topics.sh", "--bootstrap-server", "localhost:9092",
+		"--create", "--if-not-exists", "--topic", topic, "--partitions", strconv.Itoa(partitions))
+}
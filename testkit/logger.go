@@ -0,0 +1,125 @@
+package testkit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+// Logs 测试中捕获的日志
+type Logs struct {
+	t     testing.TB
+	obs   *observer.ObservedLogs
+	sugar *zap.SugaredLogger
+}
+
+// Logger 创建记录到内存的日志（Debug 及以上），并替换全局日志 logger.Default()；
+// 测试结束时恢复原日志，测试失败时输出捕获的全部日志便于排查
+//
+//	logs := testkit.Logger(t)
+//	svc.Do()
+//	logs.AssertLogged(zap.WarnLevel, "retrying")
+//	logs.AssertField("retrying", "attempt", 2)
+func Logger(t testing.TB) *Logs {
+	t.Helper()
+	core, obs := observer.New(zapcore.DebugLevel)
+	l := &Logs{t: t, obs: obs, sugar: zap.New(core).Sugar()}
+
+	old := logger.Default()
+	logger.SetDefault(logger.Wrap(l.sugar))
+	t.Cleanup(func() {
+		logger.SetDefault(old)
+		if t.Failed() {
+			for _, e := range obs.All() {
+				t.Logf("captured log: %s", formatEntry(e))
+			}
+		}
+	})
+	return l
+}
+
+// Sugar 返回写入捕获器的日志，可注入到接收 *zap.SugaredLogger 的组件
+func (l *Logs) Sugar() *zap.SugaredLogger {
+	return l.sugar
+}
+
+// All 返回全部日志
+func (l *Logs) All() []observer.LoggedEntry {
+	return l.obs.All()
+}
+
+// Reset 清空已捕获的日志
+func (l *Logs) Reset() {
+	l.obs.TakeAll()
+}
+
+// Messages 返回指定级别中包含 substr 的日志
+func (l *Logs) Messages(level zapcore.Level, substr string) []observer.LoggedEntry {
+	return l.obs.Filter(func(e observer.LoggedEntry) bool {
+		return e.Level == level && strings.Contains(e.Message, substr)
+	}).All()
+}
+
+// AssertLogged 断言存在指定级别且消息包含 substr 的日志
+func (l *Logs) AssertLogged(level zapcore.Level, substr string) {
+	l.t.Helper()
+	if len(l.Messages(level, substr)) == 0 {
+		l.t.Errorf("expected %s log containing %q, got:\n%s", level, substr, l.dump())
+	}
+}
+
+// AssertNotLogged 断言不存在指定级别且消息包含 substr 的日志
+func (l *Logs) AssertNotLogged(level zapcore.Level, substr string) {
+	l.t.Helper()
+	if entries := l.Messages(level, substr); len(entries) > 0 {
+		l.t.Errorf("unexpected %s log containing %q: %s", level, substr, formatEntry(entries[0]))
+	}
+}
+
+// AssertNoErrors 断言没有 Error 及以上级别的日志
+func (l *Logs) AssertNoErrors() {
+	l.t.Helper()
+	for _, e := range l.obs.All() {
+		if e.Level >= zapcore.ErrorLevel {
+			l.t.Errorf("unexpected error log: %s", formatEntry(e))
+		}
+	}
+}
+
+// AssertField 断言存在消息包含 substr 且字段 key 等于 want 的日志；
+// 按字符串形式比较，因此 int 与 int64 等数值类型可以直接比较
+func (l *Logs) AssertField(substr, key string, want any) {
+	l.t.Helper()
+	for _, e := range l.obs.All() {
+		if !strings.Contains(e.Message, substr) {
+			continue
+		}
+		if v, ok := e.ContextMap()[key]; ok && fmt.Sprint(v) == fmt.Sprint(want) {
+			return
+		}
+	}
+	l.t.Errorf("expected log containing %q with %s=%v, got:\n%s", substr, key, want, l.dump())
+}
+
+func (l *Logs) dump() string {
+	var b strings.Builder
+	for _, e := range l.obs.All() {
+		b.WriteString("  ")
+		b.WriteString(formatEntry(e))
+		b.WriteByte('\n')
+	}
+	if b.Len() == 0 {
+		return "  (no logs)"
+	}
+	return b.String()
+}
+
+func formatEntry(e observer.LoggedEntry) string {
+	return fmt.Sprintf("[%s] %s %v", e.Level, e.Message, e.ContextMap())
+}
@@ -0,0 +1,77 @@
+package testkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/abs2free/go-kit/db"
+)
+
+// MySQL 一次性 MySQL 实例的连接信息
+type MySQL struct {
+	*Container
+	Host     string
+	Port     string
+	User     string
+	Password string
+	Database string
+}
+
+// StartMySQL 启动 MySQL 8 容器（库名 test，root 密码 test），端口可连接且能执行查询后返回
+func StartMySQL(t testing.TB, opts ...ContainerOption) *MySQL {
+	t.Helper()
+	o := newContainerOptions("mysql:8.0", map[string]string{
+		"MYSQL_ROOT_PASSWORD": "test",
+		"MYSQL_DATABASE":      "test",
+	}, opts)
+
+	m := &MySQL{User: "root", Password: o.env["MYSQL_ROOT_PASSWORD"], Database: o.env["MYSQL_DATABASE"]}
+	m.Container = startContainer(t, o, []string{"3306/tcp"}, func(ctx context.Context, c *Container) error {
+		// 通过 TCP 查询：初始化阶段的临时实例不监听网络，避免过早判定就绪
+		_, err := c.exec(ctx, nil, "mysql", "-h127.0.0.1", "-u"+m.User, "-p"+m.Password, "-e", "SELECT 1")
+		return err
+	})
+	m.Host, m.Port, _ = net.SplitHostPort(m.HostPort("3306/tcp"))
+	return m
+}
+
+// DSN 返回 go-sql-driver/mysql 格式的连接串
+func (m *MySQL) DSN() string {
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true&loc=Local&charset=utf8mb4",
+		m.User, m.Password, net.JoinHostPort(m.Host, m.Port), m.Database)
+}
+
+// DB 通过 db.Open 打开带指标的 *sql.DB，测试结束时关闭。
+// 需要调用方导入 MySQL 驱动（如 _ "github.com/go-sql-driver/mysql"），本模块不依赖具体驱动
+func (m *MySQL) DB(opts ...db.Option) *sql.DB {
+	m.t.Helper()
+	conn, err := db.Open("mysql", m.DSN(), opts...)
+	if err != nil {
+		m.t.Fatalf("testkit: open mysql (is the driver imported?): %v", err)
+	}
+	m.t.Cleanup(func() { _ = conn.Close() })
+	if err := conn.Ping(); err != nil {
+		m.t.Fatalf("testkit: ping mysql: %v", err)
+	}
+	return conn
+}
+
+// Exec 在测试库中执行 SQL，用于建表与写入种子数据
+func (m *MySQL) Exec(sql string) {
+	m.t.Helper()
+	m.Container.Exec([]byte(sql), "mysql", "-h127.0.0.1", "-u"+m.User, "-p"+m.Password, m.Database)
+}
+
+// ExecFile 执行 SQL 文件（如 migrations/schema.sql）
+func (m *MySQL) ExecFile(path string) {
+	m.t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.t.Fatalf("testkit: read %s: %v", path, err)
+	}
+	m.Exec(string(data))
+}
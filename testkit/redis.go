@@ -0,0 +1,37 @@
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Redis 一次性 Redis 实例
+type Redis struct {
+	*Container
+	Addr string
+}
+
+// StartRedis 启动 Redis 7 容器，PING 返回 PONG 后返回
+func StartRedis(t testing.TB, opts ...ContainerOption) *Redis {
+	t.Helper()
+	o := newContainerOptions("redis:7-alpine", map[string]string{}, opts)
+	c := startContainer(t, o, []string{"6379/tcp"}, func(ctx context.Context, c *Container) error {
+		out, err := c.exec(ctx, nil, "redis-cli", "PING")
+		if err != nil {
+			return err
+		}
+		if strings.TrimSpace(out) != "PONG" {
+			return fmt.Errorf("unexpected ping reply %q", out)
+		}
+		return nil
+	})
+	return &Redis{Container: c, Addr: c.HostPort("6379/tcp")}
+}
+
+// Do 通过 redis-cli 执行命令写入种子数据，返回原始输出
+func (r *Redis) Do(args ...string) string {
+	r.t.Helper()
+	return strings.TrimSpace(r.Exec(nil, append([]string{"redis-cli"}, args...)...))
+}
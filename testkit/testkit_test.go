@@ -0,0 +1,128 @@
+package testkit
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/abs2free/go-kit/logger"
+)
+
+func TestLoggerCapture(t *testing.T) {
+	old := logger.Default()
+	t.Run("capture", func(t *testing.T) {
+		logs := Logger(t)
+		logger.S().Warnw("retrying request", "attempt", 2, "url", "/api")
+		logs.Sugar().Debug("debug detail")
+
+		logs.AssertLogged(zap.WarnLevel, "retrying")
+		logs.AssertField("retrying", "attempt", 2)
+		logs.AssertNotLogged(zap.InfoLevel, "retrying")
+		logs.AssertNoErrors()
+		if len(logs.All()) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(logs.All()))
+		}
+		logs.Reset()
+		if len(logs.All()) != 0 {
+			t.Fatal("reset did not clear logs")
+		}
+	})
+	if logger.Default() != old {
+		t.Fatal("global logger not restored")
+	}
+}
+
+func TestLoggerAssertionFailure(t *testing.T) {
+	ft := &fakeT{TB: t}
+	logs := Logger(ft)
+	logs.AssertLogged(zap.ErrorLevel, "missing")
+	logs.AssertField("missing", "k", "v")
+	if ft.errors != 2 {
+		t.Fatalf("expected 2 failures, got %d", ft.errors)
+	}
+}
+
+// fakeT 记录失败次数而不让外层测试失败
+type fakeT struct {
+	testing.TB
+	errors int
+}
+
+func (f *fakeT) Errorf(string, ...any) { f.errors++ }
+func (f *fakeT) Helper()               {}
+
+func TestDockerRunArgs(t *testing.T) {
+	o := newContainerOptions("redis:7", map[string]string{}, []ContainerOption{WithImage("redis:6"), WithEnv("A", "1")})
+	args := dockerRunArgs(o, []string{"6379/tcp", "19092:9092"}, "redis-server")
+	want := []string{"run", "-d", "--rm", "--label", "org.abs2free.testkit=true",
+		"-p", "127.0.0.1::6379/tcp", "-p", "127.0.0.1:19092:9092", "-e", "A=1", "redis:6", "redis-server"}
+	if strings.Join(args, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	if got := parsePortOutput("0.0.0.0:49153\n[::]:49153\n"); got != "127.0.0.1:49153" {
+		t.Fatalf("unexpected host port %q", got)
+	}
+}
+
+func TestStartRedis(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container test in short mode")
+	}
+	r := StartRedis(t)
+	r.Do("SET", "k", "v")
+	if got := r.Do("GET", "k"); got != "v" {
+		t.Fatalf("unexpected value %q", got)
+	}
+}
+
+func TestHTTPHelpers(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name":  body["name"],
+			"page":  r.URL.Query().Get("page"),
+			"trace": r.Header.Get("X-Trace-Id"),
+		})
+	})
+
+	req := NewRequest(http.MethodPost, "/users?x=1").
+		Query("page", "2").
+		Header("X-Trace-Id", "abc").
+		JSON(map[string]string{"name": "alice"}).
+		Build()
+
+	resp := Serve(t, h, req)
+	resp.AssertStatus(http.StatusCreated).
+		AssertHeader("Content-Type", "application/json").
+		AssertJSON(`{"trace":"abc","page":"2","name":"alice"}`).
+		AssertGolden("create_user")
+
+	var out struct{ Name string }
+	resp.DecodeJSON(&out)
+	if out.Name != "alice" {
+		t.Fatalf("unexpected decoded body %+v", out)
+	}
+}
+
+func TestFakeClockWaitForWaiters(t *testing.T) {
+	clock := Clock(t)
+	done := make(chan time.Time)
+	go func() {
+		clock.Sleep(time.Hour)
+		done <- clock.Now()
+	}()
+
+	clock.WaitForWaiters(1)
+	clock.Advance(time.Hour)
+	if got := <-done; !got.Equal(ClockStart.Add(time.Hour)) {
+		t.Fatalf("unexpected wake time %v", got)
+	}
+}
@@ -0,0 +1,160 @@
+package timeutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata" // 精简镜像中可能缺少时区数据库
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar 工作日历：周一至周五为工作日，可额外配置节假日与调休上班日
+type Calendar struct {
+	holidays map[string]bool
+	workdays map[string]bool
+}
+
+// NewCalendar 创建工作日历，日期格式 2006-01-02
+func NewCalendar(holidays, workdays []string) *Calendar {
+	c := &Calendar{holidays: make(map[string]bool), workdays: make(map[string]bool)}
+	for _, d := range holidays {
+		c.holidays[d] = true
+	}
+	for _, d := range workdays {
+		c.workdays[d] = true
+	}
+	return c
+}
+
+// DefaultCalendar 仅区分周末的日历
+var DefaultCalendar = NewCalendar(nil, nil)
+
+// IsBusinessDay 是否工作日
+func (c *Calendar) IsBusinessDay(t time.Time) bool {
+	day := t.Format(dateLayout)
+	if c.workdays[day] {
+		return true
+	}
+	if c.holidays[day] {
+		return false
+	}
+	wd := t.Weekday()
+	return wd != time.Saturday && wd != time.Sunday
+}
+
+// TruncateToBusinessDay 返回不晚于 t 的最近一个工作日的零点（t 所在时区）
+func (c *Calendar) TruncateToBusinessDay(t time.Time) time.Time {
+	d := StartOfDay(t)
+	for !c.IsBusinessDay(d) {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+// AddBusinessDays 加上 n 个工作日（n 可为负），返回结果日期的零点
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	d := StartOfDay(t)
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		d = d.AddDate(0, 0, step)
+		if c.IsBusinessDay(d) {
+			n--
+		}
+	}
+	return d
+}
+
+// StartOfDay 返回 t 所在时区当天零点，跨夏令时也正确
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+var locations sync.Map
+
+// LoadLocation 带缓存的 time.LoadLocation
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("timeutil: load location %q: %w", name, err)
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// Shanghai 东八区
+var Shanghai = mustLoad("Asia/Shanghai")
+
+func mustLoad(name string) *time.Location {
+	loc, err := LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// ParseIn 在指定时区解析不带时区信息的时间字符串。
+// 与 time.Parse（默认 UTC）和 time.ParseInLocation(..., time.Local)（依赖部署机器时区）不同，结果不受运行环境影响
+func ParseIn(layout, value, tz string) (time.Time, error) {
+	loc, err := LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("timeutil: parse %q: %w", value, err)
+	}
+	return t, nil
+}
+
+// Humanize 将时长格式化为易读形式，保留最高的两个单位：1d 2h、3m 20s、150ms
+func Humanize(d time.Duration) string {
+	if d == math.MinInt64 {
+		// -MinInt64 溢出后仍为 MinInt64，加 1ns 后再取反，只保留两个单位时结果不变
+		d++
+	}
+	if d < 0 {
+		return "-" + Humanize(-d)
+	}
+	if d < time.Second {
+		if d < time.Millisecond {
+			return d.String()
+		}
+		return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	}
+
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+
+	var parts []string
+	for _, u := range units {
+		if d >= u.d {
+			parts = append(parts, strconv.FormatInt(int64(d/u.d), 10)+u.name)
+			d %= u.d
+		} else if len(parts) > 0 {
+			// 高位单位之后出现 0 则停止，避免 "1h 0m" 这类输出
+			break
+		}
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,104 @@
+package timeutil
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	after := c.After(5 * time.Second)
+	ticker := c.NewTicker(2 * time.Second)
+	if c.Waiters() != 2 {
+		t.Fatalf("unexpected waiters %d", c.Waiters())
+	}
+
+	c.Advance(3 * time.Second)
+	select {
+	case <-after:
+		t.Fatal("After fired too early")
+	default:
+	}
+	if got := <-ticker.C(); !got.Equal(start.Add(2 * time.Second)) {
+		t.Fatalf("unexpected tick %v", got)
+	}
+
+	c.Advance(2 * time.Second)
+	if got := <-after; !got.Equal(start.Add(5 * time.Second)) {
+		t.Fatalf("unexpected After time %v", got)
+	}
+	<-ticker.C()
+	if c.Since(start) != 5*time.Second {
+		t.Fatalf("unexpected Since %v", c.Since(start))
+	}
+
+	ticker.Stop()
+	c.Advance(time.Minute)
+	select {
+	case <-ticker.C():
+		t.Fatal("stopped ticker fired")
+	default:
+	}
+	if c.Waiters() != 0 {
+		t.Fatalf("waiters left: %d", c.Waiters())
+	}
+}
+
+func TestBusinessDays(t *testing.T) {
+	// 2024-10-01 国庆假期，2024-10-12 周六调休上班
+	cal := NewCalendar([]string{"2024-10-01", "2024-10-02"}, []string{"2024-10-12"})
+	at := func(s string) time.Time {
+		d, err := ParseIn("2006-01-02 15:04", s, "Asia/Shanghai")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	if got := cal.TruncateToBusinessDay(at("2024-10-02 15:00")); !got.Equal(at("2024-09-30 00:00")) {
+		t.Fatalf("truncate: %v", got)
+	}
+	if !cal.IsBusinessDay(at("2024-10-12 09:00")) {
+		t.Fatal("makeup workday should be a business day")
+	}
+	if got := cal.AddBusinessDays(at("2024-09-30 10:00"), 1); !got.Equal(at("2024-10-03 00:00")) {
+		t.Fatalf("add: %v", got)
+	}
+	if got := DefaultCalendar.AddBusinessDays(at("2024-10-14 10:00"), -1); !got.Equal(at("2024-10-11 00:00")) {
+		t.Fatalf("add negative: %v", got)
+	}
+}
+
+func TestParseIn(t *testing.T) {
+	got, err := ParseIn("2006-01-02 15:04:05", "2024-06-01 08:00:00", "Asia/Shanghai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UTC().Hour() != 0 {
+		t.Fatalf("unexpected UTC time %v", got.UTC())
+	}
+	if _, err := ParseIn("2006-01-02", "2024-06-01", "Mars/Base"); err == nil {
+		t.Fatal("expected error for unknown zone")
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	cases := map[time.Duration]string{
+		150 * time.Millisecond:       "150ms",
+		200 * time.Second:            "3m 20s",
+		26*time.Hour + 5*time.Minute: "1d 2h",
+		time.Hour + 30*time.Second:   "1h",
+		-90 * time.Second:            "-1m 30s",
+		500 * time.Microsecond:       "500µs",
+		math.MinInt64:                "-106751d 23h",
+		math.MaxInt64:                "106751d 23h",
+	}
+	for d, want := range cases {
+		if got := Humanize(d); got != want {
+			t.Errorf("Humanize(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/abs2free/go-kit/logger"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// 常用的 baggage key
+const (
+	BaggageTenant     = "tenant"
+	BaggageUser       = "user.id"
+	BaggageExperiment = "experiment"
+)
+
+// propagator 同时传播 W3C trace context 与 baggage，不依赖是否调用过 Init
+var propagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Propagator 返回本包使用的传播器
+func Propagator() propagation.TextMapPropagator {
+	return propagator
+}
+
+// SetBaggage 在 ctx 中设置 baggage 条目，已有条目会被覆盖
+func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, fmt.Errorf("tracing: invalid baggage %s: %w", key, err)
+	}
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, fmt.Errorf("tracing: set baggage %s: %w", key, err)
+	}
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// GetBaggage 读取 baggage 条目，不存在时返回空字符串
+func GetBaggage(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// LogBaggageKeys BaggageFields 与 logger.FromContext 默认输出的 baggage 条目，按需在启动时修改
+var LogBaggageKeys = []string{BaggageTenant, BaggageUser, BaggageExperiment}
+
+func init() {
+	logger.RegisterContextFields(func(ctx context.Context) []zap.Field {
+		return BaggageFields(ctx)
+	})
+}
+
+// BaggageFields 将指定的 baggage 条目转换为日志字段，keys 为空时使用 LogBaggageKeys，值为空的 key 会被跳过。
+// 字段名带 baggage. 前缀，避免与 ctxmeta 的同名字段（如 tenant）冲突
+func BaggageFields(ctx context.Context, keys ...string) []zap.Field {
+	if len(keys) == 0 {
+		keys = LogBaggageKeys
+	}
+	b := baggage.FromContext(ctx)
+	fields := make([]zap.Field, 0, len(keys))
+	for _, k := range keys {
+		if v := b.Member(k).Value(); v != "" {
+			fields = append(fields, zap.String("baggage."+k, v))
+		}
+	}
+	return fields
+}
+
+// InjectHTTP 将 trace context 与 baggage 写入请求头
+func InjectHTTP(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// ExtractHTTP 从请求头中恢复 trace context 与 baggage
+func ExtractHTTP(ctx context.Context, header http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// HTTPMiddleware 服务端中间件：从入站请求头恢复上下文
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ExtractHTTP(r.Context(), r.Header)))
+	})
+}
+
+// Transport 客户端 RoundTripper：向出站请求注入上下文，base 为 nil 时使用 http.DefaultTransport
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	InjectHTTP(req.Context(), req.Header)
+	return t.base.RoundTrip(req)
+}
+
+// metadataCarrier 将 gRPC metadata 适配为 TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// UnaryServerInterceptor 从 gRPC 入站 metadata 恢复上下文
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = propagator.Extract(ctx, metadataCarrier(md))
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor 从 gRPC 入站 metadata 恢复流的上下文
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = propagator.Extract(ctx, metadataCarrier(md))
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// serverStream 替换 ServerStream 的 Context
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor 向 gRPC 出站 metadata 注入上下文
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(injectOutgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 向 gRPC 出站流的 metadata 注入上下文
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(injectOutgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+func injectOutgoing(ctx context.Context) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	propagator.Inject(ctx, metadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md)
+}
@@ -0,0 +1,84 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/abs2free/go-kit/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestBaggageAcrossHTTP(t *testing.T) {
+	ctx, err := SetBaggage(context.Background(), BaggageTenant, "acme")
+	if err != nil {
+		t.Fatalf("set tenant: %v", err)
+	}
+	ctx, _ = SetBaggage(ctx, BaggageUser, "u-1")
+
+	var got context.Context
+	srv := httptest.NewServer(HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Context()
+	})))
+	defer srv.Close()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	resp, err := (&http.Client{Transport: Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	resp.Body.Close()
+
+	if GetBaggage(got, BaggageTenant) != "acme" || GetBaggage(got, BaggageUser) != "u-1" {
+		t.Fatalf("baggage not propagated")
+	}
+
+	fields := BaggageFields(got, BaggageTenant, BaggageExperiment)
+	if len(fields) != 1 || fields[0].Key != "baggage.tenant" || fields[0].String != "acme" {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+
+	// init 中注册到 logger，FromContext 默认附加 LogBaggageKeys
+	var keys []string
+	for _, f := range logger.ContextFieldsOf(got) {
+		keys = append(keys, f.Key)
+	}
+	if len(keys) != 2 || keys[0] != "baggage.tenant" || keys[1] != "baggage.user.id" {
+		t.Fatalf("unexpected context fields %v", keys)
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s fakeServerStream) Context() context.Context { return s.ctx }
+
+func TestBaggageAcrossGRPCStream(t *testing.T) {
+	ctx, _ := SetBaggage(context.Background(), BaggageTenant, "acme")
+
+	// 客户端流拦截器写入出站 metadata
+	var out metadata.MD
+	_, err := StreamClientInterceptor()(ctx, &grpc.StreamDesc{}, nil, "/svc/Stream",
+		func(ctx context.Context, _ *grpc.StreamDesc, _ *grpc.ClientConn, _ string, _ ...grpc.CallOption) (grpc.ClientStream, error) {
+			out, _ = metadata.FromOutgoingContext(ctx)
+			return nil, nil
+		})
+	if err != nil || len(out.Get("baggage")) == 0 {
+		t.Fatalf("baggage not injected: %v %v", out, err)
+	}
+
+	// 服务端流拦截器从入站 metadata 恢复
+	in := fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), out)}
+	var got string
+	err = StreamServerInterceptor()(nil, in, &grpc.StreamServerInfo{}, func(_ any, ss grpc.ServerStream) error {
+		got = GetBaggage(ss.Context(), BaggageTenant)
+		return nil
+	})
+	if err != nil || got != "acme" {
+		t.Fatalf("baggage not extracted: %q %v", got, err)
+	}
+}
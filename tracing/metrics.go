@@ -0,0 +1,102 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// REDProcessor 根据结束的 span 按名称统计请求数、错误数和耗时（RED 指标），
+// 只做了链路埋点的服务也能得到服务级监控面板。注册到 monitor.Registry 即可随 /metrics 暴露：
+//
+//	tracing.Init("svc", tracing.WithSpanProcessor(tracing.NewREDProcessor(monitor.Registry)))
+//
+// span 处理器只能看到被记录的 span，按比例采样时指标会按采样率偏低。Init 检测到 REDProcessor 时以 RecordUnsampled
+// 包装采样器，未采样的 span 也会被记录和统计（但不导出）；直接使用 sdktrace 时需自行包装
+type REDProcessor struct {
+	kinds    map[trace.SpanKind]bool
+	requests *prometheus.CounterVec
+	errors   *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var _ sdktrace.SpanProcessor = (*REDProcessor)(nil)
+
+// NewREDProcessor 创建 RED 指标处理器；kinds 限定统计的 span 类型，
+// 为空时统计 server 与 consumer 类型，避免内部 span 名称导致指标基数膨胀
+func NewREDProcessor(reg prometheus.Registerer, kinds ...trace.SpanKind) *REDProcessor {
+	if len(kinds) == 0 {
+		kinds = []trace.SpanKind{trace.SpanKindServer, trace.SpanKindConsumer}
+	}
+
+	p := &REDProcessor{
+		kinds: make(map[trace.SpanKind]bool, len(kinds)),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "span_requests_total",
+			Help: "Number of finished spans by name and kind.",
+		}, []string{"span_name", "span_kind"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "span_errors_total",
+			Help: "Number of finished spans with error status by name and kind.",
+		}, []string{"span_name", "span_kind"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "span_duration_seconds",
+			Help:    "Span duration by name and kind.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"span_name", "span_kind"}),
+	}
+	for _, k := range kinds {
+		p.kinds[k] = true
+	}
+
+	reg.MustRegister(p.requests, p.errors, p.duration)
+	return p
+}
+
+// RecordUnsampled 包装采样器，将丢弃（Drop）的决策改为只记录不采样（RecordOnly）：span 处理器能看到全部 span，
+// 导出器仍只导出采样的 span，传播给下游的采样标志不变。代价是未采样的 span 也会记录属性与事件
+func RecordUnsampled(s sdktrace.Sampler) sdktrace.Sampler {
+	return recordUnsampled{s}
+}
+
+type recordUnsampled struct {
+	sdktrace.Sampler
+}
+
+func (r recordUnsampled) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	res := r.Sampler.ShouldSample(p)
+	if res.Decision == sdktrace.Drop {
+		res.Decision = sdktrace.RecordOnly
+	}
+	return res
+}
+
+func (r recordUnsampled) Description() string {
+	return "RecordUnsampled{" + r.Sampler.Description() + "}"
+}
+
+func (p *REDProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *REDProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !p.kinds[s.SpanKind()] {
+		return
+	}
+
+	labels := prometheus.Labels{"span_name": s.Name(), "span_kind": s.SpanKind().String()}
+	p.requests.With(labels).Inc()
+	if s.Status().Code == codes.Error {
+		p.errors.With(labels).Inc()
+	}
+	p.duration.With(labels).Observe(s.EndTime().Sub(s.StartTime()).Seconds())
+}
+
+func (p *REDProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+func (p *REDProcessor) ForceFlush(context.Context) error {
+	return nil
+}
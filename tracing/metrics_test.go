@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestREDProcessor(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	red := NewREDProcessor(reg)
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(red))
+	tracer := tp.Tracer("test")
+
+	for i := 0; i < 3; i++ {
+		_, span := tracer.Start(context.Background(), "GET /orders", trace.WithSpanKind(trace.SpanKindServer))
+		if i == 0 {
+			(&Span{Span: span}).RecordError(errors.New("boom"))
+		}
+		span.End()
+	}
+
+	// internal span 默认不统计
+	_, span := tracer.Start(context.Background(), "db.query")
+	span.End()
+
+	labels := prometheus.Labels{"span_name": "GET /orders", "span_kind": "server"}
+	if got := testutil.ToFloat64(red.requests.With(labels)); got != 3 {
+		t.Fatalf("expected 3 requests, got %v", got)
+	}
+	if got := testutil.ToFloat64(red.errors.With(labels)); got != 1 {
+		t.Fatalf("expected 1 error, got %v", got)
+	}
+	if got := testutil.CollectAndCount(red.requests); got != 1 {
+		t.Fatalf("expected only server spans to be counted, got %d series", got)
+	}
+}
+
+func TestREDProcessorCountsUnsampled(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	red := NewREDProcessor(reg)
+	exporter := tracetest.NewInMemoryExporter()
+	p, err := Init("svc", WithExporter(exporter), WithSampleRatio(0), WithSpanProcessor(red), WithoutGlobal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Shutdown(context.Background())
+
+	for i := 0; i < 4; i++ {
+		_, span := p.Tracer().Start(context.Background(), "GET /orders", trace.WithSpanKind(trace.SpanKindServer))
+		if span.SpanContext().IsSampled() {
+			t.Fatal("span should not be sampled")
+		}
+		span.End()
+	}
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	labels := prometheus.Labels{"span_name": "GET /orders", "span_kind": "server"}
+	if got := testutil.ToFloat64(red.requests.With(labels)); got != 4 {
+		t.Fatalf("expected 4 requests regardless of sampling, got %v", got)
+	}
+	if n := len(exporter.GetSpans()); n != 0 {
+		t.Fatalf("unsampled spans should not be exported, got %d", n)
+	}
+}
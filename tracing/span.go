@@ -0,0 +1,112 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/abs2free/go-kit/tracing"
+
+// Span 对 trace.Span 的简单封装，提供更易用的属性和错误记录方法
+type Span struct {
+	trace.Span
+}
+
+// Start 使用全局 TracerProvider 创建子 span
+func Start(ctx context.Context, name string, kv ...any) (context.Context, *Span) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(Attrs(kv...)...))
+	return ctx, &Span{Span: span}
+}
+
+// SpanFromContext 获取 ctx 中的当前 span，不存在时返回 no-op span
+func SpanFromContext(ctx context.Context) *Span {
+	return &Span{Span: trace.SpanFromContext(ctx)}
+}
+
+// RecordError 记录错误并将 span 状态置为 Error，err 为 nil 时忽略
+func (s *Span) RecordError(err error, opts ...trace.EventOption) {
+	if err == nil {
+		return
+	}
+	s.Span.RecordError(err, opts...)
+	s.Span.SetStatus(codes.Error, err.Error())
+}
+
+// SetAttrs 以 key/value 交替的方式设置属性，用法同 SugaredLogger.Infow
+func (s *Span) SetAttrs(kv ...any) {
+	s.Span.SetAttributes(Attrs(kv...)...)
+}
+
+// End 结束 span
+func (s *Span) End(opts ...trace.SpanEndOption) {
+	s.Span.End(opts...)
+}
+
+// Traced 在 span 中执行 fn，自动记录返回的错误；fn panic 时记录后继续向上抛出
+func Traced(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	ctx, span := Start(ctx, name)
+	defer func() {
+		if r := recover(); r != nil {
+			span.RecordError(fmt.Errorf("panic: %v", r), trace.WithStackTrace(true))
+			span.End()
+			panic(r)
+		}
+		span.RecordError(err)
+		span.End()
+	}()
+
+	return fn(ctx)
+}
+
+// Attrs 将 key/value 交替的参数转换为 attribute，key 必须为 string，多余的参数被忽略
+func Attrs(kv ...any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			continue
+		}
+		attrs = append(attrs, Attr(key, kv[i+1]))
+	}
+	return attrs
+}
+
+// Attr 根据值类型创建 attribute，未知类型使用 fmt 格式化为字符串
+func Attr(key string, value any) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case time.Duration:
+		return attribute.String(key, v.String())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	case error:
+		return attribute.String(key, v.Error())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedRecordsErrorAndAttrs(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(prev)
+
+	errBoom := errors.New("boom")
+	err := Traced(context.Background(), "load-user", func(ctx context.Context) error {
+		SpanFromContext(ctx).SetAttrs("user.id", 42, "cache.hit", false)
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected error to be returned, got %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	s := spans[0]
+	if s.Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", s.Status())
+	}
+	if len(s.Attributes()) != 2 || s.Attributes()[0].Value.AsInt64() != 42 {
+		t.Fatalf("unexpected attributes %v", s.Attributes())
+	}
+}
@@ -0,0 +1,284 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Protocol OTLP 传输协议
+type Protocol string
+
+const (
+	ProtocolGRPC Protocol = "grpc"
+	ProtocolHTTP Protocol = "http"
+)
+
+type config struct {
+	version     string
+	environment string
+	attrs       []attribute.KeyValue
+
+	sampler  sdktrace.Sampler
+	exporter sdktrace.SpanExporter
+
+	protocol Protocol
+	endpoint string
+	insecure bool
+	headers  map[string]string
+
+	batchTimeout time.Duration
+	maxQueueSize int
+	processors   []sdktrace.SpanProcessor
+
+	global bool
+}
+
+// Option 配置选项
+type Option func(*config)
+
+// WithVersion 设置 service.version 资源属性
+func WithVersion(version string) Option {
+	return func(c *config) {
+		c.version = version
+	}
+}
+
+// WithEnvironment 设置 deployment.environment 资源属性
+func WithEnvironment(env string) Option {
+	return func(c *config) {
+		c.environment = env
+	}
+}
+
+// WithAttributes 追加自定义资源属性
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithSampleRatio 按比例采样，并遵循上游的采样决定（ParentBased）
+func WithSampleRatio(ratio float64) Option {
+	return func(c *config) {
+		c.sampler = sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	}
+}
+
+// WithSampler 使用自定义采样器
+func WithSampler(sampler sdktrace.Sampler) Option {
+	return func(c *config) {
+		c.sampler = sampler
+	}
+}
+
+// WithEndpoint 设置 OTLP 地址（host:port），未设置时使用 OTEL_EXPORTER_OTLP_ENDPOINT 环境变量
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = endpoint
+	}
+}
+
+// WithProtocol 选择 OTLP gRPC 或 HTTP 协议，默认 gRPC
+func WithProtocol(p Protocol) Option {
+	return func(c *config) {
+		c.protocol = p
+	}
+}
+
+// WithInsecure 禁用传输加密，通常用于本机或集群内的 collector
+func WithInsecure() Option {
+	return func(c *config) {
+		c.insecure = true
+	}
+}
+
+// WithHeaders 设置导出请求头，例如鉴权 token
+func WithHeaders(headers map[string]string) Option {
+	return func(c *config) {
+		c.headers = headers
+	}
+}
+
+// WithExporter 使用自定义导出器，设置后忽略 OTLP 相关选项
+func WithExporter(exporter sdktrace.SpanExporter) Option {
+	return func(c *config) {
+		c.exporter = exporter
+	}
+}
+
+// WithBatch 设置批量导出的超时时间与队列长度
+func WithBatch(timeout time.Duration, maxQueueSize int) Option {
+	return func(c *config) {
+		c.batchTimeout = timeout
+		c.maxQueueSize = maxQueueSize
+	}
+}
+
+// WithSpanProcessor 追加 span 处理器
+func WithSpanProcessor(p sdktrace.SpanProcessor) Option {
+	return func(c *config) {
+		c.processors = append(c.processors, p)
+	}
+}
+
+// WithoutGlobal 不设置为全局 TracerProvider 和 Propagator
+func WithoutGlobal() Option {
+	return func(c *config) {
+		c.global = false
+	}
+}
+
+// Provider 封装 TracerProvider，负责优雅关闭
+type Provider struct {
+	*sdktrace.TracerProvider
+	service string
+}
+
+// Init 初始化链路追踪：资源属性、采样器、OTLP 批量导出，并默认注册为全局 Provider
+func Init(service string, opts ...Option) (*Provider, error) {
+	if service == "" {
+		return nil, errors.New("tracing: service name is required")
+	}
+
+	cfg := &config{
+		sampler:  sdktrace.ParentBased(sdktrace.AlwaysSample()),
+		protocol: ProtocolGRPC,
+		global:   true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	res, err := newResource(service, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := cfg.exporter
+	if exporter == nil {
+		exporter, err = newExporter(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var batchOpts []sdktrace.BatchSpanProcessorOption
+	if cfg.batchTimeout > 0 {
+		batchOpts = append(batchOpts, sdktrace.WithBatchTimeout(cfg.batchTimeout))
+	}
+	if cfg.maxQueueSize > 0 {
+		batchOpts = append(batchOpts, sdktrace.WithMaxQueueSize(cfg.maxQueueSize))
+	}
+
+	sampler := cfg.sampler
+	for _, p := range cfg.processors {
+		// RED 指标需要统计未采样的请求
+		if _, ok := p.(*REDProcessor); ok {
+			sampler = RecordUnsampled(sampler)
+			break
+		}
+	}
+
+	tpOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sampler),
+		sdktrace.WithBatcher(exporter, batchOpts...),
+	}
+	for _, p := range cfg.processors {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(p))
+	}
+
+	tp := sdktrace.NewTracerProvider(tpOpts...)
+
+	if cfg.global {
+		otel.SetTracerProvider(tp)
+		otel.SetTextMapPropagator(propagator)
+	}
+
+	return &Provider{TracerProvider: tp, service: service}, nil
+}
+
+// Tracer 返回以服务名命名的 Tracer
+func (p *Provider) Tracer() trace.Tracer {
+	return p.TracerProvider.Tracer(p.service)
+}
+
+// Shutdown 导出剩余 span 并关闭，ctx 控制最长等待时间
+func (p *Provider) Shutdown(ctx context.Context) error {
+	if err := p.TracerProvider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("tracing: shutdown: %w", err)
+	}
+	return nil
+}
+
+func newResource(service string, cfg *config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(service)}
+	if cfg.version != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.version))
+	}
+	if cfg.environment != "" {
+		// v1.27.0 起改名为 deployment.environment.name，保留原属性名以兼容已有的查询与看板
+		attrs = append(attrs, attribute.String("deployment.environment", cfg.environment))
+	}
+	attrs = append(attrs, cfg.attrs...)
+
+	// semconv 版本需与 otel/sdk 的 resource.Default 一致，Schema URL 不同时 Merge 返回错误
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, attrs...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: build resource: %w", err)
+	}
+	return res, nil
+}
+
+func newExporter(cfg *config) (sdktrace.SpanExporter, error) {
+	ctx := context.Background()
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch cfg.protocol {
+	case ProtocolHTTP:
+		var opts []otlptracehttp.Option
+		if cfg.endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.endpoint))
+		}
+		if cfg.insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(cfg.headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.headers))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		var opts []otlptracegrpc.Option
+		if cfg.endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.endpoint))
+		}
+		if cfg.insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(cfg.headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.headers))
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing: create %s exporter: %w", cfg.protocol, err)
+	}
+	return exporter, nil
+}
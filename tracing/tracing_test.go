@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+func TestInitExportsWithResource(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	p, err := Init("order-service",
+		WithExporter(exporter),
+		WithVersion("1.2.3"),
+		WithEnvironment("test"),
+		WithoutGlobal(),
+	)
+	if err != nil {
+		t.Fatalf("init: %v", err)
+	}
+
+	_, span := p.Tracer().Start(context.Background(), "checkout")
+	span.End()
+
+	// 内存导出器在 Shutdown 时会清空数据，这里只做 flush
+	if err := p.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	defer p.Shutdown(context.Background())
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "checkout" {
+		t.Fatalf("unexpected spans %v", spans)
+	}
+
+	var service string
+	for _, kv := range spans[0].Resource.Attributes() {
+		if kv.Key == semconv.ServiceNameKey {
+			service = kv.Value.AsString()
+		}
+	}
+	if service != "order-service" {
+		t.Fatalf("expected service.name order-service, got %q", service)
+	}
+}
+
+func TestInitRequiresService(t *testing.T) {
+	if _, err := Init(""); err == nil {
+		t.Fatalf("expected error for empty service name")
+	}
+}
@@ -0,0 +1,37 @@
+package validate
+
+import "regexp"
+
+var mobileRe = regexp.MustCompile(`^1[3-9]\d{9}$`)
+
+// isMobile 中国大陆手机号，兼容 +86 / 86 前缀
+func isMobile(s string) bool {
+	if len(s) == 14 && s[:3] == "+86" {
+		s = s[3:]
+	} else if len(s) == 13 && s[:2] == "86" {
+		s = s[2:]
+	}
+	return mobileRe.MatchString(s)
+}
+
+var idCardRe = regexp.MustCompile(`^\d{17}[\dXx]$`)
+
+// isIDCard 18 位居民身份证号，按 GB 11643 校验码校验
+func isIDCard(s string) bool {
+	if !idCardRe.MatchString(s) {
+		return false
+	}
+
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	const checks = "10X98765432"
+
+	sum := 0
+	for i, w := range weights {
+		sum += int(s[i]-'0') * w
+	}
+	last := s[17]
+	if last == 'x' {
+		last = 'X'
+	}
+	return checks[sum%11] == last
+}
@@ -0,0 +1,192 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// 支持的语言
+const (
+	LangZH = "zh"
+	LangEN = "en"
+)
+
+// CodeInvalidArgument 校验失败时响应体中的错误码
+const CodeInvalidArgument = "invalid_argument"
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	// Field 字段名，优先取 json tag，嵌套字段以 . 连接，如 address.city
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Error 校验错误，JSON 结构与接口统一响应体一致：
+//
+//	{"code": "invalid_argument", "message": "手机号格式不正确", "details": [{"field": "mobile", ...}]}
+type Error struct {
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details []FieldError `json:"details"`
+}
+
+func (e *Error) Error() string {
+	msgs := make([]string, 0, len(e.Details))
+	for _, d := range e.Details {
+		msgs = append(msgs, d.Message)
+	}
+	return "validate: " + strings.Join(msgs, "; ")
+}
+
+// Validator 基于 struct tag 的校验器，内置中英文错误信息与 mobile、idcard 规则
+type Validator struct {
+	v   *validator.Validate
+	uni *ut.UniversalTranslator
+}
+
+// New 创建校验器
+func New() (*Validator, error) {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	// 错误中的字段名使用 json tag，与接口入参保持一致
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	zhLocale := zh.New()
+	uni := ut.New(zhLocale, zhLocale, en.New())
+	val := &Validator{v: v, uni: uni}
+
+	zhTrans, _ := uni.GetTranslator(LangZH)
+	if err := zh_translations.RegisterDefaultTranslations(v, zhTrans); err != nil {
+		return nil, fmt.Errorf("validate: register zh translations: %w", err)
+	}
+	enTrans, _ := uni.GetTranslator(LangEN)
+	if err := en_translations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return nil, fmt.Errorf("validate: register en translations: %w", err)
+	}
+
+	if err := val.Register("mobile", isMobile, map[string]string{
+		LangZH: "{0}必须是有效的手机号",
+		LangEN: "{0} must be a valid mobile number",
+	}); err != nil {
+		return nil, err
+	}
+	if err := val.Register("idcard", isIDCard, map[string]string{
+		LangZH: "{0}必须是有效的身份证号",
+		LangEN: "{0} must be a valid ID card number",
+	}); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Register 注册自定义规则及各语言的错误信息，{0} 为字段名
+func (val *Validator) Register(tag string, fn func(string) bool, messages map[string]string) error {
+	err := val.v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+		return fn(fl.Field().String())
+	})
+	if err != nil {
+		return fmt.Errorf("validate: register %s: %w", tag, err)
+	}
+
+	for lang, msg := range messages {
+		trans, ok := val.uni.GetTranslator(lang)
+		if !ok {
+			return fmt.Errorf("validate: unsupported language %q", lang)
+		}
+		err := val.v.RegisterTranslation(tag, trans,
+			func(t ut.Translator) error { return t.Add(tag, msg, true) },
+			func(t ut.Translator, fe validator.FieldError) string {
+				s, _ := t.T(tag, fe.Field())
+				return s
+			})
+		if err != nil {
+			return fmt.Errorf("validate: register %s translation: %w", tag, err)
+		}
+	}
+	return nil
+}
+
+// Struct 校验结构体，lang 为空时使用中文；校验失败返回 *Error
+func (val *Validator) Struct(s any, lang string) error {
+	err := val.v.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	trans, ok := val.uni.GetTranslator(Lang(lang))
+	if !ok {
+		trans, _ = val.uni.GetTranslator(LangZH)
+	}
+
+	details := make([]FieldError, 0, len(errs))
+	for _, fe := range errs {
+		details = append(details, FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    fe.Tag(),
+			Message: fe.Translate(trans),
+		})
+	}
+	return &Error{Code: CodeInvalidArgument, Message: details[0].Message, Details: details}
+}
+
+// fieldPath 去掉命名空间开头的结构体名：User.address.city -> address.city
+func fieldPath(ns string) string {
+	if _, rest, ok := strings.Cut(ns, "."); ok {
+		return rest
+	}
+	return ns
+}
+
+// Lang 根据 Accept-Language 选择语言，无法识别时返回中文
+func Lang(acceptLanguage string) string {
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, _, _ := strings.Cut(strings.TrimSpace(part), ";")
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		switch base {
+		case LangZH:
+			return LangZH
+		case LangEN:
+			return LangEN
+		}
+	}
+	return LangZH
+}
+
+// Default 包级校验器
+var Default = mustNew()
+
+func mustNew() *Validator {
+	v, err := New()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Struct 使用 Default 校验结构体
+func Struct(s any, lang string) error {
+	return Default.Struct(s, lang)
+}
@@ -0,0 +1,71 @@
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type address struct {
+	City string `json:"city" validate:"required"`
+}
+
+type signup struct {
+	Name    string  `json:"name" validate:"required,max=10"`
+	Mobile  string  `json:"mobile" validate:"mobile"`
+	IDCard  string  `json:"id_card" validate:"omitempty,idcard"`
+	Address address `json:"address"`
+}
+
+func TestStruct(t *testing.T) {
+	ok := signup{Name: "tom", Mobile: "+8613800138000", IDCard: "11010519491231002X", Address: address{City: "bj"}}
+	if err := Struct(ok, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bad := signup{Mobile: "12345", IDCard: "110105194912310021"}
+	err := Struct(bad, "zh-CN,zh;q=0.9")
+	var verr *Error
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if len(verr.Details) != 4 {
+		t.Fatalf("expected 4 field errors, got %+v", verr.Details)
+	}
+
+	fields := map[string]FieldError{}
+	for _, d := range verr.Details {
+		fields[d.Field] = d
+	}
+	if fields["mobile"].Message != "mobile必须是有效的手机号" {
+		t.Fatalf("unexpected zh message %q", fields["mobile"].Message)
+	}
+	if fields["id_card"].Rule != "idcard" || fields["address.city"].Rule != "required" {
+		t.Fatalf("unexpected fields %+v", fields)
+	}
+
+	data, _ := json.Marshal(verr)
+	if !strings.Contains(string(data), `"code":"invalid_argument"`) {
+		t.Fatalf("unexpected envelope %s", data)
+	}
+
+	err = Struct(bad, "en-US")
+	if !strings.Contains(err.Error(), "mobile must be a valid mobile number") {
+		t.Fatalf("unexpected en message %v", err)
+	}
+}
+
+func TestLang(t *testing.T) {
+	cases := map[string]string{
+		"":                       LangZH,
+		"en-US,en;q=0.9":         LangEN,
+		"fr-FR, en;q=0.8":        LangEN,
+		"zh-TW,zh;q=0.9,en;q=.8": LangZH,
+	}
+	for in, want := range cases {
+		if got := Lang(in); got != want {
+			t.Errorf("Lang(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
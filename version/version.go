@@ -0,0 +1,155 @@
+package version
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// 构建时通过 ldflags 注入，未注入时从 debug.ReadBuildInfo 读取：
+//
+//	go build -ldflags "-X github.com/abs2free/go-kit/version.Version=v1.2.3 -X github.com/abs2free/go-kit/version.Commit=abc1234"
+var (
+	Version string
+	Commit  string
+	Date    string
+)
+
+const pkgPath = "github.com/abs2free/go-kit/version"
+
+// Info 构建信息
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get 返回构建信息，ldflags 注入的值优先，其次是 go 工具链写入的模块版本与 vcs 信息
+func Get() Info {
+	once.Do(func() {
+		info = load(debug.ReadBuildInfo)
+	})
+	return info
+}
+
+func load(read func() (*debug.BuildInfo, bool)) Info {
+	i := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if bi, ok := read(); ok {
+		if i.Version == "" && bi.Main.Version != "(devel)" {
+			i.Version = bi.Main.Version
+		}
+		var modified bool
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if i.Commit == "" {
+					i.Commit = s.Value
+				}
+			case "vcs.time":
+				if i.Date == "" {
+					i.Date = s.Value
+				}
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if modified && Commit == "" && i.Commit != "" {
+			i.Commit += "-dirty"
+		}
+	}
+	if i.Version == "" {
+		i.Version = "dev"
+	}
+	if i.Commit == "" {
+		i.Commit = "unknown"
+	}
+	if i.Date == "" {
+		i.Date = "unknown"
+	}
+	return i
+}
+
+// String 单行描述，如 "v1.2.3 (commit abc1234, built 2024-01-01T00:00:00Z, go1.24 linux/amd64)"
+func (i Info) String() string {
+	return fmt.Sprintf("%s (commit %s, built %s, %s %s)", i.Version, shortCommit(i.Commit), i.Date, i.GoVersion, i.Platform)
+}
+
+func shortCommit(c string) string {
+	hash, dirty, _ := strings.Cut(c, "-")
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	if dirty != "" {
+		return hash + "-" + dirty
+	}
+	return hash
+}
+
+// LDFlags 生成注入构建信息的 -ldflags 参数，供构建脚本使用
+func LDFlags(version, commit, date string) string {
+	return fmt.Sprintf("-X %[1]s.Version=%[2]s -X %[1]s.Commit=%[3]s -X %[1]s.Date=%[4]s", pkgPath, version, commit, date)
+}
+
+// Print 输出 "name v1.2.3 (commit ...)"
+func Print(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s %s\n", name, Get())
+}
+
+// HandleFlag 检查命令行参数中的 --version / -version / -v，命中时输出版本并返回 true，
+// 通常在 main 开头调用：
+//
+//	if version.HandleFlag(os.Args[1:], os.Stdout, "app") {
+//		return
+//	}
+func HandleFlag(args []string, w io.Writer, name string) bool {
+	for _, a := range args {
+		if a == "--" {
+			return false
+		}
+		switch a {
+		case "--version", "-version", "-v":
+			Print(w, name)
+			return true
+		}
+	}
+	return false
+}
+
+// Handler 以 JSON 返回构建信息，可挂载到 monitor 的 /version
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Get())
+	})
+}
+
+// Fields 返回版本相关的日志字段，用于 logger 的常量字段：
+//
+//	log = log.Desugar().With(version.Fields()...).Sugar()
+func Fields() []zap.Field {
+	i := Get()
+	return []zap.Field{
+		zap.String("version", i.Version),
+		zap.String("commit", shortCommit(i.Commit)),
+	}
+}
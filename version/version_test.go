@@ -0,0 +1,72 @@
+package version
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	read := func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{
+			Main: debug.Module{Version: "v1.2.3"},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123456789abcdef0123"},
+				{Key: "vcs.time", Value: "2024-01-02T03:04:05Z"},
+				{Key: "vcs.modified", Value: "true"},
+			},
+		}, true
+	}
+	i := load(read)
+	if i.Version != "v1.2.3" || i.Commit != "0123456789abcdef0123-dirty" || i.Date != "2024-01-02T03:04:05Z" {
+		t.Fatalf("unexpected info %+v", i)
+	}
+	if !strings.HasPrefix(i.String(), "v1.2.3 (commit 0123456789ab-dirty, built 2024-01-02T03:04:05Z, go") {
+		t.Fatalf("unexpected string %q", i.String())
+	}
+
+	Version, Commit = "v2.0.0", "feedbee"
+	defer func() { Version, Commit = "", "" }()
+	i = load(read)
+	if i.Version != "v2.0.0" || i.Commit != "feedbee" {
+		t.Fatalf("ldflags should take precedence: %+v", i)
+	}
+
+	i = load(func() (*debug.BuildInfo, bool) { return nil, false })
+	if i.Date != "unknown" {
+		t.Fatalf("unexpected fallback %+v", i)
+	}
+}
+
+func TestHandleFlagAndHandler(t *testing.T) {
+	var buf bytes.Buffer
+	if HandleFlag([]string{"-c", "app.yaml"}, &buf, "app") || buf.Len() != 0 {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+	if HandleFlag([]string{"--", "--version"}, &buf, "app") {
+		t.Fatalf("arguments after -- should be ignored")
+	}
+	if !HandleFlag([]string{"--version"}, &buf, "app") || !strings.HasPrefix(buf.String(), "app ") {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/version", nil))
+	var got Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil || got != Get() {
+		t.Fatalf("unexpected response %s: %v", rec.Body, err)
+	}
+	if len(Fields()) != 2 {
+		t.Fatalf("unexpected fields %v", Fields())
+	}
+}
+
+func TestLDFlags(t *testing.T) {
+	want := "-X github.com/abs2free/go-kit/version.Version=v1 -X github.com/abs2free/go-kit/version.Commit=abc -X github.com/abs2free/go-kit/version.Date=today"
+	if got := LDFlags("v1", "abc", "today"); got != want {
+		t.Fatalf("got %q", got)
+	}
+}
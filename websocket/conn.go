@@ -0,0 +1,179 @@
+package websocket
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Conn 单个 WebSocket 连接，写操作通过发送队列串行化
+type Conn struct {
+	hub  *Hub
+	ws   *websocket.Conn
+	req  *http.Request
+	id   string
+	send chan []byte
+	// readDone 读协程退出时关闭
+	readDone chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+	values map[string]any
+}
+
+func newConn(h *Hub, ws *websocket.Conn, r *http.Request) *Conn {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return &Conn{
+		hub:      h,
+		ws:       ws,
+		req:      r,
+		id:       hex.EncodeToString(b),
+		send:     make(chan []byte, h.opts.sendQueue),
+		readDone: make(chan struct{}),
+	}
+}
+
+// ID 返回连接唯一标识
+func (c *Conn) ID() string {
+	return c.id
+}
+
+// Request 返回升级时的 HTTP 请求，可读取认证信息与查询参数
+func (c *Conn) Request() *http.Request {
+	return c.req
+}
+
+// Set 绑定自定义数据（如用户 ID）
+func (c *Conn) Set(key string, value any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.values == nil {
+		c.values = make(map[string]any)
+	}
+	c.values[key] = value
+}
+
+// Get 读取自定义数据
+func (c *Conn) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[key]
+	return v, ok
+}
+
+// Send 将文本消息放入发送队列，不阻塞；队列满时返回 ErrQueueFull，连接关闭后返回 ErrClosed
+func (c *Conn) Send(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	select {
+	case c.send <- msg:
+		return nil
+	default:
+		c.hub.dropped.Inc()
+		return ErrQueueFull
+	}
+}
+
+// Close 发送完已排队的消息后关闭连接
+func (c *Conn) Close() {
+	c.closeSend()
+}
+
+// closeSend 关闭发送队列，写协程发送完剩余消息后发出 close 帧
+func (c *Conn) closeSend() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.send)
+	}
+}
+
+// run 启动读写协程，阻塞直到连接结束
+func (c *Conn) run() {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.writePump()
+	}()
+	c.readPump()
+	<-done
+}
+
+func (c *Conn) readPump() {
+	// 读协程退出时通知写协程收尾
+	defer func() {
+		close(c.readDone)
+		c.closeSend()
+	}()
+
+	opts := c.hub.opts
+	c.ws.SetReadLimit(opts.maxMessageSize)
+	_ = c.ws.SetReadDeadline(time.Now().Add(opts.pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(opts.pongWait))
+	})
+
+	for {
+		_, msg, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				opts.log.Debugf("websocket: connection %s read: %v", c.id, err)
+			}
+			return
+		}
+		if opts.onMessage != nil {
+			opts.onMessage(c, msg)
+		}
+	}
+}
+
+func (c *Conn) writePump() {
+	opts := c.hub.opts
+	ticker := time.NewTicker(opts.pingInterval)
+	defer func() {
+		ticker.Stop()
+		_ = c.ws.Close()
+	}()
+
+	for {
+		select {
+		case msg, ok := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(opts.writeWait))
+			if !ok {
+				// 队列已关闭且消息已全部发出
+				code := websocket.CloseNormalClosure
+				c.hub.mu.RLock()
+				if c.hub.closing {
+					code = websocket.CloseGoingAway
+				}
+				c.hub.mu.RUnlock()
+				_ = c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(code, ""))
+				// 等待对端回应 close 帧（读协程随之退出），超时则直接断开
+				select {
+				case <-c.readDone:
+				case <-time.After(opts.writeWait):
+				}
+				return
+			}
+			if err := c.ws.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.closeSend()
+				return
+			}
+		case <-ticker.C:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(opts.writeWait))
+			if err := c.ws.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.closeSend()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrQueueFull = errors.New("websocket: send queue full")
+	ErrClosed    = errors.New("websocket: connection closed")
+)
+
+type options struct {
+	sendQueue      int
+	writeWait      time.Duration
+	pongWait       time.Duration
+	pingInterval   time.Duration
+	maxMessageSize int64
+	upgrader       *websocket.Upgrader
+	onConnect      func(*Conn)
+	onMessage      func(*Conn, []byte)
+	onClose        func(*Conn)
+	log            *zap.SugaredLogger
+	reg            prometheus.Registerer
+	namespace      string
+}
+
+// Option 配置选项
+type Option func(*options)
+
+// WithSendQueue 设置每个连接的发送队列长度，默认 256
+func WithSendQueue(n int) Option {
+	return func(o *options) {
+		o.sendQueue = n
+	}
+}
+
+// WithTimeouts 设置写超时与 pong 等待时间，ping 间隔为 pongWait 的 9/10；默认 10s / 60s。
+// 非正数的时长被忽略，保留默认值
+func WithTimeouts(writeWait, pongWait time.Duration) Option {
+	return func(o *options) {
+		if writeWait > 0 {
+			o.writeWait = writeWait
+		}
+		if pongWait > 0 {
+			o.pongWait = pongWait
+			o.pingInterval = max(pongWait*9/10, 1)
+		}
+	}
+}
+
+// WithMaxMessageSize 限制客户端消息大小，默认 64KB
+func WithMaxMessageSize(n int64) Option {
+	return func(o *options) {
+		o.maxMessageSize = n
+	}
+}
+
+// WithUpgrader 自定义 Upgrader（如跨域检查 CheckOrigin）
+func WithUpgrader(u *websocket.Upgrader) Option {
+	return func(o *options) {
+		o.upgrader = u
+	}
+}
+
+// WithOnConnect 连接建立后回调，可在此根据 Conn.Request 绑定用户信息
+func WithOnConnect(fn func(*Conn)) Option {
+	return func(o *options) {
+		o.onConnect = fn
+	}
+}
+
+// WithOnMessage 收到客户端消息时回调，在连接的读协程中同步执行
+func WithOnMessage(fn func(*Conn, []byte)) Option {
+	return func(o *options) {
+		o.onMessage = fn
+	}
+}
+
+// WithOnClose 连接关闭后回调
+func WithOnClose(fn func(*Conn)) Option {
+	return func(o *options) {
+		o.onClose = fn
+	}
+}
+
+// WithLogger 设置日志，默认不输出
+func WithLogger(log *zap.SugaredLogger) Option {
+	return func(o *options) {
+		o.log = log
+	}
+}
+
+// WithMetrics 注册连接数指标 websocket_connections、websocket_connections_total 与
+// 因队列满被丢弃的消息数 websocket_dropped_messages_total。
+// 同一注册表上命名空间相同的多个 Hub 共用同一组指标
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(o *options) {
+		o.reg = reg
+	}
+}
+
+// WithNamespace 设置指标命名空间，如 chat 得到 chat_websocket_connections，用于区分同一进程内的多个 Hub
+func WithNamespace(ns string) Option {
+	return func(o *options) {
+		o.namespace = ns
+	}
+}
+
+// Hub 管理 WebSocket 连接：注册/注销、广播、心跳与优雅关闭
+//
+//	hub := websocket.NewHub(websocket.WithOnMessage(func(c *websocket.Conn, msg []byte) { ... }))
+//	http.Handle("/ws", hub)
+//	...
+//	hub.Shutdown(ctx)
+type Hub struct {
+	opts *options
+
+	mu      sync.RWMutex
+	conns   map[*Conn]struct{}
+	closing bool
+	wg      sync.WaitGroup
+
+	active  prometheus.Gauge
+	total   prometheus.Counter
+	dropped prometheus.Counter
+}
+
+// NewHub 创建连接管理器
+func NewHub(opts ...Option) *Hub {
+	o := &options{
+		sendQueue:      256,
+		writeWait:      10 * time.Second,
+		pongWait:       60 * time.Second,
+		pingInterval:   54 * time.Second,
+		maxMessageSize: 64 << 10,
+		upgrader:       &websocket.Upgrader{},
+		log:            zap.NewNop().Sugar(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	h := &Hub{
+		opts:  o,
+		conns: make(map[*Conn]struct{}),
+		active: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: o.namespace,
+			Name:      "websocket_connections",
+			Help:      "Number of active websocket connections.",
+		}),
+		total: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "websocket_connections_total",
+			Help:      "Number of accepted websocket connections.",
+		}),
+		dropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: o.namespace,
+			Name:      "websocket_dropped_messages_total",
+			Help:      "Number of messages dropped because the send queue was full.",
+		}),
+	}
+	if o.reg != nil {
+		h.active = registerOrExisting(o, h.active)
+		h.total = registerOrExisting(o, h.total)
+		h.dropped = registerOrExisting(o, h.dropped)
+	}
+	return h
+}
+
+// registerOrExisting 注册指标，已存在时复用已注册的指标；其他注册错误只记录日志，指标不导出
+func registerOrExisting[T prometheus.Collector](o *options, c T) T {
+	if err := o.reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+		o.log.Warnf("websocket: register metrics: %v", err)
+	}
+	return c
+}
+
+// ServeHTTP 升级为 WebSocket 连接并注册到 Hub，关闭中返回 503
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	if h.closing {
+		h.mu.Unlock()
+		http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	// 在锁内计数，保证 Shutdown 的 Wait 不会漏掉正在升级的连接
+	h.wg.Add(1)
+	h.mu.Unlock()
+	defer h.wg.Done()
+
+	ws, err := h.opts.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade 已写入错误响应
+		h.opts.log.Debugf("websocket: upgrade failed: %v", err)
+		return
+	}
+
+	c := newConn(h, ws, r)
+	h.register(c)
+	c.run()
+	h.unregister(c)
+}
+
+func (h *Hub) register(c *Conn) {
+	h.mu.Lock()
+	h.conns[c] = struct{}{}
+	closing := h.closing
+	h.mu.Unlock()
+
+	h.active.Inc()
+	h.total.Inc()
+
+	// 升级期间开始了关闭流程
+	if closing {
+		c.closeSend()
+		return
+	}
+	if h.opts.onConnect != nil {
+		h.opts.onConnect(c)
+	}
+}
+
+func (h *Hub) unregister(c *Conn) {
+	h.mu.Lock()
+	delete(h.conns, c)
+	h.mu.Unlock()
+
+	h.active.Dec()
+	if h.opts.onClose != nil {
+		h.opts.onClose(c)
+	}
+}
+
+// Len 返回当前连接数
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// Each 遍历当前连接，fn 返回 false 时停止
+func (h *Hub) Each(fn func(*Conn) bool) {
+	h.mu.RLock()
+	conns := make([]*Conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.RUnlock()
+
+	for _, c := range conns {
+		if !fn(c) {
+			return
+		}
+	}
+}
+
+// Broadcast 向所有连接发送文本消息；发送队列已满的慢连接会被关闭，返回被关闭的连接数
+func (h *Hub) Broadcast(msg []byte) int {
+	slow := 0
+	h.Each(func(c *Conn) bool {
+		if err := c.Send(msg); errors.Is(err, ErrQueueFull) {
+			slow++
+			h.opts.log.Warnf("websocket: closing slow connection %s", c.ID())
+			c.Close()
+		}
+		return true
+	})
+	return slow
+}
+
+// Shutdown 停止接受新连接，已排队的消息发送完毕后以 1001 (going away) 关闭各连接；
+// ctx 到期时强制断开剩余连接
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closing = true
+	h.mu.Unlock()
+
+	h.Each(func(c *Conn) bool {
+		c.closeSend()
+		return true
+	})
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		h.Each(func(c *Conn) bool {
+			_ = c.ws.Close()
+			return true
+		})
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,142 @@
+package websocket
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func dial(t *testing.T, srv *httptest.Server) *websocket.Conn {
+	t.Helper()
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	return ws
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHubEchoAndBroadcast(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	hub := NewHub(
+		WithMetrics(reg),
+		WithOnMessage(func(c *Conn, msg []byte) {
+			_ = c.Send(append([]byte("echo:"), msg...))
+		}),
+	)
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	a, b := dial(t, srv), dial(t, srv)
+	defer a.Close()
+	defer b.Close()
+	waitFor(t, func() bool { return hub.Len() == 2 })
+
+	if err := a.WriteMessage(websocket.TextMessage, []byte("hi")); err != nil {
+		t.Fatal(err)
+	}
+	if _, msg, err := a.ReadMessage(); err != nil || string(msg) != "echo:hi" {
+		t.Fatalf("unexpected echo %q: %v", msg, err)
+	}
+
+	hub.Broadcast([]byte("all"))
+	for _, ws := range []*websocket.Conn{a, b} {
+		if _, msg, err := ws.ReadMessage(); err != nil || string(msg) != "all" {
+			t.Fatalf("unexpected broadcast %q: %v", msg, err)
+		}
+	}
+
+	if got := testutil.ToFloat64(hub.active); got != 2 {
+		t.Fatalf("expected 2 active connections, got %v", got)
+	}
+	_ = a.Close()
+	waitFor(t, func() bool { return hub.Len() == 1 })
+	if got := testutil.ToFloat64(hub.total); got != 2 {
+		t.Fatalf("expected 2 total connections, got %v", got)
+	}
+}
+
+func TestHubShutdownDrains(t *testing.T) {
+	hub := NewHub(WithTimeouts(time.Second, time.Second))
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+
+	ws := dial(t, srv)
+	defer ws.Close()
+	waitFor(t, func() bool { return hub.Len() == 1 })
+
+	hub.Each(func(c *Conn) bool {
+		_ = c.Send([]byte("last"))
+		return true
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- hub.Shutdown(context.Background()) }()
+
+	if _, msg, err := ws.ReadMessage(); err != nil || string(msg) != "last" {
+		t.Fatalf("queued message not drained: %q %v", msg, err)
+	}
+	_, _, err := ws.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+		t.Fatalf("expected going away close, got %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("shutdown: %v", err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("shutdown did not finish")
+	}
+
+	if _, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil); err == nil || resp.StatusCode != 503 {
+		t.Fatalf("expected 503 after shutdown, got %v", err)
+	}
+}
+
+func TestWithTimeoutsInvalid(t *testing.T) {
+	// 非正数被忽略，保留默认值
+	h := NewHub(WithTimeouts(0, -time.Second))
+	if h.opts.writeWait != 10*time.Second || h.opts.pongWait != 60*time.Second || h.opts.pingInterval != 54*time.Second {
+		t.Fatalf("unexpected timeouts %+v", h.opts)
+	}
+
+	// 极小的 pongWait 不会得到 0 的 ping 间隔
+	h = NewHub(WithTimeouts(time.Second, time.Nanosecond))
+	if h.opts.pingInterval <= 0 {
+		t.Fatalf("unexpected ping interval %v", h.opts.pingInterval)
+	}
+}
+
+func TestMetricsSharedRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	a := NewHub(WithMetrics(reg))
+	b := NewHub(WithMetrics(reg))
+	if a.total != b.total {
+		t.Fatalf("expected hubs on one registry to share metrics")
+	}
+
+	c := NewHub(WithMetrics(reg), WithNamespace("chat"))
+	c.total.Inc()
+	n, err := testutil.GatherAndCount(reg, "chat_websocket_connections_total")
+	if err != nil || n != 1 {
+		t.Fatalf("expected namespaced metric, got %d: %v", n, err)
+	}
+}